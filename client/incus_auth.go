@@ -0,0 +1,303 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Authorization group handling functions.
+
+// GetAuthGroupNames returns the names of all the authorization groups.
+func (r *ProtocolIncus) GetAuthGroupNames() ([]string, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	urls := []string{}
+
+	_, err := r.queryStruct("GET", "/auth/groups", nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames("/1.0/auth/groups", urls...)
+}
+
+// GetAuthGroups returns all the authorization groups.
+func (r *ProtocolIncus) GetAuthGroups() ([]api.AuthGroup, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	groups := []api.AuthGroup{}
+
+	_, err := r.queryStruct("GET", "/auth/groups?recursion=1", nil, "", &groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetAuthGroup returns information about the given authorization group.
+func (r *ProtocolIncus) GetAuthGroup(name string) (*api.AuthGroup, string, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, "", fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	group := api.AuthGroup{}
+
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "", &group)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &group, etag, nil
+}
+
+// CreateAuthGroup creates a new authorization group.
+func (r *ProtocolIncus) CreateAuthGroup(group api.AuthGroupsPost) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("POST", "/auth/groups", group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAuthGroup updates the given authorization group.
+func (r *ProtocolIncus) UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameAuthGroup renames an existing authorization group.
+func (r *ProtocolIncus) RenameAuthGroup(name string, group api.AuthGroupPost) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("POST", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), group, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthGroup deletes an existing authorization group.
+func (r *ProtocolIncus) DeleteAuthGroup(name string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/groups/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Authorization role handling functions.
+
+// GetAuthRoleNames returns the names of all the authorization roles.
+func (r *ProtocolIncus) GetAuthRoleNames() ([]string, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	urls := []string{}
+
+	_, err := r.queryStruct("GET", "/auth/roles", nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames("/1.0/auth/roles", urls...)
+}
+
+// GetAuthRoles returns all the authorization roles.
+func (r *ProtocolIncus) GetAuthRoles() ([]api.AuthRole, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	roles := []api.AuthRole{}
+
+	_, err := r.queryStruct("GET", "/auth/roles?recursion=1", nil, "", &roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// GetAuthRole returns information about the given authorization role.
+func (r *ProtocolIncus) GetAuthRole(name string) (*api.AuthRole, string, error) {
+	if !r.HasExtension("auth_rbac") {
+		return nil, "", fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	role := api.AuthRole{}
+
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/auth/roles/%s", url.PathEscape(name)), nil, "", &role)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &role, etag, nil
+}
+
+// CreateAuthRole creates a new authorization role.
+func (r *ProtocolIncus) CreateAuthRole(role api.AuthRolesPost) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("POST", "/auth/roles", role, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateAuthRole updates the given authorization role.
+func (r *ProtocolIncus) UpdateAuthRole(name string, role api.AuthRolePut, ETag string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("PUT", fmt.Sprintf("/auth/roles/%s", url.PathEscape(name)), role, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameAuthRole renames an existing authorization role.
+func (r *ProtocolIncus) RenameAuthRole(name string, role api.AuthRolePost) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("POST", fmt.Sprintf("/auth/roles/%s", url.PathEscape(name)), role, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteAuthRole deletes an existing authorization role.
+func (r *ProtocolIncus) DeleteAuthRole(name string) error {
+	if !r.HasExtension("auth_rbac") {
+		return fmt.Errorf("The server is missing the required \"auth_rbac\" API extension")
+	}
+
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/roles/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// API token handling functions.
+
+// GetAuthTokenNames returns the names of all the API tokens.
+func (r *ProtocolIncus) GetAuthTokenNames() ([]string, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	urls := []string{}
+
+	_, err := r.queryStruct("GET", "/auth/tokens", nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames("/1.0/auth/tokens", urls...)
+}
+
+// GetAuthTokens returns all the API tokens.
+func (r *ProtocolIncus) GetAuthTokens() ([]api.AuthToken, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	tokens := []api.AuthToken{}
+
+	_, err := r.queryStruct("GET", "/auth/tokens?recursion=1", nil, "", &tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// GetAuthToken returns information about the given API token.
+func (r *ProtocolIncus) GetAuthToken(name string) (*api.AuthToken, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	token := api.AuthToken{}
+
+	_, err := r.queryStruct("GET", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), nil, "", &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// CreateAuthToken creates a new API token and returns its secret.
+func (r *ProtocolIncus) CreateAuthToken(token api.AuthTokensPost) (*api.AuthTokenSecret, error) {
+	if !r.HasExtension("auth_tokens") {
+		return nil, fmt.Errorf("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	secret := api.AuthTokenSecret{}
+
+	_, err := r.queryStruct("POST", "/auth/tokens", token, "", &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// DeleteAuthToken revokes an existing API token.
+func (r *ProtocolIncus) DeleteAuthToken(name string) error {
+	if !r.HasExtension("auth_tokens") {
+		return fmt.Errorf("The server is missing the required \"auth_tokens\" API extension")
+	}
+
+	_, _, err := r.query("DELETE", fmt.Sprintf("/auth/tokens/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
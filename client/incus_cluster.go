@@ -307,3 +307,46 @@ func (r *ProtocolIncus) GetClusterGroup(name string) (*api.ClusterGroup, string,
 
 	return &group, etag, nil
 }
+
+// GetClusterRebalancePlan returns the instance migrations the next re-balancing run would perform.
+func (r *ProtocolIncus) GetClusterRebalancePlan() (*api.ClusterRebalancePlan, error) {
+	if !r.HasExtension("cluster_rebalance_policy") {
+		return nil, fmt.Errorf("The server is missing the required \"cluster_rebalance_policy\" API extension")
+	}
+
+	plan := api.ClusterRebalancePlan{}
+	_, err := r.queryStruct("GET", "/cluster/rebalance", nil, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// RebalanceCluster performs a re-balancing run immediately.
+func (r *ProtocolIncus) RebalanceCluster() (Operation, error) {
+	if !r.HasExtension("cluster_rebalance_policy") {
+		return nil, fmt.Errorf("The server is missing the required \"cluster_rebalance_policy\" API extension")
+	}
+
+	op, _, err := r.queryOperation("POST", "/cluster/rebalance", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// CreateClusterMaintenance starts a rolling maintenance of cluster members.
+func (r *ProtocolIncus) CreateClusterMaintenance(maintenance api.ClusterMaintenancePost) (Operation, error) {
+	if !r.HasExtension("cluster_rolling_maintenance") {
+		return nil, fmt.Errorf("The server is missing the required \"cluster_rolling_maintenance\" API extension")
+	}
+
+	op, _, err := r.queryOperation("POST", "/cluster/maintenance", maintenance, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
@@ -0,0 +1,160 @@
+package incus
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/cancel"
+	"github.com/lxc/incus/v6/shared/ioprogress"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// Global database backup handling functions
+
+// GetDatabaseBackupNames returns a list of global database backup names.
+func (r *ProtocolIncus) GetDatabaseBackupNames() ([]string, error) {
+	if !r.HasExtension("database_backup") {
+		return nil, fmt.Errorf("The server is missing the required \"database_backup\" API extension")
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/database/backups"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetDatabaseBackups returns a list of global database backups.
+func (r *ProtocolIncus) GetDatabaseBackups() ([]api.DatabaseBackup, error) {
+	if !r.HasExtension("database_backup") {
+		return nil, fmt.Errorf("The server is missing the required \"database_backup\" API extension")
+	}
+
+	backups := []api.DatabaseBackup{}
+
+	_, err := r.queryStruct("GET", "/database/backups?recursion=1", nil, "", &backups)
+	if err != nil {
+		return nil, err
+	}
+
+	return backups, nil
+}
+
+// GetDatabaseBackup returns a global database backup.
+func (r *ProtocolIncus) GetDatabaseBackup(name string) (*api.DatabaseBackup, string, error) {
+	if !r.HasExtension("database_backup") {
+		return nil, "", fmt.Errorf("The server is missing the required \"database_backup\" API extension")
+	}
+
+	backup := api.DatabaseBackup{}
+
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/database/backups/%s", url.PathEscape(name)), nil, "", &backup)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &backup, etag, nil
+}
+
+// CreateDatabaseBackup requests that the server takes a new backup of the global database.
+func (r *ProtocolIncus) CreateDatabaseBackup(backup api.DatabaseBackupsPost) (Operation, error) {
+	if !r.HasExtension("database_backup") {
+		return nil, fmt.Errorf("The server is missing the required \"database_backup\" API extension")
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", "/database/backups", backup, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteDatabaseBackup deletes a global database backup.
+func (r *ProtocolIncus) DeleteDatabaseBackup(name string) error {
+	if !r.HasExtension("database_backup") {
+		return fmt.Errorf("The server is missing the required \"database_backup\" API extension")
+	}
+
+	// Send the request
+	_, _, err := r.query("DELETE", fmt.Sprintf("/database/backups/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetDatabaseBackupFile requests the global database backup content.
+func (r *ProtocolIncus) GetDatabaseBackupFile(name string, req *BackupFileRequest) (*BackupFileResponse, error) {
+	if !r.HasExtension("database_backup") {
+		return nil, fmt.Errorf("The server is missing the required \"database_backup\" API extension")
+	}
+
+	// Build the URL
+	uri := fmt.Sprintf("%s/1.0/database/backups/%s/export", r.httpBaseURL.String(), url.PathEscape(name))
+
+	uri, err := r.setQueryAttributes(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare the download request
+	request, err := http.NewRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.httpUserAgent != "" {
+		request.Header.Set("User-Agent", r.httpUserAgent)
+	}
+
+	// Start the request
+	response, doneCh, err := cancel.CancelableDownload(req.Canceler, r.DoHTTP, request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = response.Body.Close() }()
+	defer close(doneCh)
+
+	if response.StatusCode != http.StatusOK {
+		_, _, err := incusParseResponse(response)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Handle the data
+	body := response.Body
+	if req.ProgressHandler != nil {
+		body = &ioprogress.ProgressReader{
+			ReadCloser: response.Body,
+			Tracker: &ioprogress.ProgressTracker{
+				Length: response.ContentLength,
+				Handler: func(percent int64, speed int64) {
+					req.ProgressHandler(ioprogress.ProgressData{Text: fmt.Sprintf("%d%% (%s/s)", percent, units.GetByteSizeString(speed, 2))})
+				},
+			},
+		}
+	}
+
+	size, err := io.Copy(req.BackupFile, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := BackupFileResponse{}
+	resp.Size = size
+
+	return &resp, nil
+}
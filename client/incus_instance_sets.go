@@ -0,0 +1,105 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetInstanceSetNames returns a list of instance set names.
+func (r *ProtocolIncus) GetInstanceSetNames() ([]string, error) {
+	if !r.HasExtension("instance_sets") {
+		return nil, fmt.Errorf(`The server is missing the required "instance_sets" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/instance-sets"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetInstanceSets returns a list of instance set structs.
+func (r *ProtocolIncus) GetInstanceSets() ([]api.InstanceSet, error) {
+	if !r.HasExtension("instance_sets") {
+		return nil, fmt.Errorf(`The server is missing the required "instance_sets" API extension`)
+	}
+
+	instanceSets := []api.InstanceSet{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/instance-sets?recursion=1", nil, "", &instanceSets)
+	if err != nil {
+		return nil, err
+	}
+
+	return instanceSets, nil
+}
+
+// GetInstanceSet returns an instance set entry for the provided name.
+func (r *ProtocolIncus) GetInstanceSet(name string) (*api.InstanceSet, string, error) {
+	if !r.HasExtension("instance_sets") {
+		return nil, "", fmt.Errorf(`The server is missing the required "instance_sets" API extension`)
+	}
+
+	instanceSet := api.InstanceSet{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/instance-sets/%s", url.PathEscape(name)), nil, "", &instanceSet)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &instanceSet, etag, nil
+}
+
+// CreateInstanceSet creates a new instance set along with its instances.
+func (r *ProtocolIncus) CreateInstanceSet(instanceSet api.InstanceSetsPost) (Operation, error) {
+	if !r.HasExtension("instance_sets") {
+		return nil, fmt.Errorf(`The server is missing the required "instance_sets" API extension`)
+	}
+
+	// Send the request.
+	op, _, err := r.queryOperation("POST", "/instance-sets", instanceSet, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UpdateInstanceSet updates the definition of an existing instance set.
+func (r *ProtocolIncus) UpdateInstanceSet(name string, instanceSet api.InstanceSetPut, ETag string) (Operation, error) {
+	if !r.HasExtension("instance_sets") {
+		return nil, fmt.Errorf(`The server is missing the required "instance_sets" API extension`)
+	}
+
+	// Send the request.
+	op, _, err := r.queryOperation("PUT", fmt.Sprintf("/instance-sets/%s", url.PathEscape(name)), instanceSet, ETag)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteInstanceSet deletes an instance set along with its instances.
+func (r *ProtocolIncus) DeleteInstanceSet(name string) (Operation, error) {
+	if !r.HasExtension("instance_sets") {
+		return nil, fmt.Errorf(`The server is missing the required "instance_sets" API extension`)
+	}
+
+	// Send the request.
+	op, _, err := r.queryOperation("DELETE", fmt.Sprintf("/instance-sets/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
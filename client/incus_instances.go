@@ -2188,6 +2188,50 @@ func (r *ProtocolIncus) UpdateInstanceState(name string, state api.InstanceState
 	return op, nil
 }
 
+// GetInstanceReplication returns the replication status of the instance.
+func (r *ProtocolIncus) GetInstanceReplication(name string) (*api.InstanceReplication, string, error) {
+	err := r.CheckExtension("instance_replication")
+	if err != nil {
+		return nil, "", err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, "", err
+	}
+
+	replication := api.InstanceReplication{}
+
+	// Fetch the raw value
+	etag, err := r.queryStruct("GET", fmt.Sprintf("%s/%s/replication", path, url.PathEscape(name)), nil, "", &replication)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &replication, etag, nil
+}
+
+// UpdateInstanceReplication replicates the instance or fails over to it.
+func (r *ProtocolIncus) UpdateInstanceReplication(name string, req api.InstanceReplicationPost) (Operation, error) {
+	err := r.CheckExtension("instance_replication")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation("POST", fmt.Sprintf("%s/%s/replication", path, url.PathEscape(name)), req, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // GetInstanceAccess returns an Access entry for the provided instance name.
 func (r *ProtocolIncus) GetInstanceAccess(name string) (api.Access, error) {
 	access := api.Access{}
@@ -3,6 +3,7 @@ package incus
 import (
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -78,6 +79,33 @@ func (r *ProtocolIncus) GetProjectState(name string) (*api.ProjectState, error)
 	return &projectState, nil
 }
 
+// GetProjectUsage returns the resources consumed by the project over the given period.
+// Zero dates default to the start of the current month and to now.
+func (r *ProtocolIncus) GetProjectUsage(name string, from time.Time, to time.Time) (*api.ProjectUsage, error) {
+	if !r.HasExtension("projects_usage_accounting") {
+		return nil, fmt.Errorf("The server is missing the required \"projects_usage_accounting\" API extension")
+	}
+
+	v := url.Values{}
+	if !from.IsZero() {
+		v.Set("from", from.Format(time.RFC3339))
+	}
+
+	if !to.IsZero() {
+		v.Set("to", to.Format(time.RFC3339))
+	}
+
+	usage := api.ProjectUsage{}
+
+	// Fetch the raw value
+	_, err := r.queryStruct("GET", fmt.Sprintf("/projects/%s/usage?%s", url.PathEscape(name), v.Encode()), nil, "", &usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
 // GetProjectAccess returns an Access entry for the specified project.
 func (r *ProtocolIncus) GetProjectAccess(name string) (api.Access, error) {
 	access := api.Access{}
@@ -101,6 +129,10 @@ func (r *ProtocolIncus) CreateProject(project api.ProjectsPost) error {
 		return fmt.Errorf("The server is missing the required \"projects\" API extension")
 	}
 
+	if project.Template != "" && !r.HasExtension("project_templates") {
+		return fmt.Errorf("The server is missing the required \"project_templates\" API extension")
+	}
+
 	// Send the request
 	_, _, err := r.query("POST", "/projects", project, "")
 	if err != nil {
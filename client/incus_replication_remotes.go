@@ -0,0 +1,105 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetReplicationRemoteNames returns a list of replication remote names.
+func (r *ProtocolIncus) GetReplicationRemoteNames() ([]string, error) {
+	if !r.HasExtension("instance_replication") {
+		return nil, fmt.Errorf(`The server is missing the required "instance_replication" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/replication-remotes"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetReplicationRemotes returns a list of replication remote structs.
+func (r *ProtocolIncus) GetReplicationRemotes() ([]api.ReplicationRemote, error) {
+	if !r.HasExtension("instance_replication") {
+		return nil, fmt.Errorf(`The server is missing the required "instance_replication" API extension`)
+	}
+
+	remotes := []api.ReplicationRemote{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/replication-remotes?recursion=1", nil, "", &remotes)
+	if err != nil {
+		return nil, err
+	}
+
+	return remotes, nil
+}
+
+// GetReplicationRemote returns a replication remote entry for the provided name.
+func (r *ProtocolIncus) GetReplicationRemote(name string) (*api.ReplicationRemote, string, error) {
+	if !r.HasExtension("instance_replication") {
+		return nil, "", fmt.Errorf(`The server is missing the required "instance_replication" API extension`)
+	}
+
+	remote := api.ReplicationRemote{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/replication-remotes/%s", url.PathEscape(name)), nil, "", &remote)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &remote, etag, nil
+}
+
+// CreateReplicationRemote defines a new replication remote.
+func (r *ProtocolIncus) CreateReplicationRemote(remote api.ReplicationRemotesPost) error {
+	if !r.HasExtension("instance_replication") {
+		return fmt.Errorf(`The server is missing the required "instance_replication" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/replication-remotes", remote, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateReplicationRemote updates the replication remote to match the provided struct.
+func (r *ProtocolIncus) UpdateReplicationRemote(name string, remote api.ReplicationRemotePut, ETag string) error {
+	if !r.HasExtension("instance_replication") {
+		return fmt.Errorf(`The server is missing the required "instance_replication" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/replication-remotes/%s", url.PathEscape(name)), remote, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteReplicationRemote deletes a replication remote.
+func (r *ProtocolIncus) DeleteReplicationRemote(name string) error {
+	if !r.HasExtension("instance_replication") {
+		return fmt.Errorf(`The server is missing the required "instance_replication" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/replication-remotes/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetSecretNames returns a list of secret names.
+func (r *ProtocolIncus) GetSecretNames() ([]string, error) {
+	if !r.HasExtension("secrets") {
+		return nil, fmt.Errorf(`The server is missing the required "secrets" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/secrets"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetSecrets returns a list of secret structs.
+func (r *ProtocolIncus) GetSecrets() ([]api.Secret, error) {
+	if !r.HasExtension("secrets") {
+		return nil, fmt.Errorf(`The server is missing the required "secrets" API extension`)
+	}
+
+	secrets := []api.Secret{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/secrets?recursion=1", nil, "", &secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	return secrets, nil
+}
+
+// GetSecret returns a secret entry for the provided name.
+func (r *ProtocolIncus) GetSecret(name string) (*api.Secret, string, error) {
+	if !r.HasExtension("secrets") {
+		return nil, "", fmt.Errorf(`The server is missing the required "secrets" API extension`)
+	}
+
+	secret := api.Secret{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/secrets/%s", url.PathEscape(name)), nil, "", &secret)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &secret, etag, nil
+}
+
+// CreateSecret defines a new secret.
+func (r *ProtocolIncus) CreateSecret(secret api.SecretsPost) error {
+	if !r.HasExtension("secrets") {
+		return fmt.Errorf(`The server is missing the required "secrets" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("POST", "/secrets", secret, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateSecret updates the secret to match the provided struct.
+func (r *ProtocolIncus) UpdateSecret(name string, secret api.SecretPut, ETag string) error {
+	if !r.HasExtension("secrets") {
+		return fmt.Errorf(`The server is missing the required "secrets" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("PUT", fmt.Sprintf("/secrets/%s", url.PathEscape(name)), secret, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteSecret deletes a secret.
+func (r *ProtocolIncus) DeleteSecret(name string) error {
+	if !r.HasExtension("secrets") {
+		return fmt.Errorf(`The server is missing the required "secrets" API extension`)
+	}
+
+	// Send the request.
+	_, _, err := r.query("DELETE", fmt.Sprintf("/secrets/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package incus
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// GetStackNames returns a list of stack names.
+func (r *ProtocolIncus) GetStackNames() ([]string, error) {
+	if !r.HasExtension("stacks") {
+		return nil, fmt.Errorf(`The server is missing the required "stacks" API extension`)
+	}
+
+	// Fetch the raw URL values.
+	urls := []string{}
+	baseURL := "/stacks"
+	_, err := r.queryStruct("GET", baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse it.
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetStacks returns a list of stack structs.
+func (r *ProtocolIncus) GetStacks() ([]api.Stack, error) {
+	if !r.HasExtension("stacks") {
+		return nil, fmt.Errorf(`The server is missing the required "stacks" API extension`)
+	}
+
+	stacks := []api.Stack{}
+
+	// Fetch the raw value.
+	_, err := r.queryStruct("GET", "/stacks?recursion=1", nil, "", &stacks)
+	if err != nil {
+		return nil, err
+	}
+
+	return stacks, nil
+}
+
+// GetStack returns a stack entry for the provided name.
+func (r *ProtocolIncus) GetStack(name string) (*api.Stack, string, error) {
+	if !r.HasExtension("stacks") {
+		return nil, "", fmt.Errorf(`The server is missing the required "stacks" API extension`)
+	}
+
+	stack := api.Stack{}
+
+	// Fetch the raw value.
+	etag, err := r.queryStruct("GET", fmt.Sprintf("/stacks/%s", url.PathEscape(name)), nil, "", &stack)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &stack, etag, nil
+}
+
+// GetStackPlan returns the changes needed to bring a stack to the provided definition.
+func (r *ProtocolIncus) GetStackPlan(name string, stack api.StackPut) (*api.StackPlan, error) {
+	if !r.HasExtension("stacks") {
+		return nil, fmt.Errorf(`The server is missing the required "stacks" API extension`)
+	}
+
+	plan := api.StackPlan{}
+
+	// Send the request.
+	_, err := r.queryStruct("POST", fmt.Sprintf("/stacks/%s/plan", url.PathEscape(name)), stack, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// CreateStack creates a new stack along with all of its resources.
+func (r *ProtocolIncus) CreateStack(stack api.StacksPost) (Operation, error) {
+	if !r.HasExtension("stacks") {
+		return nil, fmt.Errorf(`The server is missing the required "stacks" API extension`)
+	}
+
+	// Send the request.
+	op, _, err := r.queryOperation("POST", "/stacks", stack, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// UpdateStack brings the resources of an existing stack to the provided definition.
+func (r *ProtocolIncus) UpdateStack(name string, stack api.StackPut, ETag string) (Operation, error) {
+	if !r.HasExtension("stacks") {
+		return nil, fmt.Errorf(`The server is missing the required "stacks" API extension`)
+	}
+
+	// Send the request.
+	op, _, err := r.queryOperation("PUT", fmt.Sprintf("/stacks/%s", url.PathEscape(name)), stack, ETag)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// DeleteStack deletes a stack along with all of its resources.
+func (r *ProtocolIncus) DeleteStack(name string) (Operation, error) {
+	if !r.HasExtension("stacks") {
+		return nil, fmt.Errorf(`The server is missing the required "stacks" API extension`)
+	}
+
+	// Send the request.
+	op, _, err := r.queryOperation("DELETE", fmt.Sprintf("/stacks/%s", url.PathEscape(name)), nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
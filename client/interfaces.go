@@ -5,6 +5,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/sftp"
@@ -85,6 +86,27 @@ type InstanceServer interface {
 	UseTarget(name string) (client InstanceServer)
 	UseProject(name string) (client InstanceServer)
 
+	// Authorization functions
+	GetAuthGroupNames() (names []string, err error)
+	GetAuthGroups() (groups []api.AuthGroup, err error)
+	GetAuthGroup(name string) (group *api.AuthGroup, ETag string, err error)
+	CreateAuthGroup(group api.AuthGroupsPost) (err error)
+	UpdateAuthGroup(name string, group api.AuthGroupPut, ETag string) (err error)
+	RenameAuthGroup(name string, group api.AuthGroupPost) (err error)
+	DeleteAuthGroup(name string) (err error)
+	GetAuthRoleNames() (names []string, err error)
+	GetAuthRoles() (roles []api.AuthRole, err error)
+	GetAuthRole(name string) (role *api.AuthRole, ETag string, err error)
+	CreateAuthRole(role api.AuthRolesPost) (err error)
+	UpdateAuthRole(name string, role api.AuthRolePut, ETag string) (err error)
+	RenameAuthRole(name string, role api.AuthRolePost) (err error)
+	DeleteAuthRole(name string) (err error)
+	GetAuthTokenNames() (names []string, err error)
+	GetAuthTokens() (tokens []api.AuthToken, err error)
+	GetAuthToken(name string) (token *api.AuthToken, err error)
+	CreateAuthToken(token api.AuthTokensPost) (secret *api.AuthTokenSecret, err error)
+	DeleteAuthToken(name string) (err error)
+
 	// Certificate functions
 	GetCertificateFingerprints() (fingerprints []string, err error)
 	GetCertificates() (certificates []api.Certificate, err error)
@@ -154,6 +176,9 @@ type InstanceServer interface {
 	GetInstanceState(name string) (state *api.InstanceState, ETag string, err error)
 	UpdateInstanceState(name string, state api.InstanceStatePut, ETag string) (op Operation, err error)
 
+	GetInstanceReplication(name string) (replication *api.InstanceReplication, ETag string, err error)
+	UpdateInstanceReplication(name string, req api.InstanceReplicationPost) (op Operation, err error)
+
 	GetInstanceAccess(name string) (access api.Access, err error)
 
 	GetInstanceLogfiles(name string) (logfiles []string, err error)
@@ -292,6 +317,7 @@ type InstanceServer interface {
 	GetProjects() (projects []api.Project, err error)
 	GetProject(name string) (project *api.Project, ETag string, err error)
 	GetProjectState(name string) (project *api.ProjectState, err error)
+	GetProjectUsage(name string, from time.Time, to time.Time) (usage *api.ProjectUsage, err error)
 	GetProjectAccess(name string) (access api.Access, err error)
 	CreateProject(project api.ProjectsPost) (err error)
 	UpdateProject(name string, project api.ProjectPut, ETag string) (err error)
@@ -299,6 +325,48 @@ type InstanceServer interface {
 	DeleteProject(name string) (err error)
 	DeleteProjectForce(name string) (err error)
 
+	// Project template functions ("project_templates" API extension)
+	GetProjectTemplateNames() (names []string, err error)
+	GetProjectTemplates() (templates []api.ProjectTemplate, err error)
+	GetProjectTemplate(name string) (template *api.ProjectTemplate, ETag string, err error)
+	CreateProjectTemplate(template api.ProjectTemplatesPost) (err error)
+	UpdateProjectTemplate(name string, template api.ProjectTemplatePut, ETag string) (err error)
+	DeleteProjectTemplate(name string) (err error)
+	ApplyProjectTemplate(name string, apply api.ProjectTemplateApplyPost) (op Operation, err error)
+
+	// Instance set functions ("instance_sets" API extension)
+	GetInstanceSetNames() (names []string, err error)
+	GetInstanceSets() (instanceSets []api.InstanceSet, err error)
+	GetInstanceSet(name string) (instanceSet *api.InstanceSet, ETag string, err error)
+	CreateInstanceSet(instanceSet api.InstanceSetsPost) (op Operation, err error)
+	UpdateInstanceSet(name string, instanceSet api.InstanceSetPut, ETag string) (op Operation, err error)
+	DeleteInstanceSet(name string) (op Operation, err error)
+
+	// Replication remote functions ("instance_replication" API extension)
+	GetReplicationRemoteNames() (names []string, err error)
+	GetReplicationRemotes() (remotes []api.ReplicationRemote, err error)
+	GetReplicationRemote(name string) (remote *api.ReplicationRemote, ETag string, err error)
+	CreateReplicationRemote(remote api.ReplicationRemotesPost) (err error)
+	UpdateReplicationRemote(name string, remote api.ReplicationRemotePut, ETag string) (err error)
+	DeleteReplicationRemote(name string) (err error)
+
+	// Secret functions ("secrets" API extension)
+	GetSecretNames() (names []string, err error)
+	GetSecrets() (secrets []api.Secret, err error)
+	GetSecret(name string) (secret *api.Secret, ETag string, err error)
+	CreateSecret(secret api.SecretsPost) (err error)
+	UpdateSecret(name string, secret api.SecretPut, ETag string) (err error)
+	DeleteSecret(name string) (err error)
+
+	// Stack functions ("stacks" API extension)
+	GetStackNames() (names []string, err error)
+	GetStacks() (stacks []api.Stack, err error)
+	GetStack(name string) (stack *api.Stack, ETag string, err error)
+	GetStackPlan(name string, stack api.StackPut) (plan *api.StackPlan, err error)
+	CreateStack(stack api.StacksPost) (op Operation, err error)
+	UpdateStack(name string, stack api.StackPut, ETag string) (op Operation, err error)
+	DeleteStack(name string) (op Operation, err error)
+
 	// Storage pool functions ("storage" API extension)
 	GetStoragePoolNames() (names []string, err error)
 	GetStoragePools() (pools []api.StoragePool, err error)
@@ -388,6 +456,17 @@ type InstanceServer interface {
 	DeleteClusterGroup(name string) error
 	UpdateClusterGroup(name string, group api.ClusterGroupPut, ETag string) error
 	GetClusterGroup(name string) (*api.ClusterGroup, string, error)
+	GetClusterRebalancePlan() (plan *api.ClusterRebalancePlan, err error)
+	RebalanceCluster() (op Operation, err error)
+	CreateClusterMaintenance(maintenance api.ClusterMaintenancePost) (op Operation, err error)
+
+	// Global database backup functions
+	GetDatabaseBackupNames() (names []string, err error)
+	GetDatabaseBackups() (backups []api.DatabaseBackup, err error)
+	GetDatabaseBackup(name string) (backup *api.DatabaseBackup, ETag string, err error)
+	CreateDatabaseBackup(backup api.DatabaseBackupsPost) (op Operation, err error)
+	DeleteDatabaseBackup(name string) (err error)
+	GetDatabaseBackupFile(name string, req *BackupFileRequest) (resp *BackupFileResponse, err error)
 
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
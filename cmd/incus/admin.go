@@ -24,6 +24,10 @@ func (c *cmdAdmin) Command() *cobra.Command {
 	adminClusterCmd := cmdAdminCluster{global: c.global}
 	cmd.AddCommand(adminClusterCmd.Command())
 
+	// database
+	adminDatabaseCmd := cmdAdminDatabase{global: c.global}
+	cmd.AddCommand(adminDatabaseCmd.Command())
+
 	// init
 	adminInitCmd := cmdAdminInit{global: c.global}
 	cmd.AddCommand(adminInitCmd.Command())
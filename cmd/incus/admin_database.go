@@ -0,0 +1,339 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+type cmdAdminDatabase struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAdminDatabase) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("database")
+	cmd.Short = i18n.G("Back up and restore the global database")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Back up and restore the global database
+
+  Backups are consistent SQL dumps of the global database, stored on the local server.`))
+
+	// Backup
+	adminDatabaseBackupCmd := cmdAdminDatabaseBackup{global: c.global, database: c}
+	cmd.AddCommand(adminDatabaseBackupCmd.Command())
+
+	// Delete
+	adminDatabaseDeleteCmd := cmdAdminDatabaseDelete{global: c.global, database: c}
+	cmd.AddCommand(adminDatabaseDeleteCmd.Command())
+
+	// Export
+	adminDatabaseExportCmd := cmdAdminDatabaseExport{global: c.global, database: c}
+	cmd.AddCommand(adminDatabaseExportCmd.Command())
+
+	// List
+	adminDatabaseListCmd := cmdAdminDatabaseList{global: c.global, database: c}
+	cmd.AddCommand(adminDatabaseListCmd.Command())
+
+	// Restore
+	adminDatabaseRestoreCmd := cmdAdminDatabaseRestore{global: c.global, database: c}
+	cmd.AddCommand(adminDatabaseRestoreCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// connect returns a client for the local daemon.
+func (c *cmdAdminDatabase) connect() (incus.InstanceServer, error) {
+	return incus.ConnectIncusUnix("", nil)
+}
+
+// Backup.
+type cmdAdminDatabaseBackup struct {
+	global   *cmdGlobal
+	database *cmdAdminDatabase
+}
+
+func (c *cmdAdminDatabaseBackup) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("backup", i18n.G("[<name>]"))
+	cmd.Short = i18n.G("Back up the global database")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Back up the global database
+
+  If no name is given, one is generated from the current time.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAdminDatabaseBackup) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	d, err := c.database.connect()
+	if err != nil {
+		return err
+	}
+
+	req := api.DatabaseBackupsPost{}
+	if len(args) > 0 {
+		req.Name = args[0]
+	}
+
+	op, err := d.CreateDatabaseBackup(req)
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	// Get name of backup
+	uStr := op.Get().Resources["backups"][0]
+	u, err := url.Parse(uStr)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid URL %q: %w"), uStr, err)
+	}
+
+	backupName, err := url.PathUnescape(path.Base(u.EscapedPath()))
+	if err != nil {
+		return fmt.Errorf(i18n.G("Invalid backup name segment in path %q: %w"), u.EscapedPath(), err)
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Database backup %s created")+"\n", backupName)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdAdminDatabaseDelete struct {
+	global   *cmdGlobal
+	database *cmdAdminDatabase
+}
+
+func (c *cmdAdminDatabaseDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("<name>"))
+	cmd.Aliases = []string{"rm", "remove"}
+	cmd.Short = i18n.G("Delete a global database backup")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete a global database backup`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAdminDatabaseDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	d, err := c.database.connect()
+	if err != nil {
+		return err
+	}
+
+	return d.DeleteDatabaseBackup(args[0])
+}
+
+// Export.
+type cmdAdminDatabaseExport struct {
+	global   *cmdGlobal
+	database *cmdAdminDatabase
+}
+
+func (c *cmdAdminDatabaseExport) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("export", i18n.G("<name> [<path>]"))
+	cmd.Short = i18n.G("Export a global database backup")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Export a global database backup
+
+  The backup is written to <name>.sql unless a path is given.
+  If <path> is "-", the backup is written to standard output.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAdminDatabaseExport) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	d, err := c.database.connect()
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	target := name + ".sql"
+	if len(args) > 1 {
+		target = args[1]
+	}
+
+	file := os.Stdout
+	if target != "-" {
+		file, err = os.Create(target)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Failed to create backup file: %w"), err)
+		}
+
+		defer func() { _ = file.Close() }()
+	}
+
+	_, err = d.GetDatabaseBackupFile(name, &incus.BackupFileRequest{BackupFile: file})
+	if err != nil {
+		if target != "-" {
+			_ = os.Remove(target)
+		}
+
+		return fmt.Errorf(i18n.G("Failed to fetch database backup: %w"), err)
+	}
+
+	if target != "-" {
+		return file.Close()
+	}
+
+	return nil
+}
+
+// List.
+type cmdAdminDatabaseList struct {
+	global   *cmdGlobal
+	database *cmdAdminDatabase
+
+	flagFormat string
+}
+
+func (c *cmdAdminDatabaseList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list")
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List the global database backups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List the global database backups`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAdminDatabaseList) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 0)
+	if exit {
+		return err
+	}
+
+	d, err := c.database.connect()
+	if err != nil {
+		return err
+	}
+
+	backups, err := d.GetDatabaseBackups()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, backup := range backups {
+		data = append(data, []string{
+			backup.Name,
+			backup.CreatedAt.Local().Format(dateLayout),
+			units.GetByteSizeStringIEC(backup.Size, 2),
+			fmt.Sprintf("%d", backup.SchemaVersion),
+		})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("TAKEN AT"),
+		i18n.G("SIZE"),
+		i18n.G("SCHEMA"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, backups)
+}
+
+// Restore.
+type cmdAdminDatabaseRestore struct {
+	global   *cmdGlobal
+	database *cmdAdminDatabase
+
+	flagForce bool
+}
+
+func (c *cmdAdminDatabaseRestore) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("restore", i18n.G("<path>"))
+	cmd.Short = i18n.G("Restore the global database from a backup")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Restore the global database from a backup
+
+  The daemon must be stopped. The content of the backup replaces the one of the
+  global database when the daemon next starts.
+
+  This is a shortcut for "incus admin cluster restore-database".`))
+
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, i18n.G("Don't require user confirmation"))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAdminDatabaseRestore) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	clusterArgs := []string{"restore-database", args[0]}
+	if c.flagForce {
+		clusterArgs = append(clusterArgs, "--quiet")
+	}
+
+	adminCluster := cmdAdminCluster{global: c.global}
+	adminCluster.Run(cmd, clusterArgs)
+
+	return nil
+}
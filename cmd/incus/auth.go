@@ -0,0 +1,1340 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdAuth struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuth) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("auth")
+	cmd.Short = i18n.G("Manage the built-in role-based access control")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage the built-in role-based access control
+
+Roles are sets of entitlements on entity types. Groups grant roles to
+TLS and OpenID Connect identities, either on all projects or on a single one.
+
+The access control is enabled through the authorization.rbac server configuration key.
+
+API tokens are bearer tokens for automation, limited to their own projects and permissions.`))
+
+	// Group
+	authGroupCmd := cmdAuthGroup{global: c.global}
+	cmd.AddCommand(authGroupCmd.Command())
+
+	// Role
+	authRoleCmd := cmdAuthRole{global: c.global}
+	cmd.AddCommand(authRoleCmd.Command())
+
+	// Token
+	authTokenCmd := cmdAuthToken{global: c.global}
+	cmd.AddCommand(authTokenCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+type cmdAuthGroup struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthGroup) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("group")
+	cmd.Short = i18n.G("Manage authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage authorization groups`))
+
+	// Create
+	authGroupCreateCmd := cmdAuthGroupCreate{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupCreateCmd.Command())
+
+	// Delete
+	authGroupDeleteCmd := cmdAuthGroupDelete{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupDeleteCmd.Command())
+
+	// Edit
+	authGroupEditCmd := cmdAuthGroupEdit{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupEditCmd.Command())
+
+	// List
+	authGroupListCmd := cmdAuthGroupList{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupListCmd.Command())
+
+	// Rename
+	authGroupRenameCmd := cmdAuthGroupRename{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupRenameCmd.Command())
+
+	// Show
+	authGroupShowCmd := cmdAuthGroupShow{global: c.global, authGroup: c}
+	cmd.AddCommand(authGroupShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdAuthGroupCreate struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+
+	flagDescription string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthGroupCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Create authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create authorization groups`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth group create operators < operators.yaml
+    Create the group operators using the identities and roles in operators.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Group description")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthGroupCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.AuthGroupPut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	if c.flagDescription != "" {
+		stdinData.Description = c.flagDescription
+	}
+
+	// Create the group
+	err = resource.server.CreateAuthGroup(api.AuthGroupsPost{Name: resource.name, AuthGroupPut: stdinData})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Group %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdAuthGroupDelete struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthGroupDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<group>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete authorization groups`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthGroupDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Delete the group
+	err = resource.server.DeleteAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Group %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdAuthGroupEdit struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthGroupEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Edit authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit authorization groups`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus auth group edit <group> < group.yaml
+    Update an authorization group using the content of group.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthGroupEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the authorization group.
+### Any line starting with a '# will be ignored.
+###
+### identities:
+### - authentication_method: oidc
+###   identifier: jane@example.com
+### identity_provider_groups:
+### - incus-operators
+### roles:
+### - role: operator
+###   project: default`)
+}
+
+// Run actually performs the action.
+func (c *cmdAuthGroupEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.AuthGroupPut{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateAuthGroup(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	group, etag, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&group.AuthGroupPut)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.AuthGroupPut{}
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateAuthGroup(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdAuthGroupList struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthGroupList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List authorization groups`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthGroupList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the groups
+	groups, err := resource.server.GetAuthGroups()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, group := range groups {
+		data = append(data, []string{group.Name, group.Description, strconv.Itoa(len(group.Identities)), strconv.Itoa(len(group.Roles))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("IDENTITIES"),
+		i18n.G("ROLES"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, groups)
+}
+
+// Rename.
+type cmdAuthGroupRename struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthGroupRename) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<group> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rename authorization groups`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthGroupRename) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Rename the group
+	err = resource.server.RenameAuthGroup(resource.name, api.AuthGroupPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Group %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Show.
+type cmdAuthGroupShow struct {
+	global    *cmdGlobal
+	authGroup *cmdAuthGroup
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthGroupShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<group>"))
+	cmd.Short = i18n.G("Show authorization groups")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show authorization groups`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthGroupShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing group name"))
+	}
+
+	// Show the group
+	group, _, err := resource.server.GetAuthGroup(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&group)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+type cmdAuthRole struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthRole) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("role")
+	cmd.Short = i18n.G("Manage authorization roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage authorization roles`))
+
+	// Create
+	authRoleCreateCmd := cmdAuthRoleCreate{global: c.global, authRole: c}
+	cmd.AddCommand(authRoleCreateCmd.Command())
+
+	// Delete
+	authRoleDeleteCmd := cmdAuthRoleDelete{global: c.global, authRole: c}
+	cmd.AddCommand(authRoleDeleteCmd.Command())
+
+	// Edit
+	authRoleEditCmd := cmdAuthRoleEdit{global: c.global, authRole: c}
+	cmd.AddCommand(authRoleEditCmd.Command())
+
+	// List
+	authRoleListCmd := cmdAuthRoleList{global: c.global, authRole: c}
+	cmd.AddCommand(authRoleListCmd.Command())
+
+	// Rename
+	authRoleRenameCmd := cmdAuthRoleRename{global: c.global, authRole: c}
+	cmd.AddCommand(authRoleRenameCmd.Command())
+
+	// Show
+	authRoleShowCmd := cmdAuthRoleShow{global: c.global, authRole: c}
+	cmd.AddCommand(authRoleShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdAuthRoleCreate struct {
+	global   *cmdGlobal
+	authRole *cmdAuthRole
+
+	flagDescription string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthRoleCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<role>"))
+	cmd.Short = i18n.G("Create authorization roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create authorization roles`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth role create operator < operator.yaml
+    Create the role operator using the permissions in operator.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Role description")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthRoleCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.AuthRolePut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing role name"))
+	}
+
+	if c.flagDescription != "" {
+		stdinData.Description = c.flagDescription
+	}
+
+	// Create the role
+	err = resource.server.CreateAuthRole(api.AuthRolesPost{Name: resource.name, AuthRolePut: stdinData})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Role %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdAuthRoleDelete struct {
+	global   *cmdGlobal
+	authRole *cmdAuthRole
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthRoleDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<role>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete authorization roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete authorization roles`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthRoleDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing role name"))
+	}
+
+	// Delete the role
+	err = resource.server.DeleteAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Role %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdAuthRoleEdit struct {
+	global   *cmdGlobal
+	authRole *cmdAuthRole
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthRoleEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<role>"))
+	cmd.Short = i18n.G("Edit authorization roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit authorization roles`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus auth role edit <role> < role.yaml
+    Update an authorization role using the content of role.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdAuthRoleEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the authorization role.
+### Any line starting with a '# will be ignored.
+###
+### permissions:
+### - entity_type: instance
+###   entitlement: can_exec`)
+}
+
+// Run actually performs the action.
+func (c *cmdAuthRoleEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing role name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.AuthRolePut{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateAuthRole(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	role, etag, err := resource.server.GetAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&role.AuthRolePut)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.AuthRolePut{}
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateAuthRole(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdAuthRoleList struct {
+	global   *cmdGlobal
+	authRole *cmdAuthRole
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthRoleList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List authorization roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List authorization roles`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthRoleList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the roles
+	roles, err := resource.server.GetAuthRoles()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, role := range roles {
+		data = append(data, []string{role.Name, role.Description, strconv.Itoa(len(role.Permissions)), strconv.Itoa(len(role.UsedBy))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("PERMISSIONS"),
+		i18n.G("USED BY"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, roles)
+}
+
+// Rename.
+type cmdAuthRoleRename struct {
+	global   *cmdGlobal
+	authRole *cmdAuthRole
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthRoleRename) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", i18n.G("[<remote>:]<role> <new-name>"))
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = i18n.G("Rename authorization roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Rename authorization roles`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthRoleRename) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing role name"))
+	}
+
+	// Rename the role
+	err = resource.server.RenameAuthRole(resource.name, api.AuthRolePost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Role %s renamed to %s")+"\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Show.
+type cmdAuthRoleShow struct {
+	global   *cmdGlobal
+	authRole *cmdAuthRole
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthRoleShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<role>"))
+	cmd.Short = i18n.G("Show authorization roles")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show authorization roles`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthRoleShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing role name"))
+	}
+
+	// Show the role
+	role, _, err := resource.server.GetAuthRole(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+type cmdAuthToken struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthToken) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("token")
+	cmd.Short = i18n.G("Manage API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage API tokens`))
+
+	// Create
+	authTokenCreateCmd := cmdAuthTokenCreate{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenCreateCmd.Command())
+
+	// Delete
+	authTokenDeleteCmd := cmdAuthTokenDelete{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenDeleteCmd.Command())
+
+	// List
+	authTokenListCmd := cmdAuthTokenList{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenListCmd.Command())
+
+	// Show
+	authTokenShowCmd := cmdAuthTokenShow{global: c.global, authToken: c}
+	cmd.AddCommand(authTokenShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdAuthTokenCreate struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+
+	flagDescription string
+	flagExpiry      string
+	flagIdentity    string
+	flagProjects    []string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthTokenCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<token>"))
+	cmd.Short = i18n.G("Create API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create API tokens
+
+The secret is only displayed once and can't be retrieved afterwards.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus auth token create ci-deploy --identity ci --expiry 90d --project staging
+    Create the token ci-deploy for the identity ci, valid for 90 days and restricted to the project staging
+
+incus auth token create ci-deploy --identity ci < permissions.yaml
+    Create the token ci-deploy limited to the permissions in permissions.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Token description")+"``")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "30d", i18n.G("Token lifetime (e.g. 1d 12H)")+"``")
+	cmd.Flags().StringVar(&c.flagIdentity, "identity", "", i18n.G("Identity the requests are attributed to")+"``")
+	cmd.Flags().StringArrayVar(&c.flagProjects, "project", nil, i18n.G("Project the token is restricted to (can be repeated)")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthTokenCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.AuthTokensPost
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing token name"))
+	}
+
+	stdinData.Name = resource.name
+
+	if c.flagDescription != "" {
+		stdinData.Description = c.flagDescription
+	}
+
+	if c.flagIdentity != "" {
+		stdinData.Identity = c.flagIdentity
+	}
+
+	if stdinData.Identity == "" {
+		return fmt.Errorf(i18n.G("Missing token identity"))
+	}
+
+	if len(c.flagProjects) > 0 {
+		stdinData.Restricted = true
+		stdinData.Projects = c.flagProjects
+	}
+
+	if stdinData.ExpiresAt.IsZero() {
+		stdinData.ExpiresAt, err = instance.GetExpiry(time.Now(), c.flagExpiry)
+		if err != nil {
+			return fmt.Errorf(i18n.G("Invalid token expiry: %w"), err)
+		}
+	}
+
+	// Create the token
+	secret, err := resource.server.CreateAuthToken(stdinData)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Token %s created, its secret is:")+"\n", resource.name)
+	}
+
+	fmt.Println(secret.Secret)
+
+	return nil
+}
+
+// Delete.
+type cmdAuthTokenDelete struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthTokenDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<token>"))
+	cmd.Aliases = []string{"rm", "revoke"}
+	cmd.Short = i18n.G("Delete API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete API tokens
+
+Requests using the token are rejected as soon as it's deleted.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthTokenDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing token name"))
+	}
+
+	// Delete the token
+	err = resource.server.DeleteAuthToken(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Token %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// List.
+type cmdAuthTokenList struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthTokenList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List API tokens`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthTokenList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the tokens
+	tokens, err := resource.server.GetAuthTokens()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, token := range tokens {
+		projects := "*"
+		if token.Restricted {
+			projects = strings.Join(token.Projects, ",")
+		}
+
+		data = append(data, []string{token.Name, token.Description, token.Identity, projects, token.ExpiresAt.Local().Format(dateLayout)})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("IDENTITY"),
+		i18n.G("PROJECTS"),
+		i18n.G("EXPIRES AT"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, tokens)
+}
+
+// Show.
+type cmdAuthTokenShow struct {
+	global    *cmdGlobal
+	authToken *cmdAuthToken
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdAuthTokenShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<token>"))
+	cmd.Short = i18n.G("Show API tokens")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show API tokens`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdAuthTokenShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing token name"))
+	}
+
+	// Show the token
+	token, err := resource.server.GetAuthToken(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&token)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -99,6 +99,22 @@ func (c *cmdCluster) Command() *cobra.Command {
 	cmdClusterRestore := cmdClusterRestore{global: c.global, cluster: c}
 	cmd.AddCommand(cmdClusterRestore.Command())
 
+	// Cordon cluster member
+	cmdClusterCordon := cmdClusterCordon{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterCordon.Command())
+
+	// Uncordon cluster member
+	cmdClusterUncordon := cmdClusterUncordon{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterUncordon.Command())
+
+	// Rolling maintenance
+	cmdClusterMaintenance := cmdClusterMaintenance{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterMaintenance.Command())
+
+	// Re-balance cluster
+	cmdClusterRebalance := cmdClusterRebalance{global: c.global, cluster: c}
+	cmd.AddCommand(cmdClusterRebalance.Command())
+
 	clusterGroupCmd := cmdClusterGroup{global: c.global, cluster: c}
 	cmd.AddCommand(clusterGroupCmd.Command())
 
@@ -989,13 +1005,20 @@ func (c *cmdClusterEdit) Run(cmd *cobra.Command, args []string) error {
 type cmdClusterAdd struct {
 	global  *cmdGlobal
 	cluster *cmdCluster
+
+	flagReplace bool
 }
 
 func (c *cmdClusterAdd) Command() *cobra.Command {
 	cmd := &cobra.Command{}
 	cmd.Use = usage("add", i18n.G("[[<remote>:]<member>]"))
 	cmd.Short = i18n.G("Request a join token for adding a cluster member")
-	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Request a join token for adding a cluster member`))
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Request a join token for adding a cluster member
+
+  With --replace, the token lets a new server take over an existing offline member,
+  keeping its name, configuration and instances.`))
+
+	cmd.Flags().BoolVar(&c.flagReplace, "replace", false, i18n.G("Replace the existing (offline) member of that name"))
 
 	cmd.RunE = c.Run
 
@@ -1030,9 +1053,14 @@ func (c *cmdClusterAdd) Run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(i18n.G("A cluster member name must be provided"))
 	}
 
+	if c.flagReplace && !resource.server.HasExtension("clustering_replace") {
+		return fmt.Errorf(i18n.G("The server doesn't support replacing cluster members"))
+	}
+
 	// Request the join token.
 	member := api.ClusterMembersPost{
 		ServerName: resource.name,
+		Replace:    c.flagReplace,
 	}
 
 	op, err := resource.server.CreateClusterMember(member)
@@ -1568,3 +1596,368 @@ func (c *cmdClusterEvacuateAction) Run(cmd *cobra.Command, args []string) error
 	progress.Done("")
 	return nil
 }
+
+// Cluster member cordon.
+type cmdClusterCordon struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+}
+
+func (c *cmdClusterCordon) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("cordon", i18n.G("[<remote>:]<member>"))
+	cmd.Short = i18n.G("Cordon cluster member")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Cordon cluster member
+
+A cordoned member keeps running its instances but isn't used for new instance
+placement, re-balancing or image replication until it's uncordoned.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpClusterMembers(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterCordon) Run(cmd *cobra.Command, args []string) error {
+	return c.cluster.updateMemberState(cmd, args, "cordon", i18n.G("Cordoning cluster member: %s"))
+}
+
+// Cluster member uncordon.
+type cmdClusterUncordon struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+}
+
+func (c *cmdClusterUncordon) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("uncordon", i18n.G("[<remote>:]<member>"))
+	cmd.Short = i18n.G("Uncordon cluster member")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Uncordon cluster member`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpClusterMembers(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterUncordon) Run(cmd *cobra.Command, args []string) error {
+	return c.cluster.updateMemberState(cmd, args, "uncordon", i18n.G("Uncordoning cluster member: %s"))
+}
+
+// updateMemberState applies a state action to a cluster member and waits for it to complete.
+func (c *cmdCluster) updateMemberState(cmd *cobra.Command, args []string, action string, format string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed to parse servers: %w"), err)
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing cluster member name"))
+	}
+
+	if !resource.server.HasExtension("clustering_cordon") {
+		return fmt.Errorf(i18n.G("The server doesn't support cordoning cluster members"))
+	}
+
+	op, err := resource.server.UpdateClusterMemberState(resource.name, api.ClusterMemberStatePost{Action: action})
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed to update cluster member state: %w"), err)
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: format,
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+	return nil
+}
+
+// Re-balance.
+type cmdClusterRebalance struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagDryRun bool
+	flagFormat string
+}
+
+func (c *cmdClusterRebalance) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rebalance", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Re-balance instances across cluster members")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Re-balance instances across cluster members
+
+With --dry-run, the instance migrations are only listed.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus cluster rebalance --dry-run
+    List the instances the next re-balancing run would move.`))
+
+	cmd.Flags().BoolVar(&c.flagDryRun, "dry-run", false, i18n.G("Only show the planned instance migrations"))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, _ []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterRebalance) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if c.flagDryRun {
+		plan, err := resource.server.GetClusterRebalancePlan()
+		if err != nil {
+			return err
+		}
+
+		data := [][]string{}
+		for _, move := range plan.Moves {
+			live := i18n.G("NO")
+			if move.Live {
+				live = i18n.G("YES")
+			}
+
+			data = append(data, []string{move.Instance, move.Project, move.Source, move.Target, live})
+		}
+
+		header := []string{
+			i18n.G("INSTANCE"),
+			i18n.G("PROJECT"),
+			i18n.G("SOURCE"),
+			i18n.G("TARGET"),
+			i18n.G("LIVE"),
+		}
+
+		return cli.RenderTable(os.Stdout, c.flagFormat, header, data, plan)
+	}
+
+	op, err := resource.server.RebalanceCluster()
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Re-balancing cluster: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+	return nil
+}
+
+// Rolling maintenance.
+type cmdClusterMaintenance struct {
+	global  *cmdGlobal
+	cluster *cmdCluster
+
+	flagMembers  []string
+	flagGroup    string
+	flagParallel int
+	flagAction   string
+	flagHook     string
+	flagWait     string
+	flagTimeout  int
+	flagForce    bool
+}
+
+func (c *cmdClusterMaintenance) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("maintenance", i18n.G("[<remote>:]"))
+	cmd.Short = i18n.G("Perform a rolling maintenance of cluster members")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(`Perform a rolling maintenance of cluster members
+
+Each member is evacuated, the hook is called and the member is optionally waited for
+to restart or come back with a new version, then it is restored and checked before
+moving on to the next one. The maintenance stops on the first failure, leaving the
+failed member evacuated. Running the command again with the remaining members resumes it.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus cluster maintenance --hook https://automation.example.net/patch --wait restart
+    Patch and reboot all cluster members, one at a time.
+
+incus cluster maintenance --group rack1 --parallel 2 --wait version
+    Maintain the members of the rack1 cluster group two at a time, waiting for a new version.`))
+
+	cmd.Flags().StringSliceVar(&c.flagMembers, "members", nil, i18n.G("Cluster members to maintain (defaults to all)")+"``")
+	cmd.Flags().StringVar(&c.flagGroup, "group", "", i18n.G("Only maintain the members of this cluster group")+"``")
+	cmd.Flags().IntVar(&c.flagParallel, "parallel", 1, i18n.G("Number of members of each cluster group maintained at the same time")+"``")
+	cmd.Flags().StringVar(&c.flagAction, "action", "", i18n.G(`Force a particular evacuation action`)+"``")
+	cmd.Flags().StringVar(&c.flagHook, "hook", "", i18n.G("URL to call once a member is evacuated")+"``")
+	cmd.Flags().StringVar(&c.flagWait, "wait", "", i18n.G("Wait for the member to restart or come back with a new version (restart or version)")+"``")
+	cmd.Flags().IntVar(&c.flagTimeout, "timeout", 0, i18n.G("How long to wait for each member (in seconds)")+"``")
+	cmd.Flags().BoolVar(&c.flagForce, "force", false, i18n.G(`Perform the maintenance without user confirmation`)+"``")
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdClusterMaintenance) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) == 1 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if !c.flagForce {
+		maintain, err := c.global.asker.AskBool(i18n.G("Are you sure you want to evacuate and restore the cluster members one after the other? (yes/no) [default=no]: "), "no")
+		if err != nil {
+			return err
+		}
+
+		if !maintain {
+			return nil
+		}
+	}
+
+	req := api.ClusterMaintenancePost{
+		Members:  c.flagMembers,
+		Group:    c.flagGroup,
+		Parallel: c.flagParallel,
+		Mode:     c.flagAction,
+		Hook:     c.flagHook,
+		Wait:     c.flagWait,
+		Timeout:  c.flagTimeout,
+	}
+
+	op, err := resource.server.CreateClusterMaintenance(req)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Quiet: c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(func(op api.Operation) {
+		if op.Metadata == nil {
+			return
+		}
+
+		members, ok := op.Metadata["maintenance_progress"].(map[string]any)
+		if !ok {
+			return
+		}
+
+		names := make([]string, 0, len(members))
+		for name := range members {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		status := make([]string, 0, len(names))
+		for _, name := range names {
+			status = append(status, fmt.Sprintf("%s: %v", name, members[name]))
+		}
+
+		progress.Update(strings.Join(status, ", "))
+	})
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done(i18n.G("Cluster maintenance completed"))
+	return nil
+}
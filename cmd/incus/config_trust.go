@@ -12,12 +12,14 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 
 	cli "github.com/lxc/incus/v6/internal/cmd"
 	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/termios"
 	localtls "github.com/lxc/incus/v6/shared/tls"
@@ -82,6 +84,7 @@ type cmdConfigTrustAdd struct {
 
 	flagProjects   string
 	flagRestricted bool
+	flagExpiry     string
 }
 
 func (c *cmdConfigTrustAdd) Command() *cobra.Command {
@@ -96,6 +99,7 @@ This will issue a trust token to be used by the client to add itself to the trus
 
 	cmd.Flags().BoolVar(&c.flagRestricted, "restricted", false, i18n.G("Restrict the certificate to one or more projects"))
 	cmd.Flags().StringVar(&c.flagProjects, "projects", "", i18n.G("List of projects to restrict the certificate to")+"``")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "", i18n.G("How long the client is trusted for (e.g. 90d)")+"``")
 
 	cmd.RunE = c.Run
 
@@ -131,6 +135,11 @@ func (c *cmdConfigTrustAdd) Run(cmd *cobra.Command, args []string) error {
 		cert.Projects = strings.Split(c.flagProjects, ",")
 	}
 
+	cert.ExpiresAt, err = trustExpiry(c.flagExpiry)
+	if err != nil {
+		return err
+	}
+
 	// Create the token.
 	op, err := resource.server.CreateCertificateToken(cert)
 	if err != nil {
@@ -163,6 +172,7 @@ type cmdConfigTrustAddCertificate struct {
 	flagName        string
 	flagType        string
 	flagDescription string
+	flagExpiry      string
 }
 
 func (c *cmdConfigTrustAddCertificate) Command() *cobra.Command {
@@ -182,6 +192,7 @@ The following certificate types are supported:
 	cmd.Flags().StringVar(&c.flagName, "name", "", i18n.G("Alternative certificate name")+"``")
 	cmd.Flags().StringVar(&c.flagType, "type", "client", i18n.G("Type of certificate")+"``")
 	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Certificate description")+"``")
+	cmd.Flags().StringVar(&c.flagExpiry, "expiry", "", i18n.G("How long the certificate is trusted for (e.g. 90d)")+"``")
 
 	cmd.RunE = c.Run
 
@@ -261,9 +272,28 @@ func (c *cmdConfigTrustAddCertificate) Run(cmd *cobra.Command, args []string) er
 		cert.Projects = strings.Split(c.flagProjects, ",")
 	}
 
+	cert.ExpiresAt, err = trustExpiry(c.flagExpiry)
+	if err != nil {
+		return err
+	}
+
 	return resource.server.CreateCertificate(cert)
 }
 
+// trustExpiry returns the expiry date of a new trust relationship from its lifetime, if any.
+func trustExpiry(lifetime string) (*time.Time, error) {
+	if lifetime == "" {
+		return nil, nil
+	}
+
+	expiry, err := instance.GetExpiry(time.Now(), lifetime)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Invalid expiry: %w"), err)
+	}
+
+	return &expiry, nil
+}
+
 // Edit.
 type cmdConfigTrustEdit struct {
 	global      *cmdGlobal
@@ -492,6 +522,11 @@ func (c *cmdConfigTrustList) issueDateColumnData(rowData rowData) string {
 }
 
 func (c *cmdConfigTrustList) expiryDateColumnData(rowData rowData) string {
+	// The trust can expire before the certificate itself.
+	if rowData.Cert.ExpiresAt != nil && rowData.Cert.ExpiresAt.Before(rowData.TlsCert.NotAfter) {
+		return rowData.Cert.ExpiresAt.Local().Format(dateLayout)
+	}
+
 	return rowData.TlsCert.NotAfter.Local().Format(dateLayout)
 }
 
@@ -684,6 +684,20 @@ func (c *cmdInfo) instanceInfo(d incus.InstanceServer, remote config.Remote, nam
 			fmt.Print(osInfo)
 		}
 
+		// Health check info
+		if inst.State.Health != nil {
+			fmt.Println("\n" + i18n.G("Health:"))
+			fmt.Printf("  "+i18n.G("Status: %s")+"\n", inst.State.Health.Status)
+
+			if !inst.State.Health.LastCheck.IsZero() {
+				fmt.Printf("  "+i18n.G("Last check: %s")+"\n", inst.State.Health.LastCheck.Local().Format(dateLayout))
+			}
+
+			if inst.State.Health.LastError != "" {
+				fmt.Printf("  "+i18n.G("Last error: %s")+"\n", inst.State.Health.LastError)
+			}
+		}
+
 		fmt.Println("\n" + i18n.G("Resources:"))
 		// Processes
 		fmt.Printf("  "+i18n.G("Processes: %d")+"\n", inst.State.Processes)
@@ -0,0 +1,557 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdInstanceSet struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdInstanceSet) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("instance-set")
+	cmd.Short = i18n.G("Manage instance sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage instance sets
+
+Instance sets are groups of identical instances named <set>-<n>, with the
+number of instances set through the replicas property.`))
+
+	// Create
+	instanceSetCreateCmd := cmdInstanceSetCreate{global: c.global, instanceSet: c}
+	cmd.AddCommand(instanceSetCreateCmd.Command())
+
+	// Delete
+	instanceSetDeleteCmd := cmdInstanceSetDelete{global: c.global, instanceSet: c}
+	cmd.AddCommand(instanceSetDeleteCmd.Command())
+
+	// Edit
+	instanceSetEditCmd := cmdInstanceSetEdit{global: c.global, instanceSet: c}
+	cmd.AddCommand(instanceSetEditCmd.Command())
+
+	// List
+	instanceSetListCmd := cmdInstanceSetList{global: c.global, instanceSet: c}
+	cmd.AddCommand(instanceSetListCmd.Command())
+
+	// Scale
+	instanceSetScaleCmd := cmdInstanceSetScale{global: c.global, instanceSet: c}
+	cmd.AddCommand(instanceSetScaleCmd.Command())
+
+	// Show
+	instanceSetShowCmd := cmdInstanceSetShow{global: c.global, instanceSet: c}
+	cmd.AddCommand(instanceSetShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// wait waits for an instance set operation to complete while showing its progress.
+func (c *cmdInstanceSet) wait(op incus.Operation) error {
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Updating instances: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err := op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	return nil
+}
+
+// Create.
+type cmdInstanceSetCreate struct {
+	global      *cmdGlobal
+	instanceSet *cmdInstanceSet
+
+	flagReplicas int
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdInstanceSetCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<instance set>"))
+	cmd.Short = i18n.G("Create instance sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create instance sets`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus instance-set create web < web.yaml
+    Create the instance set web using the definition in web.yaml
+
+incus instance-set create web --replicas=3 < web.yaml
+    Create the instance set web with 3 instances`))
+
+	cmd.Flags().IntVarP(&c.flagReplicas, "replicas", "r", -1, i18n.G("Number of instances in the set")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdInstanceSetCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.InstanceSetPut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance set name"))
+	}
+
+	if c.flagReplicas >= 0 {
+		stdinData.Replicas = c.flagReplicas
+	}
+
+	// Create the instance set
+	op, err := resource.server.CreateInstanceSet(api.InstanceSetsPost{Name: resource.name, InstanceSetPut: stdinData})
+	if err != nil {
+		return err
+	}
+
+	err = c.instanceSet.wait(op)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Instance set %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdInstanceSetDelete struct {
+	global      *cmdGlobal
+	instanceSet *cmdInstanceSet
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdInstanceSetDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<instance set>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete instance sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete instance sets
+
+All the instances of the set are stopped and deleted.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdInstanceSetDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance set name"))
+	}
+
+	// Delete the instance set
+	op, err := resource.server.DeleteInstanceSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	err = c.instanceSet.wait(op)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Instance set %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdInstanceSetEdit struct {
+	global      *cmdGlobal
+	instanceSet *cmdInstanceSet
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdInstanceSetEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<instance set>"))
+	cmd.Short = i18n.G("Edit instance set definitions as YAML")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit instance set definitions as YAML`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus instance-set edit <instance set> < instance-set.yaml
+    Update an instance set using the content of instance-set.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdInstanceSetEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the instance set.
+### Any line starting with a '# will be ignored.
+###
+### Changes to the source only apply to newly created instances.
+### Note that the name and list of instances are shown but cannot be changed`)
+}
+
+// Run actually performs the action.
+func (c *cmdInstanceSetEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance set name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.InstanceSetPut{}
+		err = yaml.Unmarshal(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		op, err := resource.server.UpdateInstanceSet(resource.name, newdata, "")
+		if err != nil {
+			return err
+		}
+
+		return c.instanceSet.wait(op)
+	}
+
+	// Extract the current value
+	instanceSet, etag, err := resource.server.GetInstanceSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&instanceSet)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.InstanceSetPut{}
+		err = yaml.Unmarshal(content, &newdata)
+		if err == nil {
+			var op incus.Operation
+
+			op, err = resource.server.UpdateInstanceSet(resource.name, newdata, etag)
+			if err == nil {
+				err = c.instanceSet.wait(op)
+			}
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdInstanceSetList struct {
+	global      *cmdGlobal
+	instanceSet *cmdInstanceSet
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdInstanceSetList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List instance sets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List instance sets`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdInstanceSetList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List instance sets
+	instanceSets, err := resource.server.GetInstanceSets()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, instanceSet := range instanceSets {
+		loadBalancer := ""
+		if instanceSet.LoadBalancer != nil {
+			loadBalancer = instanceSet.LoadBalancer.Network + "/" + instanceSet.LoadBalancer.ListenAddress
+		}
+
+		data = append(data, []string{instanceSet.Name, instanceSet.Description, strconv.Itoa(instanceSet.Replicas), instanceSet.ClusterGroup, loadBalancer})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("REPLICAS"),
+		i18n.G("CLUSTER GROUP"),
+		i18n.G("LOAD BALANCER"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, instanceSets)
+}
+
+// Scale.
+type cmdInstanceSetScale struct {
+	global      *cmdGlobal
+	instanceSet *cmdInstanceSet
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdInstanceSetScale) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("scale", i18n.G("[<remote>:]<instance set> <replicas>"))
+	cmd.Short = i18n.G("Change the number of instances in a set")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Change the number of instances in a set
+
+Instances are added or removed at the end of the set.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus instance-set scale web 5
+    Grow or shrink the instance set web to 5 instances`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdInstanceSetScale) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance set name"))
+	}
+
+	replicas, err := strconv.Atoi(args[1])
+	if err != nil || replicas < 0 {
+		return fmt.Errorf(i18n.G("Invalid number of replicas %q"), args[1])
+	}
+
+	instanceSet, etag, err := resource.server.GetInstanceSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	put := instanceSet.Writable()
+	put.Replicas = replicas
+
+	op, err := resource.server.UpdateInstanceSet(resource.name, put, etag)
+	if err != nil {
+		return err
+	}
+
+	return c.instanceSet.wait(op)
+}
+
+// Show.
+type cmdInstanceSetShow struct {
+	global      *cmdGlobal
+	instanceSet *cmdInstanceSet
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdInstanceSetShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<instance set>"))
+	cmd.Short = i18n.G("Show instance set definitions")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show instance set definitions`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdInstanceSetShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance set name"))
+	}
+
+	// Show the instance set
+	instanceSet, _, err := resource.server.GetInstanceSet(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&instanceSet)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -137,6 +137,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	adminCmd := cmdAdmin{global: &globalCmd}
 	app.AddCommand(adminCmd.Command())
 
+	// auth sub-command
+	authCmd := cmdAuth{global: &globalCmd}
+	app.AddCommand(authCmd.Command())
+
 	// cluster sub-command
 	clusterCmd := cmdCluster{global: &globalCmd}
 	app.AddCommand(clusterCmd.Command())
@@ -185,6 +189,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	imageCmd := cmdImage{global: &globalCmd}
 	app.AddCommand(imageCmd.Command())
 
+	// instance-set sub-command
+	instanceSetCmd := cmdInstanceSet{global: &globalCmd}
+	app.AddCommand(instanceSetCmd.Command())
+
 	// launch sub-command
 	launchCmd := cmdLaunch{global: &globalCmd, init: &createCmd}
 	app.AddCommand(launchCmd.Command())
@@ -237,6 +245,10 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	rebuildCmd := cmdRebuild{global: &globalCmd}
 	app.AddCommand(rebuildCmd.Command())
 
+	// replication sub-command
+	replicationCmd := cmdReplication{global: &globalCmd}
+	app.AddCommand(replicationCmd.Command())
+
 	// rename sub-command
 	renameCmd := cmdRename{global: &globalCmd}
 	app.AddCommand(renameCmd.Command())
@@ -253,10 +265,18 @@ Custom commands can be defined through aliases, use "incus alias" to control tho
 	resumeCmd := cmdResume{global: &globalCmd}
 	app.AddCommand(resumeCmd.Command())
 
+	// secret sub-command
+	secretCmd := cmdSecret{global: &globalCmd}
+	app.AddCommand(secretCmd.Command())
+
 	// snapshot sub-command
 	snapshotCmd := cmdSnapshot{global: &globalCmd}
 	app.AddCommand(snapshotCmd.Command())
 
+	// stack sub-command
+	stackCmd := cmdStack{global: &globalCmd}
+	app.AddCommand(stackCmd.Command())
+
 	// storage sub-command
 	storageCmd := cmdStorage{global: &globalCmd}
 	app.AddCommand(storageCmd.Command())
@@ -8,6 +8,7 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
@@ -76,6 +77,14 @@ func (c *cmdProject) Command() *cobra.Command {
 	projectGetInfo := cmdProjectInfo{global: c.global, project: c}
 	cmd.AddCommand(projectGetInfo.Command())
 
+	// Usage
+	projectUsageCmd := cmdProjectUsage{global: c.global, project: c}
+	cmd.AddCommand(projectUsageCmd.Command())
+
+	// Template
+	projectTemplateCmd := cmdProjectTemplate{global: c.global, project: c}
+	cmd.AddCommand(projectTemplateCmd.Command())
+
 	// Set default
 	projectSwitchCmd := cmdProjectSwitch{global: c.global, project: c}
 	cmd.AddCommand(projectSwitchCmd.Command())
@@ -96,6 +105,7 @@ type cmdProjectCreate struct {
 	project         *cmdProject
 	flagConfig      []string
 	flagDescription string
+	flagTemplate    string
 }
 
 func (c *cmdProjectCreate) Command() *cobra.Command {
@@ -108,10 +118,14 @@ func (c *cmdProjectCreate) Command() *cobra.Command {
     Create a project named p1
 
 incus project create p1 < config.yaml
-    Create a project named p1 with configuration from config.yaml`))
+    Create a project named p1 with configuration from config.yaml
+
+incus project create p1 --template tenant
+    Create a project named p1 from the tenant project template`))
 
 	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, i18n.G("Config key/value to apply to the new project")+"``")
 	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Project description")+"``")
+	cmd.Flags().StringVar(&c.flagTemplate, "template", "", i18n.G("Project template to create the project from")+"``")
 
 	cmd.RunE = c.Run
 
@@ -181,6 +195,8 @@ func (c *cmdProjectCreate) Run(cmd *cobra.Command, args []string) error {
 		project.Description = c.flagDescription
 	}
 
+	project.Template = c.flagTemplate
+
 	err = resource.server.CreateProject(project)
 	if err != nil {
 		return err
@@ -1124,6 +1140,7 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 
 	// Render the output
 	byteLimits := []string{"disk", "memory"}
+	bitLimits := []string{"egress", "ingress"}
 	data := [][]string{}
 	for k, v := range projectState.Resources {
 		shortKey := strings.SplitN(k, ".", 2)[0]
@@ -1132,6 +1149,8 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 		if v.Limit >= 0 {
 			if slices.Contains(byteLimits, shortKey) {
 				limit = units.GetByteSizeStringIEC(v.Limit, 2)
+			} else if slices.Contains(bitLimits, shortKey) {
+				limit = units.GetBitSizeString(v.Limit, 2)
 			} else {
 				limit = fmt.Sprintf("%d", v.Limit)
 			}
@@ -1140,6 +1159,8 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 		usage := ""
 		if slices.Contains(byteLimits, shortKey) {
 			usage = units.GetByteSizeStringIEC(v.Usage, 2)
+		} else if slices.Contains(bitLimits, shortKey) {
+			usage = units.GetBitSizeString(v.Usage, 2)
 		} else {
 			usage = fmt.Sprintf("%d", v.Usage)
 		}
@@ -1164,6 +1185,124 @@ func (c *cmdProjectInfo) Run(cmd *cobra.Command, args []string) error {
 	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, projectState)
 }
 
+// Usage.
+type cmdProjectUsage struct {
+	global  *cmdGlobal
+	project *cmdProject
+
+	flagFrom   string
+	flagTo     string
+	flagFormat string
+}
+
+func (c *cmdProjectUsage) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("usage", i18n.G("[<remote>:]<project>"))
+	cmd.Short = i18n.G("Show the resources consumed by a project")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the resources consumed by a project
+
+The instances are sampled every 5 minutes. The period defaults to the current month.
+Use the json or yaml formats to get the exact values (in seconds, byte-seconds and bytes).`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus project usage p1 --from 2026-09-01 --to 2026-10-01 --format csv
+    Export the resources consumed by the project p1 in September 2026 as CSV`))
+	cmd.Flags().StringVar(&c.flagFrom, "from", "", i18n.G("Start of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().StringVar(&c.flagTo, "to", "", i18n.G("End of the period (YYYY-MM-DD or RFC3339)")+"``")
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpProjects(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// parseDate parses a date given either as YYYY-MM-DD or in RFC3339 format.
+func (c *cmdProjectUsage) parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(time.DateOnly, value)
+	if err == nil {
+		return date, nil
+	}
+
+	date, err = time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf(i18n.G("Invalid date %q: %w"), value, err)
+	}
+
+	return date, nil
+}
+
+func (c *cmdProjectUsage) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	from, err := c.parseDate(c.flagFrom)
+	if err != nil {
+		return err
+	}
+
+	to, err := c.parseDate(c.flagTo)
+	if err != nil {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project name"))
+	}
+
+	usage, err := resource.server.GetProjectUsage(resource.name, from, to)
+	if err != nil {
+		return err
+	}
+
+	// Render the output
+	const hour = float64(time.Hour / time.Second)
+	const gibHour = 1024 * 1024 * 1024 * hour
+
+	data := [][]string{
+		{i18n.G("INSTANCES (HOURS)"), fmt.Sprintf("%.2f", usage.InstanceSeconds/hour)},
+		{i18n.G("CPU (HOURS)"), fmt.Sprintf("%.2f", usage.CPUSeconds/hour)},
+		{i18n.G("MEMORY (GiB-HOURS)"), fmt.Sprintf("%.2f", usage.MemoryByteSeconds/gibHour)},
+		{i18n.G("DISK (GiB-HOURS)"), fmt.Sprintf("%.2f", usage.DiskByteSeconds/gibHour)},
+		{i18n.G("DISK READ"), units.GetByteSizeStringIEC(usage.DiskReadBytes, 2)},
+		{i18n.G("DISK WRITTEN"), units.GetByteSizeStringIEC(usage.DiskWrittenBytes, 2)},
+		{i18n.G("NETWORK RECEIVED"), units.GetByteSizeStringIEC(usage.NetworkReceivedBytes, 2)},
+		{i18n.G("NETWORK TRANSMITTED"), units.GetByteSizeStringIEC(usage.NetworkTransmittedBytes, 2)},
+	}
+
+	header := []string{
+		i18n.G("RESOURCE"),
+		i18n.G("USAGE"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, usage)
+}
+
 // Get current project.
 type cmdProjectGetCurrent struct {
 	global  *cmdGlobal
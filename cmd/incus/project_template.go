@@ -0,0 +1,518 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdProjectTemplate struct {
+	global  *cmdGlobal
+	project *cmdProject
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdProjectTemplate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("template")
+	cmd.Short = i18n.G("Manage project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage project templates
+
+Project templates hold the configuration, profiles, network ACLs, networks,
+custom storage volumes and access of new projects created with
+"incus project create --template".`))
+
+	// Apply
+	projectTemplateApplyCmd := cmdProjectTemplateApply{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateApplyCmd.Command())
+
+	// Create
+	projectTemplateCreateCmd := cmdProjectTemplateCreate{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateCreateCmd.Command())
+
+	// Delete
+	projectTemplateDeleteCmd := cmdProjectTemplateDelete{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateDeleteCmd.Command())
+
+	// Edit
+	projectTemplateEditCmd := cmdProjectTemplateEdit{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateEditCmd.Command())
+
+	// List
+	projectTemplateListCmd := cmdProjectTemplateList{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateListCmd.Command())
+
+	// Show
+	projectTemplateShowCmd := cmdProjectTemplateShow{global: c.global, projectTemplate: c}
+	cmd.AddCommand(projectTemplateShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Apply.
+type cmdProjectTemplateApply struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdProjectTemplateApply) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("apply", i18n.G("[<remote>:]<template> [<project>...]"))
+	cmd.Short = i18n.G("Update projects to the current definition of their template")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Update projects to the current definition of their template
+
+Without a list of projects, all the projects created from the template are updated.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus project template apply tenant
+    Update all the projects created from the tenant template
+
+incus project template apply tenant p1 p2
+    Only update the projects p1 and p2`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdProjectTemplateApply) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	// Apply the template
+	op, err := resource.server.ApplyProjectTemplate(resource.name, api.ProjectTemplateApplyPost{Projects: args[1:]})
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project template %s applied")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Create.
+type cmdProjectTemplateCreate struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+
+	flagDescription string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdProjectTemplateCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Create project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create project templates`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus project template create tenant < tenant.yaml
+    Create the project template tenant using the definition in tenant.yaml`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Project template description")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdProjectTemplateCreate) Run(cmd *cobra.Command, args []string) error {
+	var stdinData api.ProjectTemplatePut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	if c.flagDescription != "" {
+		stdinData.Description = c.flagDescription
+	}
+
+	// Create the template
+	err = resource.server.CreateProjectTemplate(api.ProjectTemplatesPost{Name: resource.name, ProjectTemplatePut: stdinData})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project template %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdProjectTemplateDelete struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdProjectTemplateDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<template>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete project templates`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdProjectTemplateDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	// Delete the template
+	err = resource.server.DeleteProjectTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Project template %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdProjectTemplateEdit struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdProjectTemplateEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Edit project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit project templates
+
+Projects created from the template are only updated by "incus project template apply".`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus project template edit <template> < template.yaml
+    Update a project template using the content of template.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdProjectTemplateEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the project template.
+### Any line starting with a '# will be ignored.
+###
+### config:
+###   features.networks: "true"
+###   restricted: "true"
+###   limits.instances: "10"
+### profiles:
+### - name: default
+###   devices:
+###     root:
+###       type: disk
+###       path: /
+###       pool: default
+### certificates:
+### - 2b9fa2e1bc7e7bd1a5bf3e9a6e4b3e2d9c0c7b1b8e0f4a4b3d2b7d4c2a1f0e9d
+### auth_groups:
+### - group: tenant-admins
+###   role: instance-operator`)
+}
+
+// Run actually performs the action.
+func (c *cmdProjectTemplateEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.ProjectTemplatePut{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateProjectTemplate(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	template, etag, err := resource.server.GetProjectTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&template.ProjectTemplatePut)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.ProjectTemplatePut{}
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateProjectTemplate(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdProjectTemplateList struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdProjectTemplateList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List project templates`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdProjectTemplateList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the templates
+	templates, err := resource.server.GetProjectTemplates()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, template := range templates {
+		data = append(data, []string{template.Name, template.Description, strconv.Itoa(len(template.UsedBy))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("USED BY"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, templates)
+}
+
+// Show.
+type cmdProjectTemplateShow struct {
+	global          *cmdGlobal
+	projectTemplate *cmdProjectTemplate
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdProjectTemplateShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<template>"))
+	cmd.Short = i18n.G("Show project templates")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show project templates`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdProjectTemplateShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing project template name"))
+	}
+
+	// Show the template
+	template, _, err := resource.server.GetProjectTemplate(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&template)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -74,6 +74,10 @@ func (c *cmdRemote) Command() *cobra.Command {
 	remoteRemoveCmd := cmdRemoteRemove{global: c.global, remote: c}
 	cmd.AddCommand(remoteRemoveCmd.Command())
 
+	// Renew certificate
+	remoteRenewCertificateCmd := cmdRemoteRenewCertificate{global: c.global, remote: c}
+	cmd.AddCommand(remoteRenewCertificateCmd.Command())
+
 	// Set default
 	remoteSwitchCmd := cmdRemoteSwitch{global: c.global, remote: c}
 	cmd.AddCommand(remoteSwitchCmd.Command())
@@ -1033,6 +1037,152 @@ func (c *cmdRemoteRemove) Run(cmd *cobra.Command, args []string) error {
 	return conf.SaveConfig(c.global.confPath)
 }
 
+// Renew certificate.
+type cmdRemoteRenewCertificate struct {
+	global *cmdGlobal
+	remote *cmdRemote
+}
+
+// Command returns a cobra.Command for use with (*cobra.Command).AddCommand.
+func (c *cmdRemoteRenewCertificate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("renew-certificate", i18n.G("[<remote>]"))
+	cmd.Short = i18n.G("Renew the client certificate of a remote")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Renew the client certificate of a remote
+
+A new key pair is generated and replaces the current certificate in the trust store
+of the remote, using the current certificate to authenticate.
+
+The new key pair is stored as the certificate specific to the remote,
+other remotes keep using the certificate they were using.`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemoteNames()
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run is used in the RunE field of the cobra.Command returned by Command.
+func (c *cmdRemoteRenewCertificate) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	remoteName := conf.DefaultRemote
+	if len(args) > 0 {
+		remoteName = args[0]
+	}
+
+	rc, ok := conf.Remotes[remoteName]
+	if !ok {
+		return fmt.Errorf(i18n.G("Remote %s doesn't exist"), remoteName)
+	}
+
+	if rc.Protocol != "incus" || rc.Public || strings.HasPrefix(rc.Addr, "unix:") || (rc.AuthType != "" && rc.AuthType != api.AuthenticationMethodTLS) {
+		return fmt.Errorf(i18n.G("Remote %s doesn't use a client certificate"), remoteName)
+	}
+
+	// Figure out the current certificate.
+	certPath := conf.ConfigPath("client.crt")
+	if conf.HasRemoteClientCertificate(remoteName) {
+		certPath = conf.ConfigPath("clientcerts", fmt.Sprintf("%s.crt", remoteName))
+	}
+
+	content, err := os.ReadFile(certPath)
+	if err != nil {
+		return err
+	}
+
+	fingerprint, err := localtls.CertFingerprintStr(string(content))
+	if err != nil {
+		return err
+	}
+
+	d, err := conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	if !d.HasExtension("certificate_self_renewal") {
+		return fmt.Errorf(i18n.G("The server is missing the required \"certificate_self_renewal\" API extension"))
+	}
+
+	cert, etag, err := d.GetCertificate(fingerprint)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed getting the current certificate: %w"), err)
+	}
+
+	// Generate the new key pair, keeping it aside until the remote trusts it.
+	newCert, newKey, err := localtls.GenerateMemCert(true, false)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(conf.ConfigPath("clientcerts"), 0o750)
+	if err != nil {
+		return err
+	}
+
+	newCertPath := conf.ConfigPath("clientcerts", fmt.Sprintf("%s.crt", remoteName))
+	newKeyPath := conf.ConfigPath("clientcerts", fmt.Sprintf("%s.key", remoteName))
+
+	err = os.WriteFile(newCertPath+".new", newCert, 0o644)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(newCertPath + ".new") }()
+
+	err = os.WriteFile(newKeyPath+".new", newKey, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(newKeyPath + ".new") }()
+
+	// Replace the certificate in the trust store of the remote.
+	req := cert.Writable()
+	req.Certificate = string(newCert)
+
+	err = d.UpdateCertificate(fingerprint, req, etag)
+	if err != nil {
+		return fmt.Errorf(i18n.G("Failed replacing the certificate: %w"), err)
+	}
+
+	err = os.Rename(newKeyPath+".new", newKeyPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(newCertPath+".new", newCertPath)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		newFingerprint, err := localtls.CertFingerprintStr(string(newCert))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf(i18n.G("Client certificate of remote %s renewed (new fingerprint: %s)")+"\n", remoteName, newFingerprint)
+	}
+
+	return nil
+}
+
 // Set default.
 type cmdRemoteSwitch struct {
 	global *cmdGlobal
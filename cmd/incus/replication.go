@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdReplication struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplication) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("replication")
+	cmd.Short = i18n.G("Manage instance replication")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage instance replication
+
+Instances are replicated to the replication remote set in their
+replication.remote configuration key, either on the replication.schedule
+schedule or on demand.`))
+
+	// Failover
+	replicationFailoverCmd := cmdReplicationFailover{global: c.global, replication: c}
+	cmd.AddCommand(replicationFailoverCmd.Command())
+
+	// Remote
+	replicationRemoteCmd := cmdReplicationRemote{global: c.global}
+	cmd.AddCommand(replicationRemoteCmd.Command())
+
+	// Run
+	replicationRunCmd := cmdReplicationRun{global: c.global, replication: c}
+	cmd.AddCommand(replicationRunCmd.Command())
+
+	// Show
+	replicationShowCmd := cmdReplicationShow{global: c.global, replication: c}
+	cmd.AddCommand(replicationShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// action performs a replication action on an instance and waits for it to complete.
+func (c *cmdReplication) action(cmd *cobra.Command, args []string, req api.InstanceReplicationPost, format string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	op, err := resource.server.UpdateInstanceReplication(resource.name, req)
+	if err != nil {
+		return err
+	}
+
+	return c.wait(op, format)
+}
+
+// wait waits for a replication operation to complete while showing its progress.
+func (c *cmdReplication) wait(op incus.Operation, format string) error {
+	progress := cli.ProgressRenderer{
+		Format: format,
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err := op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	return nil
+}
+
+// Failover.
+type cmdReplicationFailover struct {
+	global      *cmdGlobal
+	replication *cmdReplication
+
+	flagForce bool
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationFailover) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("failover", i18n.G("[<remote>:]<instance>"))
+	cmd.Short = i18n.G("Promote a replica instance")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Promote a replica instance
+
+The primary instance is stopped, replicated one last time and turned into a
+replica of this instance, reversing the replication direction.
+
+If the primary can't be reached, --force promotes the replica anyway.`))
+
+	cmd.Flags().BoolVarP(&c.flagForce, "force", "f", false, i18n.G("Fail over even if the primary can't be reached"))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationFailover) Run(cmd *cobra.Command, args []string) error {
+	return c.replication.action(cmd, args, api.InstanceReplicationPost{Action: "failover", Force: c.flagForce}, i18n.G("Failing over: %s"))
+}
+
+// Run.
+type cmdReplicationRun struct {
+	global      *cmdGlobal
+	replication *cmdReplication
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationRun) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("run", i18n.G("[<remote>:]<instance>"))
+	cmd.Short = i18n.G("Replicate an instance now")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Replicate an instance now`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationRun) Run(cmd *cobra.Command, args []string) error {
+	return c.replication.action(cmd, args, api.InstanceReplicationPost{Action: "replicate"}, i18n.G("Replicating: %s"))
+}
+
+// Show.
+type cmdReplicationShow struct {
+	global      *cmdGlobal
+	replication *cmdReplication
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<instance>"))
+	cmd.Short = i18n.G("Show the replication status of an instance")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the replication status of an instance`))
+
+	cmd.RunE = c.Run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpInstances(toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing instance name"))
+	}
+
+	replication, _, err := resource.server.GetInstanceReplication(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&replication)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
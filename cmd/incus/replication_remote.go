@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdReplicationRemote struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationRemote) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("remote")
+	cmd.Short = i18n.G("Manage replication remotes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage replication remotes
+
+Replication remotes are the servers that instances can be replicated to.
+Each replication remote has its own client certificate, which must be trusted
+by the remote server.`))
+
+	// Create
+	replicationRemoteCreateCmd := cmdReplicationRemoteCreate{global: c.global, replicationRemote: c}
+	cmd.AddCommand(replicationRemoteCreateCmd.Command())
+
+	// Delete
+	replicationRemoteDeleteCmd := cmdReplicationRemoteDelete{global: c.global, replicationRemote: c}
+	cmd.AddCommand(replicationRemoteDeleteCmd.Command())
+
+	// Edit
+	replicationRemoteEditCmd := cmdReplicationRemoteEdit{global: c.global, replicationRemote: c}
+	cmd.AddCommand(replicationRemoteEditCmd.Command())
+
+	// List
+	replicationRemoteListCmd := cmdReplicationRemoteList{global: c.global, replicationRemote: c}
+	cmd.AddCommand(replicationRemoteListCmd.Command())
+
+	// Show
+	replicationRemoteShowCmd := cmdReplicationRemoteShow{global: c.global, replicationRemote: c}
+	cmd.AddCommand(replicationRemoteShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// Create.
+type cmdReplicationRemoteCreate struct {
+	global            *cmdGlobal
+	replicationRemote *cmdReplicationRemote
+
+	flagCertificate   string
+	flagDescription   string
+	flagTargetProject string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationRemoteCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<replication remote> <address>"))
+	cmd.Short = i18n.G("Create replication remotes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create replication remotes
+
+A new client certificate is generated for the replication remote. Add it to the
+trust store of the remote server, ideally restricted to the projects holding
+the replicas.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus replication remote create dr-site https://dr.example.net:8443 --certificate dr.crt
+    Create the replication remote dr-site
+
+incus replication remote show dr-site
+    Show the client certificate to trust on the remote server`))
+
+	cmd.Flags().StringVar(&c.flagCertificate, "certificate", "", i18n.G("Path to the certificate of the remote server")+"``")
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Replication remote description")+"``")
+	cmd.Flags().StringVar(&c.flagTargetProject, "target-project", "", i18n.G("Project to replicate instances into on the remote server")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationRemoteCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing replication remote name"))
+	}
+
+	// Create the replication remote
+	remote := api.ReplicationRemotesPost{Name: resource.name}
+	remote.Address = args[1]
+	remote.Description = c.flagDescription
+	remote.TargetProject = c.flagTargetProject
+
+	if c.flagCertificate != "" {
+		content, err := os.ReadFile(c.flagCertificate)
+		if err != nil {
+			return err
+		}
+
+		remote.Certificate = string(content)
+	}
+
+	err = resource.server.CreateReplicationRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Replication remote %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdReplicationRemoteDelete struct {
+	global            *cmdGlobal
+	replicationRemote *cmdReplicationRemote
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationRemoteDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<replication remote>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete replication remotes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete replication remotes`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationRemoteDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing replication remote name"))
+	}
+
+	// Delete the replication remote
+	err = resource.server.DeleteReplicationRemote(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Replication remote %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdReplicationRemoteEdit struct {
+	global            *cmdGlobal
+	replicationRemote *cmdReplicationRemote
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationRemoteEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<replication remote>"))
+	cmd.Short = i18n.G("Edit replication remotes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit replication remotes`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus replication remote edit <replication remote> < remote.yaml
+    Update a replication remote using the content of remote.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdReplicationRemoteEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the replication remote.
+### Any line starting with a '# will be ignored.
+###
+### description: Disaster recovery site
+### address: https://dr.example.net:8443
+### certificate: ""
+### target_project: replicas`)
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationRemoteEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing replication remote name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.ReplicationRemotePut{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateReplicationRemote(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	remote, etag, err := resource.server.GetReplicationRemote(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&remote.ReplicationRemotePut)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.ReplicationRemotePut{}
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateReplicationRemote(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdReplicationRemoteList struct {
+	global            *cmdGlobal
+	replicationRemote *cmdReplicationRemote
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationRemoteList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List replication remotes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List replication remotes`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationRemoteList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the replication remotes
+	remotes, err := resource.server.GetReplicationRemotes()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, remote := range remotes {
+		data = append(data, []string{remote.Name, remote.Address, remote.TargetProject, remote.Description, strconv.Itoa(len(remote.UsedBy))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("ADDRESS"),
+		i18n.G("TARGET PROJECT"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("USED BY"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, remotes)
+}
+
+// Show.
+type cmdReplicationRemoteShow struct {
+	global            *cmdGlobal
+	replicationRemote *cmdReplicationRemote
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdReplicationRemoteShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<replication remote>"))
+	cmd.Short = i18n.G("Show replication remotes")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show replication remotes`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdReplicationRemoteShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing replication remote name"))
+	}
+
+	// Show the replication remote
+	remote, _, err := resource.server.GetReplicationRemote(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&remote)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
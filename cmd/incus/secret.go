@@ -0,0 +1,518 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/termios"
+)
+
+type cmdSecret struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdSecret) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("secret")
+	cmd.Short = i18n.G("Manage secrets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage secrets
+
+Secrets are stored encrypted and referenced from the environment.*, cloud-init.*
+and user.* instance options as ${secret:<name>}. Their values can't be read back.`))
+
+	// Create
+	secretCreateCmd := cmdSecretCreate{global: c.global, secret: c}
+	cmd.AddCommand(secretCreateCmd.Command())
+
+	// Delete
+	secretDeleteCmd := cmdSecretDelete{global: c.global, secret: c}
+	cmd.AddCommand(secretDeleteCmd.Command())
+
+	// Edit
+	secretEditCmd := cmdSecretEdit{global: c.global, secret: c}
+	cmd.AddCommand(secretEditCmd.Command())
+
+	// List
+	secretListCmd := cmdSecretList{global: c.global, secret: c}
+	cmd.AddCommand(secretListCmd.Command())
+
+	// Set value
+	secretSetValueCmd := cmdSecretSetValue{global: c.global, secret: c}
+	cmd.AddCommand(secretSetValueCmd.Command())
+
+	// Show
+	secretShowCmd := cmdSecretShow{global: c.global, secret: c}
+	cmd.AddCommand(secretShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// readValue returns the value passed as argument or, if missing, reads it from stdin.
+func (c *cmdSecret) readValue(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	// Don't echo the value when typed in a terminal.
+	if termios.IsTerminal(getStdinFd()) {
+		return c.global.asker.AskPasswordOnce(i18n.G("Secret value: ")), nil
+	}
+
+	contents, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(string(contents), "\n"), nil
+}
+
+// Create.
+type cmdSecretCreate struct {
+	global *cmdGlobal
+	secret *cmdSecret
+
+	flagDescription string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdSecretCreate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", i18n.G("[<remote>:]<secret> [<value>]"))
+	cmd.Short = i18n.G("Create secrets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create secrets
+
+If the value isn't provided, it's read from stdin.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus secret create db-password < password.txt
+    Create the secret db-password with the content of password.txt
+
+incus config set c1 environment.DB_PASSWORD='${secret:db-password}'
+    Pass the secret to the instance c1 as an environment variable`))
+
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", i18n.G("Secret description")+"``")
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdSecretCreate) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing secret name"))
+	}
+
+	value, err := c.secret.readValue(args[1:])
+	if err != nil {
+		return err
+	}
+
+	// Create the secret
+	secret := api.SecretsPost{Name: resource.name}
+	secret.Description = c.flagDescription
+	secret.Value = value
+
+	err = resource.server.CreateSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Secret %s created")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdSecretDelete struct {
+	global *cmdGlobal
+	secret *cmdSecret
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdSecretDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<secret>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete secrets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete secrets`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdSecretDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing secret name"))
+	}
+
+	// Delete the secret
+	err = resource.server.DeleteSecret(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Secret %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdSecretEdit struct {
+	global *cmdGlobal
+	secret *cmdSecret
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdSecretEdit) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", i18n.G("[<remote>:]<secret>"))
+	cmd.Short = i18n.G("Edit secrets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Edit secrets`))
+	cmd.Example = cli.FormatSection("", i18n.G(
+		`incus secret edit <secret> < secret.yaml
+    Update a secret using the content of secret.yaml`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+func (c *cmdSecretEdit) helpTemplate() string {
+	return i18n.G(
+		`### This is a YAML representation of the secret.
+### Any line starting with a '# will be ignored.
+###
+### The current value isn't shown, set "value" to replace it.
+###
+### description: Password of the application database
+### value: s3cr3t`)
+}
+
+// Run actually performs the action.
+func (c *cmdSecretEdit) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing secret name"))
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		newdata := api.SecretPut{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateSecret(resource.name, newdata, "")
+	}
+
+	// Extract the current value
+	secret, etag, err := resource.server.GetSecret(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&secret.SecretPut)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor
+	content, err := textEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor
+		newdata := api.SecretPut{}
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateSecret(resource.name, newdata, etag)
+		}
+
+		// Respawn the editor
+		if err != nil {
+			fmt.Fprintf(os.Stderr, i18n.G("Config parsing error: %s")+"\n", err)
+			fmt.Println(i18n.G("Press enter to open the editor again or ctrl+c to abort change"))
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = textEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// List.
+type cmdSecretList struct {
+	global *cmdGlobal
+	secret *cmdSecret
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdSecretList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List secrets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List secrets`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdSecretList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the secrets
+	secrets, err := resource.server.GetSecrets()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, secret := range secrets {
+		data = append(data, []string{secret.Name, secret.Description, strconv.Itoa(len(secret.UsedBy))})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("USED BY"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, secrets)
+}
+
+// Set value.
+type cmdSecretSetValue struct {
+	global *cmdGlobal
+	secret *cmdSecret
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdSecretSetValue) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("set-value", i18n.G("[<remote>:]<secret> [<value>]"))
+	cmd.Short = i18n.G("Set the value of secrets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Set the value of secrets
+
+If the value isn't provided, it's read from stdin.
+Instances pick up the new value the next time it's resolved, for example on their next start.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdSecretSetValue) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing secret name"))
+	}
+
+	value, err := c.secret.readValue(args[1:])
+	if err != nil {
+		return err
+	}
+
+	if value == "" {
+		return fmt.Errorf(i18n.G("The secret value can't be empty"))
+	}
+
+	// Update the secret
+	secret, etag, err := resource.server.GetSecret(resource.name)
+	if err != nil {
+		return err
+	}
+
+	newdata := secret.Writable()
+	newdata.Value = value
+
+	return resource.server.UpdateSecret(resource.name, newdata, etag)
+}
+
+// Show.
+type cmdSecretShow struct {
+	global *cmdGlobal
+	secret *cmdSecret
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdSecretShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<secret>"))
+	cmd.Short = i18n.G("Show secrets")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show secrets
+
+The value of the secret isn't shown.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdSecretShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing secret name"))
+	}
+
+	// Show the secret
+	secret, _, err := resource.server.GetSecret(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&secret)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
@@ -0,0 +1,459 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	incus "github.com/lxc/incus/v6/client"
+	cli "github.com/lxc/incus/v6/internal/cmd"
+	"github.com/lxc/incus/v6/internal/i18n"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+type cmdStack struct {
+	global *cmdGlobal
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdStack) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("stack")
+	cmd.Short = i18n.G("Manage stacks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Manage stacks
+
+Stacks are sets of profiles, network ACLs, networks, network forwards, custom
+storage volumes and instances defined together in a single YAML file.`))
+
+	// Apply
+	stackApplyCmd := cmdStackApply{global: c.global, stack: c}
+	cmd.AddCommand(stackApplyCmd.Command())
+
+	// Delete
+	stackDeleteCmd := cmdStackDelete{global: c.global, stack: c}
+	cmd.AddCommand(stackDeleteCmd.Command())
+
+	// Diff
+	stackDiffCmd := cmdStackDiff{global: c.global, stack: c}
+	cmd.AddCommand(stackDiffCmd.Command())
+
+	// List
+	stackListCmd := cmdStackList{global: c.global, stack: c}
+	cmd.AddCommand(stackListCmd.Command())
+
+	// Show
+	stackShowCmd := cmdStackShow{global: c.global, stack: c}
+	cmd.AddCommand(stackShowCmd.Command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// readDefinition parses a stack definition from a file, or from stdin if the path is "-".
+func (c *cmdStack) readDefinition(path string) (*api.StackPut, error) {
+	var contents []byte
+	var err error
+
+	if path == "-" {
+		contents, err = io.ReadAll(os.Stdin)
+	} else {
+		contents, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	definition := api.StackPut{}
+	err = yaml.UnmarshalStrict(contents, &definition)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.G("Failed to parse stack definition: %w"), err)
+	}
+
+	return &definition, nil
+}
+
+// Apply.
+type cmdStackApply struct {
+	global *cmdGlobal
+	stack  *cmdStack
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdStackApply) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("apply", i18n.G("[<remote>:]<stack> <file>"))
+	cmd.Short = i18n.G("Create or update stacks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Create or update stacks
+
+The stack is created if it doesn't exist yet. Otherwise its resources are
+updated to match the definition, with resources removed from the definition
+being deleted. If any change fails, all changes made so far are reverted.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus stack apply myapp myapp.yaml
+    Create or update the stack myapp using the definition in myapp.yaml
+
+incus stack apply myapp - < myapp.yaml
+    Create or update the stack myapp using the definition read from stdin`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdStackApply) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing stack name"))
+	}
+
+	definition, err := c.stack.readDefinition(args[1])
+	if err != nil {
+		return err
+	}
+
+	// Create the stack or update it if it already exists.
+	_, etag, err := resource.server.GetStack(resource.name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	created := err != nil
+
+	progress := cli.ProgressRenderer{
+		Format: i18n.G("Applying stack: %s"),
+		Quiet:  c.global.flagQuiet,
+	}
+
+	var op incus.Operation
+	if created {
+		op, err = resource.server.CreateStack(api.StacksPost{Name: resource.name, StackPut: *definition})
+	} else {
+		op, err = resource.server.UpdateStack(resource.name, *definition, etag)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// Register progress handler
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	// Wait for operation to finish
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		if created {
+			fmt.Printf(i18n.G("Stack %s created")+"\n", resource.name)
+		} else {
+			fmt.Printf(i18n.G("Stack %s updated")+"\n", resource.name)
+		}
+	}
+
+	return nil
+}
+
+// Delete.
+type cmdStackDelete struct {
+	global *cmdGlobal
+	stack  *cmdStack
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdStackDelete) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", i18n.G("[<remote>:]<stack>"))
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = i18n.G("Delete stacks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Delete stacks
+
+All the resources of the stack are deleted, instances being stopped first.`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdStackDelete) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing stack name"))
+	}
+
+	// Delete the stack
+	op, err := resource.server.DeleteStack(resource.name)
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf(i18n.G("Stack %s deleted")+"\n", resource.name)
+	}
+
+	return nil
+}
+
+// Diff.
+type cmdStackDiff struct {
+	global *cmdGlobal
+	stack  *cmdStack
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdStackDiff) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("diff", i18n.G("[<remote>:]<stack> <file>"))
+	cmd.Short = i18n.G("Show the changes applying a stack definition would make")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show the changes applying a stack definition would make
+
+Changes are listed in the order they would be applied.`))
+	cmd.Example = cli.FormatSection("", i18n.G(`incus stack diff myapp myapp.yaml
+    Show the changes needed to bring the stack myapp to the definition in myapp.yaml`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdStackDiff) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing stack name"))
+	}
+
+	definition, err := c.stack.readDefinition(args[1])
+	if err != nil {
+		return err
+	}
+
+	plan, err := resource.server.GetStackPlan(resource.name, *definition)
+	if err != nil {
+		return err
+	}
+
+	if len(plan.Changes) == 0 && c.flagFormat == "table" {
+		fmt.Println(i18n.G("No changes"))
+		return nil
+	}
+
+	// Keep the order in which the changes are applied.
+	data := [][]string{}
+	for _, change := range plan.Changes {
+		data = append(data, []string{change.Action, change.Type, change.Name, strings.Join(change.Fields, "\n")})
+	}
+
+	header := []string{
+		i18n.G("ACTION"),
+		i18n.G("TYPE"),
+		i18n.G("NAME"),
+		i18n.G("FIELDS"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, plan.Changes)
+}
+
+// List.
+type cmdStackList struct {
+	global *cmdGlobal
+	stack  *cmdStack
+
+	flagFormat string
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdStackList) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", i18n.G("[<remote>:]"))
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = i18n.G("List stacks")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`List stacks`))
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", i18n.G(`Format (csv|json|table|yaml|compact), use suffix ",noheader" to disable headers and ",header" to enable it if missing, e.g. csv,header`)+"``")
+
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return cli.ValidateFlagFormatForListOutput(cmd.Flag("format").Value.String())
+	}
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdStackList) Run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := conf.DefaultRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List stacks
+	stacks, err := resource.server.GetStacks()
+	if err != nil {
+		return err
+	}
+
+	data := [][]string{}
+	for _, stack := range stacks {
+		count := len(stack.Profiles) + len(stack.Networks) + len(stack.NetworkForwards) + len(stack.StorageVolumes) + len(stack.Instances)
+		data = append(data, []string{stack.Name, stack.Description, fmt.Sprintf("%d", len(stack.Instances)), fmt.Sprintf("%d", count)})
+	}
+
+	sort.Sort(cli.SortColumnsNaturally(data))
+
+	header := []string{
+		i18n.G("NAME"),
+		i18n.G("DESCRIPTION"),
+		i18n.G("INSTANCES"),
+		i18n.G("RESOURCES"),
+	}
+
+	return cli.RenderTable(os.Stdout, c.flagFormat, header, data, stacks)
+}
+
+// Show.
+type cmdStackShow struct {
+	global *cmdGlobal
+	stack  *cmdStack
+}
+
+// Command returns a cobra command for inclusion.
+func (c *cmdStackShow) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", i18n.G("[<remote>:]<stack>"))
+	cmd.Short = i18n.G("Show stack definitions")
+	cmd.Long = cli.FormatSection(i18n.G("Description"), i18n.G(
+		`Show stack definitions`))
+
+	cmd.RunE = c.Run
+
+	return cmd
+}
+
+// Run actually performs the action.
+func (c *cmdStackShow) Run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return fmt.Errorf(i18n.G("Missing stack name"))
+	}
+
+	// Show the stack
+	stack, _, err := resource.server.GetStack(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&stack)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
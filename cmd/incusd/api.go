@@ -415,6 +415,53 @@ func setCORSHeaders(rw http.ResponseWriter, req *http.Request, config *clusterCo
 	}
 }
 
+// isAuditedRequest returns whether an API request gets an audit record.
+// That's the case for all mutating requests, except for those replicating another member's request or exchanged between cluster members.
+func isAuditedRequest(r *http.Request, version string, protocol string) bool {
+	if r.Method == "GET" || r.Method == "HEAD" {
+		return false
+	}
+
+	if isClusterNotification(r) {
+		return false
+	}
+
+	return version != "internal" || protocol != "cluster"
+}
+
+// auditRequest sends the audit event of an API request.
+func (d *Daemon) auditRequest(r *http.Request, username string, protocol string, bodyDigest string, statusCode int, authorization string) {
+	requestor := request.CreateRequestor(r)
+
+	// Untrusted requests lack the identity in their context.
+	if requestor.Protocol == "" {
+		requestor.Username = username
+		requestor.Protocol = protocol
+	}
+
+	outcome := api.EventAuditOutcomeSuccess
+	if statusCode >= http.StatusBadRequest {
+		outcome = api.EventAuditOutcomeFailure
+	}
+
+	if statusCode == http.StatusForbidden && authorization == api.EventAuditAuthorizationAllowed {
+		authorization = api.EventAuditAuthorizationDenied
+	}
+
+	err := d.events.Send("", api.EventTypeAudit, api.EventAudit{
+		Requestor:     requestor,
+		Method:        r.Method,
+		URL:           r.URL.RequestURI(),
+		BodyDigest:    bodyDigest,
+		StatusCode:    statusCode,
+		Outcome:       outcome,
+		Authorization: authorization,
+	})
+	if err != nil {
+		logger.Warn("Failed sending audit event", logger.Ctx{"err": err})
+	}
+}
+
 // Return true if this an API request coming from a cluster node that is
 // notifying us of some user-initiated API request that needs some action to be
 // taken on this node as well.
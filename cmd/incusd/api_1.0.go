@@ -41,15 +41,26 @@ var api10Cmd = APIEndpoint{
 var api10 = []APIEndpoint{
 	api10Cmd,
 	api10ResourcesCmd,
+	authGroupCmd,
+	authGroupsCmd,
+	authRoleCmd,
+	authRolesCmd,
+	authTokenCmd,
+	authTokensCmd,
 	certificateCmd,
 	certificatesCmd,
 	clusterCmd,
 	clusterGroupCmd,
+	clusterMaintenanceCmd,
 	clusterGroupsCmd,
 	clusterNodeCmd,
 	clusterNodeStateCmd,
 	clusterNodesCmd,
+	clusterRebalanceCmd,
 	clusterCertificateCmd,
+	databaseBackupCmd,
+	databaseBackupExportCmd,
+	databaseBackupsCmd,
 	instanceBackupCmd,
 	instanceBackupExportCmd,
 	instanceBackupsCmd,
@@ -65,12 +76,15 @@ var api10 = []APIEndpoint{
 	instanceMetadataTemplatesCmd,
 	instancesCmd,
 	instanceRebuildCmd,
+	instanceReplicationCmd,
 	instanceSFTPCmd,
 	instanceSnapshotCmd,
 	instanceSnapshotsCmd,
 	instanceStateCmd,
 	instanceAccessCmd,
 	instanceDebugMemoryCmd,
+	instanceSetCmd,
+	instanceSetsCmd,
 	eventsCmd,
 	imageAliasCmd,
 	imageAliasesCmd,
@@ -110,7 +124,18 @@ var api10 = []APIEndpoint{
 	projectCmd,
 	projectsCmd,
 	projectStateCmd,
+	projectUsageCmd,
 	projectAccessCmd,
+	projectTemplatesCmd,
+	projectTemplateCmd,
+	projectTemplateApplyCmd,
+	replicationRemotesCmd,
+	replicationRemoteCmd,
+	secretsCmd,
+	secretCmd,
+	stacksCmd,
+	stackCmd,
+	stackPlanCmd,
 	storagePoolCmd,
 	storagePoolResourcesCmd,
 	storagePoolsCmd,
@@ -227,7 +252,7 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 	// Get the authentication methods.
 	authMethods := []string{api.AuthenticationMethodTLS}
 
-	oidcIssuer, oidcClientID, _, _, _ := s.GlobalConfig.OIDCServer()
+	oidcIssuer, oidcClientID, _, _, _, _ := s.GlobalConfig.OIDCServer()
 	if oidcIssuer != "" && oidcClientID != "" {
 		authMethods = append(authMethods, api.AuthenticationMethodOIDC)
 	}
@@ -789,6 +814,7 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	bgpChanged := false
 	dnsChanged := false
 	lokiChanged := false
+	auditChanged := false
 	oidcChanged := false
 	openFGAChanged := false
 	ovnChanged := false
@@ -801,6 +827,9 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "acme.agree_tos", "acme.ca_url", "acme.challenge", "acme.domain", "acme.email", "acme.provider", "acme.provider.environment", "acme.provider.resolvers":
 			acmeChanged = true
 
+		case "audit.file", "audit.file.max_files", "audit.file.max_size", "audit.syslog":
+			auditChanged = true
+
 		case "cluster.images_minimal_replica":
 			err := autoSyncImages(s.ShutdownCtx, s)
 			if err != nil {
@@ -831,7 +860,7 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		case "network.ovn.northbound_connection", "network.ovn.ca_cert", "network.ovn.client_cert", "network.ovn.client_key":
 			ovnChanged = true
 
-		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim":
+		case "oidc.issuer", "oidc.client.id", "oidc.audience", "oidc.claim", "oidc.groups.claim":
 			oidcChanged = true
 
 		case "openfga.api.url", "openfga.api.token", "openfga.store.id":
@@ -950,6 +979,15 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	if auditChanged {
+		auditFile, auditFileMaxSize, auditFileMaxFiles, auditSyslog := clusterConfig.Audit()
+
+		err := d.setupAudit(auditFile, auditFileMaxSize, auditFileMaxFiles, auditSyslog)
+		if err != nil {
+			return err
+		}
+	}
+
 	if lokiChanged {
 		lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := clusterConfig.LokiServer()
 
@@ -964,13 +1002,13 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 	}
 
 	if oidcChanged {
-		oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := clusterConfig.OIDCServer()
+		oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim := clusterConfig.OIDCServer()
 
 		if oidcIssuer == "" || oidcClientID == "" {
 			d.oidcVerifier = nil
 		} else {
 			var err error
-			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+			d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim)
 			if err != nil {
 				return fmt.Errorf("Failed creating verifier: %w", err)
 			}
@@ -1022,14 +1060,44 @@ func doApi10UpdateTriggers(d *Daemon, nodeChanged, clusterChanged map[string]str
 		}
 	}
 
+	// Compile and load the instance admission scriptlet.
+	value, ok = clusterChanged["instances.admission.scriptlet"]
+	if ok {
+		err := scriptletLoad.InstanceAdmissionSet(value)
+		if err != nil {
+			return fmt.Errorf("Failed saving instance admission scriptlet: %w", err)
+		}
+	}
+
+	// Compile and load the cluster re-balancing scriptlet.
+	value, ok = clusterChanged["cluster.rebalance.scriptlet"]
+	if ok {
+		err := scriptletLoad.ClusterRebalanceSet(value)
+		if err != nil {
+			return fmt.Errorf("Failed saving cluster re-balancing scriptlet: %w", err)
+		}
+	}
+
 	// Setup the authorization scriptlet.
+	scriptletChanged := false
 	value, ok = clusterChanged["authorization.scriptlet"]
 	if ok {
+		scriptletChanged = true
+
 		err := d.setupAuthorizationScriptlet(value)
 		if err != nil {
 			return err
 		}
 	}
 
+	// Setup the built-in role-based access control, the other authorizers reset to the default one when disabled.
+	_, ok = clusterChanged["authorization.rbac"]
+	if ok || openFGAChanged || scriptletChanged {
+		err := d.setupRBAC(clusterConfig.AuthorizationRBAC())
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
@@ -9,9 +10,11 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/acme"
 	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
@@ -153,6 +156,19 @@ func autoRenewCertificate(ctx context.Context, d *Daemon, force bool) error {
 			return err
 		}
 
+		// Re-encrypt the secrets and the replication remote client keys with the key derived from the new certificate.
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			err := secrets.Reencrypt(ctx, tx.Tx(), s.Endpoints.NetworkCert(), cert)
+			if err != nil {
+				return err
+			}
+
+			return replicationRemotesReencrypt(ctx, tx.Tx(), s.Endpoints.NetworkCert(), cert)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed re-encrypting secrets: %w", err)
+		}
+
 		s.Endpoints.NetworkUpdateCert(cert)
 
 		err = util.WriteCert(s.OS.VarDir, "server", newCert.Certificate, newCert.PrivateKey, nil)
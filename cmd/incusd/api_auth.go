@@ -0,0 +1,1185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var authGroupsCmd = APIEndpoint{
+	Path: "auth/groups",
+
+	Get:  APIEndpointAction{Handler: authGroupsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authGroupsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authGroupCmd = APIEndpoint{
+	Path: "auth/groups/{name}",
+
+	Get:    APIEndpointAction{Handler: authGroupGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authGroupPut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: authGroupPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: authGroupDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authRolesCmd = APIEndpoint{
+	Path: "auth/roles",
+
+	Get:  APIEndpointAction{Handler: authRolesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authRolesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authRoleCmd = APIEndpoint{
+	Path: "auth/roles/{name}",
+
+	Get:    APIEndpointAction{Handler: authRoleGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Put:    APIEndpointAction{Handler: authRolePut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post:   APIEndpointAction{Handler: authRolePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: authRoleDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// authRefresh notifies the other cluster members of a change to the groups or roles and reloads them locally.
+func authRefresh(ctx context.Context, s *state.State, r *http.Request, hook func(client incus.InstanceServer) error) error {
+	if !isClusterNotification(r) {
+		notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+		if err != nil {
+			return err
+		}
+
+		err = notifier(hook)
+		if err != nil {
+			return err
+		}
+	}
+
+	rbac, ok := s.Authorizer.(*auth.RBAC)
+	if ok {
+		err := rbac.Refresh(ctx)
+		if err != nil {
+			logger.Error("Failed to refresh the authorization groups", logger.Ctx{"err": err})
+		}
+	}
+
+	return nil
+}
+
+// authRefreshTask periodically reloads the groups and roles, so that members which missed a change
+// notification, for example because they were offline, catch up with it.
+func authRefreshTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		rbac, ok := d.State().Authorizer.(*auth.RBAC)
+		if !ok {
+			return
+		}
+
+		err := rbac.Refresh(ctx)
+		if err != nil {
+			logger.Warn("Failed to refresh the authorization groups", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(time.Minute)
+}
+
+// authValidateName checks that a group or role name is valid.
+func authValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.ContainsAny(name, "/ '\"") {
+		return errors.New("Names may not contain slashes, spaces or quotes")
+	}
+
+	if slices.Contains([]string{".", ".."}, name) {
+		return fmt.Errorf("Invalid name %q", name)
+	}
+
+	return nil
+}
+
+// authGroupValidate checks the identities, identity provider groups and roles of a group.
+func authGroupValidate(ctx context.Context, tx *db.ClusterTx, req api.AuthGroupPut) error {
+	for _, identity := range req.Identities {
+		if !slices.Contains([]string{api.AuthenticationMethodTLS, api.AuthenticationMethodOIDC}, identity.AuthenticationMethod) {
+			return api.StatusErrorf(http.StatusBadRequest, "Unsupported authentication method %q", identity.AuthenticationMethod)
+		}
+
+		if identity.Identifier == "" {
+			return api.StatusErrorf(http.StatusBadRequest, "Identities require an identifier")
+		}
+	}
+
+	for _, name := range req.IdentityProviderGroups {
+		if name == "" {
+			return api.StatusErrorf(http.StatusBadRequest, "Identity provider group names cannot be empty")
+		}
+	}
+
+	for _, role := range req.Roles {
+		exists, err := dbCluster.AuthRoleExists(ctx, tx.Tx(), role.Role)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return api.StatusErrorf(http.StatusBadRequest, "Role %q doesn't exist", role.Role)
+		}
+
+		if role.Project == "" {
+			continue
+		}
+
+		_, err = dbCluster.GetProjectID(ctx, tx.Tx(), role.Project)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusNotFound) {
+				return api.StatusErrorf(http.StatusBadRequest, "Project %q doesn't exist", role.Project)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// authRoleValidate checks the permissions of a role.
+func authRoleValidate(req api.AuthRolePut) error {
+	for _, permission := range req.Permissions {
+		err := auth.ValidateEntitlement(auth.ObjectType(permission.EntityType), auth.Entitlement(permission.Entitlement))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/auth/groups auth auth_groups_get
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/groups/operators",
+//	              "/1.0/auth/groups/auditors"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/groups?recursion=1 auth auth_groups_get_recursion1
+//
+//	Get the authorization groups
+//
+//	Returns a list of authorization groups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of authorization groups
+//	          items:
+//	            $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var groups []dbCluster.AuthGroup
+	var apiGroups []*api.AuthGroup
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		groups, err = dbCluster.GetAuthGroups(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if !recursion {
+			return nil
+		}
+
+		apiGroups = make([]*api.AuthGroup, 0, len(groups))
+		for _, group := range groups {
+			apiGroup, err := group.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			apiGroups = append(apiGroups, apiGroup)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, apiGroups)
+	}
+
+	urls := make([]string, 0, len(groups))
+	for _, group := range groups {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "groups", group.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/groups auth auth_groups_post
+//
+//	Add an authorization group
+//
+//	Creates a new authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthGroupsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = authValidateName(req.Name)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			err := authGroupValidate(ctx, tx, req.AuthGroupPut)
+			if err != nil {
+				return err
+			}
+
+			groupID, err := dbCluster.CreateAuthGroup(ctx, tx.Tx(), dbCluster.AuthGroup{Name: req.Name, Description: req.Description})
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateAuthGroupIdentities(ctx, tx.Tx(), int(groupID), req.Identities)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateAuthGroupIdentityProviderGroups(ctx, tx.Tx(), int(groupID), req.IdentityProviderGroups)
+			if err != nil {
+				return err
+			}
+
+			return dbCluster.UpdateAuthGroupRoles(ctx, tx.Tx(), int(groupID), req.Roles)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.CreateAuthGroup(req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if isClusterNotification(r) {
+		return response.EmptySyncResponse
+	}
+
+	lc := lifecycle.AuthGroupCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/groups/{name} auth auth_group_get
+//
+//	Get the authorization group
+//
+//	Gets a specific authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Authorization group
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthGroup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var apiGroup *api.AuthGroup
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		group, err := dbCluster.GetAuthGroup(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		apiGroup, err = group.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, apiGroup, apiGroup.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/groups/{name} auth auth_group_put
+//
+//	Update the authorization group
+//
+//	Updates the description, identities and roles of the group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthGroupPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			group, err := dbCluster.GetAuthGroup(ctx, tx.Tx(), name)
+			if err != nil {
+				return err
+			}
+
+			apiGroup, err := group.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			err = localUtil.EtagCheck(r, apiGroup.Writable())
+			if err != nil {
+				return err
+			}
+
+			err = authGroupValidate(ctx, tx, req)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateAuthGroup(ctx, tx.Tx(), name, dbCluster.AuthGroup{Name: name, Description: req.Description})
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateAuthGroupIdentities(ctx, tx.Tx(), group.ID, req.Identities)
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateAuthGroupIdentityProviderGroups(ctx, tx.Tx(), group.ID, req.IdentityProviderGroups)
+			if err != nil {
+				return err
+			}
+
+			return dbCluster.UpdateAuthGroupRoles(ctx, tx.Tx(), group.ID, req.Roles)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.UpdateAuthGroup(name, req, "")
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupUpdated.Event(name, request.CreateRequestor(r), nil))
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/auth/groups/{name} auth auth_group_post
+//
+//	Rename the authorization group
+//
+//	Renames an existing authorization group.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: group
+//	    description: Group rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthGroupPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthGroupPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = authValidateName(req.Name)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			exists, err := dbCluster.AuthGroupExists(ctx, tx.Tx(), req.Name)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				return api.StatusErrorf(http.StatusConflict, "Name %q already in use", req.Name)
+			}
+
+			return dbCluster.RenameAuthGroup(ctx, tx.Tx(), name, req.Name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.RenameAuthGroup(name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if isClusterNotification(r) {
+		return response.EmptySyncResponse
+	}
+
+	lc := lifecycle.AuthGroupRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/auth/groups/{name} auth auth_group_delete
+//
+//	Delete the authorization group
+//
+//	Removes the authorization group.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authGroupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteAuthGroup(ctx, tx.Tx(), name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.DeleteAuthGroup(name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthGroupDeleted.Event(name, request.CreateRequestor(r), nil))
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/auth/roles auth auth_roles_get
+//
+//	Get the authorization roles
+//
+//	Returns a list of authorization roles (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/roles/instance-operator",
+//	              "/1.0/auth/roles/viewer"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/roles?recursion=1 auth auth_roles_get_recursion1
+//
+//	Get the authorization roles
+//
+//	Returns a list of authorization roles (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of authorization roles
+//	          items:
+//	            $ref: "#/definitions/AuthRole"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authRolesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var roles []dbCluster.AuthRole
+	var apiRoles []*api.AuthRole
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		roles, err = dbCluster.GetAuthRoles(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if !recursion {
+			return nil
+		}
+
+		apiRoles = make([]*api.AuthRole, 0, len(roles))
+		for _, role := range roles {
+			apiRole, err := role.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			apiRoles = append(apiRoles, apiRole)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, apiRoles)
+	}
+
+	urls := make([]string, 0, len(roles))
+	for _, role := range roles {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "roles", role.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/roles auth auth_roles_post
+//
+//	Add an authorization role
+//
+//	Creates a new authorization role.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: role
+//	    description: Role
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthRolesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authRolesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthRolesPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = authValidateName(req.Name)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = authRoleValidate(req.AuthRolePut)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			roleID, err := dbCluster.CreateAuthRole(ctx, tx.Tx(), dbCluster.AuthRole{Name: req.Name, Description: req.Description})
+			if err != nil {
+				return err
+			}
+
+			return dbCluster.UpdateAuthRolePermissions(ctx, tx.Tx(), int(roleID), req.Permissions)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.CreateAuthRole(req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if isClusterNotification(r) {
+		return response.EmptySyncResponse
+	}
+
+	lc := lifecycle.AuthRoleCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/roles/{name} auth auth_role_get
+//
+//	Get the authorization role
+//
+//	Gets a specific authorization role.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Authorization role
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthRole"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authRoleGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var apiRole *api.AuthRole
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		role, err := dbCluster.GetAuthRole(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		apiRole, err = role.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, apiRole, apiRole.Writable())
+}
+
+// swagger:operation PUT /1.0/auth/roles/{name} auth auth_role_put
+//
+//	Update the authorization role
+//
+//	Updates the description and permissions of the role.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: role
+//	    description: Role configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthRolePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authRolePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthRolePut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = authRoleValidate(req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			role, err := dbCluster.GetAuthRole(ctx, tx.Tx(), name)
+			if err != nil {
+				return err
+			}
+
+			apiRole, err := role.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			err = localUtil.EtagCheck(r, apiRole.Writable())
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.UpdateAuthRole(ctx, tx.Tx(), name, dbCluster.AuthRole{Name: name, Description: req.Description})
+			if err != nil {
+				return err
+			}
+
+			return dbCluster.UpdateAuthRolePermissions(ctx, tx.Tx(), role.ID, req.Permissions)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.UpdateAuthRole(name, req, "")
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthRoleUpdated.Event(name, request.CreateRequestor(r), nil))
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/auth/roles/{name} auth auth_role_post
+//
+//	Rename the authorization role
+//
+//	Renames an existing authorization role.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: role
+//	    description: Role rename request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthRolePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authRolePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.AuthRolePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = authValidateName(req.Name)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			exists, err := dbCluster.AuthRoleExists(ctx, tx.Tx(), req.Name)
+			if err != nil {
+				return err
+			}
+
+			if exists {
+				return api.StatusErrorf(http.StatusConflict, "Name %q already in use", req.Name)
+			}
+
+			return dbCluster.RenameAuthRole(ctx, tx.Tx(), name, req.Name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.RenameAuthRole(name, req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if isClusterNotification(r) {
+		return response.EmptySyncResponse
+	}
+
+	lc := lifecycle.AuthRoleRenamed.Event(req.Name, request.CreateRequestor(r), logger.Ctx{"old_name": name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation DELETE /1.0/auth/roles/{name} auth auth_role_delete
+//
+//	Delete the authorization role
+//
+//	Removes the authorization role. Roles granted to groups cannot be removed.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authRoleDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			role, err := dbCluster.GetAuthRole(ctx, tx.Tx(), name)
+			if err != nil {
+				return err
+			}
+
+			apiRole, err := role.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			if len(apiRole.UsedBy) > 0 {
+				return api.StatusErrorf(http.StatusBadRequest, "The role is currently granted to groups")
+			}
+
+			return dbCluster.DeleteAuthRole(ctx, tx.Tx(), name)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	err = authRefresh(r.Context(), s, r, func(client incus.InstanceServer) error {
+		return client.DeleteAuthRole(name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isClusterNotification(r) {
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthRoleDeleted.Event(name, request.CreateRequestor(r), nil))
+	}
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var authTokensCmd = APIEndpoint{
+	Path: "auth/tokens",
+
+	Get:  APIEndpointAction{Handler: authTokensGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Post: APIEndpointAction{Handler: authTokensPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var authTokenCmd = APIEndpoint{
+	Path: "auth/tokens/{name}",
+
+	Get:    APIEndpointAction{Handler: authTokenGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanViewSensitive)},
+	Delete: APIEndpointAction{Handler: authTokenDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// authTokenLoad returns the API token matching the filter, failing if it has expired.
+func authTokenLoad(ctx context.Context, s *state.State, filter dbCluster.AuthTokenFilter) (*api.AuthToken, error) {
+	var token *api.AuthToken
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		tokens, err := dbCluster.GetAuthTokens(ctx, tx.Tx(), filter)
+		if err != nil {
+			return err
+		}
+
+		if len(tokens) != 1 {
+			return api.StatusErrorf(http.StatusNotFound, "API token not found")
+		}
+
+		token, err = tokens[0].ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !time.Now().Before(token.ExpiresAt) {
+		return nil, fmt.Errorf("API token %q has expired", token.Name)
+	}
+
+	return token, nil
+}
+
+// authTokenScopeCheck checks that a new API token doesn't get more than the API token it's created with.
+func authTokenScopeCheck(token *api.AuthToken, req api.AuthTokensPost) error {
+	if token.Restricted {
+		if !req.Restricted {
+			return errors.New("API tokens created with a restricted API token must be restricted")
+		}
+
+		for _, projectName := range req.Projects {
+			if !slices.Contains(token.Projects, projectName) {
+				return fmt.Errorf("Project %q isn't allowed by the API token", projectName)
+			}
+		}
+	}
+
+	if len(token.Permissions) > 0 {
+		if len(req.Permissions) == 0 {
+			return errors.New("API tokens created with an API token limited to permissions must be limited to permissions")
+		}
+
+		for _, permission := range req.Permissions {
+			if !slices.Contains(token.Permissions, permission) {
+				return fmt.Errorf("Entitlement %q on %q isn't allowed by the API token", permission.Entitlement, permission.EntityType)
+			}
+		}
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/auth/tokens auth auth_tokens_get
+//
+//	Get the API tokens
+//
+//	Returns a list of API tokens (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/auth/tokens/ci-deploy",
+//	              "/1.0/auth/tokens/webhook"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/auth/tokens?recursion=1 auth auth_tokens_get_recursion1
+//
+//	Get the API tokens
+//
+//	Returns a list of API tokens (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of API tokens
+//	          items:
+//	            $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var tokens []dbCluster.AuthToken
+	var apiTokens []*api.AuthToken
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		tokens, err = dbCluster.GetAuthTokens(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if !recursion {
+			return nil
+		}
+
+		apiTokens = make([]*api.AuthToken, 0, len(tokens))
+		for _, token := range tokens {
+			apiToken, err := token.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			apiTokens = append(apiTokens, apiToken)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion {
+		return response.SyncResponse(true, apiTokens)
+	}
+
+	urls := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		urls = append(urls, api.NewURL().Path(version.APIVersion, "auth", "tokens", token.Name).String())
+	}
+
+	return response.SyncResponse(true, urls)
+}
+
+// swagger:operation POST /1.0/auth/tokens auth auth_tokens_post
+//
+//	Add an API token
+//
+//	Creates a new API token and returns its secret, which can't be retrieved afterwards.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: token
+//	    description: API token
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/AuthTokensPost"
+//	responses:
+//	  "201":
+//	    description: API token secret
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthTokenSecret"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokensPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.AuthTokensPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = authValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Identity == "" {
+		return response.BadRequest(errors.New("API tokens require an identity"))
+	}
+
+	if !time.Now().Before(req.ExpiresAt) {
+		return response.BadRequest(errors.New("API tokens require an expiry date in the future"))
+	}
+
+	err = authRoleValidate(api.AuthRolePut{Permissions: req.Permissions})
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// API tokens can't be used to create API tokens with a wider scope.
+	token, ok := r.Context().Value(request.CtxAuthToken).(*api.AuthToken)
+	if ok && token != nil {
+		err = authTokenScopeCheck(token, req)
+		if err != nil {
+			return response.Forbidden(err)
+		}
+	}
+
+	secret, err := internalUtil.RandomHexString(32)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	secret = request.AuthTokenPrefix + secret
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		token := dbCluster.AuthToken{
+			Name:         req.Name,
+			Description:  req.Description,
+			Identity:     req.Identity,
+			SecretHash:   request.HashAuthToken(secret),
+			Restricted:   req.Restricted,
+			CreationDate: time.Now().UTC(),
+			ExpiryDate:   req.ExpiresAt.UTC(),
+		}
+
+		tokenID, err := dbCluster.CreateAuthToken(ctx, tx.Tx(), token)
+		if err != nil {
+			return err
+		}
+
+		if req.Restricted {
+			err = dbCluster.UpdateAuthTokenProjects(ctx, tx.Tx(), int(tokenID), req.Projects)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					return api.StatusErrorf(http.StatusBadRequest, "Project not found: %w", err)
+				}
+
+				return err
+			}
+		}
+
+		return dbCluster.UpdateAuthTokenPermissions(ctx, tx.Tx(), int(tokenID), req.Permissions)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.AuthTokenCreated.Event(req.Name, request.CreateRequestor(r), map[string]any{"identity": req.Identity})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, api.AuthTokenSecret{Name: req.Name, Secret: secret}, lc.Source)
+}
+
+// swagger:operation GET /1.0/auth/tokens/{name} auth auth_token_get
+//
+//	Get the API token
+//
+//	Gets a specific API token.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API token
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/AuthToken"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var apiToken *api.AuthToken
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		token, err := dbCluster.GetAuthToken(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		apiToken, err = token.ToAPI(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, apiToken)
+}
+
+// swagger:operation DELETE /1.0/auth/tokens/{name} auth auth_token_delete
+//
+//	Revoke the API token
+//
+//	Removes the API token, immediately rejecting any further request made with it.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func authTokenDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteAuthToken(ctx, tx.Tx(), name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.AuthTokenDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
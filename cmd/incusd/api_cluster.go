@@ -24,6 +24,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/certificate"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	clusterConfig "github.com/lxc/incus/v6/internal/server/cluster/config"
+	"github.com/lxc/incus/v6/internal/server/cluster/fencing"
 	clusterRequest "github.com/lxc/incus/v6/internal/server/cluster/request"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
@@ -35,6 +36,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	"github.com/lxc/incus/v6/internal/server/state"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
@@ -316,12 +318,28 @@ func clusterPutBootstrap(d *Daemon, r *http.Request, req api.ClusterPut) respons
 		// Start clustering tasks
 		d.startClusterTasks()
 
+		// Keep the standalone certificate around to re-encrypt the secrets.
+		oldCert := s.Endpoints.NetworkCert()
+
 		err := cluster.Bootstrap(s, d.gateway, req.ServerName)
 		if err != nil {
 			d.stopClusterTasks()
 			return err
 		}
 
+		// Re-encrypt the secrets and the replication remote client keys with the key derived from the new cluster certificate.
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			err := secrets.Reencrypt(ctx, tx.Tx(), oldCert, s.Endpoints.NetworkCert())
+			if err != nil {
+				return err
+			}
+
+			return replicationRemotesReencrypt(ctx, tx.Tx(), oldCert, s.Endpoints.NetworkCert())
+		})
+		if err != nil {
+			return fmt.Errorf("Failed re-encrypting secrets: %w", err)
+		}
+
 		// Restart the networks.
 		err = networkStartup(s)
 		if err != nil {
@@ -491,9 +509,18 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 		UserAgent:     version.UserAgent,
 	}
 
+	// Check whether the join token is for replacing an existing member.
+	replace := false
+	if req.ClusterToken != "" {
+		joinToken, err := internalUtil.JoinTokenDecode(req.ClusterToken)
+		if err == nil {
+			replace = joinToken.Replace
+		}
+	}
+
 	// Asynchronously join the cluster.
 	run := func(op *operations.Operation) error {
-		logger.Debug("Running cluster join operation")
+		logger.Debug("Running cluster join operation", logger.Ctx{"replace": replace})
 
 		// If the user has provided a join token, setup the trust
 		// relationship by adding our own certificate to the cluster.
@@ -524,10 +551,14 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 		}
 
 		// Verify if a node with the same name already exists in the cluster.
-		for _, member := range members {
-			if member.ServerName == req.ServerName {
-				return fmt.Errorf("The cluster already has a member with name: %s", req.ServerName)
-			}
+		memberExists := slices.ContainsFunc(members, func(member api.ClusterMember) bool {
+			return member.ServerName == req.ServerName
+		})
+
+		if memberExists && !replace {
+			return fmt.Errorf("The cluster already has a member with name: %s", req.ServerName)
+		} else if !memberExists && replace {
+			return fmt.Errorf("The cluster has no member with name: %s", req.ServerName)
 		}
 
 		// As ServerAddress field is required to be set it means that we're using the new join API
@@ -558,8 +589,28 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 
 		d.events.SetLocalLocation(d.serverName)
 
+		memberConfig := req.MemberConfig
+		var adoptedPools []string
+
+		if replace {
+			// Inherit the member-specific configuration of the replaced member.
+			memberConfig, err = clusterReplaceMemberConfig(client, req.ServerName, req.MemberConfig)
+			if err != nil {
+				return err
+			}
+
+			// Re-use the storage of the replaced member if it's available on this server.
+			var cleanup func()
+			adoptedPools, cleanup, err = clusterReplaceAdoptStoragePools(s, client, memberConfig)
+			if err != nil {
+				return fmt.Errorf("Failed to adopt existing storage: %w", err)
+			}
+
+			revert.Add(cleanup)
+		}
+
 		// Create all storage pools and networks.
-		err = clusterInitMember(localClient, client, req.MemberConfig)
+		err = clusterInitMember(localClient, client, memberConfig, adoptedPools)
 		if err != nil {
 			return fmt.Errorf("Failed to initialize member: %w", err)
 		}
@@ -623,7 +674,7 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 		}
 
 		// Now request for this node to be added to the list of cluster nodes.
-		info, err := clusterAcceptMember(client, req.ServerName, localHTTPSAddress, cluster.SchemaVersion, version.APIExtensionsCount(), pools, networks)
+		info, err := clusterAcceptMember(client, req.ServerName, localHTTPSAddress, cluster.SchemaVersion, version.APIExtensionsCount(), pools, networks, replace)
 		if err != nil {
 			return fmt.Errorf("Failed request to add member: %w", err)
 		}
@@ -701,17 +752,19 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 			return err
 		}
 
-		// Add the new node to the default cluster group.
-		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-			err := tx.AddNodeToClusterGroup(ctx, "default", req.ServerName)
+		// Add the new node to the default cluster group (a replaced member keeps its groups).
+		if !replace {
+			err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+				err := tx.AddNodeToClusterGroup(ctx, "default", req.ServerName)
+				if err != nil {
+					return fmt.Errorf("Failed to add new member to the default cluster group: %w", err)
+				}
+
+				return nil
+			})
 			if err != nil {
-				return fmt.Errorf("Failed to add new member to the default cluster group: %w", err)
+				return err
 			}
-
-			return nil
-		})
-		if err != nil {
-			return err
 		}
 
 		// Start clustering tasks.
@@ -795,7 +848,14 @@ func clusterPutJoin(d *Daemon, r *http.Request, req api.ClusterPut) response.Res
 		// Update the cert cache again to add client and metric certs to the cache.
 		s.UpdateCertificateCache()
 
-		s.Events.SendLifecycle(request.ProjectParam(r), lifecycle.ClusterMemberAdded.Event(req.ServerName, op.Requestor(), nil))
+		if replace {
+			// Make the instances of the replaced member usable on this server.
+			clusterReplaceRecoverInstances(s, localClient, adoptedPools)
+
+			s.Events.SendLifecycle(request.ProjectParam(r), lifecycle.ClusterMemberReplaced.Event(req.ServerName, op.Requestor(), map[string]any{"address": localHTTPSAddress}))
+		} else {
+			s.Events.SendLifecycle(request.ProjectParam(r), lifecycle.ClusterMemberAdded.Event(req.ServerName, op.Requestor(), nil))
+		}
 
 		revert.Success()
 		return nil
@@ -899,7 +959,8 @@ func clusterPutDisable(d *Daemon, r *http.Request, req api.ClusterPut) response.
 
 // clusterInitMember initializes storage pools and networks on this member. We pass two client instances, one
 // connected to ourselves (the joining member) and one connected to the target cluster member to join.
-func clusterInitMember(d incus.InstanceServer, client incus.InstanceServer, memberConfig []api.ClusterMemberConfigKey) error {
+// The storage pools listed in skipPools are already set up on this member and are left untouched.
+func clusterInitMember(d incus.InstanceServer, client incus.InstanceServer, memberConfig []api.ClusterMemberConfigKey, skipPools []string) error {
 	data := api.InitLocalPreseed{}
 
 	// Fetch all pools currently defined in the cluster.
@@ -911,8 +972,8 @@ func clusterInitMember(d incus.InstanceServer, client incus.InstanceServer, memb
 	// Merge the returned storage pools configs with the node-specific
 	// configs provided by the user.
 	for _, pool := range pools {
-		// Skip pending pools.
-		if pool.Status == "Pending" {
+		// Skip pending pools and the ones already set up.
+		if pool.Status == "Pending" || slices.Contains(skipPools, pool.Name) {
 			continue
 		}
 
@@ -1041,7 +1102,7 @@ func clusterInitMember(d incus.InstanceServer, client incus.InstanceServer, memb
 // Perform a request to the /internal/cluster/accept endpoint to check if a new
 // node can be accepted into the cluster and obtain joining information such as
 // the cluster private certificate.
-func clusterAcceptMember(client incus.InstanceServer, name string, address string, schema int, apiExt int, pools []api.StoragePool, networks []api.InitNetworksProjectPost) (*internalClusterPostAcceptResponse, error) {
+func clusterAcceptMember(client incus.InstanceServer, name string, address string, schema int, apiExt int, pools []api.StoragePool, networks []api.InitNetworksProjectPost, replace bool) (*internalClusterPostAcceptResponse, error) {
 	architecture, err := osarch.ArchitectureGetLocalID()
 	if err != nil {
 		return nil, err
@@ -1055,6 +1116,7 @@ func clusterAcceptMember(client incus.InstanceServer, name string, address strin
 		StoragePools: pools,
 		Networks:     networks,
 		Architecture: architecture,
+		Replace:      replace,
 	}
 
 	info := &internalClusterPostAcceptResponse{}
@@ -1224,6 +1286,12 @@ func clusterNodesGet(d *Daemon, r *http.Request) response.Response {
 	}
 
 	if recursion {
+		if !clusterMemberCanViewSensitive(r.Context(), s, r) {
+			for i := range membersInfo {
+				clusterMemberHideSensitive(&membersInfo[i])
+			}
+		}
+
 		return response.SyncResponse(true, membersInfo)
 	}
 
@@ -1299,10 +1367,20 @@ func clusterNodesPost(d *Daemon, r *http.Request) response.Response {
 		}
 
 		// Filter to online members.
+		found := false
 		for _, member := range members {
 			// Verify if a node with the same name already exists in the cluster.
 			if member.Name == req.ServerName {
-				return fmt.Errorf("The cluster already has a member with name: %s", req.ServerName)
+				if !req.Replace {
+					return fmt.Errorf("The cluster already has a member with name: %s", req.ServerName)
+				}
+
+				// Only offline members can be replaced.
+				if !member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+					return api.StatusErrorf(http.StatusBadRequest, "Cluster member %q is still online", req.ServerName)
+				}
+
+				found = true
 			}
 
 			if member.State == db.ClusterMemberStateEvacuated || member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
@@ -1312,6 +1390,10 @@ func clusterNodesPost(d *Daemon, r *http.Request) response.Response {
 			onlineNodeAddresses = append(onlineNodeAddresses, member.Address)
 		}
 
+		if req.Replace && !found {
+			return api.StatusErrorf(http.StatusNotFound, "The cluster has no member with name: %s", req.ServerName)
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -1378,6 +1460,10 @@ func clusterNodesPost(d *Daemon, r *http.Request) response.Response {
 		"expiresAt":   expiry,
 	}
 
+	if req.Replace {
+		meta["replace"] = true
+	}
+
 	resources := map[string][]api.URL{}
 	resources["cluster"] = []api.URL{}
 
@@ -1493,9 +1579,33 @@ func clusterNodeGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	if !clusterMemberCanViewSensitive(r.Context(), s, r) {
+		clusterMemberHideSensitive(memberInfo)
+	}
+
 	return response.SyncResponseETag(true, memberInfo, memberInfo.ClusterMemberPut)
 }
 
+// clusterMemberSensitiveKeys is the prefix of the member configuration keys holding credentials.
+const clusterMemberSensitiveKeys = "fencing."
+
+// clusterMemberCanViewSensitive returns whether the requestor may see the credentials in the member configuration.
+func clusterMemberCanViewSensitive(ctx context.Context, s *state.State, r *http.Request) bool {
+	return s.Authorizer.CheckPermission(ctx, r, auth.ObjectServer(), auth.EntitlementCanViewSensitive) == nil
+}
+
+// clusterMemberHideSensitive removes the keys holding credentials from the configuration of a member.
+func clusterMemberHideSensitive(member *api.ClusterMember) {
+	config := make(map[string]string, len(member.Config))
+	for key, value := range member.Config {
+		if !strings.HasPrefix(key, clusterMemberSensitiveKeys) {
+			config[key] = value
+		}
+	}
+
+	member.Config = config
+}
+
 // swagger:operation PATCH /1.0/cluster/members/{name} cluster cluster_member_patch
 //
 //	Partially update the cluster member
@@ -1630,6 +1740,12 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 		return response.SmartError(err)
 	}
 
+	// Requestors who can't see the credentials don't get to change them either.
+	canViewSensitive := clusterMemberCanViewSensitive(r.Context(), s, r)
+	if !canViewSensitive {
+		clusterMemberHideSensitive(memberInfo)
+	}
+
 	// Validate the request is fine
 	err = localUtil.EtagCheck(r, memberInfo.ClusterMemberPut)
 	if err != nil {
@@ -1689,6 +1805,42 @@ func updateClusterNode(s *state.State, gateway *cluster.Gateway, r *http.Request
 			}
 		}
 
+		if !canViewSensitive {
+			if req.Config == nil {
+				req.Config = map[string]string{}
+			}
+
+			for k := range req.Config {
+				if strings.HasPrefix(k, clusterMemberSensitiveKeys) && req.Config[k] != nodeInfo.Config[k] {
+					return api.StatusErrorf(http.StatusForbidden, "Not allowed to change %q", k)
+				}
+			}
+
+			// Keep the current credentials.
+			for k, v := range nodeInfo.Config {
+				if strings.HasPrefix(k, clusterMemberSensitiveKeys) {
+					req.Config[k] = v
+				}
+			}
+		}
+
+		// Volatile keys are managed by the server.
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		for k := range req.Config {
+			if strings.HasPrefix(k, "volatile.") {
+				delete(req.Config, k)
+			}
+		}
+
+		for k, v := range nodeInfo.Config {
+			if strings.HasPrefix(k, "volatile.") {
+				req.Config[k] = v
+			}
+		}
+
 		// Update node config.
 		err = tx.UpdateNodeConfig(ctx, nodeInfo.ID, req.Config)
 		if err != nil {
@@ -1769,6 +1921,70 @@ func clusterRolesChanged(oldRoles []db.ClusterRole, newRoles []db.ClusterRole) b
 // clusterValidateConfig validates the configuration keys/values for cluster members.
 func clusterValidateConfig(config map[string]string) error {
 	clusterConfigKeys := map[string]func(value string) error{
+		// gendoc:generate(entity=cluster, group=cluster, key=fencing.address)
+		// Address of the BMC used to fence the member (host name or IP address for `ipmi`, URL for `redfish`).
+		// With the `command` driver, it's passed to the command in the `INCUS_FENCING_ADDRESS` environment variable.
+		// ---
+		//  type: string
+		//  shortdesc: Address of the member's BMC
+		"fencing.address": validate.IsAny,
+
+		// gendoc:generate(entity=cluster, group=cluster, key=fencing.certificate)
+		// PEM encoded certificate to trust when connecting to the BMC with the `redfish` driver.
+		// If not set, the system's certificate authorities are used.
+		// ---
+		//  type: string
+		//  shortdesc: Certificate of the member's BMC
+		"fencing.certificate": validate.IsAny,
+
+		// gendoc:generate(entity=cluster, group=cluster, key=fencing.command)
+		// Command to run on the cluster leader to fence the member when using the `command` driver.
+		// The member name is passed as the last argument and the command must only succeed once the member is confirmed off.
+		// ---
+		//  type: string
+		//  shortdesc: Fencing command
+		"fencing.command": validate.IsAny,
+
+		// gendoc:generate(entity=cluster, group=cluster, key=fencing.driver)
+		// Possible values are `ipmi`, `redfish` and `command`. See
+		// {ref}`cluster-fencing` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Driver used to fence the member before healing it
+		"fencing.driver": validate.Optional(validate.IsOneOf(fencing.Drivers()...)),
+
+		// gendoc:generate(entity=cluster, group=cluster, key=fencing.password)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: Password for the member's BMC
+		"fencing.password": validate.IsAny,
+
+		// gendoc:generate(entity=cluster, group=cluster, key=fencing.redfish.system)
+		// Identifier of the Redfish computer system to power off.
+		// If not set, the BMC must only have one system.
+		// ---
+		//  type: string
+		//  shortdesc: Redfish system identifier
+		"fencing.redfish.system": validate.Optional(validate.IsURLSegmentSafe),
+
+		// gendoc:generate(entity=cluster, group=cluster, key=fencing.username)
+		//
+		// ---
+		//  type: string
+		//  shortdesc: User name for the member's BMC
+		"fencing.username": validate.IsAny,
+
+		// gendoc:generate(entity=cluster, group=cluster, key=maintenance.windows)
+		// Comma-and-space-separated list of windows, each made of a cron expression (`<minute> <hour> <dom> <month> <dow>`) or schedule alias (`@hourly`, `@daily`, `@weekly`, `@monthly`, `@yearly`) followed by a duration (for example, `0 22 * * 1-5 8h, @weekly 24h`).
+		// When set, disruptive scheduled work is only performed on the member while one of the windows is open.
+		// This takes precedence over the maintenance windows of the member's cluster groups.
+		// See {ref}`cluster-maintenance-windows` for more information.
+		// ---
+		//  type: string
+		//  shortdesc: Maintenance windows of the member
+		"maintenance.windows": validate.Optional(validateMaintenanceWindows),
+
 		// gendoc:generate(entity=cluster, group=cluster, key=scheduler.instance)
 		// Possible values are `all`, `manual`, and `group`. See
 		// {ref}`clustering-instance-placement` for more information.
@@ -1791,6 +2007,11 @@ func clusterValidateConfig(config map[string]string) error {
 			continue
 		}
 
+		// Volatile keys are managed by the server and can't be changed.
+		if strings.HasPrefix(k, "volatile.") {
+			continue
+		}
+
 		validator, ok := clusterConfigKeys[k]
 		if !ok {
 			return fmt.Errorf("Invalid cluster configuration key %q", k)
@@ -2201,7 +2422,13 @@ func internalClusterPostAccept(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	nodes, err := cluster.Accept(s, d.gateway, req.Name, req.Address, req.Schema, req.API, req.Architecture)
+	var nodes []db.RaftNode
+	if req.Replace {
+		nodes, err = cluster.Replace(s, d.gateway, req.Name, req.Address, req.Schema, req.API, req.Architecture)
+	} else {
+		nodes, err = cluster.Accept(s, d.gateway, req.Name, req.Address, req.Schema, req.API, req.Architecture)
+	}
+
 	if err != nil {
 		return response.BadRequest(err)
 	}
@@ -2229,6 +2456,7 @@ type internalClusterPostAcceptRequest struct {
 	StoragePools []api.StoragePool             `json:"storage_pools" yaml:"storage_pools"`
 	Networks     []api.InitNetworksProjectPost `json:"networks" yaml:"networks"`
 	Architecture int                           `json:"architecture" yaml:"architecture"`
+	Replace      bool                          `json:"replace" yaml:"replace"`
 }
 
 // A Response for the /internal/cluster/accept endpoint.
@@ -2342,6 +2570,57 @@ again:
 	goto again
 }
 
+// clusterRolesNeedSpreading returns whether a database role is held more than once within a failure domain while
+// an online member of a failure domain without that role could take it over.
+func clusterRolesNeedSpreading(s *state.State, members map[int64]cluster.APIHeartbeatMember) (bool, error) {
+	var domains map[string]uint64
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		domains, err = tx.GetNodesFailureDomains(ctx)
+
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	for _, role := range []db.RaftRole{db.RaftVoter, db.RaftStandBy} {
+		held := map[uint64]int{}
+		shared := false
+		for _, member := range members {
+			if member.Online && db.RaftRole(member.RaftRole) == role {
+				held[domains[member.Address]]++
+				if held[domains[member.Address]] > 1 {
+					shared = true
+				}
+			}
+		}
+
+		if !shared {
+			continue
+		}
+
+		for _, member := range members {
+			memberRole := db.RaftRole(member.RaftRole)
+			if !member.Online || member.RaftID == 0 || memberRole == role {
+				continue
+			}
+
+			// Voters can be picked from the stand-by and spare members, stand-bys from the spare ones.
+			if role == db.RaftStandBy && memberRole != db.RaftSpare {
+				continue
+			}
+
+			if held[domains[member.Address]] == 0 {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
 // Check if there are nodes not part of the raft configuration and add them in
 // case.
 func upgradeNodesWithoutRaftRole(s *state.State, gateway *cluster.Gateway) error {
@@ -2747,9 +3026,9 @@ func clusterNodeStateGet(d *Daemon, r *http.Request) response.Response {
 
 // swagger:operation POST /1.0/cluster/members/{name}/state cluster cluster_member_state_post
 //
-//	Evacuate or restore a cluster member
+//	Change the state of a cluster member
 //
-//	Evacuates or restores a cluster member.
+//	Evacuates, restores, cordons or uncordons a cluster member.
 //
 //	---
 //	consumes:
@@ -2903,6 +3182,10 @@ func clusterNodeStatePost(d *Daemon, r *http.Request) response.Response {
 		return operations.OperationResponse(op)
 	} else if req.Action == "restore" {
 		return restoreClusterMember(d, r)
+	} else if req.Action == "cordon" {
+		return cordonClusterMember(s, r, name, true)
+	} else if req.Action == "uncordon" {
+		return cordonClusterMember(s, r, name, false)
 	}
 
 	return response.BadRequest(fmt.Errorf("Unknown action %q", req.Action))
@@ -17,6 +17,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/server/warnings"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
@@ -190,6 +191,35 @@ func updateClusterCertificate(ctx context.Context, s *state.State, gateway *clus
 				}
 			})
 		}
+
+		// Re-encrypt the secrets and the replication remote client keys with the key derived from the new certificate.
+		oldCertInfo := s.Endpoints.NetworkCert()
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			err := secrets.Reencrypt(ctx, tx.Tx(), oldCertInfo, newCertInfo)
+			if err != nil {
+				return err
+			}
+
+			return replicationRemotesReencrypt(ctx, tx.Tx(), oldCertInfo, newCertInfo)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed re-encrypting secrets: %w", err)
+		}
+
+		reverter.Add(func() {
+			err := s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+				err := secrets.Reencrypt(ctx, tx.Tx(), newCertInfo, oldCertInfo)
+				if err != nil {
+					return err
+				}
+
+				return replicationRemotesReencrypt(ctx, tx.Tx(), newCertInfo, oldCertInfo)
+			})
+			if err != nil {
+				logger.Error("Failed restoring encryption of secrets", logger.Ctx{"err": err})
+			}
+		})
 	}
 
 	err := internalUtil.WriteCert(s.OS.VarDir, "cluster", []byte(req.ClusterCertificate), []byte(req.ClusterCertificateKey), nil)
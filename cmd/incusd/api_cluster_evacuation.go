@@ -19,9 +19,11 @@ import (
 
 	incus "github.com/lxc/incus/v6/client"
 	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/cluster/fencing"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/operations"
@@ -30,12 +32,14 @@ import (
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/server/warnings"
 	"github.com/lxc/incus/v6/shared/api"
 	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/osarch"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/subprocess"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type (
@@ -53,6 +57,12 @@ type evacuateOpts struct {
 	op              *operations.Operation
 }
 
+// evacuateCordonedKey is set on cluster members which were cordoned when evacuated, so they're cordoned again once
+// restored.
+const evacuateCordonedKey = "volatile.evacuate.cordoned"
+
+// evacuateClusterSetState changes the state of a cluster member. Members which were cordoned before being evacuated
+// go back to being cordoned rather than created once restored.
 func evacuateClusterSetState(s *state.State, name string, newState int) error {
 	return s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Get the node.
@@ -69,6 +79,8 @@ func evacuateClusterSetState(s *state.State, name string, newState int) error {
 		if node.State == newState {
 			if newState == db.ClusterMemberStateEvacuated {
 				return fmt.Errorf("Cluster member is already evacuated")
+			} else if newState == db.ClusterMemberStateCordoned {
+				return fmt.Errorf("Cluster member is already cordoned")
 			} else if newState == db.ClusterMemberStateCreated {
 				return fmt.Errorf("Cluster member is already restored")
 			}
@@ -76,6 +88,34 @@ func evacuateClusterSetState(s *state.State, name string, newState int) error {
 			return fmt.Errorf("Cluster member is already in requested state")
 		}
 
+		// An evacuated member is already unavailable, cordoning it would lose track of the evacuation.
+		if newState == db.ClusterMemberStateCordoned && node.State == db.ClusterMemberStateEvacuated {
+			return fmt.Errorf("Cannot cordon an evacuated cluster member")
+		}
+
+		// Remember whether an evacuated member was cordoned, so that it's cordoned again when restored.
+		if node.State == db.ClusterMemberStateEvacuated || newState == db.ClusterMemberStateEvacuated {
+			config := util.CloneMap(node.Config)
+			if config == nil {
+				config = map[string]string{}
+			}
+
+			if node.State == db.ClusterMemberStateCordoned {
+				config[evacuateCordonedKey] = "true"
+			} else if newState == db.ClusterMemberStateCreated && util.IsTrue(config[evacuateCordonedKey]) {
+				newState = db.ClusterMemberStateCordoned
+			}
+
+			if newState != db.ClusterMemberStateEvacuated {
+				delete(config, evacuateCordonedKey)
+			}
+
+			err = tx.UpdateNodeConfig(ctx, node.ID, config)
+			if err != nil {
+				return fmt.Errorf("Failed to update cluster member config: %w", err)
+			}
+		}
+
 		// Set node status to requested value.
 		err = tx.UpdateNodeStatus(node.ID, newState)
 		if err != nil {
@@ -273,6 +313,16 @@ func restoreClusterMember(d *Daemon, r *http.Request) response.Response {
 	// List the instances.
 	var dbInstances []dbCluster.Instance
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		member, err := tx.GetNodeByName(ctx, originName)
+		if err != nil {
+			return fmt.Errorf("Failed to get cluster member by name: %w", err)
+		}
+
+		// Cordoned members have nothing to restore.
+		if member.State == db.ClusterMemberStateCordoned {
+			return api.StatusErrorf(http.StatusBadRequest, "Cluster member is cordoned, uncordon it instead")
+		}
+
 		dbInstances, err = dbCluster.GetInstances(ctx, tx.Tx())
 		if err != nil {
 			return fmt.Errorf("Failed to get instances: %w", err)
@@ -392,6 +442,61 @@ func restoreClusterMember(d *Daemon, r *http.Request) response.Response {
 	return operations.OperationResponse(op)
 }
 
+// cordonClusterMember cordons or uncordons a cluster member.
+// A cordoned member keeps running its instances but isn't considered for new placements,
+// rebalancing or image replication.
+func cordonClusterMember(s *state.State, r *http.Request, name string, cordon bool) response.Response {
+	opType := operationtype.ClusterMemberCordon
+	if !cordon {
+		opType = operationtype.ClusterMemberUncordon
+	}
+
+	run := func(op *operations.Operation) error {
+		if cordon {
+			err := evacuateClusterSetState(s, name, db.ClusterMemberStateCordoned)
+			if err != nil {
+				return err
+			}
+
+			s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberCordoned.Event(name, op.Requestor(), nil))
+
+			return nil
+		}
+
+		err := s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			member, err := tx.GetNodeByName(ctx, name)
+			if err != nil {
+				return fmt.Errorf("Failed to get cluster member by name: %w", err)
+			}
+
+			if member.State != db.ClusterMemberStateCordoned {
+				return fmt.Errorf("Cluster member isn't cordoned")
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = evacuateClusterSetState(s, name, db.ClusterMemberStateCreated)
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberUncordoned.Event(name, op.Requestor(), nil))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, opType, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
 func restoreClusterMemberFunc(inst instance.Instance, op *operations.Operation, originName string, r *http.Request, s *state.State) error {
 	var err error
 	var source incus.InstanceServer
@@ -572,6 +677,12 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 		return nil, nil, err
 	}
 
+	// Only keep the members with enough capacity left for the instance.
+	candidateMembers, err = clusterGroupCapacityFilter(ctx, s, candidateMembers, inst.Project().Name, inst.Name(), inst.Type(), inst.ExpandedConfig())
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed placing instance %q in project %q: %w", inst.Name(), inst.Project().Name, err)
+	}
+
 	// Run instance placement scriptlet if enabled.
 	if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 		leaderAddress, err := s.Cluster.LeaderAddress()
@@ -670,6 +781,23 @@ func autoHealClusterTask(d *Daemon) (task.Func, task.Schedule) {
 					continue
 				}
 
+				// Make sure the dead system is really off before moving its instances elsewhere.
+				if fencing.IsConfigured(member.Config) {
+					err := fenceClusterMember(ctx, s, member)
+					if err != nil {
+						// Server couldn't be confirmed off, not risking auto-healing.
+						continue
+					}
+
+					offlineMembers = append(offlineMembers, member)
+					continue
+				}
+
+				if s.GlobalConfig.ClusterHealingRequireFencing() {
+					clusterMemberFencingWarning(ctx, s, member, "No fencing configured")
+					continue
+				}
+
 				// As an extra safety net, make sure the dead system doesn't still respond on the network.
 				hostAddress, _, err := net.SplitHostPort(member.Address)
 				if err == nil {
@@ -722,6 +850,40 @@ func autoHealClusterTask(d *Daemon) (task.Func, task.Schedule) {
 	return f, task.Every(time.Minute)
 }
 
+// clusterMemberFencingWarning records a warning about a cluster member which couldn't be fenced.
+func clusterMemberFencingWarning(ctx context.Context, s *state.State, member db.NodeInfo, message string) {
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, "", dbCluster.TypeNode, int(member.ID), warningtype.ClusterMemberFencingFailed, message)
+	})
+	if err != nil {
+		logger.Warn("Failed to create warning", logger.Ctx{"err": err})
+	}
+}
+
+// fenceClusterMember powers off an offline cluster member using its fencing configuration.
+func fenceClusterMember(ctx context.Context, s *state.State, member db.NodeInfo) error {
+	logger.Info("Fencing cluster member", logger.Ctx{"server": member.Name, "driver": member.Config["fencing.driver"]})
+
+	err := fencing.Fence(ctx, member.Name, member.Config)
+	if err != nil {
+		logger.Error("Failed fencing cluster member", logger.Ctx{"server": member.Name, "err": err})
+		s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberFencingFailed.Event(member.Name, nil, map[string]any{"error": err.Error()}))
+		clusterMemberFencingWarning(ctx, s, member, err.Error())
+
+		return err
+	}
+
+	logger.Info("Fenced cluster member", logger.Ctx{"server": member.Name})
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ClusterMemberFenced.Event(member.Name, nil, nil))
+
+	err = warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, "", warningtype.ClusterMemberFencingFailed, dbCluster.TypeNode, int(member.ID))
+	if err != nil {
+		logger.Warn("Failed to resolve warning", logger.Ctx{"err": err})
+	}
+
+	return nil
+}
+
 func healClusterMember(d *Daemon, op *operations.Operation, name string) error {
 	s := d.State()
 
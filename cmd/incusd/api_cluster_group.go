@@ -8,14 +8,18 @@ import (
 	"net/http"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 
 	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/cluster/capacity"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
@@ -325,6 +329,8 @@ func clusterGroupGet(d *Daemon, r *http.Request) response.Response {
 	}
 
 	var apiGroup *api.ClusterGroup
+	var members []db.NodeInfo
+	var memberLimits map[string]*capacity.Limits
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Get the cluster group.
 		group, err := dbCluster.GetClusterGroup(ctx, tx.Tx(), name)
@@ -347,12 +353,60 @@ func clusterGroupGet(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		// Get the capacity limits of the members if the group has any.
+		groupLimits, err := capacity.ParseLimits(apiGroup.Config)
+		if err != nil {
+			return err
+		}
+
+		if groupLimits == nil {
+			return nil
+		}
+
+		memberLimits, err = capacity.MemberLimits(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		members, err = tx.GetNodes(ctx)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	// Report the resource allocation of the online members.
+	groupMembers := []db.NodeInfo{}
+	for _, member := range members {
+		_, ok := memberLimits[member.Name]
+		if !ok || !slices.Contains(apiGroup.Members, member.Name) || member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			continue
+		}
+
+		groupMembers = append(groupMembers, member)
+	}
+
+	if len(groupMembers) > 0 {
+		capacities, memberErrors, err := clusterGroupMemberCapacities(r.Context(), s, groupMembers, memberLimits, "", "")
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		for _, member := range groupMembers {
+			err := memberErrors[member.Name]
+			if err != nil {
+				logger.Warn("Failed getting cluster member capacity", logger.Ctx{"member": member.Name, "err": err})
+				continue
+			}
+
+			apiGroup.Capacity = append(apiGroup.Capacity, *capacities[member.Name])
+		}
+	}
+
 	return response.SyncResponseETag(true, apiGroup, apiGroup.ClusterGroupPut)
 }
 
@@ -888,7 +942,42 @@ func clusterGroupValidateName(name string) error {
 
 // clusterGroupValidate validates the configuration keys/values for cluster groups.
 func clusterGroupValidate(config map[string]string) error {
-	configKeys := map[string]func(value string) error{}
+	configKeys := map[string]func(value string) error{
+		// gendoc:generate(entity=cluster_group, group=common, key=limits.cpu.overcommit)
+		// Maximum ratio between the number of CPUs allocated to instances and the number of CPU threads of each member of the group.
+		//
+		// For example, `1.0` prevents any overcommit while `4.0` allows four allocated CPUs per CPU thread.
+		// Instances placed on the group members must then set `limits.cpu` (virtual machines default to one CPU).
+		// ---
+		//  type: string
+		//  shortdesc: CPU overcommit ratio
+		"limits.cpu.overcommit": validate.Optional(clusterGroupValidateOvercommit),
+
+		// gendoc:generate(entity=cluster_group, group=common, key=limits.instances)
+		// Maximum number of instances on each member of the group.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of instances per member
+		"limits.instances": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=cluster_group, group=common, key=limits.memory.overcommit)
+		// Maximum ratio between the memory allocated to instances and the memory of each member of the group.
+		//
+		// For example, `0.9` keeps 10% of the memory available to the host while `1.5` allows allocating 50% more memory than available.
+		// Instances placed on the group members must then set `limits.memory` (virtual machines default to 1 GiB).
+		// ---
+		//  type: string
+		//  shortdesc: Memory overcommit ratio
+		"limits.memory.overcommit": validate.Optional(clusterGroupValidateOvercommit),
+
+		// gendoc:generate(entity=cluster_group, group=common, key=maintenance.windows)
+		// Maintenance windows of the members of the group, in the same format as the {config:option}`cluster-cluster:maintenance.windows` member configuration key.
+		// The windows of all the groups of a member are combined, unless the member sets its own.
+		// ---
+		//  type: string
+		//  shortdesc: Maintenance windows of the group members
+		"maintenance.windows": validate.Optional(validateMaintenanceWindows),
+	}
 
 	// Add architecture keys.
 	for _, arch := range osarch.SupportedArchitectures() {
@@ -973,3 +1062,137 @@ func clusterGroupFill(ctx context.Context, s *state.State, servers []string, req
 
 	return nil
 }
+
+// clusterGroupValidateOvercommit validates an overcommit ratio.
+func clusterGroupValidateOvercommit(value string) error {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid overcommit ratio %q", value)
+	}
+
+	if ratio <= 0 {
+		return fmt.Errorf("Overcommit ratio must be greater than zero")
+	}
+
+	return nil
+}
+
+// clusterGroupMemberResources returns the number of CPU threads and the memory of a cluster member.
+// The resources are cached as they rarely change.
+func clusterGroupMemberResources(s *state.State, member db.NodeInfo) (capacity.Resources, error) {
+	if member.Name == s.ServerName {
+		return capacity.MemberResources(member.Name, capacity.LocalResources)
+	}
+
+	return capacity.MemberResources(member.Name, func() (capacity.Resources, error) {
+		client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err != nil {
+			return capacity.Resources{}, err
+		}
+
+		res, err := client.GetServerResources()
+		if err != nil {
+			return capacity.Resources{}, err
+		}
+
+		return capacity.Resources{CPU: int64(res.CPU.Total), Memory: int64(res.Memory.Total)}, nil
+	})
+}
+
+// clusterGroupMemberCapacities returns the resource allocation of the given members with capacity limits.
+// Members whose resources can't be retrieved are left out. The instance matching skipProject and skipName isn't
+// accounted for.
+func clusterGroupMemberCapacities(ctx context.Context, s *state.State, members []db.NodeInfo, memberLimits map[string]*capacity.Limits, skipProject string, skipName string) (map[string]*api.ClusterGroupMemberCapacity, map[string]error, error) {
+	memberResources := map[string]capacity.Resources{}
+	memberErrors := map[string]error{}
+	for _, member := range members {
+		_, ok := memberLimits[member.Name]
+		if !ok {
+			continue
+		}
+
+		res, err := clusterGroupMemberResources(s, member)
+		if err != nil {
+			memberErrors[member.Name] = fmt.Errorf("Failed getting resources of cluster member %q: %w", member.Name, err)
+			continue
+		}
+
+		memberResources[member.Name] = res
+	}
+
+	var capacities map[string]*api.ClusterGroupMemberCapacity
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		capacities, err = capacity.Members(ctx, tx, memberResources, memberLimits, skipProject, skipName)
+
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return capacities, memberErrors, nil
+}
+
+// clusterGroupCapacityFilter returns the candidate members with enough capacity left for an instance.
+// An existing instance isn't accounted for on the member it's currently on.
+//
+// This is only a pre-check for placement, the member creating the instance checks its capacity again in the same
+// transaction as it records the instance.
+func clusterGroupCapacityFilter(ctx context.Context, s *state.State, candidates []db.NodeInfo, projectName string, instanceName string, instanceType instancetype.Type, config map[string]string) ([]db.NodeInfo, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	var memberLimits map[string]*capacity.Limits
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		memberLimits, err = capacity.MemberLimits(ctx, tx)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading cluster group limits: %w", err)
+	}
+
+	if len(memberLimits) == 0 {
+		return candidates, nil
+	}
+
+	capacities, memberErrors, err := clusterGroupMemberCapacities(ctx, s, candidates, memberLimits, projectName, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	filtered := make([]db.NodeInfo, 0, len(candidates))
+	for _, member := range candidates {
+		_, ok := memberLimits[member.Name]
+		if ok {
+			err := memberErrors[member.Name]
+			if err == nil {
+				err = capacity.Check(capacities[member.Name], instanceType, config)
+			}
+
+			if err != nil {
+				logger.Debug("Skipping cluster member without enough capacity", logger.Ctx{"member": member.Name, "project": projectName, "instance": instanceName, "err": err})
+				lastErr = err
+				continue
+			}
+		}
+
+		filtered = append(filtered, member)
+	}
+
+	if len(filtered) == 0 {
+		if len(candidates) == 1 {
+			return nil, lastErr
+		}
+
+		return nil, fmt.Errorf("No cluster member has enough capacity left for the instance")
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,549 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	incus "github.com/lxc/incus/v6/client"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var clusterMaintenanceCmd = APIEndpoint{
+	Path: "cluster/maintenance",
+
+	Post: APIEndpointAction{Handler: clusterMaintenancePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// clusterMaintenanceDefaultTimeout is how long to wait by default for a member to be ready to be restored.
+const clusterMaintenanceDefaultTimeout = time.Hour
+
+// clusterMaintenancePollInterval is how often a member is checked while waiting for it.
+const clusterMaintenancePollInterval = 5 * time.Second
+
+// clusterMaintenanceHealthTimeout is how long a restored member has to report itself as online.
+const clusterMaintenanceHealthTimeout = 2 * time.Minute
+
+// swagger:operation POST /1.0/cluster/maintenance cluster cluster_maintenance_post
+//
+//	Perform a rolling maintenance
+//
+//	Evacuates, maintains and restores cluster members one after the other (or a few per cluster group at a time).
+//	For each member, the optional hook is called once the member is evacuated, then the member is optionally
+//	waited for to restart or come back with a new version, before being restored and checked.
+//	The maintenance stops on the first failure, leaving the failed member evacuated.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: maintenance
+//	    description: Maintenance request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ClusterMaintenancePost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterMaintenancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(fmt.Errorf("This server is not clustered"))
+	}
+
+	// Parse the request.
+	req := api.ClusterMaintenancePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = clusterMaintenanceValidate(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Select the members to maintain.
+	var members []db.NodeInfo
+	var delegates []db.NodeInfo
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		allMembers, err := tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		members, err = clusterMaintenanceMembers(allMembers, req)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "%v", err)
+		}
+
+		for _, member := range allMembers {
+			if member.Name != s.ServerName && !member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+				delegates = append(delegates, member)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for _, member := range members {
+		if member.IsOffline(s.GlobalConfig.OfflineThreshold()) {
+			return response.BadRequest(fmt.Errorf("Cluster member %q is offline", member.Name))
+		}
+	}
+
+	run := func(op *operations.Operation) error {
+		return clusterMaintenance(context.Background(), s, op, members, delegates, req)
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterMaintenance, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// clusterMaintenanceValidate validates a maintenance request and fills in the defaults.
+func clusterMaintenanceValidate(req *api.ClusterMaintenancePost) error {
+	if req.Parallel < 0 {
+		return fmt.Errorf("The number of members maintained in parallel can't be negative")
+	}
+
+	if req.Parallel == 0 {
+		req.Parallel = 1
+	}
+
+	if req.Timeout < 0 {
+		return fmt.Errorf("The timeout can't be negative")
+	}
+
+	if !slices.Contains([]string{"", "restart", "version"}, req.Wait) {
+		return fmt.Errorf("Invalid wait condition %q", req.Wait)
+	}
+
+	if req.Mode != "" {
+		// Use the validator from the instance logic.
+		validator := internalInstance.InstanceConfigKeysAny["cluster.evacuate"]
+		err := validator(req.Mode)
+		if err != nil {
+			return fmt.Errorf("Invalid evacuation mode: %w", err)
+		}
+	}
+
+	if req.Hook != "" {
+		u, err := url.Parse(req.Hook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("The hook must be an HTTP or HTTPS URL")
+		}
+	}
+
+	return nil
+}
+
+// clusterMaintenanceMembers returns the members selected by a maintenance request, in the order they are processed.
+func clusterMaintenanceMembers(allMembers []db.NodeInfo, req api.ClusterMaintenancePost) ([]db.NodeInfo, error) {
+	members := []db.NodeInfo{}
+
+	if len(req.Members) > 0 {
+		for _, name := range req.Members {
+			i := slices.IndexFunc(allMembers, func(member db.NodeInfo) bool { return member.Name == name })
+			if i < 0 {
+				return nil, fmt.Errorf("Cluster member %q doesn't exist", name)
+			}
+
+			members = append(members, allMembers[i])
+		}
+	} else {
+		members = append(members, allMembers...)
+		slices.SortFunc(members, func(a db.NodeInfo, b db.NodeInfo) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+
+	if req.Group != "" {
+		members = slices.DeleteFunc(members, func(member db.NodeInfo) bool {
+			return !slices.Contains(member.Groups, req.Group)
+		})
+	}
+
+	if len(members) == 0 {
+		return nil, fmt.Errorf("No cluster member to maintain")
+	}
+
+	return members, nil
+}
+
+// clusterMaintenanceRounds splits the members into rounds processed one after the other, with at most parallel
+// members of each cluster group in a round.
+func clusterMaintenanceRounds(members []db.NodeInfo, parallel int) [][]db.NodeInfo {
+	rounds := [][]db.NodeInfo{}
+
+	remaining := members
+	for len(remaining) > 0 {
+		round := []db.NodeInfo{}
+		next := []db.NodeInfo{}
+		counts := map[string]int{}
+
+		for _, member := range remaining {
+			groups := member.Groups
+			if len(groups) == 0 {
+				groups = []string{""}
+			}
+
+			fits := true
+			for _, group := range groups {
+				if counts[group] >= parallel {
+					fits = false
+					break
+				}
+			}
+
+			if !fits {
+				next = append(next, member)
+				continue
+			}
+
+			for _, group := range groups {
+				counts[group]++
+			}
+
+			round = append(round, member)
+		}
+
+		rounds = append(rounds, round)
+		remaining = next
+	}
+
+	return rounds
+}
+
+// clusterMaintenance maintains the given members in rounds and stops on the first failure.
+// The local member is handed over to another member last, as it can't wait for itself to restart.
+func clusterMaintenance(ctx context.Context, s *state.State, op *operations.Operation, members []db.NodeInfo, delegates []db.NodeInfo, req api.ClusterMaintenancePost) error {
+	var local *db.NodeInfo
+	others := make([]db.NodeInfo, 0, len(members))
+	for i := range members {
+		if members[i].Name == s.ServerName {
+			local = &members[i]
+			continue
+		}
+
+		others = append(others, members[i])
+	}
+
+	metadataMu := sync.Mutex{}
+	metadata := map[string]any{}
+	progress := map[string]string{}
+	completed := []string{}
+
+	setProgress := func(member string, status string) {
+		metadataMu.Lock()
+		defer metadataMu.Unlock()
+
+		if status == "" {
+			delete(progress, member)
+		} else {
+			progress[member] = status
+		}
+
+		metadata["maintenance_progress"] = progress
+		metadata["maintenance_completed"] = completed
+		_ = op.UpdateMetadata(metadata)
+	}
+
+	for _, round := range clusterMaintenanceRounds(others, req.Parallel) {
+		cordoned, err := clusterMaintenanceCordon(s, round)
+		if err != nil {
+			return err
+		}
+
+		g, groupCtx := errgroup.WithContext(ctx)
+		for _, member := range round {
+			g.Go(func() error {
+				err := clusterMaintenanceMember(groupCtx, s, member, req, func(status string) { setProgress(member.Name, status) })
+				if err != nil {
+					return fmt.Errorf("Failed maintaining cluster member %q: %w", member.Name, err)
+				}
+
+				metadataMu.Lock()
+				completed = append(completed, member.Name)
+				metadataMu.Unlock()
+
+				setProgress(member.Name, "")
+
+				return nil
+			})
+		}
+
+		err = g.Wait()
+		clusterMaintenanceUncordon(ctx, s, cordoned)
+		if err != nil {
+			logger.Error("Cluster maintenance paused", logger.Ctx{"completed": completed, "err": err})
+			return fmt.Errorf("Maintenance paused after %d member(s): %w", len(completed), err)
+		}
+	}
+
+	if local == nil {
+		return nil
+	}
+
+	if len(delegates) == 0 {
+		return fmt.Errorf("No other online cluster member to maintain %q from", local.Name)
+	}
+
+	setProgress(local.Name, fmt.Sprintf("Handing over to %q", delegates[0].Name))
+
+	client, err := cluster.Connect(delegates[0].Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to cluster member %q: %w", delegates[0].Name, err)
+	}
+
+	localReq := req
+	localReq.Members = []string{local.Name}
+	localReq.Group = ""
+
+	delegateOp, err := client.CreateClusterMaintenance(localReq)
+	if err != nil {
+		return fmt.Errorf("Failed handing over maintenance of %q to %q: %w", local.Name, delegates[0].Name, err)
+	}
+
+	err = delegateOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed maintaining cluster member %q: %w", local.Name, err)
+	}
+
+	return nil
+}
+
+// clusterMaintenanceCordon cordons the available members of a round evacuated concurrently, so that they aren't
+// picked as targets for each other's instances. The names of the cordoned members are returned.
+func clusterMaintenanceCordon(s *state.State, round []db.NodeInfo) ([]string, error) {
+	cordoned := []string{}
+	if len(round) < 2 {
+		return cordoned, nil
+	}
+
+	for _, member := range round {
+		if member.State != db.ClusterMemberStateCreated {
+			continue
+		}
+
+		err := evacuateClusterSetState(s, member.Name, db.ClusterMemberStateCordoned)
+		if err != nil {
+			return nil, fmt.Errorf("Failed cordoning cluster member %q: %w", member.Name, err)
+		}
+
+		cordoned = append(cordoned, member.Name)
+	}
+
+	return cordoned, nil
+}
+
+// clusterMaintenanceUncordon uncordons the members cordoned for a round. Members left evacuated by a failure
+// are left as they are.
+func clusterMaintenanceUncordon(ctx context.Context, s *state.State, names []string) {
+	for _, name := range names {
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			member, err := tx.GetNodeByName(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			if member.State != db.ClusterMemberStateCordoned {
+				return nil
+			}
+
+			return tx.UpdateNodeStatus(member.ID, db.ClusterMemberStateCreated)
+		})
+		if err != nil {
+			logger.Warn("Failed uncordoning cluster member after maintenance", logger.Ctx{"member": name, "err": err})
+		}
+	}
+}
+
+// clusterMaintenanceMember evacuates a member, runs the hook, waits for it and restores it.
+// A member which is already evacuated (from a previously paused maintenance) isn't evacuated again.
+func clusterMaintenanceMember(ctx context.Context, s *state.State, member db.NodeInfo, req api.ClusterMaintenancePost, progress func(status string)) error {
+	timeout := clusterMaintenanceDefaultTimeout
+	if req.Timeout > 0 {
+		timeout = time.Duration(req.Timeout) * time.Second
+	}
+
+	client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to cluster member: %w", err)
+	}
+
+	server, _, err := client.GetServer()
+	if err != nil {
+		return fmt.Errorf("Failed getting server information: %w", err)
+	}
+
+	if member.State != db.ClusterMemberStateEvacuated {
+		progress("Evacuating")
+
+		op, err := client.UpdateClusterMemberState(member.Name, api.ClusterMemberStatePost{Action: "evacuate", Mode: req.Mode})
+		if err == nil {
+			err = op.Wait()
+		}
+
+		if err != nil {
+			return fmt.Errorf("Failed evacuating: %w", err)
+		}
+	}
+
+	if req.Hook != "" {
+		progress("Running hook")
+
+		err = clusterMaintenanceHook(ctx, s, req.Hook, member, timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.Wait != "" {
+		progress(fmt.Sprintf("Waiting for %s", req.Wait))
+
+		client, err = clusterMaintenanceWait(ctx, s, member, server.Environment, req.Wait, timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	progress("Restoring")
+
+	op, err := client.UpdateClusterMemberState(member.Name, api.ClusterMemberStatePost{Action: "restore"})
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed restoring: %w", err)
+	}
+
+	progress("Checking health")
+
+	deadline := time.Now().Add(clusterMaintenanceHealthTimeout)
+	for {
+		clusterMember, _, err := client.GetClusterMember(member.Name)
+		if err == nil && clusterMember.Status == "Online" {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("Failed checking health after restore: %w", err)
+			}
+
+			return fmt.Errorf("Cluster member isn't healthy after restore: %s (%s)", clusterMember.Status, clusterMember.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(clusterMaintenancePollInterval):
+		}
+	}
+}
+
+// clusterMaintenanceHook calls the maintenance hook for an evacuated member.
+func clusterMaintenanceHook(ctx context.Context, s *state.State, hook string, member db.NodeInfo, timeout time.Duration) error {
+	body, err := json.Marshal(api.ClusterMaintenanceHook{Member: member.Name, Address: member.Address})
+	if err != nil {
+		return err
+	}
+
+	httpClient, err := localUtil.HTTPClient("", s.Proxy)
+	if err != nil {
+		return err
+	}
+
+	httpClient.Timeout = timeout
+
+	hookReq, err := http.NewRequestWithContext(ctx, http.MethodPost, hook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	hookReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(hookReq)
+	if err != nil {
+		return fmt.Errorf("Failed calling maintenance hook: %w", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Maintenance hook failed: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// clusterMaintenanceWait waits for a member to restart or to come back with a new server or kernel version
+// and returns a new client for it.
+func clusterMaintenanceWait(ctx context.Context, s *state.State, member db.NodeInfo, before api.ServerEnvironment, condition string, timeout time.Duration) (incus.InstanceServer, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		client, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+		if err == nil {
+			server, _, err := client.GetServer()
+			if err == nil {
+				env := server.Environment
+
+				if condition == "restart" && env.ServerPid != before.ServerPid {
+					return client, nil
+				}
+
+				if condition == "version" && (env.ServerVersion != before.ServerVersion || env.KernelVersion != before.KernelVersion) {
+					return client, nil
+				}
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Timed out waiting for %s", condition)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(clusterMaintenancePollInterval):
+		}
+	}
+}
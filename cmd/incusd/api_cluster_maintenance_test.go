@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func maintenanceTestNames(members []db.NodeInfo) []string {
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, member.Name)
+	}
+
+	return names
+}
+
+func TestClusterMaintenanceValidate(t *testing.T) {
+	req := api.ClusterMaintenancePost{}
+	require.NoError(t, clusterMaintenanceValidate(&req))
+	assert.Equal(t, 1, req.Parallel)
+
+	req = api.ClusterMaintenancePost{Parallel: 3, Timeout: 600, Wait: "version", Mode: "migrate", Hook: "https://automation.example.net/maintain"}
+	require.NoError(t, clusterMaintenanceValidate(&req))
+	assert.Equal(t, 3, req.Parallel)
+
+	for _, req := range []api.ClusterMaintenancePost{
+		{Parallel: -1},
+		{Timeout: -1},
+		{Wait: "reboot"},
+		{Mode: "teleport"},
+		{Hook: "ftp://automation.example.net"},
+		{Hook: "automation.example.net"},
+	} {
+		assert.Error(t, clusterMaintenanceValidate(&req), "Request %+v", req)
+	}
+}
+
+func TestClusterMaintenanceMembers(t *testing.T) {
+	allMembers := []db.NodeInfo{
+		{Name: "m3", Groups: []string{"default"}},
+		{Name: "m1", Groups: []string{"default", "gpu"}},
+		{Name: "m2", Groups: []string{"gpu"}},
+	}
+
+	// All members are maintained in order of name by default.
+	members, err := clusterMaintenanceMembers(allMembers, api.ClusterMaintenancePost{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, maintenanceTestNames(members))
+
+	// Members given explicitly are maintained in the given order.
+	members, err = clusterMaintenanceMembers(allMembers, api.ClusterMaintenancePost{Members: []string{"m3", "m1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m3", "m1"}, maintenanceTestNames(members))
+
+	// The selection can be restricted to a cluster group.
+	members, err = clusterMaintenanceMembers(allMembers, api.ClusterMaintenancePost{Group: "gpu"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1", "m2"}, maintenanceTestNames(members))
+
+	members, err = clusterMaintenanceMembers(allMembers, api.ClusterMaintenancePost{Members: []string{"m3", "m2"}, Group: "gpu"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m2"}, maintenanceTestNames(members))
+
+	_, err = clusterMaintenanceMembers(allMembers, api.ClusterMaintenancePost{Members: []string{"m4"}})
+	assert.Error(t, err)
+
+	_, err = clusterMaintenanceMembers(allMembers, api.ClusterMaintenancePost{Group: "storage"})
+	assert.Error(t, err)
+}
+
+func TestClusterMaintenanceRounds(t *testing.T) {
+	members := []db.NodeInfo{
+		{Name: "m1", Groups: []string{"a"}},
+		{Name: "m2", Groups: []string{"a"}},
+		{Name: "m3", Groups: []string{"b"}},
+		{Name: "m4", Groups: []string{"a", "b"}},
+		{Name: "m5"},
+		{Name: "m6"},
+	}
+
+	rounds := func(parallel int) [][]string {
+		result := [][]string{}
+		for _, round := range clusterMaintenanceRounds(members, parallel) {
+			result = append(result, maintenanceTestNames(round))
+		}
+
+		return result
+	}
+
+	// One member of each cluster group per round, members without a group are counted together.
+	assert.Equal(t, [][]string{{"m1", "m3", "m5"}, {"m2", "m6"}, {"m4"}}, rounds(1))
+
+	// A member in several groups needs room in all of them.
+	assert.Equal(t, [][]string{{"m1", "m2", "m3", "m5", "m6"}, {"m4"}}, rounds(2))
+
+	assert.Equal(t, [][]string{{"m1", "m2", "m3", "m4", "m5", "m6"}}, rounds(3))
+
+	assert.Empty(t, clusterMaintenanceRounds(nil, 1))
+}
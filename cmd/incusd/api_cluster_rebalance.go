@@ -5,80 +5,234 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"time"
 
+	incus "github.com/lxc/incus/v6/client"
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
 	"github.com/lxc/incus/v6/internal/server/instance"
-	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	"github.com/lxc/incus/v6/internal/server/state"
+	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
+var clusterRebalanceCmd = APIEndpoint{
+	Path: "cluster/rebalance",
+
+	Get:  APIEndpointAction{Handler: clusterRebalanceGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: clusterRebalancePost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// rebalanceResources is the list of resources which can be weighted in the load score.
+var rebalanceResources = []string{"cpu", "memory", "disk", "network"}
+
 // ServerScore represents server score taken into account during load balancing.
 type ServerScore struct {
 	NodeInfo  db.NodeInfo
 	Resources *api.Resources
+	Usage     *ServerUsage
 	Score     uint8
 }
 
 // ServerUsage represents current server load.
 type ServerUsage struct {
-	MemoryUsage uint64
-	MemoryTotal uint64
-	CPUUsage    float64
-	CPUTotal    uint64
+	MemoryUsage  uint64
+	MemoryTotal  uint64
+	CPUUsage     float64
+	CPUTotal     uint64
+	DiskUsage    uint64
+	DiskTotal    uint64
+	NetworkUsage uint64
+	NetworkTotal uint64
+}
+
+// add returns the combined usage.
+func (su *ServerUsage) add(au *ServerUsage) *ServerUsage {
+	return &ServerUsage{
+		MemoryUsage:  su.MemoryUsage + au.MemoryUsage,
+		MemoryTotal:  su.MemoryTotal + au.MemoryTotal,
+		CPUUsage:     su.CPUUsage + au.CPUUsage,
+		CPUTotal:     su.CPUTotal + au.CPUTotal,
+		DiskUsage:    su.DiskUsage + au.DiskUsage,
+		DiskTotal:    su.DiskTotal + au.DiskTotal,
+		NetworkUsage: su.NetworkUsage + au.NetworkUsage,
+		NetworkTotal: su.NetworkTotal + au.NetworkTotal,
+	}
+}
+
+// percentages returns the usage of each resource as a percentage of its total.
+func (su *ServerUsage) percentages() map[string]uint64 {
+	percentage := func(usage float64, total float64) uint64 {
+		if total <= 0 {
+			return 0
+		}
+
+		return uint64(min(usage*100/total, 100))
+	}
+
+	return map[string]uint64{
+		"cpu":     percentage(su.CPUUsage, float64(su.CPUTotal)),
+		"memory":  percentage(float64(su.MemoryUsage), float64(su.MemoryTotal)),
+		"disk":    percentage(float64(su.DiskUsage), float64(su.DiskTotal)),
+		"network": percentage(float64(su.NetworkUsage), float64(su.NetworkTotal)),
+	}
+}
+
+// clusterRebalanceCandidate represents an instance which may be moved to another server.
+type clusterRebalanceCandidate struct {
+	inst   instance.Instance
+	source *ServerScore
+	usage  *ServerUsage
+	live   bool
+}
+
+// clusterRebalanceMove represents a planned instance migration.
+type clusterRebalanceMove struct {
+	candidate *clusterRebalanceCandidate
+	target    *ServerScore
+}
+
+// rebalanceWeights returns the configured weight of each resource in the load score.
+func rebalanceWeights(s *state.State) map[string]int64 {
+	weights := make(map[string]int64, len(rebalanceResources))
+	for _, resource := range rebalanceResources {
+		weights[resource] = s.GlobalConfig.ClusterRebalanceWeight(resource)
+	}
+
+	return weights
 }
 
-// sortAndGroupByArch sorts servers by its score and groups them by cpu architecture.
-func sortAndGroupByArch(servers []*ServerScore) map[string][]*ServerScore {
-	sort.Slice(servers, func(i, j int) bool {
+// rebalanceBuckets groups servers between which instances can be moved and sorts them by score.
+// Servers must share the same architecture and, when balancing within cluster groups, the same group.
+func rebalanceBuckets(servers []*ServerScore, scope string) map[string][]*ServerScore {
+	sort.SliceStable(servers, func(i, j int) bool {
 		return servers[i].Score > servers[j].Score
 	})
 
 	result := make(map[string][]*ServerScore)
 	for _, s := range servers {
 		arch := s.Resources.CPU.Architecture
-		_, ok := result[arch]
-		if !ok {
-			result[arch] = []*ServerScore{}
+
+		keys := []string{arch}
+		if scope == "group" {
+			keys = make([]string, 0, len(s.NodeInfo.Groups))
+			for _, group := range s.NodeInfo.Groups {
+				keys = append(keys, group+"/"+arch)
+			}
 		}
 
-		result[arch] = append(result[arch], s)
+		for _, key := range keys {
+			result[key] = append(result[key], s)
+		}
 	}
 
 	return result
 }
 
 // calculateScore calculates score for single server.
-func calculateScore(su *ServerUsage, au *ServerUsage) uint8 {
-	memoryUsage := su.MemoryUsage
-	memoryTotal := su.MemoryTotal
-	cpuUsage := su.CPUUsage
-	cpuTotal := su.CPUTotal
-
+func calculateScore(su *ServerUsage, au *ServerUsage, weights map[string]int64) uint8 {
+	usage := su
 	if au != nil {
-		memoryUsage += au.MemoryUsage
-		memoryTotal += au.MemoryTotal
-		cpuUsage += au.CPUUsage
-		cpuTotal += au.CPUTotal
+		usage = su.add(au)
+	}
+
+	percentages := usage.percentages()
+
+	var total int64
+	var weighted int64
+	for _, resource := range rebalanceResources {
+		weight := weights[resource]
+		if weight <= 0 {
+			continue
+		}
+
+		weighted += weight * int64(percentages[resource])
+		total += weight
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	return uint8(weighted / total)
+}
+
+// rebalanceNetworkAllocation returns the bandwidth (bit/s) reserved by the network limits of an instance.
+func rebalanceNetworkAllocation(devices map[string]map[string]string) uint64 {
+	var total uint64
+	for _, dev := range devices {
+		if dev["type"] != "nic" {
+			continue
+		}
+
+		var limit int64
+		for _, key := range []string{"limits.max", "limits.ingress", "limits.egress"} {
+			if dev[key] == "" {
+				continue
+			}
+
+			value, err := units.ParseBitSizeString(dev[key])
+			if err != nil {
+				continue
+			}
+
+			limit = max(limit, value)
+		}
+
+		total += uint64(limit)
+	}
+
+	return total
+}
+
+// rebalanceInstanceUsage returns the resources an instance adds to the server it runs on.
+// Disk usage is only accounted for instances on local storage pools as moving others doesn't change anything.
+func rebalanceInstanceUsage(inst instance.Instance, remotePools []string) (*ServerUsage, error) {
+	devices := inst.ExpandedDevices().CloneNative()
+
+	cpuUsage, memUsage, diskUsage, err := instance.ResourceUsage(inst.ExpandedConfig(), devices, api.InstanceType(inst.Type().String()))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to establish instance resource usage: %w", err)
 	}
 
-	memoryScore := uint8(float64(memoryUsage) * 100 / float64(memoryTotal))
-	cpuScore := uint8((cpuUsage * 100) / float64(cpuTotal))
+	_, rootDisk, err := internalInstance.GetRootDiskDevice(devices)
+	if err == nil && slices.Contains(remotePools, rootDisk["pool"]) {
+		diskUsage = 0
+	}
+
+	return &ServerUsage{
+		MemoryUsage:  uint64(memUsage),
+		CPUUsage:     float64(cpuUsage),
+		DiskUsage:    uint64(diskUsage),
+		NetworkUsage: rebalanceNetworkAllocation(devices),
+	}, nil
+}
 
-	return (memoryScore + cpuScore) / 2
+// rebalanceIsRunning returns whether the instance was last recorded as running.
+// This is used for instances on other servers whose state can't be checked locally.
+func rebalanceIsRunning(inst instance.Instance) bool {
+	return inst.LocalConfig()["volatile.last_state.power"] == instance.PowerStateRunning
 }
 
-// calculateServersScore calculates score based on memory and CPU usage for servers in cluster.
-func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]*ServerScore, error) {
+// calculateServersScore calculates score based on the weighted resource usage for servers in cluster.
+func calculateServersScore(s *state.State, members []db.NodeInfo, instances map[string][]instance.Instance, remotePools []string, weights map[string]int64) ([]*ServerScore, error) {
 	scores := []*ServerScore{}
 	for _, member := range members {
 		clusterMember, err := cluster.Connect(member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
@@ -98,192 +252,220 @@ func calculateServersScore(s *state.State, members []db.NodeInfo) (map[string][]
 			CPUTotal:    res.CPU.Total,
 		}
 
-		serverScore := calculateScore(su, nil)
-		scores = append(scores, &ServerScore{NodeInfo: member, Resources: res, Score: serverScore})
+		// Only consider local storage pools as remote ones are shared by all servers.
+		if weights["disk"] > 0 {
+			memberState, _, err := clusterMember.GetClusterMemberState(member.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to get state of cluster member: %w", err)
+			}
+
+			for poolName, pool := range memberState.StoragePools {
+				if slices.Contains(remotePools, poolName) {
+					continue
+				}
+
+				su.DiskUsage += pool.Space.Used
+				su.DiskTotal += pool.Space.Total
+			}
+		}
+
+		// Compare the bandwidth reserved by running instances to the speed of the server's links.
+		if weights["network"] > 0 {
+			for _, card := range res.Network.Cards {
+				for _, port := range card.Ports {
+					if port.LinkDetected {
+						su.NetworkTotal += port.LinkSpeed * 1000 * 1000
+					}
+				}
+			}
+
+			for _, inst := range instances[member.Name] {
+				if rebalanceIsRunning(inst) {
+					su.NetworkUsage += rebalanceNetworkAllocation(inst.ExpandedDevices().CloneNative())
+				}
+			}
+		}
+
+		scores = append(scores, &ServerScore{NodeInfo: member, Resources: res, Usage: su, Score: calculateScore(su, nil, weights)})
 	}
 
-	return sortAndGroupByArch(scores), nil
+	return scores, nil
 }
 
-// clusterRebalanceServers is responsible for instances migration from most to less busy server.
-func clusterRebalanceServers(ctx context.Context, s *state.State, srcServer *ServerScore, dstServer *ServerScore, maxToMigrate int64) (int64, error) {
-	numOfMigrated := int64(0)
+// clusterRebalanceCandidates returns the instances of each server which may be moved.
+func clusterRebalanceCandidates(s *state.State, servers []*ServerScore, instances map[string][]instance.Instance, remotePools []string) (map[string][]*clusterRebalanceCandidate, error) {
+	cooldown := s.GlobalConfig.ClusterRebalanceCooldown()
+	coldMigration := s.GlobalConfig.ClusterRebalanceColdMigration()
 
-	// Keep track of project restrictions.
-	projectStatuses := map[string]bool{}
-
-	// Get a list of migratable instances.
-	var dbInstances []dbCluster.Instance
-	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-		var err error
+	candidates := make(map[string][]*clusterRebalanceCandidate, len(servers))
+	for _, server := range servers {
+		for _, inst := range instances[server.NodeInfo.Name] {
+			// Stopped instances don't contribute to the load.
+			if !rebalanceIsRunning(inst) {
+				continue
+			}
 
-		// Get the instance list.
-		instType := instancetype.VM
-		dbInstances, err = dbCluster.GetInstances(ctx, tx.Tx(), dbCluster.InstanceFilter{Node: &srcServer.NodeInfo.Name, Type: &instType})
-		if err != nil {
-			return fmt.Errorf("Failed to get instances: %w", err)
-		}
+			if util.IsTrue(inst.ExpandedConfig()["cluster.rebalance.exclude"]) {
+				continue
+			}
 
-		// Check project restrictions.
-		for _, dbInst := range dbInstances {
-			_, ok := projectStatuses[dbInst.Project]
-			if ok {
+			// Respect the evacuation mode of the instance.
+			action := inst.CanMigrate()
+			if action != "live-migrate" && (action != "migrate" || !coldMigration) {
 				continue
 			}
 
-			dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), dbInst.Project)
-			if err != nil {
-				return fmt.Errorf("Failed to get project: %w", err)
+			// Check if instance is ready for next migration.
+			lastMove := inst.LocalConfig()["volatile.rebalance.last_move"]
+			if lastMove != "" {
+				v, err := strconv.ParseInt(lastMove, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to parse last_move value: %w", err)
+				}
+
+				expiry, err := internalInstance.GetExpiry(time.Unix(v, 0), cooldown)
+				if err != nil {
+					return nil, fmt.Errorf("Failed to calculate expiration for cooldown time: %w", err)
+				}
+
+				if time.Now().Before(expiry) {
+					continue
+				}
 			}
 
-			apiProject, err := dbProject.ToAPI(ctx, tx.Tx())
+			usage, err := rebalanceInstanceUsage(inst, remotePools)
 			if err != nil {
-				return fmt.Errorf("Failed to load project: %w", err)
+				return nil, err
 			}
 
-			_, _, err = project.CheckTarget(ctx, s.Authorizer, nil, tx, apiProject, dstServer.NodeInfo.Name, []db.NodeInfo{dstServer.NodeInfo})
-			projectStatuses[dbInst.Project] = err == nil
+			candidates[server.NodeInfo.Name] = append(candidates[server.NodeInfo.Name], &clusterRebalanceCandidate{
+				inst:   inst,
+				source: server,
+				usage:  usage,
+				live:   action == "live-migrate",
+			})
 		}
-
-		return nil
-	})
-	if err != nil {
-		return -1, fmt.Errorf("Failed to get instances: %w", err)
 	}
 
-	// Filter for instances that can be live migrated to the new target.
-	var instances []instance.Instance
-	for _, dbInst := range dbInstances {
-		if !projectStatuses[dbInst.Project] {
-			// Project restrictions prevent moving to that target.
-			continue
-		}
+	return candidates, nil
+}
 
-		inst, err := instance.LoadByProjectAndName(s, dbInst.Project, dbInst.Name)
-		if err != nil {
-			return -1, fmt.Errorf("Failed to load instance: %w", err)
-		}
+// clusterRebalanceTargetCheck returns a function checking whether an instance may be moved to a server.
+// Instances placed in a cluster group stay within it, project restrictions and cluster group capacity limits apply.
+func clusterRebalanceTargetCheck(ctx context.Context, s *state.State) func(c *clusterRebalanceCandidate, target *ServerScore) (bool, error) {
+	// Keep track of project restrictions.
+	projectStatuses := map[string]bool{}
 
-		// Do not allow to migrate instance which doesn't support live migration.
-		if inst.CanMigrate() != "live-migrate" {
-			continue
+	return func(c *clusterRebalanceCandidate, target *ServerScore) (bool, error) {
+		group := c.inst.LocalConfig()["volatile.cluster.group"]
+		if group != "" && !slices.Contains(target.NodeInfo.Groups, group) {
+			return false, nil
 		}
 
-		// Check if instance is ready for next migration.
-		lastMove := inst.LocalConfig()["volatile.rebalance.last_move"]
-		cooldown := s.GlobalConfig.ClusterRebalanceCooldown()
-		if lastMove != "" {
-			v, err := strconv.ParseInt(lastMove, 10, 64)
-			if err != nil {
-				return -1, fmt.Errorf("Failed to parse last_move value: %w", err)
-			}
+		projectName := c.inst.Project().Name
+		key := projectName + "/" + target.NodeInfo.Name
 
-			expiry, err := internalInstance.GetExpiry(time.Unix(v, 0), cooldown)
+		_, ok := projectStatuses[key]
+		if !ok {
+			instProject := c.inst.Project()
+			err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+				_, _, err := project.CheckTarget(ctx, s.Authorizer, nil, tx, &instProject, target.NodeInfo.Name, []db.NodeInfo{target.NodeInfo})
+				projectStatuses[key] = err == nil
+
+				return nil
+			})
 			if err != nil {
-				return -1, fmt.Errorf("Failed to calculate expiration for cooldown time: %w", err)
+				return false, fmt.Errorf("Failed to check project restrictions: %w", err)
 			}
+		}
 
-			if time.Now().Before(expiry) {
-				continue
-			}
+		if !projectStatuses[key] {
+			return false, nil
+		}
+
+		// Check the capacity limits of the target.
+		_, err := clusterGroupCapacityFilter(ctx, s, []db.NodeInfo{target.NodeInfo}, projectName, c.inst.Name(), c.inst.Type(), c.inst.ExpandedConfig())
+		if err != nil {
+			return false, nil
 		}
 
-		instances = append(instances, inst)
+		return true, nil
 	}
+}
+
+// clusterRebalanceServers plans instance migrations from the most to the least busy server.
+func clusterRebalanceServers(srcServer *ServerScore, dstServer *ServerScore, candidates []*clusterRebalanceCandidate, weights map[string]int64, maxToMigrate int64, canMove func(c *clusterRebalanceCandidate, target *ServerScore) (bool, error)) ([]clusterRebalanceMove, error) {
+	moves := []clusterRebalanceMove{}
 
 	// Calculate current and target scores.
 	targetScore := (srcServer.Score + dstServer.Score) / 2
 	currentScore := dstServer.Score
-	targetServerUsage := &ServerUsage{
-		MemoryUsage: dstServer.Resources.Memory.Used,
-		MemoryTotal: dstServer.Resources.Memory.Total,
-		CPUUsage:    dstServer.Resources.Load.Average1Min,
-		CPUTotal:    dstServer.Resources.CPU.Total,
-	}
+	targetServerUsage := dstServer.Usage
 
-	// Prepare the API client.
-	srcNode, err := cluster.Connect(srcServer.NodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
-	if err != nil {
-		return -1, fmt.Errorf("Failed to connect to cluster member: %w", err)
-	}
-
-	srcNode = srcNode.UseTarget(dstServer.NodeInfo.Name)
-
-	for _, inst := range instances {
-		if numOfMigrated >= maxToMigrate {
+	for _, c := range candidates {
+		if int64(len(moves)) >= maxToMigrate {
 			// We're done moving instances for now.
-			return numOfMigrated, nil
+			break
 		}
 
 		if currentScore >= targetScore {
 			// We've balanced the load.
-			return numOfMigrated, nil
+			break
 		}
 
-		// Calculate resource consumption.
-		cpuUsage, memUsage, _, err := instance.ResourceUsage(inst.ExpandedConfig(), inst.ExpandedDevices().CloneNative(), api.InstanceType(inst.Type().String()))
+		allowed, err := canMove(c, dstServer)
 		if err != nil {
-			return -1, fmt.Errorf("Failed to establish instance resource usage: %w", err)
+			return nil, err
 		}
 
-		// Calculate impact of migration.
-		additionalUsage := &ServerUsage{
-			MemoryUsage: uint64(cpuUsage),
-			CPUUsage:    float64(memUsage),
+		if !allowed {
+			continue
 		}
 
-		expectedScore := calculateScore(targetServerUsage, additionalUsage)
+		// Calculate impact of migration.
+		expectedScore := calculateScore(targetServerUsage, c.usage, weights)
 		if expectedScore >= targetScore {
 			// Skip the instance as it would have too big an impact.
 			continue
 		}
 
-		// Prepare for live migration.
-		req := api.InstancePost{
-			Migration: true,
-			Live:      true,
-		}
-
-		migrationOp, err := srcNode.MigrateInstance(inst.Name(), req)
-		if err != nil {
-			return -1, fmt.Errorf("Migration API failure: %w", err)
-		}
-
-		err = migrationOp.Wait()
-		if err != nil {
-			return -1, fmt.Errorf("Failed to wait for migration to finish: %w", err)
-		}
+		moves = append(moves, clusterRebalanceMove{candidate: c, target: dstServer})
 
-		// Record the migration in the instance volatile storage.
-		err = inst.VolatileSet(map[string]string{"volatile.rebalance.last_move": strconv.FormatInt(time.Now().Unix(), 10)})
-		if err != nil {
-			return -1, err
-		}
-
-		// Update counters and scores.
-		numOfMigrated += 1
+		// Update scores.
 		currentScore = expectedScore
-		targetServerUsage.MemoryUsage += additionalUsage.MemoryUsage
-		targetServerUsage.CPUUsage += additionalUsage.CPUUsage
+		targetServerUsage = targetServerUsage.add(c.usage)
 	}
 
-	return numOfMigrated, nil
+	return moves, nil
 }
 
-// clusterRebalance performs cluster re-balancing.
-func clusterRebalance(ctx context.Context, s *state.State, servers map[string][]*ServerScore) error {
+// clusterRebalanceBuiltin plans the migrations using the built-in logic.
+func clusterRebalanceBuiltin(s *state.State, buckets map[string][]*ServerScore, candidates map[string][]*clusterRebalanceCandidate, weights map[string]int64, canMove func(c *clusterRebalanceCandidate, target *ServerScore) (bool, error)) ([]clusterRebalanceMove, error) {
 	rebalanceThreshold := s.GlobalConfig.ClusterRebalanceThreshold()
 	rebalanceBatch := s.GlobalConfig.ClusterRebalanceBatch()
-	numOfMigrated := int64(0)
 
-	for archName, v := range servers {
-		if numOfMigrated >= rebalanceBatch {
+	moves := []clusterRebalanceMove{}
+
+	// Servers in several cluster groups are only balanced once per run.
+	balanced := map[string]bool{}
+
+	bucketNames := make([]string, 0, len(buckets))
+	for name := range buckets {
+		bucketNames = append(bucketNames, name)
+	}
+
+	sort.Strings(bucketNames)
+
+	for _, bucketName := range bucketNames {
+		v := buckets[bucketName]
+
+		if int64(len(moves)) >= rebalanceBatch {
 			// Maximum number of instances already migrated in this run.
-			continue
+			break
 		}
 
 		if len(v) < 2 {
-			// Skip if there isn't at least 2 servers with specific arch.
+			// Skip if there isn't at least 2 servers in the bucket.
 			continue
 		}
 
@@ -293,46 +475,144 @@ func clusterRebalance(ctx context.Context, s *state.State, servers map[string][]
 		}
 
 		leastBusyIndex := len(v) - 1
+		if balanced[v[0].NodeInfo.Name] || balanced[v[leastBusyIndex].NodeInfo.Name] {
+			continue
+		}
+
 		percentageChange := int64(float64(v[0].Score-v[leastBusyIndex].Score) / float64(v[0].Score) * 100)
-		logger.Debug("Automatic re-balancing", logger.Ctx{"Architecture": archName, "LeastBusy": v[leastBusyIndex].NodeInfo.Name, "LeastBusyScore": v[leastBusyIndex].Score, "MostBusy": v[0].NodeInfo.Name, "MostBusyScore": v[0].Score, "Difference": fmt.Sprintf("%d%%", percentageChange), "Threshold": fmt.Sprintf("%d%%", rebalanceThreshold)})
+		logger.Debug("Automatic re-balancing", logger.Ctx{"Bucket": bucketName, "LeastBusy": v[leastBusyIndex].NodeInfo.Name, "LeastBusyScore": v[leastBusyIndex].Score, "MostBusy": v[0].NodeInfo.Name, "MostBusyScore": v[0].Score, "Difference": fmt.Sprintf("%d%%", percentageChange), "Threshold": fmt.Sprintf("%d%%", rebalanceThreshold)})
 
 		if percentageChange < rebalanceThreshold {
 			continue // Skip as threshold condition is not met.
 		}
 
-		n, err := clusterRebalanceServers(ctx, s, v[0], v[leastBusyIndex], rebalanceBatch-numOfMigrated)
+		bucketMoves, err := clusterRebalanceServers(v[0], v[leastBusyIndex], candidates[v[0].NodeInfo.Name], weights, rebalanceBatch-int64(len(moves)), canMove)
 		if err != nil {
-			return fmt.Errorf("Failed to rebalance cluster: %w", err)
+			return nil, err
 		}
 
-		numOfMigrated += n
+		if len(bucketMoves) > 0 {
+			balanced[v[0].NodeInfo.Name] = true
+			balanced[v[leastBusyIndex].NodeInfo.Name] = true
+		}
+
+		moves = append(moves, bucketMoves...)
 	}
 
-	return nil
+	return moves, nil
 }
 
-func autoRebalanceCluster(ctx context.Context, d *Daemon) error {
-	s := d.State()
+// clusterRebalanceScriptlet plans the migrations using the re-balancing scriptlet and validates its decisions.
+func clusterRebalanceScriptlet(ctx context.Context, s *state.State, servers []*ServerScore, buckets map[string][]*ServerScore, candidates map[string][]*clusterRebalanceCandidate, canMove func(c *clusterRebalanceCandidate, target *ServerScore) (bool, error)) ([]clusterRebalanceMove, error) {
+	members := make([]api.ClusterRebalanceMember, 0, len(servers))
+	for _, server := range servers {
+		members = append(members, clusterRebalanceMemberToAPI(server))
+	}
 
-	// Confirm we should run the rebalance.
-	leader, err := s.Cluster.LeaderAddress()
+	instances := []apiScriptlet.ClusterRebalanceInstance{}
+	for _, server := range servers {
+		for _, c := range candidates[server.NodeInfo.Name] {
+			instances = append(instances, apiScriptlet.ClusterRebalanceInstance{
+				Name:     c.inst.Name(),
+				Project:  c.inst.Project().Name,
+				Type:     c.inst.Type().String(),
+				Location: server.NodeInfo.Name,
+				Live:     c.live,
+				Config:   c.inst.ExpandedConfig(),
+				Resources: apiScriptlet.InstanceResources{
+					CPUCores:     uint64(c.usage.CPUUsage),
+					MemorySize:   c.usage.MemoryUsage,
+					RootDiskSize: c.usage.DiskUsage,
+				},
+			})
+		}
+	}
+
+	scriptletMoves, err := scriptlet.ClusterRebalanceRun(ctx, logger.Log, members, instances)
 	if err != nil {
-		if errors.Is(err, cluster.ErrNodeIsNotClustered) {
-			// Not clustered.
-			return nil
+		return nil, fmt.Errorf("Failed running cluster re-balancing scriptlet: %w", err)
+	}
+
+	rebalanceBatch := s.GlobalConfig.ClusterRebalanceBatch()
+	if int64(len(scriptletMoves)) > rebalanceBatch {
+		logger.Warn("Cluster re-balancing scriptlet requested more moves than allowed", logger.Ctx{"requested": len(scriptletMoves), "batch": rebalanceBatch})
+		scriptletMoves = scriptletMoves[:rebalanceBatch]
+	}
+
+	moves := make([]clusterRebalanceMove, 0, len(scriptletMoves))
+	for _, scriptletMove := range scriptletMoves {
+		var candidate *clusterRebalanceCandidate
+		for _, c := range candidates[scriptletMove.Source] {
+			if c.inst.Project().Name == scriptletMove.Project && c.inst.Name() == scriptletMove.Instance {
+				candidate = c
+				break
+			}
 		}
 
-		return fmt.Errorf("Failed to get leader cluster member address: %w", err)
+		if candidate == nil {
+			return nil, fmt.Errorf("Cluster re-balancing scriptlet can't move instance %q in project %q", scriptletMove.Instance, scriptletMove.Project)
+		}
+
+		// Only allow moves between servers the built-in logic would balance between.
+		var target *ServerScore
+		for _, bucket := range buckets {
+			if !slices.Contains(bucket, candidate.source) {
+				continue
+			}
+
+			for _, server := range bucket {
+				if server.NodeInfo.Name == scriptletMove.Target {
+					target = server
+					break
+				}
+			}
+		}
+
+		if target == nil || target == candidate.source {
+			return nil, fmt.Errorf("Cluster re-balancing scriptlet can't move instance %q in project %q to %q", scriptletMove.Instance, scriptletMove.Project, scriptletMove.Target)
+		}
+
+		allowed, err := canMove(candidate, target)
+		if err != nil {
+			return nil, err
+		}
+
+		if !allowed {
+			return nil, fmt.Errorf("Instance %q in project %q isn't allowed on %q", scriptletMove.Instance, scriptletMove.Project, scriptletMove.Target)
+		}
+
+		moves = append(moves, clusterRebalanceMove{candidate: candidate, target: target})
 	}
 
-	if s.LocalConfig.ClusterAddress() != leader {
-		// Not the leader.
-		return nil
+	return moves, nil
+}
+
+// clusterRebalanceMemberToAPI returns the load of a server in its API representation.
+func clusterRebalanceMemberToAPI(server *ServerScore) api.ClusterRebalanceMember {
+	percentages := server.Usage.percentages()
+
+	return api.ClusterRebalanceMember{
+		Name:         server.NodeInfo.Name,
+		Architecture: server.Resources.CPU.Architecture,
+		Groups:       server.NodeInfo.Groups,
+		Score:        uint64(server.Score),
+		CPU:          percentages["cpu"],
+		Memory:       percentages["memory"],
+		Disk:         percentages["disk"],
+		Network:      percentages["network"],
 	}
+}
 
-	// Get all online members
+// clusterRebalancePlan computes the instance migrations of a re-balancing run.
+//
+// On scheduled runs, the members outside of their maintenance windows are left out.
+func clusterRebalancePlan(ctx context.Context, s *state.State, scheduled bool) (*api.ClusterRebalancePlan, []clusterRebalanceMove, error) {
 	var onlineMembers []db.NodeInfo
-	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+	var remotePools []string
+	instances := map[string][]instance.Instance{}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		// Get all online members.
 		members, err := tx.GetNodes(ctx)
 		if err != nil {
 			return fmt.Errorf("Failed getting cluster members: %w", err)
@@ -343,18 +623,211 @@ func autoRebalanceCluster(ctx context.Context, d *Daemon) error {
 			return fmt.Errorf("Failed getting online cluster members: %w", err)
 		}
 
-		return nil
+		if scheduled {
+			now := time.Now()
+			available := make([]db.NodeInfo, 0, len(onlineMembers))
+			for _, member := range onlineMembers {
+				windows, err := clusterMemberMaintenanceWindows(ctx, tx, member)
+				if err != nil {
+					return fmt.Errorf("Failed getting maintenance windows of cluster member %q: %w", member.Name, err)
+				}
+
+				if !windows.Allow(now) {
+					logger.Debug("Leaving out cluster member outside of its maintenance windows from re-balancing", logger.Ctx{"member": member.Name})
+					continue
+				}
+
+				available = append(available, member)
+			}
+
+			onlineMembers = available
+		}
+
+		// Get the storage pools shared by all members.
+		pools, _, err := tx.GetStoragePools(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("Failed getting storage pools: %w", err)
+		}
+
+		for _, pool := range pools {
+			if slices.Contains(storageDrivers.RemoteDriverNames(), pool.Driver) {
+				remotePools = append(remotePools, pool.Name)
+			}
+		}
+
+		// Only load the instances of the members being balanced.
+		if len(onlineMembers) < 2 {
+			return nil
+		}
+
+		filters := make([]dbCluster.InstanceFilter, 0, len(onlineMembers))
+		for _, member := range onlineMembers {
+			filters = append(filters, dbCluster.InstanceFilter{Node: &member.Name})
+		}
+
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			instances[dbInst.Node] = append(instances[dbInst.Node], inst)
+
+			return nil
+		}, filters...)
 	})
 	if err != nil {
-		return fmt.Errorf("Failed getting cluster members: %w", err)
+		return nil, nil, err
+	}
+
+	weights := rebalanceWeights(s)
+
+	servers, err := calculateServersScore(s, onlineMembers, instances, remotePools, weights)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed calculating servers score: %w", err)
+	}
+
+	candidates, err := clusterRebalanceCandidates(s, servers, instances, remotePools)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed getting instances to move: %w", err)
+	}
+
+	buckets := rebalanceBuckets(servers, s.GlobalConfig.ClusterRebalanceScope())
+	canMove := clusterRebalanceTargetCheck(ctx, s)
+
+	var moves []clusterRebalanceMove
+	if s.GlobalConfig.ClusterRebalanceScriptlet() != "" {
+		moves, err = clusterRebalanceScriptlet(ctx, s, servers, buckets, candidates, canMove)
+	} else {
+		moves, err = clusterRebalanceBuiltin(s, buckets, candidates, weights, canMove)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	plan := &api.ClusterRebalancePlan{
+		Members: make([]api.ClusterRebalanceMember, 0, len(servers)),
+		Moves:   make([]api.ClusterRebalanceMove, 0, len(moves)),
+	}
+
+	for _, server := range servers {
+		plan.Members = append(plan.Members, clusterRebalanceMemberToAPI(server))
+	}
+
+	for _, move := range moves {
+		plan.Moves = append(plan.Moves, api.ClusterRebalanceMove{
+			Instance: move.candidate.inst.Name(),
+			Project:  move.candidate.inst.Project().Name,
+			Source:   move.candidate.source.NodeInfo.Name,
+			Target:   move.target.NodeInfo.Name,
+			Live:     move.candidate.live,
+		})
+	}
+
+	return plan, moves, nil
+}
+
+// clusterRebalanceMigrate moves an instance to its new server, restarting it if it can't be live-migrated.
+func clusterRebalanceMigrate(s *state.State, move clusterRebalanceMove) error {
+	inst := move.candidate.inst
+
+	// Prepare the API client.
+	srcNode, err := cluster.Connect(move.candidate.source.NodeInfo.Address, s.Endpoints.NetworkCert(), s.ServerCert(), nil, true)
+	if err != nil {
+		return fmt.Errorf("Failed to connect to cluster member: %w", err)
+	}
+
+	srcNode = srcNode.UseProject(inst.Project().Name).UseTarget(move.target.NodeInfo.Name)
+
+	setState := func(action string) error {
+		op, err := srcNode.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: action, Timeout: -1}, "")
+		if err != nil {
+			return err
+		}
+
+		return op.Wait()
+	}
+
+	if !move.candidate.live {
+		err = setState("stop")
+		if err != nil {
+			return fmt.Errorf("Failed to stop instance: %w", err)
+		}
+	}
+
+	// Prepare for migration.
+	req := api.InstancePost{
+		Migration: true,
+		Live:      move.candidate.live,
+	}
+
+	var migrationOp incus.Operation
+	migrationOp, err = srcNode.MigrateInstance(inst.Name(), req)
+	if err == nil {
+		err = migrationOp.Wait()
+	}
+
+	if err != nil {
+		if !move.candidate.live {
+			_ = setState("start")
+		}
+
+		return fmt.Errorf("Failed to migrate instance: %w", err)
+	}
+
+	if !move.candidate.live {
+		err = setState("start")
+		if err != nil {
+			return fmt.Errorf("Failed to start instance: %w", err)
+		}
 	}
 
-	servers, err := calculateServersScore(s, onlineMembers)
+	// Record the migration in the instance volatile storage.
+	return inst.VolatileSet(map[string]string{"volatile.rebalance.last_move": strconv.FormatInt(time.Now().Unix(), 10)})
+}
+
+// clusterRebalance performs cluster re-balancing.
+func clusterRebalance(ctx context.Context, s *state.State, op *operations.Operation, scheduled bool) error {
+	_, moves, err := clusterRebalancePlan(ctx, s, scheduled)
+	if err != nil {
+		return err
+	}
+
+	for _, move := range moves {
+		if op != nil {
+			_ = op.UpdateMetadata(map[string]any{"rebalance_progress": fmt.Sprintf("Migrating %q in project %q to %q", move.candidate.inst.Name(), move.candidate.inst.Project().Name, move.target.NodeInfo.Name)})
+		}
+
+		err := clusterRebalanceMigrate(s, move)
+		if err != nil {
+			return fmt.Errorf("Failed to move instance %q in project %q to %q: %w", move.candidate.inst.Name(), move.candidate.inst.Project().Name, move.target.NodeInfo.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func autoRebalanceCluster(ctx context.Context, d *Daemon) error {
+	s := d.State()
+
+	// Confirm we should run the rebalance.
+	leader, err := s.Cluster.LeaderAddress()
 	if err != nil {
-		return fmt.Errorf("Failed calculating servers score: %w", err)
+		if errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			// Not clustered.
+			return nil
+		}
+
+		return fmt.Errorf("Failed to get leader cluster member address: %w", err)
 	}
 
-	err = clusterRebalance(ctx, s, servers)
+	if s.LocalConfig.ClusterAddress() != leader {
+		// Not the leader.
+		return nil
+	}
+
+	err = clusterRebalance(ctx, s, nil, true)
 	if err != nil {
 		return fmt.Errorf("Failed rebalancing cluster: %w", err)
 	}
@@ -389,3 +862,91 @@ func autoRebalanceClusterTask(d *Daemon) (task.Func, task.Schedule) {
 
 	return f, task.Every(time.Minute)
 }
+
+// swagger:operation GET /1.0/cluster/rebalance cluster cluster_rebalance_get
+//
+//	Get the re-balancing plan
+//
+//	Computes the instance migrations the next re-balancing run would perform, without performing them.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Re-balancing plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterRebalancePlan"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalanceGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(fmt.Errorf("This server is not clustered"))
+	}
+
+	plan, _, err := clusterRebalancePlan(r.Context(), s, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, plan)
+}
+
+// swagger:operation POST /1.0/cluster/rebalance cluster cluster_rebalance_post
+//
+//	Re-balance the cluster
+//
+//	Performs a re-balancing run immediately.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalancePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(fmt.Errorf("This server is not clustered"))
+	}
+
+	run := func(op *operations.Operation) error {
+		return clusterRebalance(context.Background(), s, op, false)
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ClusterRebalance, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestCalculateScore(t *testing.T) {
+	usage := &ServerUsage{
+		CPUUsage:     4,
+		CPUTotal:     8,
+		MemoryUsage:  1024,
+		MemoryTotal:  4096,
+		DiskUsage:    10,
+		DiskTotal:    100,
+		NetworkUsage: 0,
+		NetworkTotal: 0,
+	}
+
+	// The default weights only account for CPU and memory.
+	assert.Equal(t, uint8(37), calculateScore(usage, nil, map[string]int64{"cpu": 1, "memory": 1}))
+
+	// Weights change the contribution of each resource.
+	assert.Equal(t, uint8(43), calculateScore(usage, nil, map[string]int64{"cpu": 3, "memory": 1}))
+	assert.Equal(t, uint8(10), calculateScore(usage, nil, map[string]int64{"disk": 1}))
+
+	// Resources without a total don't contribute.
+	assert.Equal(t, uint8(0), calculateScore(usage, nil, map[string]int64{"network": 1}))
+
+	// No weights means no score.
+	assert.Equal(t, uint8(0), calculateScore(usage, nil, map[string]int64{}))
+
+	// The usage of an instance is added to the one of the server.
+	assert.Equal(t, uint8(75), calculateScore(usage, &ServerUsage{CPUUsage: 2}, map[string]int64{"cpu": 1}))
+
+	// Usage is capped to the total.
+	assert.Equal(t, uint8(100), calculateScore(usage, &ServerUsage{CPUUsage: 20}, map[string]int64{"cpu": 1}))
+}
+
+func TestRebalanceBuckets(t *testing.T) {
+	newServer := func(name string, arch string, score uint8, groups ...string) *ServerScore {
+		return &ServerScore{
+			NodeInfo:  db.NodeInfo{Name: name, Groups: groups},
+			Resources: &api.Resources{CPU: api.ResourcesCPU{Architecture: arch}},
+			Score:     score,
+		}
+	}
+
+	names := func(servers []*ServerScore) []string {
+		result := make([]string, 0, len(servers))
+		for _, server := range servers {
+			result = append(result, server.NodeInfo.Name)
+		}
+
+		return result
+	}
+
+	servers := []*ServerScore{
+		newServer("m1", "x86_64", 10, "default"),
+		newServer("m2", "x86_64", 50, "default", "gpu"),
+		newServer("m3", "aarch64", 30, "default"),
+		newServer("m4", "x86_64", 30, "gpu"),
+	}
+
+	// Servers are grouped by architecture, most loaded first.
+	buckets := rebalanceBuckets(servers, "")
+	require.Len(t, buckets, 2)
+	assert.Equal(t, []string{"m2", "m4", "m1"}, names(buckets["x86_64"]))
+	assert.Equal(t, []string{"m3"}, names(buckets["aarch64"]))
+
+	// Servers in several cluster groups are part of several buckets.
+	buckets = rebalanceBuckets(servers, "group")
+	require.Len(t, buckets, 3)
+	assert.Equal(t, []string{"m2", "m1"}, names(buckets["default/x86_64"]))
+	assert.Equal(t, []string{"m3"}, names(buckets["default/aarch64"]))
+	assert.Equal(t, []string{"m2", "m4"}, names(buckets["gpu/x86_64"]))
+}
+
+func TestRebalanceNetworkAllocation(t *testing.T) {
+	devices := map[string]map[string]string{
+		"eth0": {"type": "nic", "limits.max": "100Mbit"},
+		"eth1": {"type": "nic", "limits.ingress": "10Mbit", "limits.egress": "1Gbit"},
+		"eth2": {"type": "nic"},
+		"eth3": {"type": "nic", "limits.max": "invalid"},
+		"root": {"type": "disk", "limits.max": "100Mbit"},
+	}
+
+	assert.Equal(t, uint64(1100*1000*1000), rebalanceNetworkAllocation(devices))
+	assert.Equal(t, uint64(0), rebalanceNetworkAllocation(nil))
+}
+
+func TestClusterRebalanceServers(t *testing.T) {
+	weights := map[string]int64{"cpu": 1}
+
+	src := &ServerScore{NodeInfo: db.NodeInfo{Name: "busy"}, Usage: &ServerUsage{CPUUsage: 8, CPUTotal: 10}, Score: 80}
+	dst := &ServerScore{NodeInfo: db.NodeInfo{Name: "idle"}, Usage: &ServerUsage{CPUUsage: 2, CPUTotal: 10}, Score: 20}
+
+	candidates := []*clusterRebalanceCandidate{
+		{source: src, usage: &ServerUsage{CPUUsage: 4}}, // Would overload the target.
+		{source: src, usage: &ServerUsage{CPUUsage: 1}},
+		{source: src, usage: &ServerUsage{CPUUsage: 1}},
+		{source: src, usage: &ServerUsage{CPUUsage: 1}}, // Would reach the target score.
+	}
+
+	allowAll := func(c *clusterRebalanceCandidate, target *ServerScore) (bool, error) { return true, nil }
+
+	// Instances are moved until the target gets close to the average score.
+	moves, err := clusterRebalanceServers(src, dst, candidates, weights, 10, allowAll)
+	require.NoError(t, err)
+	require.Len(t, moves, 2)
+	assert.Same(t, candidates[1], moves[0].candidate)
+	assert.Same(t, candidates[2], moves[1].candidate)
+	assert.Same(t, dst, moves[0].target)
+
+	// The number of moves is limited.
+	moves, err = clusterRebalanceServers(src, dst, candidates, weights, 1, allowAll)
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	assert.Same(t, candidates[1], moves[0].candidate)
+
+	// Instances which can't be moved to the target are skipped.
+	moves, err = clusterRebalanceServers(src, dst, candidates, weights, 10, func(c *clusterRebalanceCandidate, target *ServerScore) (bool, error) {
+		return c != candidates[1], nil
+	})
+	require.NoError(t, err)
+	require.Len(t, moves, 2)
+	assert.Same(t, candidates[2], moves[0].candidate)
+	assert.Same(t, candidates[3], moves[1].candidate)
+
+	// Nothing is moved to a target already at the average score.
+	moves, err = clusterRebalanceServers(src, &ServerScore{Usage: &ServerUsage{CPUUsage: 8, CPUTotal: 10}, Score: 80}, candidates, weights, 10, allowAll)
+	require.NoError(t, err)
+	assert.Empty(t, moves)
+}
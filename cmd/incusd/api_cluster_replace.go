@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	internalRecover "github.com/lxc/incus/v6/internal/recover"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	storagePools "github.com/lxc/incus/v6/internal/server/storage"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
+)
+
+// internalClusterGetMemberConfig returns the member-specific configuration of the storage pools and networks
+// of a cluster member, with the values set on that member.
+//
+// It's used by servers replacing the member to inherit its configuration.
+func internalClusterGetMemberConfig(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	keys := []api.ClusterMemberConfigKey{}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		// Check that the member exists.
+		_, err := tx.GetNodeByName(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		pools, err := tx.GetNonPendingStoragePoolsNamesToIDs(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to get storage pools: %w", err)
+		}
+
+		for poolName, poolID := range pools {
+			configs, err := tx.GetStoragePoolNodeConfigs(ctx, poolID)
+			if err != nil {
+				return fmt.Errorf("Failed to get configuration of storage pool %q: %w", poolName, err)
+			}
+
+			for key, value := range configs[name] {
+				if strings.HasPrefix(key, internalInstance.ConfigVolatilePrefix) {
+					continue
+				}
+
+				keys = append(keys, api.ClusterMemberConfigKey{
+					Entity:      "storage-pool",
+					Name:        poolName,
+					Key:         key,
+					Value:       value,
+					Description: fmt.Sprintf("\"%s\" property for storage pool \"%s\"", key, poolName),
+				})
+			}
+		}
+
+		networks, err := tx.GetNonPendingNetworkIDs(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed to get networks: %w", err)
+		}
+
+		// Only networks in the default project have member-specific configuration.
+		for networkName, networkID := range networks[api.ProjectDefaultName] {
+			configs, err := tx.NetworkNodeConfigs(ctx, networkID)
+			if err != nil {
+				return fmt.Errorf("Failed to get configuration of network %q: %w", networkName, err)
+			}
+
+			for key, value := range configs[name] {
+				if strings.HasPrefix(key, internalInstance.ConfigVolatilePrefix) {
+					continue
+				}
+
+				keys = append(keys, api.ClusterMemberConfigKey{
+					Entity:      "network",
+					Name:        networkName,
+					Key:         key,
+					Value:       value,
+					Description: fmt.Sprintf("\"%s\" property for network \"%s\"", key, networkName),
+				})
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, keys)
+}
+
+// clusterReplaceMemberConfig returns the member-specific configuration of the member being replaced, as
+// retrieved from the cluster, with the keys provided in memberConfig taking precedence.
+func clusterReplaceMemberConfig(client incus.InstanceServer, name string, memberConfig []api.ClusterMemberConfigKey) ([]api.ClusterMemberConfigKey, error) {
+	resp, _, err := client.RawQuery("GET", api.NewURL().Path("internal", "cluster", "member-config", name).String(), nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get configuration of cluster member %q: %w", name, err)
+	}
+
+	inherited := []api.ClusterMemberConfigKey{}
+	err = resp.MetadataAsStruct(&inherited)
+	if err != nil {
+		return nil, err
+	}
+
+	config := slices.Clone(memberConfig)
+	for _, key := range inherited {
+		overridden := slices.ContainsFunc(memberConfig, func(userKey api.ClusterMemberConfigKey) bool {
+			return userKey.Entity == key.Entity && userKey.Name == key.Name && userKey.Key == key.Key
+		})
+
+		if overridden {
+			continue
+		}
+
+		logger.Debug("Inheriting member configuration key", logger.Ctx{"entity": key.Entity, "name": key.Name, "key": key.Key, "value": key.Value})
+		config = append(config, key)
+	}
+
+	return config, nil
+}
+
+// clusterReplaceAdoptStoragePools sets up the local storage pools of the cluster whose storage is already
+// present on this server, typically after moving over or restoring the disks of the member being replaced.
+//
+// Those pools are recorded as created without going through the regular pool creation, which requires the
+// storage to be empty. The other pools are left to be created as usual.
+//
+// Returns the names of the adopted pools along with a revert function.
+func clusterReplaceAdoptStoragePools(s *state.State, client incus.InstanceServer, memberConfig []api.ClusterMemberConfigKey) ([]string, revert.Hook, error) {
+	pools, err := client.GetStoragePools()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to fetch information about cluster storage pools: %w", err)
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	adopted := []string{}
+
+	for _, pool := range pools {
+		// Skip pending pools and remote pools, whose storage is shared with the other members.
+		if pool.Status == api.StoragePoolStatusPending || slices.Contains(db.StorageRemoteDriverNames(), pool.Driver) {
+			continue
+		}
+
+		config := maps.Clone(pool.Config)
+		if config == nil {
+			config = map[string]string{}
+		}
+
+		// Same as for the regular pool creation when joining.
+		delete(config, "volatile.initial_source")
+		delete(config, "zfs.pool_name")
+
+		for _, key := range memberConfig {
+			if key.Entity != "storage-pool" || key.Name != pool.Name || !slices.Contains(db.NodeSpecificStorageConfig, key.Key) {
+				continue
+			}
+
+			config[key.Key] = key.Value
+		}
+
+		poolInfo := api.StoragePool{
+			Name:           pool.Name,
+			Driver:         pool.Driver,
+			StoragePoolPut: api.StoragePoolPut{Description: pool.Description, Config: config},
+			Status:         api.StoragePoolStatusCreated,
+		}
+
+		tmpPool, err := storagePools.NewTemporary(s, &poolInfo)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to initialize storage pool %q: %w", pool.Name, err)
+		}
+
+		err = tmpPool.Driver().FillConfig()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed to evaluate the default configuration values for storage pool %q: %w", pool.Name, err)
+		}
+
+		err = tmpPool.Driver().Validate(poolInfo.Config)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed config validation for storage pool %q: %w", pool.Name, err)
+		}
+
+		// If the storage can't be mounted, it's not there and the pool gets created.
+		ourMount, err := tmpPool.Mount()
+		if err != nil {
+			logger.Debug("Storage of pool not found, it will be created", logger.Ctx{"pool": pool.Name, "err": err})
+			continue
+		}
+
+		// Only adopt storage which has volumes on it, as the pool creation also sets up empty storage.
+		vols, err := tmpPool.ListUnknownVolumes(nil)
+		if err != nil || len(vols) == 0 {
+			if ourMount {
+				_, _ = tmpPool.Unmount()
+			}
+
+			continue
+		}
+
+		logger.Info("Adopting existing storage for pool", logger.Ctx{"pool": pool.Name, "driver": pool.Driver, "config": tmpPool.Driver().Config()})
+
+		poolID, err := dbStoragePoolCreateAndUpdateCache(context.TODO(), s, pool.Name, pool.Description, pool.Driver, tmpPool.Driver().Config())
+		if err != nil {
+			if ourMount {
+				_, _ = tmpPool.Unmount()
+			}
+
+			return nil, nil, fmt.Errorf("Failed creating storage pool %q database entry: %w", pool.Name, err)
+		}
+
+		reverter.Add(func() {
+			_ = dbStoragePoolDeleteAndUpdateCache(context.Background(), s, pool.Name)
+
+			if ourMount {
+				_, _ = tmpPool.Unmount()
+			}
+		})
+
+		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.StoragePoolNodeCreated(poolID)
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("Failed marking storage pool %q local status as created: %w", pool.Name, err)
+		}
+
+		adopted = append(adopted, pool.Name)
+	}
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+
+	return adopted, cleanup, nil
+}
+
+// clusterReplaceRecoverInstances makes the instances of the replaced member usable on this server once it
+// has joined the cluster.
+//
+// The mount paths of the instances which are still known to the cluster are restored, then any volume found
+// on the adopted storage pools but unknown to the cluster is recovered. Failures are logged rather than
+// returned, so instances whose storage is gone don't prevent the others from being recovered.
+func clusterReplaceRecoverInstances(s *state.State, localClient incus.InstanceServer, adoptedPools []string) {
+	insts, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		logger.Warn("Failed loading instances of replaced member", logger.Ctx{"err": err})
+		return
+	}
+
+	for _, inst := range insts {
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+		pool, err := storagePools.LoadByInstance(s, inst)
+		if err != nil {
+			l.Warn("Failed loading storage pool of instance", logger.Ctx{"err": err})
+			continue
+		}
+
+		_, err = pool.ImportInstance(inst, nil, nil)
+		if err != nil {
+			l.Warn("Failed restoring instance mount path", logger.Ctx{"err": err})
+			continue
+		}
+	}
+
+	if len(adoptedPools) == 0 {
+		return
+	}
+
+	req := internalRecover.ValidatePost{Pools: make([]api.StoragePoolsPost, 0, len(adoptedPools))}
+	for _, poolName := range adoptedPools {
+		req.Pools = append(req.Pools, api.StoragePoolsPost{Name: poolName})
+	}
+
+	resp, _, err := localClient.RawQuery("POST", "/internal/recover/validate", req, "")
+	if err != nil {
+		logger.Warn("Failed scanning adopted storage pools for unknown volumes", logger.Ctx{"err": err})
+		return
+	}
+
+	var res internalRecover.ValidateResult
+
+	err = resp.MetadataAsStruct(&res)
+	if err != nil {
+		logger.Warn("Failed parsing storage pools scan result", logger.Ctx{"err": err})
+		return
+	}
+
+	if len(res.DependencyErrors) > 0 {
+		logger.Warn("Unknown volumes can't be recovered due to missing dependencies, run \"incus admin recover\" once they're available", logger.Ctx{"missing": res.DependencyErrors})
+		return
+	}
+
+	if len(res.UnknownVolumes) == 0 {
+		return
+	}
+
+	logger.Info("Recovering unknown volumes from adopted storage pools", logger.Ctx{"volumes": len(res.UnknownVolumes)})
+
+	_, _, err = localClient.RawQuery("POST", "/internal/recover/import", internalRecover.ImportPost{Pools: req.Pools}, "")
+	if err != nil {
+		logger.Warn("Failed recovering unknown volumes", logger.Ctx{"err": err})
+	}
+}
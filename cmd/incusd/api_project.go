@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -59,6 +60,12 @@ var projectStateCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: projectStateGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
 }
 
+var projectUsageCmd = APIEndpoint{
+	Path: "projects/{name}/usage",
+
+	Get: APIEndpointAction{Handler: projectUsageGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView, "name")},
+}
+
 var projectAccessCmd = APIEndpoint{
 	Path: "projects/{name}/access",
 
@@ -298,6 +305,33 @@ func projectUsedBy(ctx context.Context, tx *db.ClusterTx, project *cluster.Proje
 
 	usedBy = append(usedBy, storageVolumes...)
 
+	secrets, err := cluster.GetSecrets(ctx, tx.Tx(), cluster.SecretFilter{Project: &project.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, secret := range secrets {
+		usedBy = append(usedBy, api.NewURL().Path(version.APIVersion, "secrets", secret.Name).Project(project.Name).String())
+	}
+
+	stacks, err := cluster.GetStacks(ctx, tx.Tx(), cluster.StackFilter{Project: &project.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stack := range stacks {
+		usedBy = append(usedBy, api.NewURL().Path(version.APIVersion, "stacks", stack.Name).Project(project.Name).String())
+	}
+
+	instanceSets, err := cluster.GetInstanceSets(ctx, tx.Tx(), cluster.InstanceSetFilter{Project: &project.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instanceSet := range instanceSets {
+		usedBy = append(usedBy, api.NewURL().Path(version.APIVersion, "instance-sets", instanceSet.Name).Project(project.Name).String())
+	}
+
 	return usedBy, nil
 }
 
@@ -334,11 +368,37 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 	// Parse the request.
 	project := api.ProjectsPost{}
 
-	// Set default features.
+	err := json.NewDecoder(r.Body).Decode(&project)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	if project.Config == nil {
 		project.Config = map[string]string{}
 	}
 
+	// Load the template the project is created from, the keys set in the request take precedence.
+	var template *api.ProjectTemplate
+	var templateID int
+	if project.Template != "" {
+		template, templateID, err = projectTemplateLoad(r.Context(), s, project.Template)
+		if err != nil {
+			if response.IsNotFoundError(err) {
+				return response.BadRequest(fmt.Errorf("Project template %q not found", project.Template))
+			}
+
+			return response.SmartError(err)
+		}
+
+		for k, v := range template.Config {
+			_, ok := project.Config[k]
+			if !ok {
+				project.Config[k] = v
+			}
+		}
+	}
+
+	// Set default features.
 	for featureName, featureInfo := range cluster.ProjectFeatures {
 		_, ok := project.Config[featureName]
 		if !ok && featureInfo.DefaultEnabled {
@@ -346,11 +406,6 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	err := json.NewDecoder(r.Body).Decode(&project)
-	if err != nil {
-		return response.BadRequest(err)
-	}
-
 	// Quick checks.
 	err = projectValidateName(project.Name)
 	if err != nil {
@@ -400,6 +455,13 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		logger.Error("Failed to add project to authorizer", logger.Ctx{"name": project.Name, "error": err})
 	}
 
+	if template != nil {
+		err = projectTemplateCreate(s, r, project.Name, id, templateID, template.Writable())
+		if err != nil {
+			return response.SmartError(fmt.Errorf("Failed applying project template %q: %w", project.Template, err))
+		}
+	}
+
 	requestor := request.CreateRequestor(r)
 	lc := lifecycle.ProjectCreated.Event(project.Name, requestor, nil)
 	s.Events.SendLifecycle(project.Name, lc)
@@ -1004,6 +1066,12 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 				return response.InternalError(fmt.Errorf("Bad usedBy entry: %s", u))
 			}
 
+			// Secrets, stacks and instance sets are deleted along with the project, their resources are
+			// deleted below like any other.
+			if slices.Contains([]string{"secrets", "stacks", "instance-sets"}, elements[2]) {
+				continue
+			}
+
 			if elements[2] == "storage-pools" {
 				if elements[4] == "buckets" {
 					if entries["storage-buckets"] == nil {
@@ -1296,6 +1364,101 @@ func projectStateGet(d *Daemon, r *http.Request) response.Response {
 	return response.SyncResponse(true, &state)
 }
 
+// swagger:operation GET /1.0/projects/{name}/usage projects project_usage_get
+//
+//	Get the project usage
+//
+//	Gets the resources consumed by the project over a period of time.
+//	The period defaults to the current month.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: from
+//	    description: Start of the period (RFC3339)
+//	    type: string
+//	    example: 2026-09-01T00:00:00Z
+//	  - in: query
+//	    name: to
+//	    description: End of the period (RFC3339)
+//	    type: string
+//	    example: 2026-10-01T00:00:00Z
+//	responses:
+//	  "200":
+//	    description: Project usage
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectUsage"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectUsageGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	now := time.Now().UTC()
+	to := now
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	if r.FormValue("from") != "" {
+		from, err = time.Parse(time.RFC3339, r.FormValue("from"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid start of the period: %w", err))
+		}
+	}
+
+	if r.FormValue("to") != "" {
+		to, err = time.Parse(time.RFC3339, r.FormValue("to"))
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid end of the period: %w", err))
+		}
+	}
+
+	if !from.Before(to) {
+		return response.BadRequest(fmt.Errorf("The start of the period must be before its end"))
+	}
+
+	var usage *api.ProjectUsage
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		projectID, err := cluster.GetProjectID(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		usage, err = cluster.GetProjectUsage(ctx, tx.Tx(), int(projectID), from, to)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, usage)
+}
+
 // Check if a project is empty.
 func projectIsEmpty(ctx context.Context, project *cluster.Project, tx *db.ClusterTx) (bool, error) {
 	usedBy, err := projectUsedBy(ctx, tx, project)
@@ -1474,6 +1637,48 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Maximum disk space used by the project
 		"limits.disk": validate.Optional(validate.IsSize),
 
+		// gendoc:generate(entity=project, group=limits, key=limits.disk.iops.read)
+		// This value is the maximum value for the sum of the read limits (in IOPS) set through `limits.read` or `limits.max` on the disk devices of the instances of the project.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum read IOPS of the disks of the project
+		"limits.disk.iops.read": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.disk.iops.write)
+		// This value is the maximum value for the sum of the write limits (in IOPS) set through `limits.write` or `limits.max` on the disk devices of the instances of the project.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum write IOPS of the disks of the project
+		"limits.disk.iops.write": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.ingress)
+		// This value is the maximum value for the sum of the `limits.ingress` (or `limits.max`) configurations set on the network interfaces of the instances of the project.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum incoming bandwidth of the network interfaces of the project
+		"limits.ingress": validate.Optional(validate.IsBitSize),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.egress)
+		// This value is the maximum value for the sum of the `limits.egress` (or `limits.max`) configurations set on the network interfaces of the instances of the project.
+		// ---
+		//  type: string
+		//  shortdesc: Maximum outgoing bandwidth of the network interfaces of the project
+		"limits.egress": validate.Optional(validate.IsBitSize),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.snapshots)
+		// This value is the maximum number of instance and custom volume snapshots in the project.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of snapshots that the project can have
+		"limits.snapshots": validate.Optional(validate.IsUint32),
+
+		// gendoc:generate(entity=project, group=limits, key=limits.backups)
+		// This value is the maximum number of instance, custom volume and storage bucket backups in the project.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of backups that the project can have
+		"limits.backups": validate.Optional(validate.IsUint32),
+
 		// gendoc:generate(entity=project, group=limits, key=limits.networks)
 		//
 		// ---
@@ -1750,6 +1955,15 @@ func projectValidateConfig(s *state.State, config map[string]string) error {
 		//  shortdesc: Which network zones can be used in this project
 		"restricted.networks.zones": validate.IsListOf(validate.IsAny),
 
+		// gendoc:generate(entity=project, group=restricted, key=restricted.replication)
+		// Possible values are `allow` or `block`.
+		// When set to `allow`, instances can be replicated to the replication remotes defined by the server administrator through {config:option}`instance-replication:replication.remote`.
+		// ---
+		//  type: string
+		//  defaultdesc: `block`
+		//  shortdesc: Whether to prevent replicating instances to a remote server
+		"restricted.replication": isEitherAllowOrBlock,
+
 		// gendoc:generate(entity=project, group=restricted, key=restricted.snapshots)
 		//
 		// ---
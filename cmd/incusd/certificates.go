@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rsa"
 	"crypto/x509"
+	"database/sql"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
@@ -25,11 +26,13 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/warningtype"
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
@@ -243,6 +246,7 @@ func updateCertificateCache(d *Daemon) {
 
 	newCerts := map[certificate.Type]map[string]x509.Certificate{}
 	newProjects := map[string][]string{}
+	newExpiries := map[string]time.Time{}
 
 	var certs []*api.Certificate
 	var dbCerts []dbCluster.Certificate
@@ -292,6 +296,10 @@ func updateCertificateCache(d *Daemon) {
 			newProjects[localtls.CertFingerprint(cert)] = certs[i].Projects
 		}
 
+		if dbCert.ExpiryDate.Valid {
+			newExpiries[localtls.CertFingerprint(cert)] = dbCert.ExpiryDate.Time
+		}
+
 		// Add server certs to list of certificates to store in local database to allow cluster restart.
 		if dbCert.Type == certificate.TypeServer {
 			localCerts = append(localCerts, dbCert)
@@ -309,6 +317,117 @@ func updateCertificateCache(d *Daemon) {
 	}
 
 	d.clientCerts.SetCertificatesAndProjects(newCerts, newProjects)
+	d.clientCerts.SetExpiries(newExpiries)
+}
+
+// certificateExpiryWarningPeriod is how long before the trust of a certificate expires that a warning is raised.
+const certificateExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// certificateTrustExpiry returns when the trust of the certificate expires, either through its expiry date or
+// through the validity of the certificate itself.
+func certificateTrustExpiry(dbCert dbCluster.Certificate) (time.Time, error) {
+	certBlock, _ := pem.Decode([]byte(dbCert.Certificate))
+	if certBlock == nil {
+		return time.Time{}, fmt.Errorf("Failed decoding certificate")
+	}
+
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Failed parsing certificate: %w", err)
+	}
+
+	if dbCert.ExpiryDate.Valid && dbCert.ExpiryDate.Time.Before(cert.NotAfter) {
+		return dbCert.ExpiryDate.Time, nil
+	}
+
+	return cert.NotAfter, nil
+}
+
+func certificateExpiryWarningsTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := certificateExpiryWarnings(ctx, d.State())
+		if err != nil {
+			logger.Warn("Failed checking trusted certificates expiry", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Daily()
+}
+
+// certificateExpiryWarnings raises a warning for each trusted client certificate expiring soon,
+// and resolves the warnings of the certificates which have been renewed, extended or removed since.
+func certificateExpiryWarnings(ctx context.Context, s *state.State) error {
+	// If we are clustered, let the leader handle the warnings.
+	if s.ServerClustered {
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil {
+			return err
+		}
+
+		if s.LocalConfig.ClusterAddress() != leader {
+			return nil
+		}
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbCerts, err := dbCluster.GetCertificates(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		// Find the expiring certificates.
+		expiring := map[int]string{}
+		for _, dbCert := range dbCerts {
+			if dbCert.Type == certificate.TypeServer {
+				continue
+			}
+
+			expiry, err := certificateTrustExpiry(dbCert)
+			if err != nil {
+				logger.Warn("Failed getting certificate expiry", logger.Ctx{"name": dbCert.Name, "err": err})
+				continue
+			}
+
+			if time.Until(expiry) > certificateExpiryWarningPeriod {
+				continue
+			}
+
+			if time.Now().After(expiry) {
+				expiring[dbCert.ID] = fmt.Sprintf("Trust of certificate %q (%s) expired on %s", dbCert.Name, dbCert.Fingerprint, expiry.UTC().Format(time.RFC3339))
+			} else {
+				expiring[dbCert.ID] = fmt.Sprintf("Trust of certificate %q (%s) expires on %s", dbCert.Name, dbCert.Fingerprint, expiry.UTC().Format(time.RFC3339))
+			}
+		}
+
+		// Resolve the warnings of the certificates which aren't expiring anymore.
+		typeCode := warningtype.TrustedCertificateExpiring
+		warnings, err := dbCluster.GetWarnings(ctx, tx.Tx(), dbCluster.WarningFilter{TypeCode: &typeCode})
+		if err != nil {
+			return err
+		}
+
+		for _, w := range warnings {
+			_, found := expiring[w.EntityID]
+			if found || w.Status == warningtype.StatusResolved {
+				continue
+			}
+
+			err = tx.UpdateWarningStatus(w.UUID, warningtype.StatusResolved)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Raise the warnings of the expiring certificates.
+		for certID, message := range expiring {
+			err = tx.UpsertWarning(ctx, "", "", dbCluster.TypeCertificate, certID, typeCode, message)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 // updateCertificateCacheFromLocal loads trusted server certificates from local database into memory.
@@ -563,7 +682,7 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 
 	// Access check.
 	// Check if the user is already trusted.
-	trusted, _, _, err := d.Authenticate(nil, r)
+	trusted, _, _, _, err := d.Authenticate(nil, r)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -627,6 +746,7 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 					req.Type = tokenReq.Type
 					req.Restricted = tokenReq.Restricted
 					req.Projects = tokenReq.Projects
+					req.ExpiresAt = tokenReq.ExpiresAt
 				case map[string]any:
 					req.Name = tokenReq["name"].(string)
 					req.Type = tokenReq["type"].(string)
@@ -635,6 +755,16 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 						req.Projects = append(req.Projects, project.(string))
 					}
 
+					expiresAt, ok := tokenReq["expires_at"].(string)
+					if ok {
+						expiry, err := time.Parse(time.RFC3339Nano, expiresAt)
+						if err != nil {
+							return response.InternalError(fmt.Errorf("Bad certificate add operation expiry: %w", err))
+						}
+
+						req.ExpiresAt = &expiry
+					}
+
 				default:
 					return response.InternalError(fmt.Errorf("Bad certificate add operation data"))
 				}
@@ -735,6 +865,10 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return response.BadRequest(fmt.Errorf("Trust expiry date must be in the future"))
+	}
+
 	// Calculate the fingerprint.
 	fingerprint := localtls.CertFingerprint(cert)
 
@@ -772,6 +906,10 @@ func certificatesPost(d *Daemon, r *http.Request) response.Response {
 				Description: req.Description,
 			}
 
+			if req.ExpiresAt != nil {
+				dbCert.ExpiryDate = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+			}
+
 			_, err := dbCluster.CreateCertificateWithProjects(ctx, tx.Tx(), dbCert, req.Projects)
 			return err
 		})
@@ -1029,6 +1167,10 @@ func doCertificateUpdate(d *Daemon, dbInfo api.Certificate, req api.CertificateP
 			Description: req.Description,
 		}
 
+		if req.ExpiresAt != nil {
+			dbCert.ExpiryDate = sql.NullTime{Time: req.ExpiresAt.UTC(), Valid: true}
+		}
+
 		var userCanEditCertificate bool
 		err = s.Authorizer.CheckPermission(r.Context(), r, auth.ObjectCertificate(dbInfo.Fingerprint), auth.EntitlementCanEdit)
 		if err == nil {
@@ -1047,7 +1189,7 @@ func doCertificateUpdate(d *Daemon, dbInfo api.Certificate, req api.CertificateP
 			}
 
 			// Ensure the user in not trying to change fields other than the certificate.
-			if dbInfo.Restricted != req.Restricted || dbInfo.Name != req.Name || len(dbInfo.Projects) != len(req.Projects) {
+			if dbInfo.Restricted != req.Restricted || dbInfo.Name != req.Name || len(dbInfo.Projects) != len(req.Projects) || !certificateExpiryEqual(dbInfo.ExpiresAt, req.ExpiresAt) {
 				return response.Forbidden(fmt.Errorf("Only the certificate can be changed"))
 			}
 
@@ -1067,6 +1209,10 @@ func doCertificateUpdate(d *Daemon, dbInfo api.Certificate, req api.CertificateP
 				Description: req.Description,
 			}
 
+			if dbInfo.ExpiresAt != nil {
+				dbCert.ExpiryDate = sql.NullTime{Time: dbInfo.ExpiresAt.UTC(), Valid: true}
+			}
+
 			certProjects = dbInfo.Projects
 
 			if req.Certificate != "" && dbInfo.Certificate != req.Certificate {
@@ -1288,3 +1434,12 @@ func certificateValidate(cert *x509.Certificate) error {
 
 	return nil
 }
+
+// certificateExpiryEqual returns whether both trust expiry dates are the same, unset ones included.
+func certificateExpiryEqual(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Equal(*b)
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"crypto/x509"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -31,6 +33,7 @@ import (
 	"github.com/lxc/incus/v6/internal/linux"
 	"github.com/lxc/incus/v6/internal/rsync"
 	"github.com/lxc/incus/v6/internal/server/apparmor"
+	"github.com/lxc/incus/v6/internal/server/audit"
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/auth/oidc"
 	"github.com/lxc/incus/v6/internal/server/bgp"
@@ -156,6 +159,9 @@ type Daemon struct {
 
 	lokiClient *loki.Client
 
+	// Audit file and syslog writer.
+	auditLogger *audit.Logger
+
 	// Authorization.
 	authorizer auth.Authorizer
 
@@ -416,7 +422,7 @@ func allowPermission(objectType auth.ObjectType, entitlement auth.Entitlement, m
 
 // Convenience function around Authenticate.
 func (d *Daemon) checkTrustedClient(r *http.Request) error {
-	trusted, _, _, err := d.Authenticate(nil, r)
+	trusted, _, _, _, err := d.Authenticate(nil, r)
 	if !trusted || err != nil {
 		if err != nil {
 			return err
@@ -434,6 +440,18 @@ func (d *Daemon) checkTrustedClient(r *http.Request) error {
 func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.Certificate, error) {
 	certs := d.clientCerts.GetCertificates()
 
+	// Drop the certificates whose trust has expired.
+	now := time.Now()
+	for fingerprint, expiry := range d.clientCerts.GetExpiries() {
+		if now.Before(expiry) {
+			continue
+		}
+
+		for _, certEntries := range certs {
+			delete(certEntries, fingerprint)
+		}
+	}
+
 	// If not in PKI mode, return all certificates.
 	if !util.PathExists(internalUtil.VarPath("server.ca")) {
 		return certs, nil
@@ -473,12 +491,12 @@ func (d *Daemon) getTrustedCertificates() (map[certificate.Type]map[string]x509.
 // will validate the TLS certificate.
 //
 // This does not perform authorization, only validates authentication.
-// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, and the type of
-// client that has been authenticated (cluster, unix, or tls).
-func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, error) {
+// Returns whether trusted or not, the username (or certificate fingerprint) of the trusted client, the type of
+// client that has been authenticated (cluster, unix, or tls) and, for OpenID Connect, the identity provider groups of the user.
+func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, string, string, []string, error) {
 	trustedCerts, err := d.getTrustedCertificates()
 	if err != nil {
-		return false, "", "", err
+		return false, "", "", nil, err
 	}
 
 	// Allow internal cluster traffic by checking against the trusted certfificates.
@@ -486,7 +504,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, fingerprint := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeServer], d.endpoints.NetworkCert(), false)
 			if trusted {
-				return true, fingerprint, "cluster", nil
+				return true, fingerprint, "cluster", nil, nil
 			}
 		}
 	}
@@ -496,38 +514,38 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		if w != nil {
 			cred, err := ucred.GetCredFromContext(r.Context())
 			if err != nil {
-				return false, "", "", err
+				return false, "", "", nil, err
 			}
 
 			u, err := user.LookupId(fmt.Sprintf("%d", cred.Uid))
 			if err != nil {
-				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil
+				return true, fmt.Sprintf("uid=%d", cred.Uid), "unix", nil, nil
 			}
 
-			return true, u.Username, "unix", nil
+			return true, u.Username, "unix", nil, nil
 		}
 
-		return true, "", "unix", nil
+		return true, "", "unix", nil, nil
 	}
 
 	// DevIncus unix socket credentials on main API.
 	if r.RemoteAddr == "@dev_incus" {
-		return false, "", "", fmt.Errorf("Main API query can't come from /dev/incus socket")
+		return false, "", "", nil, fmt.Errorf("Main API query can't come from /dev/incus socket")
 	}
 
 	// Cluster notification with wrong certificate.
 	if isClusterNotification(r) {
-		return false, "", "", fmt.Errorf("Cluster notification isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster notification isn't using trusted server certificate")
 	}
 
 	// Cluster internal client with wrong certificate.
 	if isClusterInternal(r) {
-		return false, "", "", fmt.Errorf("Cluster internal client isn't using trusted server certificate")
+		return false, "", "", nil, fmt.Errorf("Cluster internal client isn't using trusted server certificate")
 	}
 
 	// Bad query, no TLS found.
 	if r.TLS == nil {
-		return false, "", "", fmt.Errorf("Bad/missing TLS on network query")
+		return false, "", "", nil, fmt.Errorf("Bad/missing TLS on network query")
 	}
 
 	// Load the certificates.
@@ -538,18 +556,31 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	if jwtOk {
 		trusted, username := localUtil.CheckTrustState(*cert, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
+		}
+	}
+
+	// Check for API token.
+	secret := request.AuthToken(r)
+	if secret != "" {
+		secretHash := request.HashAuthToken(secret)
+		token, err := authTokenLoad(r.Context(), d.State(), dbCluster.AuthTokenFilter{SecretHash: &secretHash})
+		if err != nil {
+			logger.Debug("Rejecting API token", logger.Ctx{"err": err, "ip": r.RemoteAddr})
+			return false, "", "", nil, nil
 		}
+
+		return true, token.Identity, api.AuthenticationMethodToken, nil, nil
 	}
 
 	// Check for JWT token signed by an OpenID Connect provider.
 	if d.oidcVerifier != nil && d.oidcVerifier.IsRequest(r) {
-		userName, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
+		userName, groups, err := d.oidcVerifier.Auth(d.shutdownCtx, w, r)
 		if err != nil {
-			return false, "", "", err
+			return false, "", "", nil, err
 		}
 
-		return true, userName, api.AuthenticationMethodOIDC, nil
+		return true, userName, api.AuthenticationMethodOIDC, groups, nil
 	}
 
 	// Validate metrics TLS certificates.
@@ -557,7 +588,7 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 		for _, i := range r.TLS.PeerCertificates {
 			trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeMetrics], d.endpoints.NetworkCert(), trustCACertificates)
 			if trusted {
-				return true, username, api.AuthenticationMethodTLS, nil
+				return true, username, api.AuthenticationMethodTLS, nil, nil
 			}
 		}
 	}
@@ -566,12 +597,12 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request) (bool, str
 	for _, i := range r.TLS.PeerCertificates {
 		trusted, username := localUtil.CheckTrustState(*i, trustedCerts[certificate.TypeClient], d.endpoints.NetworkCert(), trustCACertificates)
 		if trusted {
-			return true, username, api.AuthenticationMethodTLS, nil
+			return true, username, api.AuthenticationMethodTLS, nil, nil
 		}
 	}
 
 	// Reject unauthorized.
-	return false, "", "", nil
+	return false, "", "", nil, nil
 }
 
 // State creates a new State instance linked to our internal db and os.
@@ -646,7 +677,19 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 		}
 
 		// Authentication
-		trusted, username, protocol, err := d.Authenticate(w, r)
+		trusted, username, protocol, groups, err := d.Authenticate(w, r)
+
+		// Record the outcome of mutating requests once handled.
+		audited := isAuditedRequest(r, version, protocol)
+		auditBodyDigest := ""
+		auditStatusCode := http.StatusOK
+		auditAuthorization := api.EventAuditAuthorizationAllowed
+		if audited {
+			defer func() {
+				d.auditRequest(r, username, protocol, auditBodyDigest, auditStatusCode, auditAuthorization)
+			}()
+		}
+
 		if err != nil {
 			_, ok := err.(*oidc.AuthError)
 			if ok {
@@ -655,6 +698,8 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 					_ = d.oidcVerifier.WriteHeaders(w)
 				}
 
+				auditStatusCode = http.StatusUnauthorized
+				auditAuthorization = api.EventAuditAuthorizationUnauthenticated
 				_ = response.Unauthorized(err).Render(w)
 				return
 			}
@@ -665,6 +710,8 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			// Except for the initial cluster accept request (done over trusted TLS)
 			if !trusted || c.Path != "cluster/accept" || protocol != api.AuthenticationMethodTLS {
 				logger.Warn("Rejecting remote internal API request", logger.Ctx{"ip": r.RemoteAddr})
+				auditStatusCode = http.StatusForbidden
+				auditAuthorization = api.EventAuditAuthorizationDenied
 				_ = response.Forbidden(nil).Render(w)
 				return
 			}
@@ -684,13 +731,39 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			// Add authentication/authorization context data.
 			ctx := context.WithValue(r.Context(), request.CtxUsername, username)
 			ctx = context.WithValue(ctx, request.CtxProtocol, protocol)
+			ctx = context.WithValue(ctx, request.CtxIdentityProviderGroups, groups)
 
-			// Add forwarded requestor data.
-			if protocol == "cluster" {
+			// Add forwarded requestor data, from other cluster members or from requests made on behalf of
+			// another requestor through the unix socket.
+			if protocol == "cluster" || (protocol == "unix" && r.Header.Get(request.HeaderForwardedProtocol) != "") {
 				// Add authentication/authorization context data.
 				ctx = context.WithValue(ctx, request.CtxForwardedAddress, r.Header.Get(request.HeaderForwardedAddress))
 				ctx = context.WithValue(ctx, request.CtxForwardedUsername, r.Header.Get(request.HeaderForwardedUsername))
 				ctx = context.WithValue(ctx, request.CtxForwardedProtocol, r.Header.Get(request.HeaderForwardedProtocol))
+				ctx = context.WithValue(ctx, request.CtxForwardedIdentityProviderGroups, r.Header.Values(request.HeaderForwardedIdentityProviderGroups))
+			}
+
+			// Add the API token scope, either from the request or from the cluster member or local request which forwarded it.
+			var tokenFilter *dbCluster.AuthTokenFilter
+			if protocol == api.AuthenticationMethodToken {
+				secretHash := request.HashAuthToken(request.AuthToken(r))
+				tokenFilter = &dbCluster.AuthTokenFilter{SecretHash: &secretHash}
+			} else if (protocol == "cluster" || protocol == "unix") && r.Header.Get(request.HeaderForwardedAuthToken) != "" {
+				tokenName := r.Header.Get(request.HeaderForwardedAuthToken)
+				tokenFilter = &dbCluster.AuthTokenFilter{Name: &tokenName}
+			}
+
+			if tokenFilter != nil {
+				token, err := authTokenLoad(ctx, d.State(), *tokenFilter)
+				if err != nil {
+					logger.Warn("Rejecting request with invalid API token", logger.Ctx{"ip": r.RemoteAddr, "err": err})
+					auditStatusCode = http.StatusForbidden
+					auditAuthorization = api.EventAuditAuthorizationUnauthenticated
+					_ = response.Forbidden(nil).Render(w)
+					return
+				}
+
+				ctx = context.WithValue(ctx, request.CtxAuthToken, token)
 			}
 
 			r = r.WithContext(ctx)
@@ -702,23 +775,35 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			}
 
 			logger.Warn("Rejecting request from untrusted client", logger.Ctx{"ip": r.RemoteAddr})
+			auditStatusCode = http.StatusForbidden
+			auditAuthorization = api.EventAuditAuthorizationUnauthenticated
 			_ = response.Forbidden(nil).Render(w)
 			return
 		}
 
-		// Dump full request JSON when in debug mode
-		if daemon.Debug && r.Method != "GET" && localUtil.IsJSONRequest(r) {
+		// Capture the full request JSON to dump it when in debug mode and to digest it for the audit record.
+		if (daemon.Debug || audited) && r.Method != "GET" && localUtil.IsJSONRequest(r) {
 			newBody := &bytes.Buffer{}
 			captured := &bytes.Buffer{}
 			multiW := io.MultiWriter(newBody, captured)
 			_, err := io.Copy(multiW, r.Body)
 			if err != nil {
+				auditStatusCode = http.StatusInternalServerError
 				_ = response.InternalError(err).Render(w)
 				return
 			}
 
 			r.Body = internalIO.BytesReadCloser{Buf: newBody}
-			localUtil.DebugJSON("API Request", captured, logger.AddContext(logCtx))
+
+			if audited {
+				digest := sha256.Sum256(captured.Bytes())
+				auditBodyDigest = hex.EncodeToString(digest[:])
+			}
+
+			// Never log the values of secrets.
+			if daemon.Debug && !strings.HasPrefix(r.URL.Path, "/1.0/secrets") {
+				localUtil.DebugJSON("API Request", captured, logger.AddContext(logCtx))
+			}
 		}
 
 		// Actually process the request
@@ -748,6 +833,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 		}
 
 		if d.shutdownCtx.Err() == context.Canceled && !allowedDuringShutdown() {
+			auditStatusCode = http.StatusServiceUnavailable
 			_ = response.Unavailable(fmt.Errorf("Shutting down")).Render(w)
 			return
 		}
@@ -764,6 +850,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 
 			// If the request is not trusted, only call the handler if the action allows it.
 			if !trusted && !action.AllowUntrusted {
+				auditAuthorization = api.EventAuditAuthorizationUnauthenticated
 				return response.Forbidden(errors.New("You must be authenticated"))
 			}
 
@@ -771,6 +858,7 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 			if action.AccessHandler != nil {
 				resp := action.AccessHandler(d, r)
 				if resp != response.EmptySyncResponse {
+					auditAuthorization = api.EventAuditAuthorizationDenied
 					return resp
 				}
 			}
@@ -801,9 +889,12 @@ func (d *Daemon) createCmd(restAPI *mux.Router, version string, c APIEndpoint) {
 		}
 
 		// Handle errors
+		auditStatusCode = resp.Code()
 		err = resp.Render(w)
 		if err != nil {
-			writeErr := response.SmartError(err).Render(w)
+			errResp := response.SmartError(err)
+			auditStatusCode = errResp.Code()
+			writeErr := errResp.Render(w)
 			if writeErr != nil {
 				logger.Error("Failed writing error for HTTP response", logger.Ctx{"url": uri, "err": err, "writeErr": writeErr})
 			}
@@ -914,6 +1005,35 @@ func (d *Daemon) setupLoki(URL string, cert string, key string, caCert string, i
 	return nil
 }
 
+// setupAudit (re)configures where audit records get written to, beside the Loki server.
+func (d *Daemon) setupAudit(file bool, maxSize int64, maxFiles int64, useSyslog bool) error {
+	// Stop any existing audit logger.
+	if d.auditLogger != nil {
+		d.internalListener.RemoveHandler("audit")
+		_ = d.auditLogger.Close()
+		d.auditLogger = nil
+	}
+
+	if !file && !useSyslog {
+		return nil
+	}
+
+	path := ""
+	if file {
+		path = internalUtil.LogPath("audit.log")
+	}
+
+	auditLogger, err := audit.NewLogger(path, maxSize, int(maxFiles), useSyslog)
+	if err != nil {
+		return err
+	}
+
+	d.auditLogger = auditLogger
+	d.internalListener.AddHandler("audit", d.auditLogger.HandleEvent)
+
+	return nil
+}
+
 func (d *Daemon) init() error {
 	var err error
 
@@ -1472,10 +1592,13 @@ func (d *Daemon) init() error {
 
 	d.gateway.HeartbeatOfflineThreshold = d.globalConfig.OfflineThreshold()
 	lokiURL, lokiUsername, lokiPassword, lokiCACert, lokiInstance, lokiLoglevel, lokiLabels, lokiTypes := d.globalConfig.LokiServer()
-	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim := d.globalConfig.OIDCServer()
+	auditFile, auditFileMaxSize, auditFileMaxFiles, auditSyslog := d.globalConfig.Audit()
+	oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim := d.globalConfig.OIDCServer()
 	syslogSocketEnabled := d.localConfig.SyslogSocket()
 	openfgaAPIURL, openfgaAPIToken, openfgaStoreID := d.globalConfig.OpenFGA()
 	instancePlacementScriptlet := d.globalConfig.InstancesPlacementScriptlet()
+	instanceAdmissionScriptlet := d.globalConfig.InstancesAdmissionScriptlet()
+	clusterRebalanceScriptlet := d.globalConfig.ClusterRebalanceScriptlet()
 	authorizationScriptlet := d.globalConfig.AuthorizationScriptlet()
 
 	d.endpoints.NetworkUpdateTrustedProxy(d.globalConfig.HTTPSTrustedProxy())
@@ -1489,6 +1612,12 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup audit logger.
+	err = d.setupAudit(auditFile, auditFileMaxSize, auditFileMaxFiles, auditSyslog)
+	if err != nil {
+		return err
+	}
+
 	// Setup syslog listener.
 	if syslogSocketEnabled {
 		err = d.setupSyslogSocket(true)
@@ -1499,7 +1628,7 @@ func (d *Daemon) init() error {
 
 	// Setup OIDC authentication.
 	if oidcIssuer != "" && oidcClientID != "" {
-		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim)
+		d.oidcVerifier, err = oidc.NewVerifier(oidcIssuer, oidcClientID, oidcScope, oidcAudience, oidcClaim, oidcGroupsClaim)
 		if err != nil {
 			return err
 		}
@@ -1521,6 +1650,14 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Setup the built-in role-based access control.
+	if d.globalConfig.AuthorizationRBAC() {
+		err = d.setupRBAC(true)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Setup BGP listener.
 	d.bgp = bgp.NewServer()
 	if bgpAddress != "" && bgpASN != 0 && bgpRouterID != "" {
@@ -1612,6 +1749,22 @@ func (d *Daemon) init() error {
 		}
 	}
 
+	// Load instance admission scriptlet.
+	if instanceAdmissionScriptlet != "" {
+		err = scriptletLoad.InstanceAdmissionSet(instanceAdmissionScriptlet)
+		if err != nil {
+			logger.Warn("Failed loading instance admission scriptlet", logger.Ctx{"err": err})
+		}
+	}
+
+	// Load cluster re-balancing scriptlet.
+	if clusterRebalanceScriptlet != "" {
+		err = scriptletLoad.ClusterRebalanceSet(clusterRebalanceScriptlet)
+		if err != nil {
+			logger.Warn("Failed loading cluster re-balancing scriptlet", logger.Ctx{"err": err})
+		}
+	}
+
 	// Apply all patches that need to be run after networks are initialized.
 	err = patchesApply(d, patchPostNetworks)
 	if err != nil {
@@ -1735,6 +1888,27 @@ func (d *Daemon) init() error {
 
 		// Remove expired tokens (hourly)
 		d.tasks.Add(autoRemoveExpiredTokensTask(d))
+
+		// Warn about trusted certificates expiring soon (daily)
+		d.tasks.Add(certificateExpiryWarningsTask(d))
+
+		// Record the resources consumed by the projects (every 5 minutes)
+		d.tasks.Add(projectUsageTask(d))
+
+		// Stop idle instances (minutely)
+		d.tasks.Add(instanceIdleStopTask(d))
+
+		// Replicate instances to their remote targets (minutely)
+		d.tasks.Add(instanceReplicationTask(d))
+
+		// Recreate the missing instances of instance sets (every 5 minutes)
+		d.tasks.Add(instanceSetReconcileTask(d))
+
+		// Back up the global database (minutely check of configurable cron expression)
+		d.tasks.Add(databaseBackupTask(d))
+
+		// Reload the role-based access control groups (minutely)
+		d.tasks.Add(authRefreshTask(d))
 	}
 
 	// Start all background tasks
@@ -1743,6 +1917,13 @@ func (d *Daemon) init() error {
 	// Restore instances
 	instancesStart(d.State(), instances)
 
+	// Resume health checks of instances which kept running while the daemon was down
+	for _, inst := range instances {
+		if inst.IsRunning() {
+			instanceDrivers.HealthcheckStart(d.State(), inst)
+		}
+	}
+
 	// Re-balance in case things changed while the daemon was down
 	deviceTaskBalance(d.State())
 
@@ -2284,6 +2465,138 @@ func (d *Daemon) setupAuthorizationScriptlet(scriptlet string) error {
 	return nil
 }
 
+// Setup the built-in role-based access control.
+func (d *Daemon) setupRBAC(enable bool) error {
+	var err error
+
+	if !enable {
+		// Reset to default authorizer if RBAC was in use.
+		_, ok := d.authorizer.(*auth.RBAC)
+		if ok {
+			d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverTLS, logger.Log, d.clientCerts)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	// Fail if not using the default tls or rbac authorizer.
+	switch d.authorizer.(type) {
+	case *auth.TLS, *auth.RBAC:
+		d.authorizer, err = auth.LoadAuthorizer(d.shutdownCtx, auth.DriverRBAC, logger.Log, d.clientCerts, auth.WithRBACGroupsFunc(d.rbacGroups))
+		if err != nil {
+			return err
+		}
+
+	default:
+		return errors.New("Attempting to setup role-based access control while another authorizer is already set")
+	}
+
+	return nil
+}
+
+// rbacGroups loads the groups of the built-in role-based access control along with their grants.
+func (d *Daemon) rbacGroups(ctx context.Context) ([]auth.RBACGroup, error) {
+	groups := map[int]*auth.RBACGroup{}
+	var groupIDs []int
+
+	err := d.db.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		err := query.Scan(ctx, tx.Tx(), "SELECT id, name FROM auth_groups ORDER BY name", func(scan func(dest ...any) error) error {
+			group := &auth.RBACGroup{Identities: map[string][]string{}}
+
+			var id int
+			err := scan(&id, &group.Name)
+			if err != nil {
+				return err
+			}
+
+			groups[id] = group
+			groupIDs = append(groupIDs, id)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = query.Scan(ctx, tx.Tx(), "SELECT auth_group_id, authentication_method, identifier FROM auth_groups_identities", func(scan func(dest ...any) error) error {
+			var id int
+			var authenticationMethod string
+			var identifier string
+
+			err := scan(&id, &authenticationMethod, &identifier)
+			if err != nil {
+				return err
+			}
+
+			group, ok := groups[id]
+			if ok {
+				group.Identities[authenticationMethod] = append(group.Identities[authenticationMethod], identifier)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = query.Scan(ctx, tx.Tx(), "SELECT auth_group_id, name FROM auth_groups_identity_provider_groups", func(scan func(dest ...any) error) error {
+			var id int
+			var name string
+
+			err := scan(&id, &name)
+			if err != nil {
+				return err
+			}
+
+			group, ok := groups[id]
+			if ok {
+				group.IdentityProviderGroups = append(group.IdentityProviderGroups, name)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		stmt := `
+SELECT auth_groups_roles.auth_group_id, auth_roles_permissions.entity_type, auth_roles_permissions.entitlement, coalesce(projects.name, '')
+  FROM auth_groups_roles
+  JOIN auth_roles_permissions ON auth_roles_permissions.auth_role_id = auth_groups_roles.auth_role_id
+  LEFT JOIN projects ON projects.id = auth_groups_roles.project_id`
+
+		return query.Scan(ctx, tx.Tx(), stmt, func(scan func(dest ...any) error) error {
+			var id int
+			var grant auth.RBACGrant
+
+			err := scan(&id, &grant.ObjectType, &grant.Entitlement, &grant.Project)
+			if err != nil {
+				return err
+			}
+
+			group, ok := groups[id]
+			if ok {
+				group.Grants = append(group.Grants, grant)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]auth.RBACGroup, 0, len(groupIDs))
+	for _, id := range groupIDs {
+		result = append(result, *groups[id])
+	}
+
+	return result, nil
+}
+
 // Syslog listener.
 func (d *Daemon) setupSyslogSocket(enable bool) error {
 	// Always cancel the context to ensure that no goroutines leak.
@@ -2602,10 +2915,16 @@ func (d *Daemon) nodeRefreshTask(heartbeatData *cluster.APIHeartbeat, isLeader b
 		maxVoters := s.GlobalConfig.MaxVoters()
 		maxStandBy := s.GlobalConfig.MaxStandBy()
 
+		// Check whether the database roles could be spread across more failure domains.
+		needsSpreading, err := clusterRolesNeedSpreading(s, heartbeatData.Members)
+		if err != nil {
+			logger.Warn("Failed checking database roles failure domains", logger.Ctx{"err": err, "local": localClusterAddress})
+		}
+
 		// If there are offline members that have voter or stand-by database roles, let's see if we can
-		// replace them with spare ones. Also, if we don't have enough voters or standbys, let's see if we
-		// can upgrade some member.
-		if isDegraded || onlineVoters < int(maxVoters) || onlineStandbys < int(maxStandBy) {
+		// replace them with spare ones. Also, if we don't have enough voters or standbys, or if they could
+		// be spread across more failure domains, let's see if we can upgrade some member.
+		if isDegraded || onlineVoters < int(maxVoters) || onlineStandbys < int(maxStandBy) || needsSpreading {
 			d.clusterMembershipMutex.Lock()
 			logger.Debug("Rebalancing member roles in heartbeat", logger.Ctx{"local": localClusterAddress})
 			err := rebalanceMemberRoles(d.State(), d.gateway, nil, unavailableMembers)
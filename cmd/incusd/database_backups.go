@@ -0,0 +1,644 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	internalUtil "github.com/lxc/incus/v6/internal/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var databaseBackupsCmd = APIEndpoint{
+	Path: "database/backups",
+
+	Get:  APIEndpointAction{Handler: databaseBackupsGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Post: APIEndpointAction{Handler: databaseBackupsPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var databaseBackupCmd = APIEndpoint{
+	Path: "database/backups/{name}",
+
+	Get:    APIEndpointAction{Handler: databaseBackupGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Delete: APIEndpointAction{Handler: databaseBackupDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var databaseBackupExportCmd = APIEndpoint{
+	Path: "database/backups/{name}/export",
+
+	Get: APIEndpointAction{Handler: databaseBackupExportGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// databaseBackupScheduledPrefix is the name prefix of the backups taken on the database.backups.schedule schedule.
+const databaseBackupScheduledPrefix = "scheduled-"
+
+// databaseBackupsPath returns the directory holding the global database backups of this server.
+func databaseBackupsPath() string {
+	return internalUtil.VarPath("backups", "database")
+}
+
+// databaseBackupPath returns the path of the file holding the named global database backup.
+func databaseBackupPath(name string) string {
+	return filepath.Join(databaseBackupsPath(), name+".sql")
+}
+
+// databaseBackupValidName checks that a global database backup name can be used as a file name.
+func databaseBackupValidName(name string) error {
+	if name == "" || strings.Contains(name, "/") || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("Invalid database backup name %q", name)
+	}
+
+	return nil
+}
+
+// databaseBackupLoad returns the details of a global database backup stored on this server.
+func databaseBackupLoad(name string) (*api.DatabaseBackup, error) {
+	path := databaseBackupPath(name)
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, api.StatusErrorf(http.StatusNotFound, "Database backup not found")
+		}
+
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// The schema version is recorded in the first two lines.
+	var header strings.Builder
+	scanner := bufio.NewScanner(file)
+	for i := 0; i < 2 && scanner.Scan(); i++ {
+		header.WriteString(scanner.Text() + "\n")
+	}
+
+	schemaVersion, err := dbCluster.BackupSchemaVersion(header.String())
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading database backup %q: %w", name, err)
+	}
+
+	return &api.DatabaseBackup{
+		Name:          name,
+		CreatedAt:     info.ModTime(),
+		Size:          info.Size(),
+		SchemaVersion: schemaVersion,
+	}, nil
+}
+
+// databaseBackupNames returns the names of the global database backups stored on this server, oldest name first.
+func databaseBackupNames() ([]string, error) {
+	entries, err := os.ReadDir(databaseBackupsPath())
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []string{}, nil
+		}
+
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".sql")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// databaseBackupCreate takes a consistent backup of the global database and stores it on this server.
+func databaseBackupCreate(ctx context.Context, s *state.State, name string) error {
+	err := os.MkdirAll(databaseBackupsPath(), 0o700)
+	if err != nil {
+		return fmt.Errorf("Failed creating database backups directory: %w", err)
+	}
+
+	// All the rows are read from the same transaction, so the dump is consistent.
+	var backup string
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		backup, err = dbCluster.Backup(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed dumping global database: %w", err)
+	}
+
+	// Write to a temporary file first so an interrupted backup never looks complete.
+	path := databaseBackupPath(name)
+	err = os.WriteFile(path+".tmp", []byte(backup), 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed writing database backup: %w", err)
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		_ = os.Remove(path + ".tmp")
+		return fmt.Errorf("Failed writing database backup: %w", err)
+	}
+
+	return nil
+}
+
+// databaseBackupPrune deletes the oldest scheduled global database backups, only keeping the given number of them.
+func databaseBackupPrune(retention int64) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	names, err := databaseBackupNames()
+	if err != nil {
+		return err
+	}
+
+	// Scheduled backups are named after their creation time, so sort chronologically.
+	scheduled := []string{}
+	for _, name := range names {
+		if strings.HasPrefix(name, databaseBackupScheduledPrefix) {
+			scheduled = append(scheduled, name)
+		}
+	}
+
+	for int64(len(scheduled)) > retention {
+		err := os.Remove(databaseBackupPath(scheduled[0]))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Failed deleting database backup %q: %w", scheduled[0], err)
+		}
+
+		scheduled = scheduled[1:]
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/database/backups server database_backups_get
+//
+//	Get the global database backups
+//
+//	Returns a list of global database backups (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/database/backups/backup0",
+//	              "/1.0/database/backups/scheduled-20210323-213837"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/database/backups?recursion=1 server database_backups_get_recursion1
+//
+//	Get the global database backups
+//
+//	Returns a list of global database backups (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of global database backups
+//	          items:
+//	            $ref: "#/definitions/DatabaseBackup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func databaseBackupsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Forward request.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	names, err := databaseBackupNames()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !localUtil.IsRecursionRequest(r) {
+		urls := []string{}
+		for _, name := range names {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "database", "backups", name).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	backups := []api.DatabaseBackup{}
+	for _, name := range names {
+		backup, err := databaseBackupLoad(name)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		backups = append(backups, *backup)
+	}
+
+	return response.SyncResponse(true, backups)
+}
+
+// swagger:operation POST /1.0/database/backups server database_backups_post
+//
+//	Back up the global database
+//
+//	Takes a consistent backup of the global database and stores it on the server.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	  - in: body
+//	    name: backup
+//	    description: Backup request
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/DatabaseBackupsPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func databaseBackupsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	// Forward request.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	req := api.DatabaseBackupsPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Name == "" {
+		req.Name = fmt.Sprintf("backup-%s", time.Now().UTC().Format("20060102-150405"))
+	}
+
+	err = databaseBackupValidName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if util.PathExists(databaseBackupPath(req.Name)) {
+		return response.Conflict(fmt.Errorf("Database backup %q already exists", req.Name))
+	}
+
+	run := func(op *operations.Operation) error {
+		return databaseBackupCreate(context.Background(), s, req.Name)
+	}
+
+	resources := map[string][]api.URL{}
+	resources["backups"] = []api.URL{*api.NewURL().Path(version.APIVersion, "database", "backups", req.Name)}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.DatabaseBackupCreate, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation GET /1.0/database/backups/{name} server database_backup_get
+//
+//	Get the global database backup
+//
+//	Gets a specific global database backup.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Global database backup
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/DatabaseBackup"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func databaseBackupGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Forward request.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	err = databaseBackupValidName(name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	backup, err := databaseBackupLoad(name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, backup)
+}
+
+// swagger:operation DELETE /1.0/database/backups/{name} server database_backup_delete
+//
+//	Delete the global database backup
+//
+//	Deletes a global database backup from the server.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func databaseBackupDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Forward request.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	err = databaseBackupValidName(name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = os.Remove(databaseBackupPath(name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return response.NotFound(fmt.Errorf("Database backup not found"))
+		}
+
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/database/backups/{name}/export server database_backup_export
+//
+//	Get the global database backup file
+//
+//	Downloads the SQL dump making up the global database backup.
+//
+//	---
+//	produces:
+//	  - application/octet-stream
+//	parameters:
+//	  - in: query
+//	    name: target
+//	    description: Cluster member name
+//	    type: string
+//	    example: server01
+//	responses:
+//	  "200":
+//	    description: Raw backup data
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func databaseBackupExportGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Forward request.
+	resp := forwardedResponseIfTargetIsRemote(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	err = databaseBackupValidName(name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if !util.PathExists(databaseBackupPath(name)) {
+		return response.NotFound(fmt.Errorf("Database backup not found"))
+	}
+
+	ent := response.FileResponseEntry{
+		Path:     databaseBackupPath(name),
+		Filename: name + ".sql",
+	}
+
+	return response.FileResponse(r, []response.FileResponseEntry{ent}, nil)
+}
+
+// databaseBackupTask takes backups of the global database on the database.backups.schedule schedule.
+func databaseBackupTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		schedule := s.LocalConfig.DatabaseBackupsSchedule()
+		if schedule == "" || !snapshotIsScheduledNow(schedule, s.DB.Cluster.GetNodeID()) {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			name := databaseBackupScheduledPrefix + time.Now().UTC().Format("20060102-150405")
+
+			err := databaseBackupCreate(ctx, s, name)
+			if err != nil {
+				return err
+			}
+
+			return databaseBackupPrune(s.LocalConfig.DatabaseBackupsRetention())
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.DatabaseBackupCreate, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled database backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Backing up the global database")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled database backup operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled database backup", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done backing up the global database")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
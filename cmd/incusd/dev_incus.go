@@ -22,6 +22,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/lifecycle"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/server/ucred"
 	"github.com/lxc/incus/v6/internal/version"
@@ -90,6 +91,12 @@ var devIncusConfigKeyGet = devIncusHandler{"/1.0/config/{key}", func(d *Daemon,
 		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusNotFound, "not found"), c.Type() == instancetype.VM)
 	}
 
+	value, err = secrets.Resolve(r.Context(), d.State(), c.Project().Name, key, value)
+	if err != nil {
+		logger.Warn("Failed resolving secrets for guest API", logger.Ctx{"project": c.Project().Name, "instance": c.Name(), "key": key, "err": err})
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"), c.Type() == instancetype.VM)
+	}
+
 	return response.DevIncusResponse(http.StatusOK, value, "raw", c.Type() == instancetype.VM)
 }}
 
@@ -120,7 +127,11 @@ var devIncusMetadataGet = devIncusHandler{"/1.0/meta-data", func(d *Daemon, inst
 		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusForbidden, "not authorized"), inst.Type() == instancetype.VM)
 	}
 
-	value := inst.ExpandedConfig()["user.meta-data"]
+	value, err := secrets.Resolve(r.Context(), d.State(), inst.Project().Name, "user.meta-data", inst.ExpandedConfig()["user.meta-data"])
+	if err != nil {
+		logger.Warn("Failed resolving secrets for guest API", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "key": "user.meta-data", "err": err})
+		return response.DevIncusErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"), inst.Type() == instancetype.VM)
+	}
 
 	return response.DevIncusResponse(http.StatusOK, fmt.Sprintf("#cloud-config\ninstance-id: %s\nlocal-hostname: %s\n%s", inst.CloudInitID(), inst.Name(), value), "raw", inst.Type() == instancetype.VM)
 }}
@@ -1765,7 +1765,12 @@ func autoUpdateImagesTask(d *Daemon) (task.Func, task.Schedule) {
 		logger.Info("Done updating images")
 	}
 
-	return f, task.Hourly()
+	// Defer the updates outside of the maintenance windows of the member.
+	windows := func() task.Windows {
+		return localMaintenanceWindows(d.State())
+	}
+
+	return f, task.Windowed(task.Hourly(), windows)
 }
 
 func autoUpdateImages(ctx context.Context, s *state.State) error {
@@ -20,16 +20,19 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v6/internal/server/locking"
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/util"
@@ -695,7 +698,7 @@ func pruneExpiredAndAutoCreateInstanceSnapshotsTask(d *Daemon) (task.Func, task.
 
 		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
-				err = project.AllowSnapshotCreation(&p)
+				err = project.AllowSnapshotCreation(tx, &p)
 				if err != nil {
 					return nil
 				}
@@ -842,3 +845,35 @@ func instanceOperationLock(ctx context.Context, projectName string, instanceName
 
 	return locking.Lock(ctx, fmt.Sprintf("InstanceOperation_%s", project.Instance(projectName, instanceName)))
 }
+
+// instanceAdmission runs the instance admission scriptlet, if one is configured, against the requested instance
+// configuration. Changes made by the scriptlet are applied to the request's local config and devices.
+func instanceAdmission(ctx context.Context, s *state.State, reason string, projectName string, instanceName string, instanceType string, req *api.InstancePut, profiles []api.Profile) error {
+	if s.GlobalConfig.InstancesAdmissionScriptlet() == "" {
+		return nil
+	}
+
+	admission := apiScriptlet.InstanceAdmission{
+		InstancePut:     *req,
+		Name:            instanceName,
+		Type:            instanceType,
+		Project:         projectName,
+		Reason:          reason,
+		ExpandedConfig:  db.ExpandInstanceConfig(req.Config, profiles),
+		ExpandedDevices: db.ExpandInstanceDevices(deviceConfig.NewDevices(req.Devices), profiles).CloneNative(),
+	}
+
+	// Give the scriptlet its own copy of the local config and devices.
+	admission.Config = util.CloneMap(req.Config)
+	admission.Devices = deviceConfig.NewDevices(req.Devices).CloneNative()
+
+	err := scriptlet.InstanceAdmissionRun(ctx, logger.Log, &admission)
+	if err != nil {
+		return err
+	}
+
+	req.Config = admission.Config
+	req.Devices = admission.Devices
+
+	return nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/instance/operationlock"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// instanceDependencyTimeout is how long to wait for the dependencies of an instance to be ready.
+const instanceDependencyTimeout = 5 * time.Minute
+
+// instanceDependencies returns the names of the instances listed in boot.depends_on.
+func instanceDependencies(inst instance.Instance) []string {
+	return util.SplitNTrimSpace(inst.ExpandedConfig()["boot.depends_on"], ",", -1, true)
+}
+
+// instanceDependencyKey returns the key used to identify an instance in dependency maps.
+func instanceDependencyKey(projectName string, instanceName string) string {
+	return projectName + "/" + instanceName
+}
+
+// instanceDependencyCheckProfile checks that updating a profile doesn't introduce dependency cycles between the
+// instances using it.
+func instanceDependencyCheckProfile(ctx context.Context, s *state.State, profileName string, req api.ProfilePut, insts map[int]db.InstanceArgs) error {
+	// Get the boot.depends_on value of each instance as expanded with the new profile.
+	overrides := map[string]map[string]string{}
+	for _, inst := range insts {
+		profiles := make([]api.Profile, 0, len(inst.Profiles))
+		for _, profile := range inst.Profiles {
+			if profile.Name == profileName {
+				profile.Config = req.Config
+			}
+
+			profiles = append(profiles, profile)
+		}
+
+		if overrides[inst.Project] == nil {
+			overrides[inst.Project] = map[string]string{}
+		}
+
+		overrides[inst.Project][inst.Name] = db.ExpandInstanceConfig(inst.Config, profiles)["boot.depends_on"]
+	}
+
+	for _, inst := range insts {
+		if overrides[inst.Project][inst.Name] == "" {
+			continue
+		}
+
+		err := instance.DependencyCheckCycle(ctx, s, inst.Project, inst.Name, overrides[inst.Project])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// instanceDependencyReady returns whether a dependency is running and, if it has health checks, healthy.
+// A nil client indicates that the dependency is located on the local member.
+func instanceDependencyReady(s *state.State, client incus.InstanceServer, projectName string, name string) (bool, error) {
+	if client != nil {
+		instState, _, err := client.GetInstanceState(name)
+		if err != nil {
+			return false, err
+		}
+
+		if instState.StatusCode != api.Running {
+			return false, nil
+		}
+
+		return instState.Health == nil || instState.Health.Status == "healthy", nil
+	}
+
+	dep, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return false, err
+	}
+
+	if !dep.IsRunning() {
+		// Only keep waiting if the dependency is being started.
+		if operationlock.Get(projectName, name) == nil {
+			return false, fmt.Errorf("Dependency %q isn't running", name)
+		}
+
+		return false, nil
+	}
+
+	status := instanceDrivers.HealthcheckStatus(dep)
+
+	return status == "" || status == "healthy", nil
+}
+
+// instanceWaitDependencies waits for the instances listed in boot.depends_on to be ready.
+func instanceWaitDependencies(ctx context.Context, s *state.State, inst instance.Instance) error {
+	deps := instanceDependencies(inst)
+	if len(deps) == 0 {
+		return nil
+	}
+
+	// Cycles are rejected when the configuration changes, but may still come from instances created before.
+	err := instance.DependencyCheckCycle(ctx, s, inst.Project().Name, inst.Name(), map[string]string{inst.Name(): inst.ExpandedConfig()["boot.depends_on"]})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, instanceDependencyTimeout)
+	defer cancel()
+
+	projectName := inst.Project().Name
+
+	for _, name := range deps {
+		client, err := cluster.ConnectIfInstanceIsRemote(s, projectName, name, nil, instancetype.Any)
+		if err != nil {
+			return fmt.Errorf("Failed locating dependency %q: %w", name, err)
+		}
+
+		for {
+			ready, err := instanceDependencyReady(s, client, projectName, name)
+			if err != nil {
+				return fmt.Errorf("Failed checking dependency %q: %w", name, err)
+			}
+
+			if ready {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return fmt.Errorf("Timed out waiting for dependency %q to be ready", name)
+				}
+
+				return ctx.Err()
+			case <-time.After(time.Second):
+			}
+		}
+	}
+
+	return nil
+}
+
+// instanceDependencyRanks returns how many levels of dependencies among the given instances must be started
+// before each of them. Instances without dependencies in the list have a rank of 0. Dependencies which aren't
+// part of the list, including same named instances of other projects, are ignored and so are cycles.
+func instanceDependencyRanks(instances []instance.Instance) map[string]int {
+	byKey := make(map[string]instance.Instance, len(instances))
+	keys := make([]string, 0, len(instances))
+	for _, inst := range instances {
+		key := instanceDependencyKey(inst.Project().Name, inst.Name())
+		byKey[key] = inst
+		keys = append(keys, key)
+	}
+
+	ranks := make(map[string]int, len(instances))
+	visiting := map[string]bool{}
+
+	var rank func(key string) int
+	rank = func(key string) int {
+		value, ok := ranks[key]
+		if ok {
+			return value
+		}
+
+		if visiting[key] {
+			return -1
+		}
+
+		visiting[key] = true
+
+		inst := byKey[key]
+		value = 0
+		for _, name := range instanceDependencies(inst) {
+			depKey := instanceDependencyKey(inst.Project().Name, name)
+			if byKey[depKey] == nil {
+				continue
+			}
+
+			depRank := rank(depKey)
+			if depRank+1 > value {
+				value = depRank + 1
+			}
+		}
+
+		visiting[key] = false
+		ranks[key] = value
+
+		return value
+	}
+
+	// Go through the instances in a stable order so that cycles are always broken at the same place.
+	sort.Strings(keys)
+	for _, key := range keys {
+		rank(key)
+	}
+
+	return ranks
+}
+
+// instanceDependencySort orders instances by dependency rank, with dependencies first unless reverse is true.
+// The sort is stable so instances of the same rank keep their previous order, such as the one given by
+// boot.autostart.priority or boot.stop.priority.
+func instanceDependencySort(instances []instance.Instance, reverse bool) {
+	ranks := instanceDependencyRanks(instances)
+	sort.SliceStable(instances, func(i, j int) bool {
+		iRank := ranks[instanceDependencyKey(instances[i].Project().Name, instances[i].Name())]
+		jRank := ranks[instanceDependencyKey(instances[j].Project().Name, instances[j].Name())]
+
+		if reverse {
+			return iRank > jRank
+		}
+
+		return iRank < jRank
+	})
+}
+
+// instanceDependencyBatches splits the instances into batches which can be started concurrently, with
+// dependencies coming first. When reverse is true, the batches are ordered for stopping the instances instead.
+func instanceDependencyBatches(instances []instance.Instance, reverse bool) [][]instance.Instance {
+	ranks := instanceDependencyRanks(instances)
+
+	byRank := map[int][]instance.Instance{}
+	for _, inst := range instances {
+		rank := ranks[instanceDependencyKey(inst.Project().Name, inst.Name())]
+		byRank[rank] = append(byRank[rank], inst)
+	}
+
+	order := make([]int, 0, len(byRank))
+	for rank := range byRank {
+		order = append(order, rank)
+	}
+
+	sort.Ints(order)
+	if reverse {
+		sort.Sort(sort.Reverse(sort.IntSlice(order)))
+	}
+
+	batches := make([][]instance.Instance, 0, len(order))
+	for _, rank := range order {
+		batches = append(batches, byRank[rank])
+	}
+
+	return batches
+}
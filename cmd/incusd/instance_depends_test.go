@@ -0,0 +1,138 @@
+package main
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// dependsTestInstance implements the parts of instance.Instance used for dependency ordering.
+type dependsTestInstance struct {
+	instance.Instance
+
+	project string
+	name    string
+	config  map[string]string
+}
+
+func (i *dependsTestInstance) Project() api.Project {
+	return api.Project{Name: i.project}
+}
+
+func (i *dependsTestInstance) Name() string {
+	return i.name
+}
+
+func (i *dependsTestInstance) ExpandedConfig() map[string]string {
+	return i.config
+}
+
+func newDependsTestInstance(projectName string, name string, config map[string]string) instance.Instance {
+	return &dependsTestInstance{project: projectName, name: name, config: config}
+}
+
+func dependsTestNames(instances []instance.Instance) []string {
+	names := make([]string, 0, len(instances))
+	for _, inst := range instances {
+		names = append(names, inst.Name())
+	}
+
+	return names
+}
+
+func TestInstanceDependencyRanks(t *testing.T) {
+	instances := []instance.Instance{
+		newDependsTestInstance("default", "web", map[string]string{"boot.depends_on": "app, cache"}),
+		newDependsTestInstance("default", "app", map[string]string{"boot.depends_on": "db"}),
+		newDependsTestInstance("default", "db", nil),
+		newDependsTestInstance("default", "cache", map[string]string{"boot.depends_on": "missing"}),
+		newDependsTestInstance("other", "db", nil),
+		newDependsTestInstance("other", "web", map[string]string{"boot.depends_on": "app"}),
+	}
+
+	ranks := instanceDependencyRanks(instances)
+
+	assert.Equal(t, map[string]int{
+		"default/web":   2,
+		"default/app":   1,
+		"default/db":    0,
+		"default/cache": 0, // Missing dependencies are ignored.
+		"other/db":      0,
+		"other/web":     0, // Instances of another project aren't dependencies.
+	}, ranks)
+}
+
+func TestInstanceDependencyRanks_Cycle(t *testing.T) {
+	instances := []instance.Instance{
+		newDependsTestInstance("default", "c1", map[string]string{"boot.depends_on": "c2"}),
+		newDependsTestInstance("default", "c2", map[string]string{"boot.depends_on": "c1"}),
+		newDependsTestInstance("default", "self", map[string]string{"boot.depends_on": "self"}),
+		newDependsTestInstance("default", "after", map[string]string{"boot.depends_on": "c1"}),
+	}
+
+	// The cycle is broken at the same place regardless of the input order.
+	for range 10 {
+		ranks := instanceDependencyRanks(instances)
+
+		assert.Equal(t, map[string]int{
+			"default/c1":    1,
+			"default/c2":    0,
+			"default/self":  0,
+			"default/after": 2,
+		}, ranks)
+
+		instances[0], instances[1], instances[2], instances[3] = instances[3], instances[0], instances[1], instances[2]
+	}
+}
+
+func TestInstanceDependencyBatches(t *testing.T) {
+	instances := []instance.Instance{
+		newDependsTestInstance("default", "web", map[string]string{"boot.depends_on": "app"}),
+		newDependsTestInstance("default", "app", map[string]string{"boot.depends_on": "db,queue"}),
+		newDependsTestInstance("default", "db", nil),
+		newDependsTestInstance("default", "queue", nil),
+		newDependsTestInstance("default", "other", nil),
+	}
+
+	var names [][]string
+	for _, batch := range instanceDependencyBatches(instances, false) {
+		batchNames := dependsTestNames(batch)
+		sort.Strings(batchNames)
+		names = append(names, batchNames)
+	}
+
+	assert.Equal(t, [][]string{{"db", "other", "queue"}, {"app"}, {"web"}}, names)
+
+	names = nil
+	for _, batch := range instanceDependencyBatches(instances, true) {
+		batchNames := dependsTestNames(batch)
+		sort.Strings(batchNames)
+		names = append(names, batchNames)
+	}
+
+	assert.Equal(t, [][]string{{"web"}, {"app"}, {"db", "other", "queue"}}, names)
+}
+
+func TestInstanceDependencySort(t *testing.T) {
+	instances := []instance.Instance{
+		newDependsTestInstance("default", "low", map[string]string{"boot.autostart.priority": "1", "boot.stop.priority": "1"}),
+		newDependsTestInstance("default", "app", map[string]string{"boot.autostart.priority": "100", "boot.stop.priority": "100", "boot.depends_on": "db"}),
+		newDependsTestInstance("default", "db", map[string]string{"boot.autostart.priority": "5", "boot.stop.priority": "5"}),
+		newDependsTestInstance("default", "high", map[string]string{"boot.autostart.priority": "10", "boot.stop.priority": "10"}),
+		newDependsTestInstance("default", "web", map[string]string{"boot.autostart.priority": "50", "boot.stop.priority": "50", "boot.depends_on": "db"}),
+	}
+
+	// Dependencies start first, the priority orders instances within each rank.
+	sort.Sort(instanceAutostartList(instances))
+	instanceDependencySort(instances, false)
+	assert.Equal(t, []string{"high", "db", "low", "app", "web"}, dependsTestNames(instances))
+
+	// Dependencies stop last, the priority orders instances within each rank.
+	sort.Sort(instanceStopList(instances))
+	instanceDependencySort(instances, true)
+	assert.Equal(t, []string{"app", "web", "high", "db", "low"}, dependsTestNames(instances))
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	"github.com/lxc/incus/v6/internal/server/state"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
@@ -623,7 +624,12 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 	}
 
 	// Override any environment variable settings from the instance if not manually specified in post.
-	for k, v := range inst.ExpandedConfig() {
+	expandedConfig, err := secrets.ResolveConfig(r.Context(), s, projectName, inst.ExpandedConfig())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	for k, v := range expandedConfig {
 		if strings.HasPrefix(k, "environment.") {
 			envKey := strings.TrimPrefix(k, "environment.")
 			_, found := post.Environment[envKey]
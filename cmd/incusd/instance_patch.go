@@ -22,6 +22,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/response"
 	localUtil "github.com/lxc/incus/v6/internal/server/util"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
 	"github.com/lxc/incus/v6/shared/osarch"
 )
 
@@ -183,7 +184,7 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
-	// Check project limits.
+	// Load the profiles.
 	apiProfiles := make([]api.Profile, 0, len(req.Profiles))
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		profiles, err := cluster.GetProfilesIfEnabled(ctx, tx.Tx(), projectName, req.Profiles)
@@ -210,6 +211,20 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 			apiProfiles = append(apiProfiles, *apiProfile)
 		}
 
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Run the instance admission scriptlet so its changes are also subject to the project's limits.
+	err = instanceAdmission(r.Context(), s, apiScriptlet.InstanceAdmissionReasonUpdate, projectName, name, c.Type().String(), &req, apiProfiles)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Check project limits.
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		return projecthelpers.AllowInstanceUpdate(tx, projectName, name, req, c.LocalConfig())
 	})
 	if err != nil {
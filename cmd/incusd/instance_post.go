@@ -324,6 +324,19 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
+		// Only keep the members with enough capacity left for the instance.
+		if targetMemberInfo != nil && targetMemberInfo.Name != inst.Location() {
+			_, err = clusterGroupCapacityFilter(r.Context(), s, []db.NodeInfo{*targetMemberInfo}, inst.Project().Name, inst.Name(), inst.Type(), inst.ExpandedConfig())
+			if err != nil {
+				return response.BadRequest(err)
+			}
+		} else if targetMemberInfo == nil {
+			targetCandidates, err = clusterGroupCapacityFilter(r.Context(), s, targetCandidates, inst.Project().Name, inst.Name(), inst.Type(), inst.ExpandedConfig())
+			if err != nil {
+				return response.BadRequest(err)
+			}
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			// If a target was specified, limit the list of candidates to that target.
@@ -0,0 +1,908 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/locking"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// instanceReplicationSnapshotPrefix is the name prefix of the snapshots created by replication.
+const instanceReplicationSnapshotPrefix = "replication-"
+
+// instanceReplicationDefaultRetention is the default number of replication snapshots to keep.
+const instanceReplicationDefaultRetention = 3
+
+// swagger:operation GET /1.0/instances/{name}/replication instances instance_replication_get
+//
+//	Get the replication status
+//
+//	Gets the replication role and status of the instance.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Replication status
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceReplication"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceReplicationGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(fmt.Errorf("Invalid instance name"))
+	}
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Handle requests targeted to an instance on a different member.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	status, err := instanceReplicationStatus(inst)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, status)
+}
+
+// swagger:operation POST /1.0/instances/{name}/replication instances instance_replication_post
+//
+//	Trigger a replication action
+//
+//	Replicates the instance to its target right away (`replicate`) or
+//	promotes a replica to be the new primary (`failover`).
+//
+//	A failover stops the primary, performs a final replication, turns the
+//	primary into a replica and then promotes the local instance. If the
+//	primary can't be reached, the failover requires `force`.
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: replication
+//	    description: Replication action
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceReplicationPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceReplicationPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if internalInstance.IsSnapshot(name) {
+		return response.BadRequest(fmt.Errorf("Invalid instance name"))
+	}
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Handle requests targeted to an instance on a different member.
+	resp, err := forwardedResponseIfInstanceIsRemote(s, r, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	// Parse the request.
+	req := api.InstanceReplicationPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = instanceReplicationCheckAction(inst.ExpandedConfig(), req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var opType operationtype.Type
+	var run func(op *operations.Operation) error
+
+	switch req.Action {
+	case "replicate":
+		opType = operationtype.InstanceReplicate
+		run = func(op *operations.Operation) error {
+			return instanceReplicate(context.TODO(), s, inst)
+		}
+
+	case "failover":
+		opType = operationtype.InstanceFailover
+		run = func(op *operations.Operation) error {
+			return instanceFailover(context.TODO(), s, inst, req.Force)
+		}
+	}
+
+	resources := map[string][]api.URL{}
+	resources["instances"] = []api.URL{*api.NewURL().Path(version.APIVersion, "instances", name)}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, opType, resources, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// instanceReplicationLock prevents concurrent replication actions on the same instance.
+func instanceReplicationLock(ctx context.Context, projectName string, name string) (locking.UnlockFunc, error) {
+	return locking.Lock(ctx, fmt.Sprintf("InstanceReplication_%s/%s", projectName, name))
+}
+
+// instanceReplicationIsReplica returns whether the instance is a replica.
+func instanceReplicationIsReplica(config map[string]string) bool {
+	return config["replication.role"] == "replica"
+}
+
+// instanceReplicationCheckAction checks that a replication action applies to an instance with the given
+// expanded configuration.
+func instanceReplicationCheckAction(config map[string]string, req api.InstanceReplicationPost) error {
+	switch req.Action {
+	case "replicate":
+		if instanceReplicationIsReplica(config) {
+			return fmt.Errorf("Replica instances can't be replicated")
+		}
+
+		if config["replication.remote"] == "" {
+			return fmt.Errorf("Instance doesn't have a replication remote")
+		}
+
+	case "failover":
+		if !instanceReplicationIsReplica(config) {
+			return fmt.Errorf("Only replica instances can be failed over to")
+		}
+
+		// Without a remote, the primary can't be demoted.
+		if config["replication.remote"] == "" && !req.Force {
+			return fmt.Errorf("Instance doesn't have a replication remote, failing over requires force")
+		}
+
+	default:
+		return fmt.Errorf("Unknown replication action %q", req.Action)
+	}
+
+	return nil
+}
+
+// instanceReplicationSnapshotName returns the name of the replication snapshot taken at the given time.
+func instanceReplicationSnapshotName(t time.Time) string {
+	return instanceReplicationSnapshotPrefix + t.UTC().Format("20060102-150405")
+}
+
+// instanceReplicationFilterSnapshots returns the replication snapshots among the given snapshots, oldest first.
+func instanceReplicationFilterSnapshots(snapshots []instance.Instance) []instance.Instance {
+	replicationSnapshots := []instance.Instance{}
+	for _, snapshot := range snapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(snapshot.Name())
+		if strings.HasPrefix(snapName, instanceReplicationSnapshotPrefix) {
+			replicationSnapshots = append(replicationSnapshots, snapshot)
+		}
+	}
+
+	slices.SortStableFunc(replicationSnapshots, func(a instance.Instance, b instance.Instance) int {
+		return a.CreationDate().Compare(b.CreationDate())
+	})
+
+	return replicationSnapshots
+}
+
+// instanceReplicationSnapshots returns the replication snapshots of the instance, oldest first.
+func instanceReplicationSnapshots(inst instance.Instance) ([]instance.Instance, error) {
+	snapshots, err := inst.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	return instanceReplicationFilterSnapshots(snapshots), nil
+}
+
+// instanceReplicationRetention returns the number of replication snapshots to keep.
+func instanceReplicationRetention(config map[string]string) (int, error) {
+	if config["replication.snapshots.retention"] == "" {
+		return instanceReplicationDefaultRetention, nil
+	}
+
+	retention, err := strconv.Atoi(config["replication.snapshots.retention"])
+	if err != nil {
+		return -1, fmt.Errorf("Invalid replication.snapshots.retention: %w", err)
+	}
+
+	// The most recent snapshot is always kept as it's the one being replicated.
+	return max(retention, 1), nil
+}
+
+// instanceReplicationPrune returns the replication snapshots, oldest first, to delete to stay within the retention.
+func instanceReplicationPrune[T any](snapshots []T, retention int) []T {
+	if len(snapshots) <= retention {
+		return nil
+	}
+
+	return snapshots[:len(snapshots)-retention]
+}
+
+// instanceReplicationVolume is a custom storage volume replicated along with an instance.
+type instanceReplicationVolume struct {
+	pool string
+	name string
+}
+
+// instanceReplicationVolumes returns the custom storage volumes attached to the instance through its disk devices.
+func instanceReplicationVolumes(devices deviceConfig.Devices) []instanceReplicationVolume {
+	volumes := []instanceReplicationVolume{}
+	for _, dev := range devices.Sorted() {
+		if dev.Config["type"] != "disk" || dev.Config["pool"] == "" || dev.Config["source"] == "" || dev.Config["path"] == "/" {
+			continue
+		}
+
+		// The source may also point to a path inside of the volume.
+		volName, _, _ := strings.Cut(dev.Config["source"], "/")
+
+		vol := instanceReplicationVolume{pool: dev.Config["pool"], name: volName}
+		if !slices.Contains(volumes, vol) {
+			volumes = append(volumes, vol)
+		}
+	}
+
+	return volumes
+}
+
+// instanceReplicationFilterVolumeSnapshots returns the names of the replication snapshots among the given volume snapshots, oldest first.
+func instanceReplicationFilterVolumeSnapshots(snapshots []api.StorageVolumeSnapshot) []string {
+	slices.SortStableFunc(snapshots, func(a api.StorageVolumeSnapshot, b api.StorageVolumeSnapshot) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	names := []string{}
+	for _, snapshot := range snapshots {
+		snapName := snapshot.Name
+		_, name, isSnap := api.GetParentAndSnapshotName(snapshot.Name)
+		if isSnap {
+			snapName = name
+		}
+
+		if strings.HasPrefix(snapName, instanceReplicationSnapshotPrefix) {
+			names = append(names, snapName)
+		}
+	}
+
+	return names
+}
+
+// instanceReplicationReplicaConfig returns the configuration of the replica of an instance.
+// The replication remote of an existing replica refers to a remote defined on its own server, so it's kept.
+func instanceReplicationReplicaConfig(config map[string]string, replicaConfig map[string]string) map[string]string {
+	result := make(map[string]string, len(config))
+	for key, value := range config {
+		if strings.HasPrefix(key, "volatile.replication.") || key == "replication.remote" {
+			continue
+		}
+
+		result[key] = value
+	}
+
+	result["replication.role"] = "replica"
+
+	if replicaConfig["replication.remote"] != "" {
+		result["replication.remote"] = replicaConfig["replication.remote"]
+	}
+
+	return result
+}
+
+// instanceReplicationStatus returns the replication status of an instance.
+func instanceReplicationStatus(inst instance.Instance) (*api.InstanceReplication, error) {
+	config := inst.ExpandedConfig()
+
+	status := api.InstanceReplication{
+		Role:      "primary",
+		Remote:    config["replication.remote"],
+		Schedule:  config["replication.schedule"],
+		LastError: config["volatile.replication.last_error"],
+		Lag:       -1,
+		Snapshots: []string{},
+	}
+
+	if instanceReplicationIsReplica(config) {
+		status.Role = "replica"
+	}
+
+	timestamp := func(key string) time.Time {
+		value, err := strconv.ParseInt(config[key], 10, 64)
+		if err != nil || value <= 0 {
+			return time.Time{}
+		}
+
+		return time.Unix(value, 0).UTC()
+	}
+
+	status.LastAttempt = timestamp("volatile.replication.last_attempt")
+	status.LastSuccess = timestamp("volatile.replication.last_success")
+
+	snapshots, err := instanceReplicationSnapshots(inst)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range snapshots {
+		_, snapName, _ := api.GetParentAndSnapshotName(snapshot.Name())
+		status.Snapshots = append(status.Snapshots, snapName)
+	}
+
+	// On the primary, the lag is the age of the last successful replication.
+	// On a replica, it's the age of the most recent replication snapshot it received.
+	if status.Role == "replica" {
+		if len(snapshots) > 0 {
+			status.Lag = int64(time.Since(snapshots[len(snapshots)-1].CreationDate()).Seconds())
+		}
+	} else if !status.LastSuccess.IsZero() {
+		status.Lag = int64(time.Since(status.LastSuccess).Seconds())
+	}
+
+	return &status, nil
+}
+
+// instanceReplicationConnect connects to the replication remote of an instance.
+func instanceReplicationConnect(ctx context.Context, s *state.State, inst instance.Instance) (incus.InstanceServer, error) {
+	remoteName := inst.ExpandedConfig()["replication.remote"]
+	if remoteName == "" {
+		return nil, fmt.Errorf("Instance doesn't have a replication remote")
+	}
+
+	return replicationRemoteConnect(ctx, s, remoteName, inst.Project().Name)
+}
+
+// instanceReplicationLocalConnect connects to the local server in the instance's project.
+func instanceReplicationLocalConnect(s *state.State, inst instance.Instance) (incus.InstanceServer, error) {
+	client, err := incus.ConnectIncusUnix(s.OS.GetUnixSocket(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.UseProject(inst.Project().Name), nil
+}
+
+// instanceReplicate replicates an instance to its target and records the outcome in its volatile config.
+func instanceReplicate(ctx context.Context, s *state.State, inst instance.Instance) error {
+	unlock, err := instanceReplicationLock(ctx, inst.Project().Name, inst.Name())
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	err = instanceReplicateRun(ctx, s, inst)
+	if err != nil {
+		volatileErr := inst.VolatileSet(map[string]string{
+			"volatile.replication.last_attempt": now,
+			"volatile.replication.last_error":   err.Error(),
+		})
+		if volatileErr != nil {
+			logger.Warn("Failed recording replication status", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": volatileErr})
+		}
+
+		return err
+	}
+
+	err = inst.VolatileSet(map[string]string{
+		"volatile.replication.last_attempt": now,
+		"volatile.replication.last_success": now,
+		"volatile.replication.last_error":   "",
+	})
+	if err != nil {
+		return fmt.Errorf("Failed recording replication status: %w", err)
+	}
+
+	return nil
+}
+
+// instanceReplicateRun takes a new replication snapshot and refreshes the replica from it.
+func instanceReplicateRun(ctx context.Context, s *state.State, inst instance.Instance) error {
+	err := instanceReplicationCheckAction(inst.ExpandedConfig(), api.InstanceReplicationPost{Action: "replicate"})
+	if err != nil {
+		return err
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "remote": inst.ExpandedConfig()["replication.remote"]})
+
+	retention, err := instanceReplicationRetention(inst.ExpandedConfig())
+	if err != nil {
+		return err
+	}
+
+	local, err := instanceReplicationLocalConnect(s, inst)
+	if err != nil {
+		return err
+	}
+
+	remote, err := instanceReplicationConnect(ctx, s, inst)
+	if err != nil {
+		return err
+	}
+
+	// Check whether a replica already exists and hasn't been promoted behind our back.
+	refresh := false
+	remoteInst, _, err := remote.GetInstance(inst.Name())
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return fmt.Errorf("Failed getting replica: %w", err)
+	}
+
+	if remoteInst != nil {
+		if remoteInst.ExpandedConfig["replication.role"] != "replica" {
+			return fmt.Errorf("Remote instance %q isn't a replica", inst.Name())
+		}
+
+		refresh = true
+	}
+
+	// Custom volumes attached to the instance are replicated along with it.
+	// Serialize their replication as shared volumes may be replicated by several instances.
+	volumes := instanceReplicationVolumes(inst.ExpandedDevices())
+	p := inst.Project()
+	volumeProjectName := project.StorageVolumeProjectFromRecord(&p, db.StoragePoolVolumeTypeCustom)
+
+	for _, vol := range volumes {
+		unlock, err := locking.Lock(ctx, fmt.Sprintf("VolumeReplication_%s/%s/%s", volumeProjectName, vol.pool, vol.name))
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+	}
+
+	// Refresh the existing replicas of the volumes, an existing volume without a replica instance isn't overwritten.
+	volumeRefresh := make(map[instanceReplicationVolume]bool, len(volumes))
+	for _, vol := range volumes {
+		_, _, err := remote.GetStoragePoolVolume(vol.pool, "custom", vol.name)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("Failed getting replica of storage volume %q: %w", vol.name, err)
+		}
+
+		if err == nil {
+			if !refresh {
+				return fmt.Errorf("Remote storage volume %q already exists and isn't used by a replica", vol.name)
+			}
+
+			volumeRefresh[vol] = true
+		}
+	}
+
+	// Take the replication snapshots.
+	snapName := instanceReplicationSnapshotName(time.Now())
+
+	op, err := local.CreateInstanceSnapshot(inst.Name(), api.InstanceSnapshotsPost{Name: snapName})
+	if err != nil {
+		return fmt.Errorf("Failed creating replication snapshot: %w", err)
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed creating replication snapshot: %w", err)
+	}
+
+	for _, vol := range volumes {
+		op, err := local.CreateStoragePoolVolumeSnapshot(vol.pool, "custom", vol.name, api.StorageVolumeSnapshotsPost{Name: snapName})
+		if err != nil {
+			return fmt.Errorf("Failed creating replication snapshot of storage volume %q: %w", vol.name, err)
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed creating replication snapshot of storage volume %q: %w", vol.name, err)
+		}
+	}
+
+	// Prune the old replication snapshots, the refresh then removes them from the replicas too.
+	snapshots, err := instanceReplicationSnapshots(inst)
+	if err != nil {
+		return err
+	}
+
+	for _, snapshot := range instanceReplicationPrune(snapshots, retention) {
+		_, oldName, _ := api.GetParentAndSnapshotName(snapshot.Name())
+
+		op, err := local.DeleteInstanceSnapshot(inst.Name(), oldName)
+		if err != nil {
+			return fmt.Errorf("Failed deleting replication snapshot %q: %w", oldName, err)
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed deleting replication snapshot %q: %w", oldName, err)
+		}
+	}
+
+	for _, vol := range volumes {
+		volSnapshots, err := local.GetStoragePoolVolumeSnapshots(vol.pool, "custom", vol.name)
+		if err != nil {
+			return fmt.Errorf("Failed getting snapshots of storage volume %q: %w", vol.name, err)
+		}
+
+		for _, oldName := range instanceReplicationPrune(instanceReplicationFilterVolumeSnapshots(volSnapshots), retention) {
+			op, err := local.DeleteStoragePoolVolumeSnapshot(vol.pool, "custom", vol.name, oldName)
+			if err != nil {
+				return fmt.Errorf("Failed deleting replication snapshot %q of storage volume %q: %w", oldName, vol.name, err)
+			}
+
+			err = op.Wait()
+			if err != nil {
+				return fmt.Errorf("Failed deleting replication snapshot %q of storage volume %q: %w", oldName, vol.name, err)
+			}
+		}
+	}
+
+	// Replicate the volumes first so that the replica can use them.
+	for _, vol := range volumes {
+		apiVol, _, err := local.GetStoragePoolVolume(vol.pool, "custom", vol.name)
+		if err != nil {
+			return err
+		}
+
+		l.Info("Replicating storage volume", logger.Ctx{"pool": vol.pool, "volume": vol.name, "snapshot": snapName, "refresh": volumeRefresh[vol]})
+
+		remoteOp, err := remote.CopyStoragePoolVolume(vol.pool, local, vol.pool, *apiVol, &incus.StoragePoolVolumeCopyArgs{
+			Name:    vol.name,
+			Mode:    "push",
+			Refresh: volumeRefresh[vol],
+		})
+		if err != nil {
+			return fmt.Errorf("Failed replicating storage volume %q: %w", vol.name, err)
+		}
+
+		err = remoteOp.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed replicating storage volume %q: %w", vol.name, err)
+		}
+	}
+
+	// Prepare the replica definition.
+	apiInst, _, err := local.GetInstance(inst.Name())
+	if err != nil {
+		return err
+	}
+
+	var replicaConfig map[string]string
+	if remoteInst != nil {
+		replicaConfig = remoteInst.Config
+	}
+
+	apiInst.Config = instanceReplicationReplicaConfig(apiInst.Config, replicaConfig)
+
+	l.Info("Replicating instance", logger.Ctx{"snapshot": snapName, "refresh": refresh})
+
+	remoteOp, err := remote.CopyInstance(local, *apiInst, &incus.InstanceCopyArgs{
+		Mode:    "push",
+		Refresh: refresh,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed replicating instance: %w", err)
+	}
+
+	err = remoteOp.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed replicating instance: %w", err)
+	}
+
+	l.Info("Replicated instance", logger.Ctx{"snapshot": snapName})
+
+	return nil
+}
+
+// instanceFailover promotes a replica to be the primary, demoting the current primary if it can be reached.
+func instanceFailover(ctx context.Context, s *state.State, inst instance.Instance, force bool) error {
+	unlock, err := instanceReplicationLock(ctx, inst.Project().Name, inst.Name())
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	err = instanceReplicationCheckAction(inst.ExpandedConfig(), api.InstanceReplicationPost{Action: "failover", Force: force})
+	if err != nil {
+		return err
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "remote": inst.ExpandedConfig()["replication.remote"]})
+
+	err = instanceFailoverDemote(ctx, s, inst)
+	if err != nil {
+		if !force {
+			return fmt.Errorf("Failed demoting the primary instance: %w", err)
+		}
+
+		l.Warn("Failed demoting the primary instance, forcing failover", logger.Ctx{"err": err})
+	}
+
+	// Promote the local instance.
+	local, err := instanceReplicationLocalConnect(s, inst)
+	if err != nil {
+		return err
+	}
+
+	apiInst, etag, err := local.GetInstance(inst.Name())
+	if err != nil {
+		return err
+	}
+
+	delete(apiInst.Config, "replication.role")
+
+	op, err := local.UpdateInstance(inst.Name(), apiInst.Writable(), etag)
+	if err != nil {
+		return fmt.Errorf("Failed promoting replica: %w", err)
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed promoting replica: %w", err)
+	}
+
+	l.Info("Promoted replica instance")
+
+	return nil
+}
+
+// instanceFailoverDemote stops the primary instance, performs a final replication and turns it into a replica.
+func instanceFailoverDemote(ctx context.Context, s *state.State, inst instance.Instance) error {
+	primary, err := instanceReplicationConnect(ctx, s, inst)
+	if err != nil {
+		return err
+	}
+
+	primaryInst, _, err := primary.GetInstance(inst.Name())
+	if err != nil {
+		return fmt.Errorf("Failed getting primary instance: %w", err)
+	}
+
+	if primaryInst.ExpandedConfig["replication.role"] == "replica" {
+		return fmt.Errorf("Remote instance %q is already a replica", inst.Name())
+	}
+
+	// Stop the primary so the final replication is consistent.
+	if primaryInst.StatusCode != api.Stopped {
+		op, err := primary.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "stop", Timeout: 30}, "")
+		if err != nil {
+			return fmt.Errorf("Failed stopping primary instance: %w", err)
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed stopping primary instance: %w", err)
+		}
+	}
+
+	// Send the final changes over.
+	op, err := primary.UpdateInstanceReplication(inst.Name(), api.InstanceReplicationPost{Action: "replicate"})
+	if err != nil {
+		return fmt.Errorf("Failed final replication: %w", err)
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed final replication: %w", err)
+	}
+
+	// Turn the primary into a replica of the local instance.
+	primaryInst, etag, err := primary.GetInstance(inst.Name())
+	if err != nil {
+		return fmt.Errorf("Failed getting primary instance: %w", err)
+	}
+
+	primaryInst.Config["replication.role"] = "replica"
+
+	op, err = primary.UpdateInstance(inst.Name(), primaryInst.Writable(), etag)
+	if err != nil {
+		return fmt.Errorf("Failed demoting primary instance: %w", err)
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed demoting primary instance: %w", err)
+	}
+
+	return nil
+}
+
+func instanceReplicationTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Get the local instances that are due for replication.
+		instances := []instance.Instance{}
+		filter := dbCluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for replication task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				config := inst.ExpandedConfig()
+				if config["replication.remote"] == "" || config["replication.schedule"] == "" || instanceReplicationIsReplica(config) {
+					return nil
+				}
+
+				if !snapshotIsScheduledNow(config["replication.schedule"], int64(inst.ID())) {
+					return nil
+				}
+
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance replication schedule info", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(instances) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			for _, inst := range instances {
+				err := instanceReplicate(ctx, s, inst)
+				if err != nil {
+					logger.Error("Failed scheduled instance replication", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+				}
+			}
+
+			return nil
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.InstanceReplicate, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating scheduled instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Replicating scheduled instances")
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting scheduled instance replication operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed scheduled instance replication", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done replicating scheduled instances")
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		interval := time.Minute
+
+		if first {
+			first = false
+			return interval, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	// Only run the replications scheduled within the maintenance windows of the member.
+	windows := func() task.Windows {
+		return localMaintenanceWindows(d.State())
+	}
+
+	return f, task.Windowed(schedule, windows)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// replicationTestSnapshot implements the parts of instance.Instance used for replication snapshots.
+type replicationTestSnapshot struct {
+	instance.Instance
+
+	name    string
+	created time.Time
+}
+
+func (s *replicationTestSnapshot) Name() string {
+	return s.name
+}
+
+func (s *replicationTestSnapshot) CreationDate() time.Time {
+	return s.created
+}
+
+func replicationTestSnapshotNames(snapshots []instance.Instance) []string {
+	names := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Name())
+	}
+
+	return names
+}
+
+func TestInstanceReplicationCheckAction(t *testing.T) {
+	primary := map[string]string{"replication.remote": "dr-site"}
+	replica := map[string]string{"replication.remote": "main-site", "replication.role": "replica"}
+	orphanReplica := map[string]string{"replication.role": "replica"}
+
+	tests := []struct {
+		name   string
+		config map[string]string
+		req    api.InstanceReplicationPost
+		err    string
+	}{
+		{name: "Replicate primary", config: primary, req: api.InstanceReplicationPost{Action: "replicate"}},
+		{name: "Replicate replica", config: replica, req: api.InstanceReplicationPost{Action: "replicate"}, err: "Replica instances can't be replicated"},
+		{name: "Replicate without remote", config: map[string]string{}, req: api.InstanceReplicationPost{Action: "replicate"}, err: "Instance doesn't have a replication remote"},
+		{name: "Fail over to replica", config: replica, req: api.InstanceReplicationPost{Action: "failover"}},
+		{name: "Fail over to primary", config: primary, req: api.InstanceReplicationPost{Action: "failover", Force: true}, err: "Only replica instances can be failed over to"},
+		{name: "Fail over without remote", config: orphanReplica, req: api.InstanceReplicationPost{Action: "failover"}, err: "failing over requires force"},
+		{name: "Force fail over without remote", config: orphanReplica, req: api.InstanceReplicationPost{Action: "failover", Force: true}},
+		{name: "Unknown action", config: primary, req: api.InstanceReplicationPost{Action: "promote"}, err: `Unknown replication action "promote"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := instanceReplicationCheckAction(tt.config, tt.req)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestInstanceReplicationSnapshots(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "replication-20240101-120000", instanceReplicationSnapshotName(start))
+	assert.Equal(t, "replication-20240101-120000", instanceReplicationSnapshotName(start.In(time.FixedZone("UTC+2", 2*60*60))))
+
+	snapshots := []instance.Instance{
+		&replicationTestSnapshot{name: "c1/replication-20240101-140000", created: start.Add(2 * time.Hour)},
+		&replicationTestSnapshot{name: "c1/snap0", created: start.Add(-time.Hour)},
+		&replicationTestSnapshot{name: "c1/replication-20240101-120000", created: start},
+		&replicationTestSnapshot{name: "c1/before-replication-20240101", created: start},
+		&replicationTestSnapshot{name: "c1/replication-20240101-130000", created: start.Add(time.Hour)},
+	}
+
+	// Only the replication snapshots are returned, oldest first.
+	replicationSnapshots := instanceReplicationFilterSnapshots(snapshots)
+	assert.Equal(t, []string{"c1/replication-20240101-120000", "c1/replication-20240101-130000", "c1/replication-20240101-140000"}, replicationTestSnapshotNames(replicationSnapshots))
+
+	// The oldest snapshots beyond the retention are pruned.
+	assert.Equal(t, []string{"c1/replication-20240101-120000"}, replicationTestSnapshotNames(instanceReplicationPrune(replicationSnapshots, 2)))
+	assert.Equal(t, []string{"c1/replication-20240101-120000", "c1/replication-20240101-130000"}, replicationTestSnapshotNames(instanceReplicationPrune(replicationSnapshots, 1)))
+	assert.Empty(t, instanceReplicationPrune(replicationSnapshots, 3))
+	assert.Empty(t, instanceReplicationPrune(replicationSnapshots, 5))
+}
+
+func TestInstanceReplicationVolumes(t *testing.T) {
+	devices := deviceConfig.Devices{
+		"root":   {"type": "disk", "pool": "default", "path": "/"},
+		"data":   {"type": "disk", "pool": "default", "source": "data", "path": "/srv/data"},
+		"logs":   {"type": "disk", "pool": "fast", "source": "data/logs", "path": "/var/log/app"},
+		"config": {"type": "disk", "pool": "default", "source": "data/config", "path": "/etc/app"},
+		"host":   {"type": "disk", "source": "/srv/shared", "path": "/mnt"},
+		"eth0":   {"type": "nic", "network": "incusbr0"},
+	}
+
+	// Only custom volumes are returned, once per pool.
+	assert.Equal(t, []instanceReplicationVolume{{pool: "default", name: "data"}, {pool: "fast", name: "data"}}, instanceReplicationVolumes(devices))
+	assert.Empty(t, instanceReplicationVolumes(deviceConfig.Devices{"root": {"type": "disk", "pool": "default", "path": "/"}}))
+}
+
+func TestInstanceReplicationVolumeSnapshots(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	snapshots := []api.StorageVolumeSnapshot{
+		{Name: "data/replication-20240101-130000", CreatedAt: start.Add(time.Hour)},
+		{Name: "data/snap0", CreatedAt: start.Add(-time.Hour)},
+		{Name: "data/replication-20240101-120000", CreatedAt: start},
+	}
+
+	// Only the names of the replication snapshots are returned, oldest first.
+	assert.Equal(t, []string{"replication-20240101-120000", "replication-20240101-130000"}, instanceReplicationFilterVolumeSnapshots(snapshots))
+}
+
+func TestInstanceReplicationRetention(t *testing.T) {
+	retention, err := instanceReplicationRetention(map[string]string{})
+	require.NoError(t, err)
+	assert.Equal(t, instanceReplicationDefaultRetention, retention)
+
+	retention, err = instanceReplicationRetention(map[string]string{"replication.snapshots.retention": "5"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, retention)
+
+	// The snapshot being replicated is always kept.
+	retention, err = instanceReplicationRetention(map[string]string{"replication.snapshots.retention": "0"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, retention)
+
+	_, err = instanceReplicationRetention(map[string]string{"replication.snapshots.retention": "foo"})
+	assert.Error(t, err)
+}
+
+func TestInstanceReplicationReplicaConfig(t *testing.T) {
+	config := map[string]string{
+		"limits.cpu":                        "2",
+		"replication.remote":                "dr-site",
+		"replication.schedule":              "@hourly",
+		"volatile.replication.last_success": "1704110400",
+		"volatile.replication.last_error":   "",
+	}
+
+	// A new replica doesn't get the primary's remote, it's only meaningful on the primary's server.
+	assert.Equal(t, map[string]string{
+		"limits.cpu":           "2",
+		"replication.role":     "replica",
+		"replication.schedule": "@hourly",
+	}, instanceReplicationReplicaConfig(config, nil))
+
+	// An existing replica keeps the remote pointing back at the primary.
+	assert.Equal(t, map[string]string{
+		"limits.cpu":           "2",
+		"replication.role":     "replica",
+		"replication.remote":   "main-site",
+		"replication.schedule": "@hourly",
+	}, instanceReplicationReplicaConfig(config, map[string]string{"replication.role": "replica", "replication.remote": "main-site"}))
+
+	// The primary's configuration isn't modified.
+	assert.Equal(t, "dr-site", config["replication.remote"])
+}
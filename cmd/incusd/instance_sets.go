@@ -0,0 +1,766 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/cluster"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/instanceset"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/locking"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+var instanceSetsCmd = APIEndpoint{
+	Path: "instance-sets",
+
+	Get:  APIEndpointAction{Handler: instanceSetsGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: instanceSetsPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+var instanceSetCmd = APIEndpoint{
+	Path: "instance-sets/{name}",
+
+	Delete: APIEndpointAction{Handler: instanceSetDelete, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: instanceSetGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Put:    APIEndpointAction{Handler: instanceSetPut, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+// instanceSetLock prevents concurrent changes to the same instance set.
+func instanceSetLock(ctx context.Context, projectName string, name string) (locking.UnlockFunc, error) {
+	return locking.Lock(ctx, fmt.Sprintf("InstanceSetOperation_%s/%s", projectName, name))
+}
+
+// instanceSetLoad returns the current definition of an instance set.
+func instanceSetLoad(ctx context.Context, s *state.State, projectName string, name string) (*api.InstanceSet, error) {
+	var info *api.InstanceSet
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbInstanceSet, err := dbCluster.GetInstanceSet(ctx, tx.Tx(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		info, err = dbInstanceSet.ToAPI()
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	instanceSetFillInstances(info)
+
+	return info, nil
+}
+
+// instanceSetFillInstances sets the URLs of the instances of the set.
+func instanceSetFillInstances(info *api.InstanceSet) {
+	info.Instances = []string{}
+	for _, instName := range instanceset.InstanceNames(info.Name, info.Replicas) {
+		info.Instances = append(info.Instances, api.NewURL().Path(version.APIVersion, "instances", instName).Project(info.Project).String())
+	}
+}
+
+// instanceSetReconcileTask recreates the instances of the sets which went missing, keeping the instances of each
+// set in line with its number of replicas.
+func instanceSetReconcileTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Only run the task on the leader to avoid concurrent reconciliations.
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil && !errors.Is(err, cluster.ErrNodeIsNotClustered) {
+			logger.Error("Failed to get leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		if err == nil && leader != s.LocalConfig.ClusterAddress() {
+			return
+		}
+
+		// Find the sets missing some of their instances.
+		incomplete := []dbCluster.InstanceSet{}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			dbInstanceSets, err := dbCluster.GetInstanceSets(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			projectInstances := map[string][]string{}
+			for _, dbInstanceSet := range dbInstanceSets {
+				info, err := dbInstanceSet.ToAPI()
+				if err != nil {
+					return err
+				}
+
+				instanceNames, ok := projectInstances[dbInstanceSet.Project]
+				if !ok {
+					instanceNames, err = tx.GetInstanceNames(ctx, dbInstanceSet.Project)
+					if err != nil {
+						return err
+					}
+
+					projectInstances[dbInstanceSet.Project] = instanceNames
+				}
+
+				for _, instName := range instanceset.InstanceNames(info.Name, info.Replicas) {
+					if !slices.Contains(instanceNames, instName) {
+						incomplete = append(incomplete, dbInstanceSet)
+						break
+					}
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed getting instance sets", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(incomplete) == 0 {
+			return
+		}
+
+		opRun := func(op *operations.Operation) error {
+			for _, dbInstanceSet := range incomplete {
+				err := instanceSetReconcile(ctx, s, dbInstanceSet.Project, dbInstanceSet.Name)
+				if err != nil {
+					logger.Error("Failed reconciling instance set", logger.Ctx{"project": dbInstanceSet.Project, "name": dbInstanceSet.Name, "err": err})
+				}
+			}
+
+			return nil
+		}
+
+		op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.InstanceSetReconcile, nil, nil, opRun, nil, nil, nil)
+		if err != nil {
+			logger.Error("Failed creating instance set reconciliation operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Start()
+		if err != nil {
+			logger.Error("Failed starting instance set reconciliation operation", logger.Ctx{"err": err})
+			return
+		}
+
+		_ = op.Wait(ctx)
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
+
+// instanceSetSource returns a function returning the source of the instances of an instance set definition.
+func instanceSetSource(spec api.InstanceSetPut) func(name string) api.InstanceSource {
+	return func(name string) api.InstanceSource {
+		return spec.Source
+	}
+}
+
+// instanceSetReconcile re-applies the current definition of an instance set, creating its missing instances.
+func instanceSetReconcile(ctx context.Context, s *state.State, projectName string, name string) error {
+	unlock, err := instanceSetLock(ctx, projectName, name)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	current, err := instanceSetLoad(ctx, s, projectName, name)
+	if err != nil {
+		return err
+	}
+
+	client, err := localProjectClient(s, nil, projectName)
+	if err != nil {
+		return err
+	}
+
+	spec := current.Writable()
+
+	_, err = instanceset.Apply(client, name, &spec, spec, nil)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Recreated missing instances of instance set", logger.Ctx{"project": projectName, "name": name})
+
+	return nil
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/instance-sets instance-sets instance_sets_get
+//
+//	Get the instance sets
+//
+//	Returns a list of instance sets (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/instance-sets/web",
+//	              "/1.0/instance-sets/workers"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/instance-sets?recursion=1 instance-sets instance_sets_get_recursion1
+//
+//	Get the instance sets
+//
+//	Returns a list of instance sets (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of instance sets
+//	          items:
+//	            $ref: "#/definitions/InstanceSet"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSetsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var dbInstanceSets []dbCluster.InstanceSet
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbInstanceSets, err = dbCluster.GetInstanceSets(ctx, tx.Tx(), dbCluster.InstanceSetFilter{Project: &projectName})
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(dbInstanceSets))
+		for _, dbInstanceSet := range dbInstanceSets {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "instance-sets", dbInstanceSet.Name).Project(projectName).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	instanceSets := make([]api.InstanceSet, 0, len(dbInstanceSets))
+	for _, dbInstanceSet := range dbInstanceSets {
+		info, err := dbInstanceSet.ToAPI()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		instanceSetFillInstances(info)
+		instanceSets = append(instanceSets, *info)
+	}
+
+	return response.SyncResponse(true, instanceSets)
+}
+
+// swagger:operation POST /1.0/instance-sets instance-sets instance_sets_post
+//
+//	Add an instance set
+//
+//	Creates a new instance set along with its instances.
+//	If any of them can't be created, those created so far are removed again.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: instance-set
+//	    description: Instance set
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceSetsPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSetsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	req := api.InstanceSetsPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// The name must result in valid instance names.
+	err = instance.ValidName(instanceset.InstanceName(req.Name, 1), false)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid instance set name: %w", err))
+	}
+
+	err = instanceset.Validate(req.InstanceSetPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		exists, err := dbCluster.InstanceSetExists(ctx, tx.Tx(), projectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "The instance set already exists")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	spec, err := json.Marshal(req.InstanceSetPut)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	run := func(op *operations.Operation) error {
+		unlock, err := instanceSetLock(context.Background(), projectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		client, err := localProjectClient(s, r, projectName)
+		if err != nil {
+			return err
+		}
+
+		check, err := stackChangeCheck(context.Background(), s, r, projectName, instanceSetSource(req.InstanceSetPut))
+		if err != nil {
+			return err
+		}
+
+		cleanup, err := instanceset.Apply(client, req.Name, nil, req.InstanceSetPut, check)
+		if err != nil {
+			return err
+		}
+
+		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := dbCluster.CreateInstanceSet(ctx, tx.Tx(), dbCluster.InstanceSet{
+				Project:     projectName,
+				Name:        req.Name,
+				Description: req.Description,
+				Spec:        string(spec),
+			})
+
+			return err
+		})
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("Failed to record instance set: %w", err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceSetCreated.Event(req.Name, projectName, requestor, map[string]any{"replicas": req.Replicas}))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceSetCreate, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation GET /1.0/instance-sets/{name} instance-sets instance_set_get
+//
+//	Get the instance set
+//
+//	Gets a specific instance set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Instance set
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/InstanceSet"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSetGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := instanceSetLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, info, info.Writable())
+}
+
+// swagger:operation PUT /1.0/instance-sets/{name} instance-sets instance_set_put
+//
+//	Update the instance set
+//
+//	Updates the definition of the instance set, creating or deleting instances
+//	to match the number of replicas and updating the configuration of the existing ones.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: instance-set
+//	    description: Instance set definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/InstanceSetPut"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSetPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := instanceSetLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, info.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.InstanceSetPut{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = instanceset.Validate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	spec, err := json.Marshal(req)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	run := func(op *operations.Operation) error {
+		unlock, err := instanceSetLock(context.Background(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		// Reload the definition now that the instance set is locked.
+		current, err := instanceSetLoad(context.Background(), s, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		client, err := localProjectClient(s, r, projectName)
+		if err != nil {
+			return err
+		}
+
+		check, err := stackChangeCheck(context.Background(), s, r, projectName, instanceSetSource(req))
+		if err != nil {
+			return err
+		}
+
+		currentSpec := current.Writable()
+
+		cleanup, err := instanceset.Apply(client, name, &currentSpec, req, check)
+		if err != nil {
+			return err
+		}
+
+		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.UpdateInstanceSet(ctx, tx.Tx(), projectName, name, dbCluster.InstanceSet{
+				Project:     projectName,
+				Name:        name,
+				Description: req.Description,
+				Spec:        string(spec),
+			})
+		})
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("Failed to record instance set: %w", err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceSetUpdated.Event(name, projectName, requestor, map[string]any{"replicas": req.Replicas}))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceSetUpdate, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation DELETE /1.0/instance-sets/{name} instance-sets instance_set_delete
+//
+//	Delete the instance set
+//
+//	Removes the instance set along with all of its instances.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSetDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	_, err = instanceSetLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	run := func(op *operations.Operation) error {
+		unlock, err := instanceSetLock(context.Background(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		current, err := instanceSetLoad(context.Background(), s, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		client, err := localProjectClient(s, r, projectName)
+		if err != nil {
+			return err
+		}
+
+		check, err := stackChangeCheck(context.Background(), s, r, projectName, instanceSetSource(api.InstanceSetPut{}))
+		if err != nil {
+			return err
+		}
+
+		cleanup, err := instanceset.Delete(client, name, current.Writable(), check)
+		if err != nil {
+			return err
+		}
+
+		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteInstanceSet(ctx, tx.Tx(), projectName, name)
+		})
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("Failed to remove instance set record: %w", err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.InstanceSetDeleted.Event(name, projectName, requestor, nil))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.InstanceSetDelete, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
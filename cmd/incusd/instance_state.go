@@ -16,6 +16,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/operations"
 	"github.com/lxc/incus/v6/internal/server/request"
 	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/api"
 )
@@ -198,7 +199,7 @@ func instanceStatePut(d *Daemon, r *http.Request) response.Response {
 	do := func(op *operations.Operation) error {
 		inst.SetOperation(op)
 
-		return doInstanceStatePut(inst, req)
+		return doInstanceStatePut(s, inst, req)
 	}
 
 	resources := map[string][]api.URL{}
@@ -228,7 +229,7 @@ func instanceActionToOptype(action string) (operationtype.Type, error) {
 	return operationtype.Unknown, fmt.Errorf("Unknown action: '%s'", action)
 }
 
-func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error {
+func doInstanceStatePut(s *state.State, inst instance.Instance, req api.InstanceStatePut) error {
 	if req.Force {
 		// A zero timeout indicates to do a forced stop/restart.
 		req.Timeout = 0
@@ -242,6 +243,11 @@ func doInstanceStatePut(inst instance.Instance, req api.InstanceStatePut) error
 
 	switch internalInstance.InstanceAction(req.Action) {
 	case internalInstance.Start:
+		err := instanceWaitDependencies(s.ShutdownCtx, s, inst)
+		if err != nil {
+			return err
+		}
+
 		return inst.Start(req.Stateful)
 	case internalInstance.Stop:
 		if req.Stateful {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -14,18 +15,23 @@ import (
 	"sync"
 	"time"
 
+	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/warningtype"
+	"github.com/lxc/incus/v6/internal/server/device"
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/metrics"
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
 	"github.com/lxc/incus/v6/internal/server/warnings"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/util"
 )
 
@@ -165,6 +171,14 @@ var instanceAccessCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceAccess, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
 }
 
+var instanceReplicationCmd = APIEndpoint{
+	Name: "instanceReplication",
+	Path: "instances/{name}/replication",
+
+	Get:  APIEndpointAction{Handler: instanceReplicationGet, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanView, "name")},
+	Post: APIEndpointAction{Handler: instanceReplicationPost, AccessHandler: allowPermission(auth.ObjectTypeInstance, auth.EntitlementCanEdit, "name")},
+}
+
 var instanceDebugMemoryCmd = APIEndpoint{
 	Name: "instanceDebugMemory",
 	Path: "instances/{name}/debug/memory",
@@ -220,12 +234,20 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 	// Sort based on instance boot priority.
 	sort.Sort(instanceAutostartList(instances))
 
+	// Start dependencies before the instances relying on them, keeping the priority order within each rank.
+	instanceDependencySort(instances, false)
+
 	// Let's make up to 3 attempts to start instances.
 	maxAttempts := 3
 
 	// Start the instances
 	for _, inst := range instances {
 		if !instanceShouldAutoStart(inst) {
+			// Instances stopped due to inactivity are woken up on incoming connections instead.
+			if !inst.IsRunning() && util.IsTrue(inst.LocalConfig()["volatile.last_state.idle"]) {
+				instanceIdleWakeListen(s, inst)
+			}
+
 			continue
 		}
 
@@ -241,6 +263,13 @@ func instancesStart(s *state.State, instances []instance.Instance) {
 
 		instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
+		// Wait for the instances it depends on.
+		err := instanceWaitDependencies(s.ShutdownCtx, s, inst)
+		if err != nil {
+			instLogger.Error("Failed to auto start instance", logger.Ctx{"err": err})
+			continue
+		}
+
 		// Try to start the instance.
 		attempt := 0
 		for {
@@ -394,6 +423,10 @@ func instancesOnDisk(s *state.State) ([]instance.Instance, error) {
 func instancesShutdown(s *state.State, instances []instance.Instance) {
 	sort.Sort(instanceStopList(instances))
 
+	// Stop the instances relying on others first, keeping the priority order within each rank.
+	instanceDependencySort(instances, true)
+	ranks := instanceDependencyRanks(instances)
+
 	// Limit shutdown concurrency to number of instances or number of CPU cores (which ever is less).
 	var wg sync.WaitGroup
 	instShutdownCh := make(chan instance.Instance)
@@ -448,6 +481,7 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 	}
 
 	var currentBatchPriority int
+	var currentBatchRank int
 	for i, inst := range instances {
 		// Skip stopped instances.
 		if !inst.IsRunning() {
@@ -455,10 +489,12 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 		}
 
 		priority, _ := strconv.Atoi(inst.ExpandedConfig()["boot.stop.priority"])
+		rank := ranks[instanceDependencyKey(inst.Project().Name, inst.Name())]
 
 		// Shutdown instances in priority batches, logging at the start of each batch.
-		if i == 0 || priority != currentBatchPriority {
+		if i == 0 || priority != currentBatchPriority || rank != currentBatchRank {
 			currentBatchPriority = priority
+			currentBatchRank = rank
 
 			// Wait for instances with higher priority to finish before starting next batch.
 			wg.Wait()
@@ -472,3 +508,227 @@ func instancesShutdown(s *state.State, instances []instance.Instance) {
 	wg.Wait()
 	close(instShutdownCh)
 }
+
+// instanceIdleState tracks the activity counters of a running instance between idle checks.
+type instanceIdleState struct {
+	sampled      time.Time
+	cpuSeconds   float64
+	networkBytes float64
+	idleSince    time.Time
+}
+
+// instanceIdleCounters returns the total CPU seconds and network bytes (excluding loopback) from the metrics.
+func instanceIdleCounters(metricSet *metrics.MetricSet) (float64, float64) {
+	var cpuSeconds, networkBytes float64
+
+	for _, sample := range metricSet.GetSamples(metrics.CPUSecondsTotal) {
+		// Don't count the time spent idling.
+		if sample.Labels["mode"] == "idle" {
+			continue
+		}
+
+		cpuSeconds += sample.Value
+	}
+
+	for _, metricType := range []metrics.MetricType{metrics.NetworkReceiveBytesTotal, metrics.NetworkTransmitBytesTotal} {
+		for _, sample := range metricSet.GetSamples(metricType) {
+			if sample.Labels["device"] == "lo" {
+				continue
+			}
+
+			networkBytes += sample.Value
+		}
+	}
+
+	return cpuSeconds, networkBytes
+}
+
+// instanceIsIdle returns whether the activity between two samples is below the instance's idle thresholds.
+func instanceIsIdle(config map[string]string, previous *instanceIdleState, current *instanceIdleState) bool {
+	elapsed := current.sampled.Sub(previous.sampled).Seconds()
+	if elapsed <= 0 {
+		return false
+	}
+
+	cpuThreshold := int64(5)
+	if config["boot.idle_stop.cpu"] != "" {
+		cpuThreshold, _ = strconv.ParseInt(config["boot.idle_stop.cpu"], 10, 64)
+	}
+
+	networkThreshold := int64(1024)
+	if config["boot.idle_stop.network"] != "" {
+		networkThreshold, _ = units.ParseByteSizeString(config["boot.idle_stop.network"])
+	}
+
+	cpuUsage := (current.cpuSeconds - previous.cpuSeconds) / elapsed * 100
+	if cpuUsage > float64(cpuThreshold) {
+		return false
+	}
+
+	networkUsage := (current.networkBytes - previous.networkBytes) / elapsed
+
+	return networkUsage <= float64(networkThreshold)
+}
+
+// instanceIdleStop stops an instance which has been idle for too long and arms its wake on connect listeners.
+func instanceIdleStop(s *state.State, inst instance.Instance) {
+	instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+	instLogger.Info("Stopping idle instance")
+
+	timeoutSeconds := 30
+	value, ok := inst.ExpandedConfig()["boot.host_shutdown_timeout"]
+	if ok {
+		timeoutSeconds, _ = strconv.Atoi(value)
+	}
+
+	err := inst.Shutdown(time.Second * time.Duration(timeoutSeconds))
+	if err != nil {
+		instLogger.Warn("Failed shutting down idle instance, forcefully stopping", logger.Ctx{"err": err})
+		err = inst.Stop(false)
+		if err != nil {
+			instLogger.Error("Failed stopping idle instance", logger.Ctx{"err": err})
+			return
+		}
+	}
+
+	err = inst.VolatileSet(map[string]string{"volatile.last_state.idle": "true"})
+	if err != nil {
+		instLogger.Warn("Failed recording idle state", logger.Ctx{"err": err})
+	}
+
+	instanceIdleWakeListen(s, inst)
+}
+
+// instanceIdleWakeListen sets up the wake on connect listeners of an instance stopped due to inactivity.
+func instanceIdleWakeListen(s *state.State, inst instance.Instance) {
+	projectName := inst.Project().Name
+	instanceName := inst.Name()
+
+	err := device.WakeListen(s, inst, func() error {
+		inst, err := instance.LoadByProjectAndName(s, projectName, instanceName)
+		if err != nil {
+			return err
+		}
+
+		if inst.IsRunning() {
+			return nil
+		}
+
+		logger.Info("Starting idle instance on incoming connection", logger.Ctx{"project": projectName, "instance": instanceName})
+
+		return inst.Start(false)
+	})
+	if err != nil {
+		logger.Warn("Failed setting up wake on connect for idle instance", logger.Ctx{"project": projectName, "instance": instanceName, "err": err})
+	}
+}
+
+func instanceIdleStopTask(d *Daemon) (task.Func, task.Schedule) {
+	idleStates := map[int]*instanceIdleState{}
+
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		// Get the running local instances which have idle stop configured.
+		var instances []instance.Instance
+		filter := cluster.InstanceFilter{Node: &s.ServerName}
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+				inst, err := instance.Load(s, dbInst, p)
+				if err != nil {
+					return fmt.Errorf("Failed loading instance %q (project %q) for idle stop task: %w", dbInst.Name, dbInst.Project, err)
+				}
+
+				if inst.ExpandedConfig()["boot.idle_stop"] == "" || !inst.IsRunning() {
+					return nil
+				}
+
+				instances = append(instances, inst)
+
+				return nil
+			}, filter)
+		})
+		if err != nil {
+			logger.Error("Failed getting instance idle stop info", logger.Ctx{"err": err})
+			return
+		}
+
+		// Gather information about host interfaces once.
+		hostInterfaces, _ := net.Interfaces()
+
+		checked := make(map[int]bool, len(instances))
+		var idleInstances []instance.Instance
+		for _, inst := range instances {
+			checked[inst.ID()] = true
+
+			instMetrics, err := inst.Metrics(hostInterfaces)
+			if err != nil {
+				logger.Debug("Failed getting instance metrics for idle stop", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+				delete(idleStates, inst.ID())
+				continue
+			}
+
+			current := &instanceIdleState{sampled: time.Now()}
+			current.cpuSeconds, current.networkBytes = instanceIdleCounters(instMetrics)
+
+			previous := idleStates[inst.ID()]
+			idleStates[inst.ID()] = current
+
+			// Wait for a second sample to compare against.
+			if previous == nil || !instanceIsIdle(inst.ExpandedConfig(), previous, current) {
+				continue
+			}
+
+			current.idleSince = previous.idleSince
+			if current.idleSince.IsZero() {
+				current.idleSince = previous.sampled
+			}
+
+			deadline, err := internalInstance.GetExpiry(current.idleSince, inst.ExpandedConfig()["boot.idle_stop"])
+			if err != nil || current.sampled.Before(deadline) {
+				continue
+			}
+
+			idleInstances = append(idleInstances, inst)
+			delete(idleStates, inst.ID())
+		}
+
+		// Stop the idle instances, limiting concurrency to the number of CPU cores.
+		var wg sync.WaitGroup
+		idleStopCh := make(chan instance.Instance)
+		maxConcurrent := min(runtime.NumCPU(), len(idleInstances))
+
+		for i := 0; i < maxConcurrent; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for inst := range idleStopCh {
+					instanceIdleStop(s, inst)
+				}
+			}()
+		}
+
+		for _, inst := range idleInstances {
+			idleStopCh <- inst
+		}
+
+		close(idleStopCh)
+		wg.Wait()
+
+		// Forget about instances which are no longer running or no longer have idle stop configured.
+		for id := range idleStates {
+			if !checked[id] {
+				delete(idleStates, id)
+			}
+		}
+	}
+
+	// Defer stopping idle instances until a maintenance window of the member opens.
+	windows := func() task.Windows {
+		return localMaintenanceWindows(d.State())
+	}
+
+	return f, task.Windowed(task.Every(time.Minute), windows)
+}
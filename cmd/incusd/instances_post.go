@@ -15,6 +15,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
+	"github.com/lxc/incus/v6/internal/server/auth"
 	"github.com/lxc/incus/v6/internal/server/backup"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -494,6 +495,61 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 		return response.SmartError(err)
 	}
 
+	// Config override
+	sourceConfig := source.LocalConfig()
+	if req.Config == nil {
+		req.Config = make(map[string]string)
+	}
+
+	for key, value := range sourceConfig {
+		if !internalInstance.InstanceIncludeWhenCopying(key, false) {
+			logger.Debug("Skipping key from copy source", logger.Ctx{"key": key, "sourceProject": source.Project().Name, "sourceInstance": source.Name(), "project": targetProject, "instance": req.Name})
+			continue
+		}
+
+		_, exists := req.Config[key]
+		if exists {
+			continue
+		}
+
+		req.Config[key] = value
+	}
+
+	// Devices override
+	sourceDevices := source.LocalDevices()
+
+	if req.Devices == nil {
+		req.Devices = make(map[string]map[string]string)
+	}
+
+	for key, value := range sourceDevices {
+		_, exists := req.Devices[key]
+		if exists {
+			continue
+		}
+
+		req.Devices[key] = value
+	}
+
+	// Run the instance admission scriptlet on the resulting configuration so its changes are also subject to the
+	// project's limits.
+	sourceType := req.Type
+	if sourceType == "" {
+		sourceType = api.InstanceType(source.Type().String())
+	}
+
+	err = instanceAdmission(ctx, s, apiScriptlet.InstanceAdmissionReasonCreate, targetProject, req.Name, string(sourceType), &req.InstancePut, profiles)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return project.AllowInstanceCreation(tx, targetProject, *req)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// When clustered, use the node name, otherwise use the hostname.
 	if s.ServerClustered {
 		serverName := s.ServerName
@@ -532,42 +588,6 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 		}
 	}
 
-	// Config override
-	sourceConfig := source.LocalConfig()
-	if req.Config == nil {
-		req.Config = make(map[string]string)
-	}
-
-	for key, value := range sourceConfig {
-		if !internalInstance.InstanceIncludeWhenCopying(key, false) {
-			logger.Debug("Skipping key from copy source", logger.Ctx{"key": key, "sourceProject": source.Project().Name, "sourceInstance": source.Name(), "project": targetProject, "instance": req.Name})
-			continue
-		}
-
-		_, exists := req.Config[key]
-		if exists {
-			continue
-		}
-
-		req.Config[key] = value
-	}
-
-	// Devices override
-	sourceDevices := source.LocalDevices()
-
-	if req.Devices == nil {
-		req.Devices = make(map[string]map[string]string)
-	}
-
-	for key, value := range sourceDevices {
-		_, exists := req.Devices[key]
-		if exists {
-			continue
-		}
-
-		req.Devices[key] = value
-	}
-
 	if req.Stateful {
 		sourceName, _, _ := api.GetParentAndSnapshotName(source.Name())
 		if sourceName != req.Name {
@@ -589,6 +609,21 @@ func createFromCopy(ctx context.Context, s *state.State, r *http.Request, projec
 		return response.BadRequest(fmt.Errorf("Instance type should not be specified or should match source type"))
 	}
 
+	// Check that the snapshots being copied fit within the target project's limits.
+	if !req.Source.InstanceOnly && !req.Source.Refresh {
+		snapshots, err := source.Snapshots()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return project.AllowSnapshotsImport(tx, targetProject, len(snapshots))
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	args := db.InstanceArgs{
 		Project:      targetProject,
 		Architecture: source.Architecture(),
@@ -704,17 +739,64 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 		return response.BadRequest(fmt.Errorf("Backup file is missing required information"))
 	}
 
+	req := api.InstancesPost{
+		InstancePut: bInfo.Config.Container.InstancePut,
+		Name:        bInfo.Name,
+		Source:      api.InstanceSource{}, // Only relevant for "copy" or "migration", but may not be nil.
+		Type:        api.InstanceType(bInfo.Config.Container.Type),
+	}
+
+	if instanceName != "" {
+		req.Name = instanceName
+	}
+
+	// Load the profiles.
+	profiles := make([]api.Profile, 0, len(req.Profiles))
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProfiles, err := dbCluster.GetProfilesIfEnabled(ctx, tx.Tx(), projectName, req.Profiles)
+		if err != nil {
+			return err
+		}
+
+		profileConfigs, err := dbCluster.GetAllProfileConfigs(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		profileDevices, err := dbCluster.GetAllProfileDevices(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, profile := range dbProfiles {
+			apiProfile, err := profile.ToAPI(ctx, tx.Tx(), profileConfigs, profileDevices)
+			if err != nil {
+				return err
+			}
+
+			profiles = append(profiles, *apiProfile)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Run the instance admission scriptlet so its changes are also subject to the project's limits.
+	err = instanceAdmission(r.Context(), s, apiScriptlet.InstanceAdmissionReasonCreate, projectName, req.Name, string(req.Type), &req.InstancePut, profiles)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Check project permissions.
-	var req api.InstancesPost
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-		req = api.InstancesPost{
-			InstancePut: bInfo.Config.Container.InstancePut,
-			Name:        bInfo.Name,
-			Source:      api.InstanceSource{}, // Only relevant for "copy" or "migration", but may not be nil.
-			Type:        api.InstanceType(bInfo.Config.Container.Type),
+		err := project.AllowInstanceCreation(tx, projectName, req)
+		if err != nil {
+			return err
 		}
 
-		return project.AllowInstanceCreation(tx, projectName, req)
+		return project.AllowSnapshotsImport(tx, projectName, len(bInfo.Snapshots))
 	})
 	if err != nil {
 		return response.SmartError(err)
@@ -830,6 +912,23 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 			}
 		}
 
+		// Apply the changes made by the instance admission scriptlet to the restored configuration.
+		if s.GlobalConfig.InstancesAdmissionScriptlet() != "" {
+			err = inst.Update(db.InstanceArgs{
+				Architecture: inst.Architecture(),
+				Config:       req.Config,
+				Description:  inst.Description(),
+				Devices:      deviceConfig.NewDevices(req.Devices),
+				Ephemeral:    inst.IsEphemeral(),
+				Profiles:     inst.Profiles(),
+				Project:      inst.Project().Name,
+				Type:         inst.Type(),
+			}, false)
+			if err != nil {
+				return fmt.Errorf("Failed applying the instance admission scriptlet changes: %w", err)
+			}
+		}
+
 		runRevert.Success()
 
 		return instanceCreateFinish(s, &req, db.InstanceArgs{Name: bInfo.Name, Project: bInfo.Project}, op)
@@ -847,6 +946,55 @@ func createFromBackup(s *state.State, r *http.Request, projectName string, data
 	return operations.OperationResponse(op)
 }
 
+// instanceSourceCheck checks that the requestor is allowed to use the source of an instance created in the
+// project. Copies require access to the source instance and local images which aren't public require access to
+// the image.
+func instanceSourceCheck(ctx context.Context, s *state.State, r *http.Request, projectName string, source api.InstanceSource) error {
+	switch source.Type {
+	case "copy":
+		sourceProject := source.Project
+		if sourceProject == "" {
+			sourceProject = projectName
+		}
+
+		sourceName, _, _ := api.GetParentAndSnapshotName(source.Source)
+
+		return s.Authorizer.CheckPermission(ctx, r, auth.ObjectInstance(sourceProject, sourceName), auth.EntitlementCanView)
+
+	case "image":
+		if source.Server != "" {
+			return nil
+		}
+
+		var sourceImage *api.Image
+
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			var imageRef string
+			var err error
+
+			sourceImage, err = getSourceImageFromInstanceSource(ctx, s, tx, projectName, source, &imageRef, "")
+
+			return err
+		})
+		if err != nil {
+			// Missing images are reported when creating the instance.
+			if response.IsNotFoundError(err) {
+				return nil
+			}
+
+			return err
+		}
+
+		if sourceImage.Public {
+			return nil
+		}
+
+		return s.Authorizer.CheckPermission(ctx, r, auth.ObjectImage(projectName, sourceImage.Fingerprint), auth.EntitlementCanView)
+	}
+
+	return nil
+}
+
 // swagger:operation POST /1.0/instances instances instances_post
 //
 //	Create a new instance
@@ -947,6 +1095,14 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Check that the requestor is allowed to use the source.
+	if !clusterNotification {
+		err = instanceSourceCheck(r.Context(), s, r, targetProjectName, req.Source)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	var targetProject *api.Project
 	var profiles []api.Profile
 	var sourceInst *dbCluster.Instance
@@ -1147,21 +1303,30 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		if !clusterNotification {
-			// Check that the project's limits are not violated. Note this check is performed after
-			// automatically generated config values (such as ones from an InstanceType) have been set.
-			err = project.AllowInstanceCreation(tx, targetProjectName, req)
-			if err != nil {
-				return err
-			}
-		}
-
 		return nil
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	if !clusterNotification && req.Source.Type != "copy" {
+		// Run the instance admission scriptlet so its changes are also subject to the project's limits.
+		// Copies are checked once merged with the configuration of their source.
+		err = instanceAdmission(r.Context(), s, apiScriptlet.InstanceAdmissionReasonCreate, targetProjectName, req.Name, string(req.Type), &req.InstancePut, profiles)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		// Check that the project's limits are not violated. Note this check is performed after
+		// automatically generated config values (such as ones from an InstanceType) have been set.
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return project.AllowInstanceCreation(tx, targetProjectName, req)
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	err = instance.ValidName(req.Name, false)
 	if err != nil {
 		return response.BadRequest(err)
@@ -1173,6 +1338,17 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			candidateMembers = []db.NodeInfo{*targetMemberInfo}
 		}
 
+		// Only keep the members with enough capacity left for the instance.
+		dbType, err := instancetype.New(string(req.Type))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		candidateMembers, err = clusterGroupCapacityFilter(r.Context(), s, candidateMembers, targetProjectName, req.Name, dbType, db.ExpandInstanceConfig(req.Config, profiles))
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
 		// Run instance placement scriptlet if enabled.
 		if s.GlobalConfig.InstancesPlacementScriptlet() != "" {
 			leaderAddress, err := s.Cluster.LeaderAddress()
@@ -158,22 +158,31 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 			failuresLock := sync.Mutex{}
 			wgAction := sync.WaitGroup{}
 
-			for _, inst := range instances {
-				wgAction.Add(1)
-				go func(inst instance.Instance) {
-					defer wgAction.Done()
+			// Start dependencies first and stop them last.
+			batches := [][]instance.Instance{instances}
+			if action == internalInstance.Start || action == internalInstance.Stop {
+				batches = instanceDependencyBatches(instances, action == internalInstance.Stop)
+			}
 
-					inst.SetOperation(op)
-					err := doInstanceStatePut(inst, *req.State)
-					if err != nil {
-						failuresLock.Lock()
-						failures[inst.Name()] = err
-						failuresLock.Unlock()
-					}
-				}(inst)
+			for _, batch := range batches {
+				for _, inst := range batch {
+					wgAction.Add(1)
+					go func(inst instance.Instance) {
+						defer wgAction.Done()
+
+						inst.SetOperation(op)
+						err := doInstanceStatePut(s, inst, *req.State)
+						if err != nil {
+							failuresLock.Lock()
+							failures[inst.Name()] = err
+							failuresLock.Unlock()
+						}
+					}(inst)
+				}
+
+				wgAction.Wait()
 			}
 
-			wgAction.Wait()
 			return coalesceErrors(local, failures)
 		}
 
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/metrics"
+)
+
+// Test that the idle counters ignore idle CPU time and loopback traffic.
+func TestInstanceIdleCounters(t *testing.T) {
+	metricSet := metrics.NewMetricSet(nil)
+	metricSet.AddSamples(metrics.CPUSecondsTotal,
+		metrics.Sample{Labels: map[string]string{"cpu": "0", "mode": "user"}, Value: 10},
+		metrics.Sample{Labels: map[string]string{"cpu": "0", "mode": "system"}, Value: 5},
+		metrics.Sample{Labels: map[string]string{"cpu": "0", "mode": "idle"}, Value: 1000},
+	)
+
+	metricSet.AddSamples(metrics.NetworkReceiveBytesTotal,
+		metrics.Sample{Labels: map[string]string{"device": "eth0"}, Value: 2048},
+		metrics.Sample{Labels: map[string]string{"device": "lo"}, Value: 1 << 20},
+	)
+
+	metricSet.AddSamples(metrics.NetworkTransmitBytesTotal,
+		metrics.Sample{Labels: map[string]string{"device": "eth0"}, Value: 1024},
+		metrics.Sample{Labels: map[string]string{"device": "lo"}, Value: 1 << 20},
+	)
+
+	cpuSeconds, networkBytes := instanceIdleCounters(metricSet)
+	assert.Equal(t, float64(15), cpuSeconds)
+	assert.Equal(t, float64(3072), networkBytes)
+}
+
+// Test that the idle check compares the usage rate between two samples against the configured thresholds.
+func TestInstanceIsIdle(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := &instanceIdleState{sampled: start, cpuSeconds: 100, networkBytes: 10000}
+
+	tests := []struct {
+		name         string
+		config       map[string]string
+		elapsed      time.Duration
+		cpuSeconds   float64
+		networkBytes float64
+		expected     bool
+	}{
+		{
+			name:         "No activity",
+			elapsed:      time.Minute,
+			cpuSeconds:   100,
+			networkBytes: 10000,
+			expected:     true,
+		},
+		{
+			name:         "CPU below default threshold",
+			elapsed:      time.Minute,
+			cpuSeconds:   102, // ~3.3%
+			networkBytes: 10000,
+			expected:     true,
+		},
+		{
+			name:         "CPU above default threshold",
+			elapsed:      time.Minute,
+			cpuSeconds:   104, // ~6.7%
+			networkBytes: 10000,
+			expected:     false,
+		},
+		{
+			name:         "CPU below custom threshold",
+			config:       map[string]string{"boot.idle_stop.cpu": "10"},
+			elapsed:      time.Minute,
+			cpuSeconds:   104,
+			networkBytes: 10000,
+			expected:     true,
+		},
+		{
+			name:         "Network above default threshold",
+			elapsed:      time.Minute,
+			cpuSeconds:   100,
+			networkBytes: 10000 + 60*2048,
+			expected:     false,
+		},
+		{
+			name:         "Network below custom threshold",
+			config:       map[string]string{"boot.idle_stop.network": "4KiB"},
+			elapsed:      time.Minute,
+			cpuSeconds:   100,
+			networkBytes: 10000 + 60*2048,
+			expected:     true,
+		},
+		{
+			name:         "No time elapsed",
+			elapsed:      0,
+			cpuSeconds:   100,
+			networkBytes: 10000,
+			expected:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := &instanceIdleState{
+				sampled:      start.Add(tt.elapsed),
+				cpuSeconds:   tt.cpuSeconds,
+				networkBytes: tt.networkBytes,
+			}
+
+			assert.Equal(t, tt.expected, instanceIsIdle(tt.config, previous, current))
+		})
+	}
+}
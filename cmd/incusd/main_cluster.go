@@ -21,11 +21,13 @@ import (
 	"github.com/lxc/incus/v6/internal/ports"
 	"github.com/lxc/incus/v6/internal/server/cluster"
 	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/node"
 	"github.com/lxc/incus/v6/internal/server/sys"
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/shared/revert"
 	"github.com/lxc/incus/v6/shared/termios"
+	"github.com/lxc/incus/v6/shared/util"
 )
 
 type cmdAdmin struct {
@@ -72,6 +74,10 @@ func (c *cmdCluster) Command() *cobra.Command {
 	removeRaftNode := cmdClusterRemoveRaftNode{global: c.global}
 	cmd.AddCommand(removeRaftNode.Command())
 
+	// Restore the global database.
+	restoreDatabase := cmdClusterRestoreDatabase{global: c.global}
+	cmd.AddCommand(restoreDatabase.Command())
+
 	// Edit cluster configuration.
 	clusterEdit := cmdClusterEdit{global: c.global}
 	cmd.AddCommand(clusterEdit.Command())
@@ -533,6 +539,94 @@ Do you want to proceed? (yes/no): `)
 	return nil
 }
 
+type cmdClusterRestoreDatabase struct {
+	global             *cmdGlobal
+	flagNonInteractive bool
+}
+
+func (c *cmdClusterRestoreDatabase) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "restore-database <backup>"
+	cmd.Short = "Restore the global database from a backup"
+	cmd.Long = `Description:
+  Restore the global database from a backup
+
+  The content of the backup replaces the one of the global database the next time
+  the daemon starts. The backup must come from a server running the same version.
+`
+
+	cmd.RunE = c.Run
+
+	cmd.Flags().BoolVarP(&c.flagNonInteractive, "quiet", "q", false, "Don't require user confirmation")
+
+	return cmd
+}
+
+func (c *cmdClusterRestoreDatabase) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		_ = cmd.Help()
+		return fmt.Errorf("Missing required arguments")
+	}
+
+	// Make sure that the daemon is not running.
+	_, err := incus.ConnectIncusUnix("", nil)
+	if err == nil {
+		return fmt.Errorf("The daemon is running, please stop it first.")
+	}
+
+	backup, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("Failed to read database backup: %w", err)
+	}
+
+	patch, err := dbCluster.RestorePatch(string(backup))
+	if err != nil {
+		return err
+	}
+
+	patchPath := filepath.Join(sys.DefaultOS().VarDir, "database", "patch.global.sql")
+	if util.PathExists(patchPath) {
+		return fmt.Errorf("A global database patch is already pending in %q", patchPath)
+	}
+
+	// Prompt for confirmation unless --quiet was passed.
+	if !c.flagNonInteractive {
+		err := c.promptConfirmation()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.WriteFile(patchPath, []byte(patch), 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed to write database patch: %w", err)
+	}
+
+	fmt.Println("The global database will be restored when the daemon starts.")
+
+	return nil
+}
+
+func (c *cmdClusterRestoreDatabase) promptConfirmation() error {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(`The whole content of the global database (instances, networks, storage,
+projects, configuration, ...) will be replaced with the one from the backup.
+Anything changed since the backup was taken will be lost.
+
+In a cluster, this should be done on a single member with all members stopped.
+The database is restored once that member starts again.
+
+Do you want to proceed? (yes/no): `)
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSuffix(input, "\n")
+
+	if !slices.Contains([]string{"yes"}, strings.ToLower(input)) {
+		return fmt.Errorf("Restore operation aborted")
+	}
+
+	return nil
+}
+
 // Spawn the editor with a temporary YAML file for editing configs.
 func textEditor(inPath string, inContent []byte) ([]byte, error) {
 	var f *os.File
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// validateMaintenanceWindows validates a list of maintenance windows.
+func validateMaintenanceWindows(value string) error {
+	_, err := task.ParseWindows(value)
+	return err
+}
+
+// clusterMemberMaintenanceWindows returns the maintenance windows of a cluster member.
+//
+// The windows set on the member take precedence, otherwise the ones of all its cluster groups are combined.
+func clusterMemberMaintenanceWindows(ctx context.Context, tx *db.ClusterTx, member db.NodeInfo) (task.Windows, error) {
+	value := member.Config["maintenance.windows"]
+	if value != "" {
+		return task.ParseWindows(value)
+	}
+
+	windows := task.Windows{}
+	for _, groupName := range member.Groups {
+		group, err := dbCluster.GetClusterGroup(ctx, tx.Tx(), groupName)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting cluster group %q: %w", groupName, err)
+		}
+
+		config, err := dbCluster.GetClusterGroupConfig(ctx, tx.Tx(), group.ID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed getting cluster group %q configuration: %w", groupName, err)
+		}
+
+		if config["maintenance.windows"] == "" {
+			continue
+		}
+
+		groupWindows, err := task.ParseWindows(config["maintenance.windows"])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid maintenance windows of cluster group %q: %w", groupName, err)
+		}
+
+		windows = append(windows, groupWindows...)
+	}
+
+	return windows, nil
+}
+
+// localMaintenanceWindows returns the maintenance windows of the local cluster member.
+//
+// Failures are logged and result in no windows, so that scheduled work isn't held back indefinitely.
+func localMaintenanceWindows(s *state.State) task.Windows {
+	if !s.ServerClustered {
+		return nil
+	}
+
+	var windows task.Windows
+
+	err := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		member, err := tx.GetNodeByName(ctx, s.ServerName)
+		if err != nil {
+			return err
+		}
+
+		windows, err = clusterMemberMaintenanceWindows(ctx, tx, member)
+		return err
+	})
+	if err != nil {
+		logger.Warn("Failed getting maintenance windows", logger.Ctx{"err": err})
+		return nil
+	}
+
+	return windows
+}
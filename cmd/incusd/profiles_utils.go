@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"maps"
 
 	internalInstance "github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/server/db"
@@ -13,6 +14,8 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/state"
 	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/osarch"
 )
 
 func doProfileUpdate(ctx context.Context, s *state.State, p api.Project, profileName string, id int64, profile *api.Profile, req api.ProfilePut) error {
@@ -42,6 +45,12 @@ func doProfileUpdate(ctx context.Context, s *state.State, p api.Project, profile
 		return fmt.Errorf("Failed to query instances associated with profile %q: %w", profileName, err)
 	}
 
+	// Check that the instances using the profile don't end up depending on each other in a cycle.
+	err = instanceDependencyCheckProfile(ctx, s, profileName, req, insts)
+	if err != nil {
+		return err
+	}
+
 	// Check if the root disk device's pool would be changed or removed and prevent that if there are instances
 	// using that root disk device.
 	oldProfileRootDiskDeviceKey, oldProfileRootDiskDevice, _ := internalInstance.GetRootDiskDevice(profile.Devices)
@@ -85,6 +94,12 @@ func doProfileUpdate(ctx context.Context, s *state.State, p api.Project, profile
 		}
 	}
 
+	// Run the instance admission scriptlet against the instances using the profile.
+	err = doProfileUpdateAdmission(ctx, s, profileName, req, insts)
+	if err != nil {
+		return err
+	}
+
 	// Update the database.
 	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		devices, err := cluster.APIToDevices(req.Devices)
@@ -158,6 +173,55 @@ func doProfileUpdate(ctx context.Context, s *state.State, p api.Project, profile
 	return nil
 }
 
+// doProfileUpdateAdmission runs the instance admission scriptlet against every instance using the profile, expanded
+// with the new profile configuration. The scriptlet can reject the change but not alter the instances, as their own
+// configuration isn't part of the request.
+func doProfileUpdateAdmission(ctx context.Context, s *state.State, profileName string, req api.ProfilePut, insts map[int]db.InstanceArgs) error {
+	if s.GlobalConfig.InstancesAdmissionScriptlet() == "" {
+		return nil
+	}
+
+	for _, inst := range insts {
+		profileNames := make([]string, 0, len(inst.Profiles))
+		profiles := make([]api.Profile, 0, len(inst.Profiles))
+		for _, profile := range inst.Profiles {
+			if profile.Name == profileName {
+				profile.Config = req.Config
+				profile.Devices = req.Devices
+			}
+
+			profileNames = append(profileNames, profile.Name)
+			profiles = append(profiles, profile)
+		}
+
+		architecture, err := osarch.ArchitectureName(inst.Architecture)
+		if err != nil {
+			return err
+		}
+
+		instPut := api.InstancePut{
+			Architecture: architecture,
+			Config:       inst.Config,
+			Devices:      inst.Devices.CloneNative(),
+			Ephemeral:    inst.Ephemeral,
+			Profiles:     profileNames,
+			Description:  inst.Description,
+		}
+
+		err = instanceAdmission(ctx, s, apiScriptlet.InstanceAdmissionReasonUpdate, inst.Project, inst.Name, inst.Type.String(), &instPut, profiles)
+		if err != nil {
+			return fmt.Errorf("Instance %q in project %q: %w", inst.Name, inst.Project, err)
+		}
+
+		removed, added, _, _ := inst.Devices.Update(deviceConfig.NewDevices(instPut.Devices), nil)
+		if !maps.Equal(inst.Config, instPut.Config) || len(removed) > 0 || len(added) > 0 {
+			return fmt.Errorf("Instance %q in project %q would be changed by the instance admission scriptlet", inst.Name, inst.Project)
+		}
+	}
+
+	return nil
+}
+
 // Like doProfileUpdate but does not update the database, since it was already
 // updated by doProfileUpdate itself, called on the notifying node.
 func doProfileUpdateCluster(ctx context.Context, s *state.State, projectName string, profileName string, old api.ProfilePut) error {
@@ -0,0 +1,951 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/locking"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/stack"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+var projectTemplatesCmd = APIEndpoint{
+	Path: "project-templates",
+
+	Get:  APIEndpointAction{Handler: projectTemplatesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: projectTemplatesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var projectTemplateCmd = APIEndpoint{
+	Path: "project-templates/{name}",
+
+	Delete: APIEndpointAction{Handler: projectTemplateDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: projectTemplateGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Put:    APIEndpointAction{Handler: projectTemplatePut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var projectTemplateApplyCmd = APIEndpoint{
+	Path: "project-templates/{name}/apply",
+
+	Post: APIEndpointAction{Handler: projectTemplateApplyPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// projectTemplateLock prevents concurrent applications of templates to the same project.
+func projectTemplateLock(ctx context.Context, projectName string) (locking.UnlockFunc, error) {
+	return locking.Lock(ctx, fmt.Sprintf("ProjectTemplateApply_%s", projectName))
+}
+
+// projectTemplateValidateName checks that a project template name can be used in URLs.
+func projectTemplateValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.Contains(name, "/") {
+		return errors.New("Project template names may not contain slashes")
+	}
+
+	if strings.Contains(name, " ") {
+		return errors.New("Project template names may not contain spaces")
+	}
+
+	return nil
+}
+
+// projectTemplateStack returns the resources of a project template as a stack definition.
+func projectTemplateStack(spec api.ProjectTemplatePut) api.StackPut {
+	return api.StackPut{
+		Profiles:       spec.Profiles,
+		NetworkACLs:    spec.NetworkACLs,
+		Networks:       spec.Networks,
+		StorageVolumes: spec.StorageVolumes,
+	}
+}
+
+// projectTemplateValidate checks that a project template definition is consistent.
+func projectTemplateValidate(s *state.State, spec api.ProjectTemplatePut) error {
+	if spec.Config == nil {
+		spec.Config = map[string]string{}
+	}
+
+	err := projectValidateConfig(s, spec.Config)
+	if err != nil {
+		return err
+	}
+
+	err = stack.Validate(projectTemplateStack(spec))
+	if err != nil {
+		return err
+	}
+
+	for _, fingerprint := range spec.Certificates {
+		if fingerprint == "" {
+			return errors.New("Certificates require a fingerprint")
+		}
+	}
+
+	for _, group := range spec.AuthGroups {
+		if group.Group == "" || group.Role == "" {
+			return errors.New("Authorization groups require a group and a role")
+		}
+	}
+
+	return nil
+}
+
+// projectTemplateFeatures checks that the project has its own set of the resources created by the template,
+// so that they don't end up in the default project.
+func projectTemplateFeatures(config map[string]string, spec api.ProjectTemplatePut) error {
+	if len(spec.Profiles) > 0 && util.IsFalseOrEmpty(config["features.profiles"]) {
+		return errors.New("Profiles require the project to have features.profiles enabled")
+	}
+
+	if (len(spec.NetworkACLs) > 0 || len(spec.Networks) > 0) && util.IsFalseOrEmpty(config["features.networks"]) {
+		return errors.New("Networks and network ACLs require the project to have features.networks enabled")
+	}
+
+	if len(spec.StorageVolumes) > 0 && util.IsFalseOrEmpty(config["features.storage.volumes"]) {
+		return errors.New("Storage volumes require the project to have features.storage.volumes enabled")
+	}
+
+	return nil
+}
+
+// projectTemplateApply brings a project to the desired template definition, using the previously applied one
+// to tell which configuration keys, resources and bindings come from the template. The previous definition is
+// nil for a project which was just created. The changes are made on behalf of the requestor of r. On failure, all
+// changes made so far are reverted. On success, a function which reverts the changes is returned for use if a later
+// step fails.
+func projectTemplateApply(s *state.State, r *http.Request, projectName string, previous *api.ProjectTemplatePut, desired api.ProjectTemplatePut) (revert.Hook, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	if previous == nil {
+		previous = &api.ProjectTemplatePut{}
+	}
+
+	client, err := localProjectClient(s, r, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the project configuration, keeping the keys which don't come from the template.
+	project, etag, err := client.GetProject(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	config := stack.MergeMap(project.Config, previous.Config, desired.Config)
+	if !maps.Equal(config, project.Config) {
+		put := project.Writable()
+		put.Config = config
+
+		err = client.UpdateProject(projectName, put, etag)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to update the project configuration: %w", err)
+		}
+
+		reverter.Add(func() { _ = client.UpdateProject(projectName, project.Writable(), "") })
+	}
+
+	err = projectTemplateFeatures(config, desired)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "%v", err)
+	}
+
+	// The default profile always exists. It's updated while part of the template and left as is otherwise.
+	isDefault := func(profile api.ProfilesPost) bool { return profile.Name == api.ProjectDefaultName }
+
+	current := projectTemplateStack(*previous)
+	current.Profiles = slices.DeleteFunc(slices.Clone(current.Profiles), isDefault)
+	if slices.ContainsFunc(desired.Profiles, isDefault) {
+		i := slices.IndexFunc(previous.Profiles, isDefault)
+		if i >= 0 {
+			current.Profiles = append(current.Profiles, previous.Profiles[i])
+		} else {
+			current.Profiles = append(current.Profiles, api.ProfilesPost{Name: api.ProjectDefaultName})
+		}
+	}
+
+	// The resources are created on behalf of the requestor, so they're authorized by the regular API endpoints.
+	cleanup, err := stack.Apply(client, &current, projectTemplateStack(desired), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(cleanup)
+
+	// Give the certificates access to the project.
+	for _, fingerprint := range desired.Certificates {
+		undo, err := projectTemplateBindCertificate(client, fingerprint, projectName, true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to give certificate %q access to the project: %w", fingerprint, err)
+		}
+
+		reverter.Add(undo)
+	}
+
+	for _, fingerprint := range previous.Certificates {
+		if slices.Contains(desired.Certificates, fingerprint) {
+			continue
+		}
+
+		undo, err := projectTemplateBindCertificate(client, fingerprint, projectName, false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove access to the project from certificate %q: %w", fingerprint, err)
+		}
+
+		reverter.Add(undo)
+	}
+
+	// Grant the roles to the groups.
+	for _, group := range desired.AuthGroups {
+		undo, err := projectTemplateBindGroup(client, group, projectName, true)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to grant role %q to group %q: %w", group.Role, group.Group, err)
+		}
+
+		reverter.Add(undo)
+	}
+
+	for _, group := range previous.AuthGroups {
+		if slices.Contains(desired.AuthGroups, group) {
+			continue
+		}
+
+		undo, err := projectTemplateBindGroup(client, group, projectName, false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to revoke role %q from group %q: %w", group.Role, group.Group, err)
+		}
+
+		reverter.Add(undo)
+	}
+
+	cleanupAll := reverter.Clone().Fail
+	reverter.Success()
+
+	return cleanupAll, nil
+}
+
+// projectTemplateBindCertificate adds or removes the project from those a certificate has access to and
+// returns a function restoring its previous list of projects.
+func projectTemplateBindCertificate(client incus.InstanceServer, fingerprint string, projectName string, add bool) (revert.Hook, error) {
+	cert, etag, err := client.GetCertificate(fingerprint)
+	if err != nil {
+		// Certificates removed since don't need to be updated.
+		if !add && api.StatusErrorCheck(err, http.StatusNotFound) {
+			return func() {}, nil
+		}
+
+		return nil, err
+	}
+
+	put := cert.Writable()
+	if add == slices.Contains(put.Projects, projectName) {
+		return func() {}, nil
+	}
+
+	if add {
+		put.Projects = append(slices.Clone(put.Projects), projectName)
+	} else {
+		put.Projects = slices.DeleteFunc(slices.Clone(put.Projects), func(name string) bool { return name == projectName })
+	}
+
+	err = client.UpdateCertificate(cert.Fingerprint, put, etag)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { _ = client.UpdateCertificate(cert.Fingerprint, cert.Writable(), "") }, nil
+}
+
+// projectTemplateBindGroup grants or revokes a role on the project to a group and returns a function
+// restoring its previous roles.
+func projectTemplateBindGroup(client incus.InstanceServer, binding api.ProjectTemplateAuthGroup, projectName string, add bool) (revert.Hook, error) {
+	group, etag, err := client.GetAuthGroup(binding.Group)
+	if err != nil {
+		// Groups removed since don't need to be updated.
+		if !add && api.StatusErrorCheck(err, http.StatusNotFound) {
+			return func() {}, nil
+		}
+
+		return nil, err
+	}
+
+	role := api.AuthGroupRole{Role: binding.Role, Project: projectName}
+
+	put := group.Writable()
+	if add == slices.Contains(put.Roles, role) {
+		return func() {}, nil
+	}
+
+	if add {
+		put.Roles = append(slices.Clone(put.Roles), role)
+	} else {
+		put.Roles = slices.DeleteFunc(slices.Clone(put.Roles), func(r api.AuthGroupRole) bool { return r == role })
+	}
+
+	err = client.UpdateAuthGroup(group.Name, put, etag)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() { _ = client.UpdateAuthGroup(group.Name, group.Writable(), "") }, nil
+}
+
+// projectTemplateLoad returns the current definition of a project template.
+func projectTemplateLoad(ctx context.Context, s *state.State, name string) (*api.ProjectTemplate, int, error) {
+	var info *api.ProjectTemplate
+	var id int
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTemplate, err := dbCluster.GetProjectTemplate(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		id = dbTemplate.ID
+		info, err = dbTemplate.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return nil, -1, err
+	}
+
+	return info, id, nil
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/project-templates projects project_templates_get
+//
+//	Get the project templates
+//
+//	Returns a list of project templates (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/project-templates/tenant",
+//	              "/1.0/project-templates/sandbox"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/project-templates?recursion=1 projects project_templates_get_recursion1
+//
+//	Get the project templates
+//
+//	Returns a list of project templates (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of project templates
+//	          items:
+//	            $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var templates []api.ProjectTemplate
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTemplates, err := dbCluster.GetProjectTemplates(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		templates = make([]api.ProjectTemplate, 0, len(dbTemplates))
+		for _, dbTemplate := range dbTemplates {
+			if !recursion {
+				templates = append(templates, api.ProjectTemplate{Name: dbTemplate.Name})
+				continue
+			}
+
+			info, err := dbTemplate.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			templates = append(templates, *info)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(templates))
+		for _, template := range templates {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "project-templates", template.Name).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	return response.SyncResponse(true, templates)
+}
+
+// swagger:operation POST /1.0/project-templates projects project_templates_post
+//
+//	Add a project template
+//
+//	Creates a new project template.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ProjectTemplatesPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidate(s, req.ProjectTemplatePut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	spec, err := json.Marshal(req.ProjectTemplatePut)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		exists, err := dbCluster.ProjectTemplateExists(ctx, tx.Tx(), req.Name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "The project template already exists")
+		}
+
+		_, err = dbCluster.CreateProjectTemplate(ctx, tx.Tx(), dbCluster.ProjectTemplate{
+			Name:        req.Name,
+			Description: req.Description,
+			Spec:        string(spec),
+		})
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	lc := lifecycle.ProjectTemplateCreated.Event(req.Name, request.CreateRequestor(r), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/project-templates/{name} projects project_template_get
+//
+//	Get the project template
+//
+//	Gets a specific project template.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Project template
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ProjectTemplate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, _, err := projectTemplateLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, info, info.Writable())
+}
+
+// swagger:operation PUT /1.0/project-templates/{name} projects project_template_put
+//
+//	Update the project template
+//
+//	Updates the definition of the project template.
+//	Projects created from the template are only updated once it's applied again.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: template
+//	    description: Project template definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplatePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplatePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, _, err := projectTemplateLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, info.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.ProjectTemplatePut{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = projectTemplateValidate(s, req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	spec, err := json.Marshal(req)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.UpdateProjectTemplate(ctx, tx.Tx(), name, dbCluster.ProjectTemplate{
+			Name:        name,
+			Description: req.Description,
+			Spec:        string(spec),
+		})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateUpdated.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/project-templates/{name} projects project_template_delete
+//
+//	Delete the project template
+//
+//	Removes the project template. Templates which projects were created from can't be removed.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTemplate, err := dbCluster.GetProjectTemplate(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		projects, err := dbCluster.GetProjectTemplateProjects(ctx, tx.Tx(), dbTemplate.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(projects) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "The project template is currently in use")
+		}
+
+		return dbCluster.DeleteProjectTemplate(ctx, tx.Tx(), name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateDeleted.Event(name, request.CreateRequestor(r), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation POST /1.0/project-templates/{name}/apply projects project_template_apply_post
+//
+//	Apply the project template
+//
+//	Brings projects created from the template to its current definition.
+//	Configuration keys, resources and bindings removed from the template are removed from the projects.
+//	Projects which can't be updated are left unchanged while the others are still updated.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: apply
+//	    description: Projects to update
+//	    required: false
+//	    schema:
+//	      $ref: "#/definitions/ProjectTemplateApplyPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func projectTemplateApplyPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ProjectTemplateApplyPost{}
+
+	// Parse the request if provided, all the projects are updated otherwise.
+	if r.ContentLength > 0 {
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+	}
+
+	var projects []string
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTemplate, err := dbCluster.GetProjectTemplate(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		projects, err = dbCluster.GetProjectTemplateProjects(ctx, tx.Tx(), dbTemplate.ID)
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(req.Projects) > 0 {
+		for _, projectName := range req.Projects {
+			if !slices.Contains(projects, projectName) {
+				return response.BadRequest(fmt.Errorf("Project %q wasn't created from the template", projectName))
+			}
+		}
+
+		projects = req.Projects
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	run := func(op *operations.Operation) error {
+		updated := []string{}
+		failures := []string{}
+
+		for _, projectName := range projects {
+			err := projectTemplateReapply(s, r, name, projectName)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", projectName, err))
+				continue
+			}
+
+			updated = append(updated, projectName)
+		}
+
+		if len(updated) > 0 {
+			s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.ProjectTemplateApplied.Event(name, requestor, map[string]any{"projects": updated}))
+		}
+
+		if len(failures) > 0 {
+			return fmt.Errorf("Failed to update projects: %s", strings.Join(failures, "; "))
+		}
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, "", operations.OperationClassTask, operationtype.ProjectTemplateApply, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// projectTemplateCreate applies a template to a newly created project and records it.
+// The project is removed again on failure.
+func projectTemplateCreate(s *state.State, r *http.Request, projectName string, projectID int64, templateID int, spec api.ProjectTemplatePut) error {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// The project was just created by the server on behalf of the requestor, which may not be allowed to remove it.
+	client, err := localProjectClient(s, nil, projectName)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(func() { _ = client.DeleteProject(projectName) })
+
+	unlock, err := projectTemplateLock(context.Background(), projectName)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	cleanup, err := projectTemplateApply(s, r, projectName, nil, spec)
+	if err != nil {
+		return err
+	}
+
+	reverter.Add(cleanup)
+
+	err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.SetProjectTemplateApplied(ctx, tx.Tx(), templateID, projectID, spec)
+	})
+	if err != nil {
+		return err
+	}
+
+	reverter.Success()
+
+	return nil
+}
+
+// projectTemplateReapply brings a project created from a template to the current definition of the template.
+func projectTemplateReapply(s *state.State, r *http.Request, name string, projectName string) error {
+	unlock, err := projectTemplateLock(context.Background(), projectName)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	// Load the definitions now that the project is locked.
+	var templateID int
+	var desired api.ProjectTemplatePut
+	var previous *api.ProjectTemplatePut
+	var projectID int64
+
+	err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbTemplate, err := dbCluster.GetProjectTemplate(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		templateID = dbTemplate.ID
+
+		err = json.Unmarshal([]byte(dbTemplate.Spec), &desired)
+		if err != nil {
+			return err
+		}
+
+		desired.Description = dbTemplate.Description
+
+		templateName, applied, err := dbCluster.GetProjectTemplateApplied(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		if templateName != name {
+			return fmt.Errorf("The project wasn't created from the template")
+		}
+
+		previous = applied
+
+		projectID, err = dbCluster.GetProjectID(ctx, tx.Tx(), projectName)
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	cleanup, err := projectTemplateApply(s, r, projectName, previous, desired)
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.SetProjectTemplateApplied(ctx, tx.Tx(), templateID, projectID, desired)
+	})
+	if err != nil {
+		cleanup()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance"
+	instanceDrivers "github.com/lxc/incus/v6/internal/server/instance/drivers"
+	"github.com/lxc/incus/v6/internal/server/metrics"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/server/task"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// projectUsageInterval is how often the resources consumed by the local instances are recorded.
+const projectUsageInterval = 5 * time.Minute
+
+// instanceUsage holds the resources consumed by an instance as reported by its metrics.
+// The counters are cumulative since the instance started while the gauges are the current values.
+type instanceUsage struct {
+	// Counters.
+	cpuSeconds              float64
+	diskReadBytes           float64
+	diskWrittenBytes        float64
+	networkReceivedBytes    float64
+	networkTransmittedBytes float64
+
+	// Gauges.
+	memoryBytes float64
+	diskBytes   float64
+}
+
+var (
+	// projectUsageLast holds the usage of the local instances at the time of the last sample, by instance ID.
+	projectUsageLast     map[int]instanceUsage
+	projectUsageLastDate time.Time
+	projectUsageLock     sync.Mutex
+)
+
+// instanceUsageFromMetrics extracts the resources consumed by an instance from its metrics.
+func instanceUsageFromMetrics(m *metrics.MetricSet) instanceUsage {
+	usage := instanceUsage{}
+
+	for _, sample := range m.GetSamples(metrics.CPUSecondsTotal) {
+		if sample.Labels["mode"] == "idle" || sample.Labels["mode"] == "iowait" {
+			continue
+		}
+
+		usage.cpuSeconds += sample.Value
+	}
+
+	for _, sample := range m.GetSamples(metrics.DiskReadBytesTotal) {
+		usage.diskReadBytes += sample.Value
+	}
+
+	for _, sample := range m.GetSamples(metrics.DiskWrittenBytesTotal) {
+		usage.diskWrittenBytes += sample.Value
+	}
+
+	for _, sample := range m.GetSamples(metrics.NetworkReceiveBytesTotal) {
+		if sample.Labels["device"] == "lo" {
+			continue
+		}
+
+		usage.networkReceivedBytes += sample.Value
+	}
+
+	for _, sample := range m.GetSamples(metrics.NetworkTransmitBytesTotal) {
+		if sample.Labels["device"] == "lo" {
+			continue
+		}
+
+		usage.networkTransmittedBytes += sample.Value
+	}
+
+	for _, sample := range m.GetSamples(metrics.MemoryMemTotalBytes) {
+		usage.memoryBytes += sample.Value
+	}
+
+	for _, sample := range m.GetSamples(metrics.MemoryMemAvailableBytes) {
+		usage.memoryBytes -= sample.Value
+	}
+
+	// Only account for the root disk.
+	for _, sample := range m.GetSamples(metrics.FilesystemSizeBytes) {
+		if sample.Labels["mountpoint"] == "/" {
+			usage.diskBytes += sample.Value
+		}
+	}
+
+	for _, sample := range m.GetSamples(metrics.FilesystemAvailBytes) {
+		if sample.Labels["mountpoint"] == "/" {
+			usage.diskBytes -= sample.Value
+		}
+	}
+
+	return usage
+}
+
+// instanceStartedAt returns when a running instance was started, based on its init process.
+func instanceStartedAt(inst instance.Instance) (time.Time, error) {
+	pid := inst.InitPID()
+	if pid < 1 {
+		return time.Time{}, fmt.Errorf("Instance %q isn't running", inst.Name())
+	}
+
+	file, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	linuxInfo, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, errors.New("Bad stat type")
+	}
+
+	return time.Unix(int64(linuxInfo.Ctim.Sec), int64(linuxInfo.Ctim.Nsec)), nil
+}
+
+// counterDelta returns the increase of a cumulative counter since its previous value.
+// A lower value means the instance restarted, so the whole value was consumed since.
+func counterDelta(previous float64, current float64) float64 {
+	if current < previous {
+		return current
+	}
+
+	return current - previous
+}
+
+func projectUsageTask(d *Daemon) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := d.State()
+
+		err := projectUsageRecord(ctx, s)
+		if err != nil {
+			logger.Warn("Failed recording project usage", logger.Ctx{"err": err})
+		}
+
+		err = projectUsagePrune(ctx, s)
+		if err != nil {
+			logger.Warn("Failed pruning project usage", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(projectUsageInterval)
+}
+
+// projectUsageRecord samples the metrics of the instances running on the local member
+// and records the resources consumed by each project since the last sample.
+func projectUsageRecord(ctx context.Context, s *state.State) error {
+	projectUsageLock.Lock()
+	defer projectUsageLock.Unlock()
+
+	var instances []instance.Instance
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q in project %q: %w", dbInst.Name, dbInst.Project, err)
+			}
+
+			instances = append(instances, inst)
+
+			return nil
+		}, dbCluster.InstanceFilter{Node: &s.ServerName})
+	})
+	if err != nil {
+		return err
+	}
+
+	// Gather the metrics of the running instances.
+	hostInterfaces, _ := net.Interfaces()
+
+	current := make(map[int]instanceUsage, len(instances))
+	currentLock := sync.Mutex{}
+
+	var wg sync.WaitGroup
+	instCh := make(chan instance.Instance)
+	for i := 0; i < min(runtime.NumCPU(), len(instances)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for inst := range instCh {
+				instanceMetrics, err := inst.Metrics(hostInterfaces)
+				if err != nil {
+					// Ignore stopped instances.
+					if !errors.Is(err, instanceDrivers.ErrInstanceIsStopped) {
+						logger.Warn("Failed getting instance metrics", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+					}
+
+					continue
+				}
+
+				currentLock.Lock()
+				current[inst.ID()] = instanceUsageFromMetrics(instanceMetrics)
+				currentLock.Unlock()
+			}
+		}()
+	}
+
+	for _, inst := range instances {
+		instCh <- inst
+	}
+
+	close(instCh)
+	wg.Wait()
+
+	now := time.Now()
+	previous := projectUsageLast
+	previousDate := projectUsageLastDate
+
+	projectUsageLast = current
+	projectUsageLastDate = now
+
+	// The first sample since the daemon started only sets the baseline.
+	if previous == nil {
+		return nil
+	}
+
+	// Sum the consumption of the instances of each project since the last sample.
+	usages := map[string]*dbCluster.ProjectUsage{}
+	for _, inst := range instances {
+		usage, ok := current[inst.ID()]
+		if !ok {
+			continue
+		}
+
+		// Without a baseline, the counters only cover the interval if the instance was started since the last
+		// sample. Otherwise, such as for instances moved from another member or whose metrics couldn't be
+		// gathered last time, the interval is skipped and the current sample only serves as the baseline.
+		last, ok := previous[inst.ID()]
+		if !ok {
+			startedAt, err := instanceStartedAt(inst)
+			if err != nil || startedAt.Before(previousDate) {
+				continue
+			}
+		}
+
+		projectName := inst.Project().Name
+		if usages[projectName] == nil {
+			usages[projectName] = &dbCluster.ProjectUsage{
+				Date:     now,
+				Duration: now.Sub(previousDate),
+			}
+		}
+
+		projectUsage := usages[projectName]
+		projectUsage.Instances++
+		projectUsage.CPUSeconds += counterDelta(last.cpuSeconds, usage.cpuSeconds)
+		projectUsage.DiskReadBytes += int64(counterDelta(last.diskReadBytes, usage.diskReadBytes))
+		projectUsage.DiskWrittenBytes += int64(counterDelta(last.diskWrittenBytes, usage.diskWrittenBytes))
+		projectUsage.NetworkReceivedBytes += int64(counterDelta(last.networkReceivedBytes, usage.networkReceivedBytes))
+		projectUsage.NetworkTransmittedBytes += int64(counterDelta(last.networkTransmittedBytes, usage.networkTransmittedBytes))
+		projectUsage.MemoryBytes += int64(usage.memoryBytes)
+		projectUsage.DiskBytes += int64(usage.diskBytes)
+	}
+
+	if len(usages) == 0 {
+		return nil
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for projectName, usage := range usages {
+			projectID, err := dbCluster.GetProjectID(ctx, tx.Tx(), projectName)
+			if err != nil {
+				return fmt.Errorf("Failed getting ID of project %q: %w", projectName, err)
+			}
+
+			usage.ProjectID = int(projectID)
+
+			err = dbCluster.CreateProjectUsage(ctx, tx.Tx(), *usage)
+			if err != nil {
+				return fmt.Errorf("Failed recording usage of project %q: %w", projectName, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// projectUsagePrune removes the project usage records older than the configured retention.
+func projectUsagePrune(ctx context.Context, s *state.State) error {
+	retention := s.GlobalConfig.UsageRetentionDays()
+	if retention == 0 {
+		return nil
+	}
+
+	// If we are clustered, let the leader handle the pruning.
+	if s.ServerClustered {
+		leader, err := s.Cluster.LeaderAddress()
+		if err != nil {
+			return err
+		}
+
+		if s.LocalConfig.ClusterAddress() != leader {
+			return nil
+		}
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteProjectUsageBefore(ctx, tx.Tx(), time.Now().AddDate(0, 0, -int(retention)))
+	})
+}
@@ -0,0 +1,640 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/secrets"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+	"github.com/lxc/incus/v6/shared/validate"
+)
+
+var replicationRemotesCmd = APIEndpoint{
+	Path: "replication-remotes",
+
+	Get:  APIEndpointAction{Handler: replicationRemotesGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: replicationRemotesPost, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+var replicationRemoteCmd = APIEndpoint{
+	Path: "replication-remotes/{name}",
+
+	Delete: APIEndpointAction{Handler: replicationRemoteDelete, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: replicationRemoteGet, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanView)},
+	Put:    APIEndpointAction{Handler: replicationRemotePut, AccessHandler: allowPermission(auth.ObjectTypeServer, auth.EntitlementCanEdit)},
+}
+
+// replicationRemoteValidate validates the modifiable fields of a replication remote.
+func replicationRemoteValidate(req api.ReplicationRemotePut) error {
+	err := validate.IsRequestURL(req.Address)
+	if err != nil {
+		return fmt.Errorf("Invalid address: %w", err)
+	}
+
+	if req.Certificate != "" {
+		certBlock, _ := pem.Decode([]byte(req.Certificate))
+		if certBlock == nil {
+			return fmt.Errorf("Invalid certificate")
+		}
+
+		_, err = x509.ParseCertificate(certBlock.Bytes)
+		if err != nil {
+			return fmt.Errorf("Invalid certificate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// replicationRemoteUsedBy returns the instances and profiles referencing each replication remote.
+func replicationRemoteUsedBy(ctx context.Context, tx *sql.Tx) (map[string][]string, error) {
+	usedBy := map[string][]string{}
+
+	record := func(config map[string]string, u *api.URL) {
+		name := config["replication.remote"]
+		if name != "" && !slices.Contains(usedBy[name], u.String()) {
+			usedBy[name] = append(usedBy[name], u.String())
+		}
+	}
+
+	instances, err := dbCluster.GetInstances(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceConfigs, err := dbCluster.GetAllInstanceConfigs(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, inst := range instances {
+		record(instanceConfigs[inst.ID], api.NewURL().Path(version.APIVersion, "instances", inst.Name).Project(inst.Project))
+	}
+
+	profiles, err := dbCluster.GetProfiles(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	profileConfigs, err := dbCluster.GetAllProfileConfigs(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		record(profileConfigs[profile.ID], api.NewURL().Path(version.APIVersion, "profiles", profile.Name).Project(profile.Project))
+	}
+
+	for name := range usedBy {
+		sort.Strings(usedBy[name])
+	}
+
+	return usedBy, nil
+}
+
+// replicationRemoteLoad returns a replication remote without its client key.
+func replicationRemoteLoad(ctx context.Context, s *state.State, name string) (*api.ReplicationRemote, error) {
+	var info *api.ReplicationRemote
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRemote, err := dbCluster.GetReplicationRemote(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		usedBy, err := replicationRemoteUsedBy(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		info = dbRemote.ToAPI()
+		info.UsedBy = usedBy[name]
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if info.UsedBy == nil {
+		info.UsedBy = []string{}
+	}
+
+	return info, nil
+}
+
+// replicationRemoteKeyEncrypt encrypts the client key of a replication remote with a key derived from the cluster certificate.
+// Replication remotes don't belong to a project so the encrypted key is only bound to the remote name.
+func replicationRemoteKeyEncrypt(cert *localtls.CertInfo, name string, clientKey string) (string, error) {
+	return secrets.Encrypt(cert, "", name, clientKey)
+}
+
+// replicationRemoteKeyDecrypt decrypts the client key of a replication remote.
+func replicationRemoteKeyDecrypt(cert *localtls.CertInfo, dbRemote *dbCluster.ReplicationRemote) (string, error) {
+	clientKey, err := secrets.Decrypt(cert, "", dbRemote.Name, dbRemote.ClientKey)
+	if err != nil {
+		return "", fmt.Errorf("Failed decrypting the client key of replication remote %q: %w", dbRemote.Name, err)
+	}
+
+	return clientKey, nil
+}
+
+// replicationRemotesReencrypt re-encrypts the client keys of all replication remotes after a change of the cluster certificate.
+func replicationRemotesReencrypt(ctx context.Context, tx *sql.Tx, oldCert *localtls.CertInfo, newCert *localtls.CertInfo) error {
+	dbRemotes, err := dbCluster.GetReplicationRemotes(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, dbRemote := range dbRemotes {
+		clientKey, err := replicationRemoteKeyDecrypt(oldCert, &dbRemote)
+		if err != nil {
+			return err
+		}
+
+		dbRemote.ClientKey, err = replicationRemoteKeyEncrypt(newCert, dbRemote.Name, clientKey)
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateReplicationRemote(ctx, tx, dbRemote.Name, dbRemote)
+		if err != nil {
+			return fmt.Errorf("Failed updating replication remote %q: %w", dbRemote.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// replicationRemoteConnect connects to a replication remote with its own client certificate.
+// The connection uses the remote's target project or, when it doesn't set one, projectName.
+func replicationRemoteConnect(ctx context.Context, s *state.State, name string, projectName string) (incus.InstanceServer, error) {
+	var dbRemote *dbCluster.ReplicationRemote
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbRemote, err = dbCluster.GetReplicationRemote(ctx, tx.Tx(), name)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading replication remote %q: %w", name, err)
+	}
+
+	clientKey, err := replicationRemoteKeyDecrypt(s.Endpoints.NetworkCert(), dbRemote)
+	if err != nil {
+		return nil, err
+	}
+
+	args := &incus.ConnectionArgs{
+		TLSServerCert: dbRemote.Certificate,
+		TLSClientCert: dbRemote.ClientCertificate,
+		TLSClientKey:  clientKey,
+		UserAgent:     version.UserAgent,
+		Proxy:         s.Proxy,
+	}
+
+	client, err := incus.ConnectIncus(dbRemote.Address, args)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to replication remote %q: %w", name, err)
+	}
+
+	if dbRemote.TargetProject != "" {
+		projectName = dbRemote.TargetProject
+	}
+
+	return client.UseProject(projectName), nil
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/replication-remotes replication-remotes replication_remotes_get
+//
+//	Get the replication remotes
+//
+//	Returns a list of replication remotes (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/replication-remotes/dr-site"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/replication-remotes?recursion=1 replication-remotes replication_remotes_get_recursion1
+//
+//	Get the replication remotes
+//
+//	Returns a list of replication remotes (structs). The client keys are never returned.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of replication remotes
+//	          items:
+//	            $ref: "#/definitions/ReplicationRemote"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicationRemotesGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var dbRemotes []dbCluster.ReplicationRemote
+	var usedBy map[string][]string
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbRemotes, err = dbCluster.GetReplicationRemotes(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if recursion {
+			usedBy, err = replicationRemoteUsedBy(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(dbRemotes))
+		for _, dbRemote := range dbRemotes {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "replication-remotes", dbRemote.Name).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	result := make([]api.ReplicationRemote, 0, len(dbRemotes))
+	for _, dbRemote := range dbRemotes {
+		info := dbRemote.ToAPI()
+
+		info.UsedBy = usedBy[dbRemote.Name]
+		if info.UsedBy == nil {
+			info.UsedBy = []string{}
+		}
+
+		result = append(result, *info)
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation POST /1.0/replication-remotes replication-remotes replication_remotes_post
+//
+//	Add a replication remote
+//
+//	Creates a new replication remote along with the client certificate used to authenticate to it.
+//	The client certificate must be trusted by the remote server before instances can be replicated to it.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: remote
+//	    description: Replication remote
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ReplicationRemotesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicationRemotesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.ReplicationRemotesPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validate.IsHostname(req.Name)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid replication remote name: %w", err))
+	}
+
+	err = replicationRemoteValidate(req.ReplicationRemotePut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Each remote gets its own credentials so that access can be granted and revoked on the remote server.
+	clientCert, clientKey, err := localtls.GenerateMemCert(true, false)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	// The client key is only stored encrypted and never returned through the API.
+	encryptedKey, err := replicationRemoteKeyEncrypt(s.Endpoints.NetworkCert(), req.Name, string(clientKey))
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		exists, err := dbCluster.ReplicationRemoteExists(ctx, tx.Tx(), req.Name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "The replication remote already exists")
+		}
+
+		_, err = dbCluster.CreateReplicationRemote(ctx, tx.Tx(), dbCluster.ReplicationRemote{
+			Name:              req.Name,
+			Description:       req.Description,
+			Address:           req.Address,
+			Certificate:       req.Certificate,
+			TargetProject:     req.TargetProject,
+			ClientCertificate: string(clientCert),
+			ClientKey:         encryptedKey,
+		})
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle("", lifecycle.ReplicationRemoteCreated.Event(req.Name, requestor, nil))
+
+	return response.SyncResponseLocation(true, nil, api.NewURL().Path(version.APIVersion, "replication-remotes", req.Name).String())
+}
+
+// swagger:operation GET /1.0/replication-remotes/{name} replication-remotes replication_remote_get
+//
+//	Get the replication remote
+//
+//	Gets a specific replication remote. Its client key is never returned.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Replication remote
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ReplicationRemote"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicationRemoteGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := replicationRemoteLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, info, info.Writable())
+}
+
+// swagger:operation PUT /1.0/replication-remotes/{name} replication-remotes replication_remote_put
+//
+//	Update the replication remote
+//
+//	Updates the address, certificate, target project and description of the replication remote.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: remote
+//	    description: Replication remote
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ReplicationRemotePut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicationRemotePut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := replicationRemoteLoad(r.Context(), s, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, info.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.ReplicationRemotePut{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = replicationRemoteValidate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbRemote, err := dbCluster.GetReplicationRemote(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		dbRemote.Description = req.Description
+		dbRemote.Address = req.Address
+		dbRemote.Certificate = req.Certificate
+		dbRemote.TargetProject = req.TargetProject
+
+		return dbCluster.UpdateReplicationRemote(ctx, tx.Tx(), name, *dbRemote)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle("", lifecycle.ReplicationRemoteUpdated.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/replication-remotes/{name} replication-remotes replication_remote_delete
+//
+//	Delete the replication remote
+//
+//	Removes the replication remote. Replication remotes referenced by instances or profiles can't be removed.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func replicationRemoteDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		usedBy, err := replicationRemoteUsedBy(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if len(usedBy[name]) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "The replication remote is currently in use")
+		}
+
+		return dbCluster.DeleteReplicationRemote(ctx, tx.Tx(), name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle("", lifecycle.ReplicationRemoteDeleted.Event(name, requestor, nil))
+
+	return response.EmptySyncResponse
+}
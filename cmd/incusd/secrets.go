@@ -0,0 +1,523 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/secrets"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var secretsCmd = APIEndpoint{
+	Path: "secrets",
+
+	Get:  APIEndpointAction{Handler: secretsGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: secretsPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+var secretCmd = APIEndpoint{
+	Path: "secrets/{name}",
+
+	Delete: APIEndpointAction{Handler: secretDelete, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: secretGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Put:    APIEndpointAction{Handler: secretPut, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+// secretLoad returns a secret without its value.
+func secretLoad(ctx context.Context, s *state.State, projectName string, name string) (*api.Secret, error) {
+	var info *api.Secret
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbSecret, err := dbCluster.GetSecret(ctx, tx.Tx(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		usedBy, err := secrets.UsedBy(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		info = dbSecret.ToAPI()
+		info.UsedBy = usedBy[name]
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if info.UsedBy == nil {
+		info.UsedBy = []string{}
+	}
+
+	return info, nil
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/secrets secrets secrets_get
+//
+//	Get the secrets
+//
+//	Returns a list of secrets (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/secrets/db-password",
+//	              "/1.0/secrets/api-token"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/secrets?recursion=1 secrets secrets_get_recursion1
+//
+//	Get the secrets
+//
+//	Returns a list of secrets (structs). The values of the secrets are never returned.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of secrets
+//	          items:
+//	            $ref: "#/definitions/Secret"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func secretsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var dbSecrets []dbCluster.Secret
+	var usedBy map[string][]string
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbSecrets, err = dbCluster.GetSecrets(ctx, tx.Tx(), dbCluster.SecretFilter{Project: &projectName})
+		if err != nil {
+			return err
+		}
+
+		if recursion {
+			usedBy, err = secrets.UsedBy(ctx, tx.Tx(), projectName)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(dbSecrets))
+		for _, dbSecret := range dbSecrets {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "secrets", dbSecret.Name).Project(projectName).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	result := make([]api.Secret, 0, len(dbSecrets))
+	for _, dbSecret := range dbSecrets {
+		info := dbSecret.ToAPI()
+
+		info.UsedBy = usedBy[dbSecret.Name]
+		if info.UsedBy == nil {
+			info.UsedBy = []string{}
+		}
+
+		result = append(result, *info)
+	}
+
+	return response.SyncResponse(true, result)
+}
+
+// swagger:operation POST /1.0/secrets secrets secrets_post
+//
+//	Add a secret
+//
+//	Creates a new secret. The value is stored encrypted and can't be retrieved through the API.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: secret
+//	    description: Secret
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/SecretsPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func secretsPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	req := api.SecretsPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = secrets.ValidName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Value == "" {
+		return response.BadRequest(fmt.Errorf("No value provided"))
+	}
+
+	value, err := secrets.Encrypt(s.Endpoints.NetworkCert(), projectName, req.Name, req.Value)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		exists, err := dbCluster.SecretExists(ctx, tx.Tx(), projectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "The secret already exists")
+		}
+
+		_, err = dbCluster.CreateSecret(ctx, tx.Tx(), dbCluster.Secret{
+			Project:     projectName,
+			Name:        req.Name,
+			Description: req.Description,
+			Value:       value,
+		})
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(projectName, lifecycle.SecretCreated.Event(req.Name, projectName, requestor, nil))
+
+	return response.SyncResponseLocation(true, nil, api.NewURL().Path(version.APIVersion, "secrets", req.Name).Project(projectName).String())
+}
+
+// swagger:operation GET /1.0/secrets/{name} secrets secret_get
+//
+//	Get the secret
+//
+//	Gets a specific secret. Its value is never returned.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Secret
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Secret"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func secretGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := secretLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, info, info.Writable())
+}
+
+// swagger:operation PUT /1.0/secrets/{name} secrets secret_put
+//
+//	Update the secret
+//
+//	Updates the description of the secret and, when provided, its value.
+//	Instances pick up a new value the next time it's resolved, for example on their next start.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: secret
+//	    description: Secret
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/SecretPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func secretPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := secretLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, info.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.SecretPut{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var value string
+	if req.Value != "" {
+		value, err = secrets.Encrypt(s.Endpoints.NetworkCert(), projectName, name, req.Value)
+		if err != nil {
+			return response.InternalError(err)
+		}
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbSecret, err := dbCluster.GetSecret(ctx, tx.Tx(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		dbSecret.Description = req.Description
+
+		// Keep the current value unless a new one was provided.
+		if value != "" {
+			dbSecret.Value = value
+		}
+
+		return dbCluster.UpdateSecret(ctx, tx.Tx(), projectName, name, *dbSecret)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(projectName, lifecycle.SecretUpdated.Event(name, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation DELETE /1.0/secrets/{name} secrets secret_delete
+//
+//	Delete the secret
+//
+//	Removes the secret. Secrets referenced by instances or profiles can't be removed.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func secretDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		usedBy, err := secrets.UsedBy(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		if len(usedBy[name]) > 0 {
+			return api.StatusErrorf(http.StatusBadRequest, "The secret is currently in use")
+		}
+
+		return dbCluster.DeleteSecret(ctx, tx.Tx(), projectName, name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+	s.Events.SendLifecycle(projectName, lifecycle.SecretDeleted.Event(name, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
@@ -0,0 +1,898 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/auth"
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/locking"
+	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/internal/server/response"
+	"github.com/lxc/incus/v6/internal/server/stack"
+	"github.com/lxc/incus/v6/internal/server/state"
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+var stacksCmd = APIEndpoint{
+	Path: "stacks",
+
+	Get:  APIEndpointAction{Handler: stacksGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: stacksPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+var stackCmd = APIEndpoint{
+	Path: "stacks/{name}",
+
+	Delete: APIEndpointAction{Handler: stackDelete, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+	Get:    APIEndpointAction{Handler: stackGet, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+	Put:    APIEndpointAction{Handler: stackPut, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanEdit)},
+}
+
+var stackPlanCmd = APIEndpoint{
+	Path: "stacks/{name}/plan",
+
+	Post: APIEndpointAction{Handler: stackPlanPost, AccessHandler: allowPermission(auth.ObjectTypeProject, auth.EntitlementCanView)},
+}
+
+// stackLock prevents concurrent changes to the same stack.
+func stackLock(ctx context.Context, projectName string, name string) (locking.UnlockFunc, error) {
+	return locking.Lock(ctx, fmt.Sprintf("StackOperation_%s/%s", projectName, name))
+}
+
+// stackLoad returns the current definition of a stack.
+func stackLoad(ctx context.Context, s *state.State, projectName string, name string) (*api.Stack, error) {
+	var info *api.Stack
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbStack, err := dbCluster.GetStack(ctx, tx.Tx(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		info, err = dbStack.ToAPI()
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// localProjectClient returns a client for the local server targeting the given project. When r is set, the
+// requests are made on behalf of its requestor, so they are authorized and recorded as coming from it.
+func localProjectClient(s *state.State, r *http.Request, projectName string) (incus.InstanceServer, error) {
+	args := &incus.ConnectionArgs{}
+	if r != nil {
+		args.Proxy = func(req *http.Request) (*url.URL, error) {
+			request.ForwardRequestor(r, req)
+
+			return nil, nil
+		}
+	}
+
+	client, err := incus.ConnectIncusUnix(s.OS.GetUnixSocket(), args)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.UseProject(projectName), nil
+}
+
+// stackChangeCheck returns a function checking that the requestor holds the entitlements needed for a change to a
+// resource. The changes are then applied on behalf of the requestor, so this lets the whole change be refused
+// before any resource is modified. The instanceSource function returns the source of a created instance.
+func stackChangeCheck(ctx context.Context, s *state.State, r *http.Request, projectName string, instanceSource func(name string) api.InstanceSource) (stack.CheckFunc, error) {
+	var p *api.Project
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		p, err = dbProject.ToAPI(ctx, tx.Tx())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return func(change api.StackChange) error {
+		var object auth.Object
+		entitlement := auth.EntitlementCanEdit
+		create := change.Action == stack.ActionCreate
+
+		switch change.Type {
+		case stack.TypeProfile:
+			object = auth.ObjectProfile(project.ProfileProjectFromRecord(p), change.Name)
+			if create {
+				object = auth.ObjectProject(projectName)
+				entitlement = auth.EntitlementCanCreateProfiles
+			}
+
+		case stack.TypeNetworkACL:
+			object = auth.ObjectNetworkACL(project.NetworkProjectFromRecord(p), change.Name)
+			if create {
+				object = auth.ObjectProject(projectName)
+				entitlement = auth.EntitlementCanCreateNetworkACLs
+			}
+
+		case stack.TypeNetwork:
+			object = auth.ObjectNetwork(project.NetworkProjectFromRecord(p), change.Name)
+			if create {
+				object = auth.ObjectProject(projectName)
+				entitlement = auth.EntitlementCanCreateNetworks
+			}
+
+		case stack.TypeNetworkForward, stack.TypeNetworkLoadBalancer:
+			// Forwards and load balancers are managed by editing their network.
+			networkName, _, _ := strings.Cut(change.Name, "/")
+			object = auth.ObjectNetwork(project.NetworkProjectFromRecord(p), networkName)
+
+		case stack.TypeStorageVolume:
+			poolName, volumeName, _ := strings.Cut(change.Name, "/")
+			if create {
+				object = auth.ObjectProject(projectName)
+				entitlement = auth.EntitlementCanCreateStorageVolumes
+				break
+			}
+
+			volumeProjectName := project.StorageVolumeProjectFromRecord(p, db.StoragePoolVolumeTypeCustom)
+
+			location, err := stackStorageVolumeLocation(ctx, s, volumeProjectName, poolName, volumeName)
+			if err != nil {
+				return err
+			}
+
+			object = auth.ObjectStorageVolume(volumeProjectName, poolName, db.StoragePoolVolumeTypeNameCustom, volumeName, location)
+
+		case stack.TypeInstance:
+			object = auth.ObjectInstance(projectName, change.Name)
+			if create {
+				object = auth.ObjectProject(projectName)
+				entitlement = auth.EntitlementCanCreateInstances
+
+				err := instanceSourceCheck(ctx, s, r, projectName, instanceSource(change.Name))
+				if err != nil {
+					return fmt.Errorf("Not allowed to use the source of %s %q: %w", change.Type, change.Name, err)
+				}
+			}
+
+		default:
+			return fmt.Errorf("Unsupported resource type %q", change.Type)
+		}
+
+		err := s.Authorizer.CheckPermission(ctx, r, object, entitlement)
+		if err != nil {
+			return fmt.Errorf("Not allowed to %s %s %q: %w", change.Action, change.Type, change.Name, err)
+		}
+
+		return nil
+	}, nil
+}
+
+// stackInstanceSource returns a function returning the source of the instances of a stack definition.
+func stackInstanceSource(spec api.StackPut) func(name string) api.InstanceSource {
+	return func(name string) api.InstanceSource {
+		for _, inst := range spec.Instances {
+			if inst.Name == name {
+				return inst.Source
+			}
+		}
+
+		return api.InstanceSource{}
+	}
+}
+
+// stackStorageVolumeLocation returns the cluster member holding a custom storage volume on a local pool.
+func stackStorageVolumeLocation(ctx context.Context, s *state.State, projectName string, poolName string, volumeName string) (string, error) {
+	if !s.ServerClustered {
+		return "", nil
+	}
+
+	var nodes []db.NodeInfo
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		poolID, err := tx.GetStoragePoolID(ctx, poolName)
+		if err != nil {
+			return err
+		}
+
+		nodes, err = tx.GetStorageVolumeNodes(ctx, poolID, projectName, volumeName, db.StoragePoolVolumeTypeCustom)
+
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(nodes) != 1 {
+		return "", nil
+	}
+
+	return nodes[0].Name, nil
+}
+
+// stackValidateName checks that a stack name can be used in URLs.
+func stackValidateName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if strings.Contains(name, "/") {
+		return errors.New("Stack names may not contain slashes")
+	}
+
+	if strings.Contains(name, " ") {
+		return errors.New("Stack names may not contain spaces")
+	}
+
+	return nil
+}
+
+// API endpoints.
+
+// swagger:operation GET /1.0/stacks stacks stacks_get
+//
+//	Get the stacks
+//
+//	Returns a list of stacks (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/stacks/myapp",
+//	              "/1.0/stacks/monitoring"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/stacks?recursion=1 stacks stacks_get_recursion1
+//
+//	Get the stacks
+//
+//	Returns a list of stacks (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of stacks
+//	          items:
+//	            $ref: "#/definitions/Stack"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func stacksGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	recursion := localUtil.IsRecursionRequest(r)
+
+	var dbStacks []dbCluster.Stack
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		dbStacks, err = dbCluster.GetStacks(ctx, tx.Tx(), dbCluster.StackFilter{Project: &projectName})
+
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !recursion {
+		urls := make([]string, 0, len(dbStacks))
+		for _, dbStack := range dbStacks {
+			urls = append(urls, api.NewURL().Path(version.APIVersion, "stacks", dbStack.Name).Project(projectName).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	stacks := make([]api.Stack, 0, len(dbStacks))
+	for _, dbStack := range dbStacks {
+		info, err := dbStack.ToAPI()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		stacks = append(stacks, *info)
+	}
+
+	return response.SyncResponse(true, stacks)
+}
+
+// swagger:operation POST /1.0/stacks stacks stacks_post
+//
+//	Add a stack
+//
+//	Creates all the resources of a new stack. If any of them can't be created,
+//	those created so far are removed again.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: stack
+//	    description: Stack
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StacksPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func stacksPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	req := api.StacksPost{}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = stackValidateName(req.Name)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = stack.Validate(req.StackPut)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		exists, err := dbCluster.StackExists(ctx, tx.Tx(), projectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return api.StatusErrorf(http.StatusConflict, "The stack already exists")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	spec, err := json.Marshal(req.StackPut)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	run := func(op *operations.Operation) error {
+		unlock, err := stackLock(context.Background(), projectName, req.Name)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		client, err := localProjectClient(s, r, projectName)
+		if err != nil {
+			return err
+		}
+
+		check, err := stackChangeCheck(context.Background(), s, r, projectName, stackInstanceSource(req.StackPut))
+		if err != nil {
+			return err
+		}
+
+		cleanup, err := stack.Apply(client, nil, req.StackPut, check)
+		if err != nil {
+			return err
+		}
+
+		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := dbCluster.CreateStack(ctx, tx.Tx(), dbCluster.Stack{
+				Project:     projectName,
+				Name:        req.Name,
+				Description: req.Description,
+				Spec:        string(spec),
+			})
+
+			return err
+		})
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("Failed to record stack: %w", err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.StackCreated.Event(req.Name, projectName, requestor, nil))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.StackCreate, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation GET /1.0/stacks/{name} stacks stack_get
+//
+//	Get the stack
+//
+//	Gets the current definition of a specific stack.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: Stack
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Stack"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func stackGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := stackLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponseETag(true, info, info.Writable())
+}
+
+// swagger:operation PUT /1.0/stacks/{name} stacks stack_put
+//
+//	Update the stack
+//
+//	Brings the resources of the stack to the provided definition.
+//	Resources no longer part of the definition are deleted.
+//	If any change fails, the changes made so far are reverted.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: stack
+//	    description: Stack definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StackPut"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func stackPut(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	info, err := stackLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate the ETag.
+	err = localUtil.EtagCheck(r, info.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.StackPut{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = stack.Validate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	spec, err := json.Marshal(req)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	run := func(op *operations.Operation) error {
+		unlock, err := stackLock(context.Background(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		// Reload the definition now that the stack is locked.
+		current, err := stackLoad(context.Background(), s, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		client, err := localProjectClient(s, r, projectName)
+		if err != nil {
+			return err
+		}
+
+		check, err := stackChangeCheck(context.Background(), s, r, projectName, stackInstanceSource(req))
+		if err != nil {
+			return err
+		}
+
+		currentSpec := current.Writable()
+
+		cleanup, err := stack.Apply(client, &currentSpec, req, check)
+		if err != nil {
+			return err
+		}
+
+		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.UpdateStack(ctx, tx.Tx(), projectName, name, dbCluster.Stack{
+				Project:     projectName,
+				Name:        name,
+				Description: req.Description,
+				Spec:        string(spec),
+			})
+		})
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("Failed to record stack: %w", err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.StackUpdated.Event(name, projectName, requestor, nil))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.StackUpdate, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation DELETE /1.0/stacks/{name} stacks stack_delete
+//
+//	Delete the stack
+//
+//	Removes the stack along with all of its resources.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func stackDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	_, err = stackLoad(r.Context(), s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r)
+
+	run := func(op *operations.Operation) error {
+		unlock, err := stackLock(context.Background(), projectName, name)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		current, err := stackLoad(context.Background(), s, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		client, err := localProjectClient(s, r, projectName)
+		if err != nil {
+			return err
+		}
+
+		check, err := stackChangeCheck(context.Background(), s, r, projectName, stackInstanceSource(api.StackPut{}))
+		if err != nil {
+			return err
+		}
+
+		cleanup, err := stack.Delete(client, current.Writable(), check)
+		if err != nil {
+			return err
+		}
+
+		err = s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+			return dbCluster.DeleteStack(ctx, tx.Tx(), projectName, name)
+		})
+		if err != nil {
+			cleanup()
+			return fmt.Errorf("Failed to remove stack record: %w", err)
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.StackDeleted.Event(name, projectName, requestor, nil))
+
+		return nil
+	}
+
+	op, err := operations.OperationCreate(s, projectName, operations.OperationClassTask, operationtype.StackDelete, nil, nil, run, nil, nil, r)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return operations.OperationResponse(op)
+}
+
+// swagger:operation POST /1.0/stacks/{name}/plan stacks stack_plan_post
+//
+//	Get the stack plan
+//
+//	Computes the changes needed to bring the stack, which may not exist yet,
+//	to the provided definition without applying them.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: stack
+//	    description: Stack definition
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/StackPut"
+//	responses:
+//	  "200":
+//	    description: Stack plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/StackPlan"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func stackPlanPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+
+	name, err := url.PathUnescape(mux.Vars(r)["name"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.StackPut{}
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = stack.Validate(req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	var currentSpec *api.StackPut
+
+	current, err := stackLoad(r.Context(), s, projectName, name)
+	if err == nil {
+		spec := current.Writable()
+		currentSpec = &spec
+	} else if !response.IsNotFoundError(err) {
+		return response.SmartError(err)
+	}
+
+	client, err := localProjectClient(s, r, projectName)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	plan, err := stack.Plan(client, currentSpec, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, plan)
+}
@@ -3,10 +3,13 @@ package instance
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/kballard/go-shellquote"
+
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
 	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/units"
@@ -63,6 +66,21 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=boot, key=boot.depends_on)
+	// Comma-separated list of instances in the same project that must be running before this instance gets started.
+	// Dependencies with health checks configured must also be healthy.
+	//
+	// Dependency cycles are rejected when the instance or its profiles are changed.
+	//
+	// When starting or stopping multiple instances at once (on server start-up and shutdown, or when changing the state of all instances of a project), dependencies are started first and stopped last.
+	// This takes precedence over {config:option}`instance-boot:boot.autostart.priority` and {config:option}`instance-boot:boot.stop.priority`.
+	// Stopping a single instance doesn't stop the instances that depend on it.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Instances that must be running before starting this one
+	"boot.depends_on": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
 	// gendoc:generate(entity=instance, group=boot, key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -92,6 +110,37 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: How long to wait for the instance to shut down
 	"boot.host_shutdown_timeout": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=boot, key=boot.idle_stop)
+	// Specify an expression like `30M`, `2H` or `1d`.
+	// The instance is shut down once its CPU and network usage have stayed below
+	// {config:option}`instance-boot:boot.idle_stop.cpu` and {config:option}`instance-boot:boot.idle_stop.network` for that long.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: How long the instance may stay idle before being stopped
+	"boot.idle_stop": validate.Optional(func(value string) error {
+		_, err := GetExpiry(time.Time{}, value)
+		return err
+	}),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.idle_stop.cpu)
+	// Expressed as a percentage of a single CPU.
+	// ---
+	//  type: integer
+	//  defaultdesc: `5`
+	//  liveupdate: yes
+	//  shortdesc: CPU usage below which the instance is considered idle
+	"boot.idle_stop.cpu": validate.Optional(validate.IsInRange(0, 100)),
+
+	// gendoc:generate(entity=instance, group=boot, key=boot.idle_stop.network)
+	// Combined received and transmitted traffic per second across all the instance's network interfaces.
+	// ---
+	//  type: string
+	//  defaultdesc: `1KiB`
+	//  liveupdate: yes
+	//  shortdesc: Network traffic below which the instance is considered idle
+	"boot.idle_stop.network": validate.Optional(validate.IsSize),
+
 	// gendoc:generate(entity=instance, group=cloud-init, key=cloud-init.network-config)
 	// The content is used as seed value for `cloud-init`.
 	// ---
@@ -177,6 +226,86 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What to do when evacuating the instance
 	"cluster.evacuate": validate.Optional(validate.IsOneOf("auto", "migrate", "live-migrate", "stop", "stateful-stop", "force-stop")),
 
+	// gendoc:generate(entity=instance, group=miscellaneous, key=cluster.rebalance.exclude)
+	// When set, the automatic cluster re-balancing never moves the instance.
+	// See {ref}`cluster-automatic-balancing` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Whether to exclude the instance from cluster re-balancing
+	"cluster.rebalance.exclude": validate.Optional(validate.IsBool),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.action)
+	// Action to take once the instance is considered unhealthy.
+	// Possible values are `none` (only report the state) and `restart`.
+	// ---
+	//  type: string
+	//  defaultdesc: `none`
+	//  liveupdate: yes
+	//  shortdesc: What to do when the instance becomes unhealthy
+	"healthcheck.action": validate.Optional(validate.IsOneOf("none", "restart")),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.command)
+	// The command is run inside the instance the same way as with [`incus exec`](incus_exec.md).
+	// An exit code of `0` indicates that the instance is healthy.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Command to run to check the instance's health
+	"healthcheck.command": validate.Optional(func(value string) error {
+		_, err := shellquote.Split(value)
+		return err
+	}),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.http)
+	// Specify a port optionally followed by a path, like `8080` or `8080/healthz`.
+	// A `GET` request is sent to the instance's IP address and any `2xx` or `3xx` response indicates that the instance is healthy.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: HTTP endpoint to query to check the instance's health
+	"healthcheck.http": validate.Optional(func(value string) error {
+		port, _, _ := strings.Cut(value, "/")
+		return validate.IsNetworkPort(port)
+	}),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.interval)
+	// Number of seconds between two checks.
+	// ---
+	//  type: integer
+	//  defaultdesc: `30`
+	//  liveupdate: yes
+	//  shortdesc: How often to check the instance's health
+	"healthcheck.interval": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.retries)
+	// Number of consecutive failed checks after which the instance is considered unhealthy.
+	// ---
+	//  type: integer
+	//  defaultdesc: `3`
+	//  liveupdate: yes
+	//  shortdesc: How many failed checks make the instance unhealthy
+	"healthcheck.retries": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.tcp)
+	// A connection is made to that port on the instance's IP address.
+	// The instance is considered healthy if the connection succeeds.
+	// ---
+	//  type: integer
+	//  liveupdate: yes
+	//  shortdesc: TCP port to connect to to check the instance's health
+	"healthcheck.tcp": validate.Optional(validate.IsNetworkPort),
+
+	// gendoc:generate(entity=instance, group=healthcheck, key=healthcheck.timeout)
+	// Number of seconds after which a single check is considered failed.
+	// ---
+	//  type: integer
+	//  defaultdesc: `5`
+	//  liveupdate: yes
+	//  shortdesc: How long a check may take
+	"healthcheck.timeout": validate.Optional(validate.IsInRange(1, math.MaxInt32)),
+
 	// gendoc:generate(entity=instance, group=resource-limits, key=limits.cpu)
 	// A number or a specific range of CPUs to expose to the instance.
 	//
@@ -278,6 +407,43 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Raw idmap configuration
 	"raw.idmap": validate.IsAny,
 
+	// gendoc:generate(entity=instance, group=replication, key=replication.remote)
+	// Name of a replication remote defined by the server administrator (see {ref}`instances-replicate`).
+	// On a replica, the replication remote points back at the server hosting the primary and is needed to fail over without forcing it.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Replication remote to replicate the instance to
+	"replication.remote": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=replication, key=replication.role)
+	// Set to `replica` on the copy of the instance that is kept on the remote server.
+	// Replica instances can't be started until they are promoted through a failover.
+	// ---
+	//  type: string
+	//  defaultdesc: `primary`
+	//  liveupdate: yes
+	//  shortdesc: Replication role of the instance (`primary` or `replica`)
+	"replication.role": validate.Optional(validate.IsOneOf("primary", "replica")),
+
+	// gendoc:generate(entity=instance, group=replication, key=replication.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-and-space-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to only replicate on demand.
+	// ---
+	//  type: string
+	//  defaultdesc: empty
+	//  liveupdate: yes
+	//  shortdesc: Schedule for automatic replication
+	"replication.schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"})),
+
+	// gendoc:generate(entity=instance, group=replication, key=replication.snapshots.retention)
+	// Older replication snapshots are deleted from both the instance and its replica, as well as from the custom storage volumes replicated with it.
+	// ---
+	//  type: integer
+	//  defaultdesc: `3`
+	//  liveupdate: yes
+	//  shortdesc: Number of replication snapshots to keep
+	"replication.snapshots.retention": validate.Optional(validate.IsUint32),
+
 	// gendoc:generate(entity=instance, group=security, key=security.guestapi)
 	// See {ref}`dev-incus` for more information.
 	// ---
@@ -393,6 +559,13 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Instance state as of last host shutdown
 	"volatile.last_state.power": validate.IsAny,
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.last_state.idle)
+	//
+	// ---
+	//  type: bool
+	//  shortdesc: Instance was stopped due to inactivity
+	"volatile.last_state.idle": validate.IsBool,
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.last_state.ready)
 	//
 	// ---
@@ -407,6 +580,27 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Timestamp of last move by automatic live-migration
 	"volatile.rebalance.last_move": validate.Optional(validate.IsInt64),
 
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.replication.last_attempt)
+	//
+	// ---
+	//  type: integer
+	//  shortdesc: Timestamp of the last replication attempt
+	"volatile.replication.last_attempt": validate.Optional(validate.IsInt64),
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.replication.last_error)
+	//
+	// ---
+	//  type: string
+	//  shortdesc: Error returned by the last failed replication
+	"volatile.replication.last_error": validate.IsAny,
+
+	// gendoc:generate(entity=instance, group=volatile, key=volatile.replication.last_success)
+	//
+	// ---
+	//  type: integer
+	//  shortdesc: Timestamp of the last successful replication
+	"volatile.replication.last_success": validate.Optional(validate.IsInt64),
+
 	// gendoc:generate(entity=instance, group=volatile, key=volatile.uuid)
 	// The instance UUID is globally unique across all servers and projects.
 	// ---
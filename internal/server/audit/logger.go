@@ -0,0 +1,167 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"os"
+	"sync"
+
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Logger writes audit events to a rotating local file and to syslog.
+type Logger struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file   *os.File
+	size   int64
+	syslog *syslog.Writer
+	mu     sync.Mutex
+}
+
+// NewLogger returns a Logger.
+// The file is skipped if path is empty and rotated once it reaches maxSize bytes, keeping maxFiles old copies.
+func NewLogger(path string, maxSize int64, maxFiles int, useSyslog bool) (*Logger, error) {
+	l := &Logger{path: path, maxSize: maxSize, maxFiles: maxFiles}
+
+	if path != "" {
+		err := l.open()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if useSyslog {
+		writer, err := syslog.New(syslog.LOG_INFO|syslog.LOG_AUTHPRIV, "incus-audit")
+		if err != nil {
+			_ = l.Close()
+			return nil, fmt.Errorf("Failed connecting to syslog: %w", err)
+		}
+
+		l.syslog = writer
+	}
+
+	return l, nil
+}
+
+// HandleEvent handles the event received from the internal event listener.
+func (l *Logger) HandleEvent(event api.Event) {
+	if event.Type != api.EventTypeAudit {
+		return
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	err = l.write(line)
+	if err != nil {
+		logger.Warn("Failed writing audit record", logger.Ctx{"err": err})
+	}
+}
+
+// Close closes the file and the syslog connection.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.syslog != nil {
+		_ = l.syslog.Close()
+		l.syslog = nil
+	}
+
+	if l.file != nil {
+		err := l.file.Close()
+		l.file = nil
+		return err
+	}
+
+	return nil
+}
+
+func (l *Logger) write(line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.syslog != nil {
+		err := l.syslog.Info(string(line))
+		if err != nil {
+			return fmt.Errorf("Failed sending to syslog: %w", err)
+		}
+	}
+
+	if l.file == nil {
+		return nil
+	}
+
+	line = append(line, '\n')
+
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.maxSize {
+		err := l.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("Failed writing to %q: %w", l.path, err)
+	}
+
+	return nil
+}
+
+// open opens the file for appending, creating it if missing.
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("Failed opening %q: %w", l.path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("Failed getting size of %q: %w", l.path, err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+
+	return nil
+}
+
+// rotate shifts the existing file to path.1, path.1 to path.2 and so on, dropping the oldest one.
+func (l *Logger) rotate() error {
+	err := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return fmt.Errorf("Failed closing %q: %w", l.path, err)
+	}
+
+	if l.maxFiles > 0 {
+		_ = os.Remove(fmt.Sprintf("%s.%d", l.path, l.maxFiles))
+
+		for i := l.maxFiles - 1; i > 0; i-- {
+			err := os.Rename(fmt.Sprintf("%s.%d", l.path, i), fmt.Sprintf("%s.%d", l.path, i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("Failed rotating %q: %w", l.path, err)
+			}
+		}
+
+		err = os.Rename(l.path, fmt.Sprintf("%s.1", l.path))
+	} else {
+		err = os.Remove(l.path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed rotating %q: %w", l.path, err)
+	}
+
+	return l.open()
+}
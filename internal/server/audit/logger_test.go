@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Audit events are appended to the file, which gets rotated once full.
+func TestLoggerRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := NewLogger(path, 400, 2, false)
+	require.NoError(t, err)
+
+	defer func() { _ = l.Close() }()
+
+	metadata, err := json.Marshal(api.EventAudit{Method: "POST", URL: "/1.0/instances", StatusCode: 202, Outcome: api.EventAuditOutcomeSuccess, Authorization: api.EventAuditAuthorizationAllowed})
+	require.NoError(t, err)
+
+	event := api.Event{Type: api.EventTypeAudit, Timestamp: time.Now(), Metadata: metadata}
+	for i := 0; i < 10; i++ {
+		l.HandleEvent(event)
+	}
+
+	// Other event types are ignored.
+	l.HandleEvent(api.Event{Type: api.EventTypeLifecycle, Timestamp: time.Now(), Metadata: metadata})
+
+	assert.FileExists(t, path+".1")
+	assert.FileExists(t, path+".2")
+	assert.NoFileExists(t, path+".3")
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		info, err := os.Stat(name)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(400))
+	}
+}
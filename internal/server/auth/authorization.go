@@ -19,6 +19,9 @@ const (
 
 	// DriverScriptlet provides scriptlet-based authorization. It is compatible with any authentication method.
 	DriverScriptlet string = "scriptlet"
+
+	// DriverRBAC provides built-in role-based access control. It is compatible with any authentication method.
+	DriverRBAC string = "rbac"
 )
 
 // ErrUnknownDriver is the "Unknown driver" error.
@@ -28,6 +31,7 @@ var authorizers = map[string]func() authorizer{
 	DriverTLS:       func() authorizer { return &TLS{} },
 	DriverOpenFGA:   func() authorizer { return &FGA{} },
 	DriverScriptlet: func() authorizer { return &Scriptlet{} },
+	DriverRBAC:      func() authorizer { return &RBAC{} },
 }
 
 type authorizer interface {
@@ -107,6 +111,7 @@ type Opts struct {
 	config          map[string]any
 	projectsGetFunc func(ctx context.Context) (map[int64]string, error)
 	resourcesFunc   func() (*Resources, error)
+	rbacGroupsFunc  func(ctx context.Context) ([]RBACGroup, error)
 }
 
 // Resources represents a set of current API resources as Object slices for use when loading an Authorizer.
@@ -147,6 +152,13 @@ func WithResourcesFunc(f func() (*Resources, error)) func(*Opts) {
 	}
 }
 
+// WithRBACGroupsFunc should be passed into LoadAuthorizer when DriverRBAC is used.
+func WithRBACGroupsFunc(f func(ctx context.Context) ([]RBACGroup, error)) func(*Opts) {
+	return func(o *Opts) {
+		o.rbacGroupsFunc = f
+	}
+}
+
 // LoadAuthorizer instantiates, configures, and initializes an Authorizer.
 func LoadAuthorizer(ctx context.Context, driver string, logger logger.Logger, certificateCache *certificate.Cache, options ...func(opts *Opts)) (Authorizer, error) {
 	opts := &Opts{}
@@ -1,5 +1,10 @@
 package auth
 
+import (
+	"fmt"
+	"slices"
+)
+
 // Entitlement is a type representation of a permission as it applies to a particular ObjectType.
 type Entitlement string
 
@@ -92,9 +97,83 @@ const (
 
 	// ObjectTypeStorageVolume represents a storage volume.
 	ObjectTypeStorageVolume ObjectType = "storage_volume"
+
+	// ObjectTypeAll matches the objects of all types in role permissions.
+	ObjectTypeAll ObjectType = "*"
 )
 
 const (
 	relationServer  = "server"
 	relationProject = "project"
 )
+
+// objectTypeEntitlements lists the entitlements which apply to each object type.
+var objectTypeEntitlements = map[ObjectType][]Entitlement{
+	ObjectTypeServer: {
+		EntitlementCanEdit,
+		EntitlementCanView,
+		EntitlementCanCreateCertificates,
+		EntitlementCanCreateNetworkIntegrations,
+		EntitlementCanCreateProjects,
+		EntitlementCanCreateStoragePools,
+		EntitlementCanOverrideClusterTargetRestriction,
+		EntitlementCanViewMetrics,
+		EntitlementCanViewPrivilegedEvents,
+		EntitlementCanViewResources,
+		EntitlementCanViewSensitive,
+	},
+	ObjectTypeCertificate:        {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeStoragePool:        {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetworkIntegration: {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeProject: {
+		EntitlementCanEdit,
+		EntitlementCanView,
+		EntitlementCanCreateImageAliases,
+		EntitlementCanCreateImages,
+		EntitlementCanCreateInstances,
+		EntitlementCanCreateNetworkACLs,
+		EntitlementCanCreateNetworks,
+		EntitlementCanCreateNetworkZones,
+		EntitlementCanCreateProfiles,
+		EntitlementCanCreateStorageBuckets,
+		EntitlementCanCreateStorageVolumes,
+		EntitlementCanViewEvents,
+		EntitlementCanViewOperations,
+	},
+	ObjectTypeImage:       {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeImageAlias:  {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetwork:     {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetworkACL:  {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeNetworkZone: {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeProfile:     {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeInstance: {
+		EntitlementCanEdit,
+		EntitlementCanView,
+		EntitlementCanAccessConsole,
+		EntitlementCanAccessFiles,
+		EntitlementCanConnectSFTP,
+		EntitlementCanExec,
+		EntitlementCanUpdateState,
+		EntitlementCanManageBackups,
+		EntitlementCanManageSnapshots,
+	},
+	ObjectTypeStorageBucket: {EntitlementCanEdit, EntitlementCanView},
+	ObjectTypeStorageVolume: {EntitlementCanEdit, EntitlementCanView, EntitlementCanManageBackups, EntitlementCanManageSnapshots},
+
+	// Editing the objects of all types grants all their entitlements.
+	ObjectTypeAll: {EntitlementCanEdit, EntitlementCanView},
+}
+
+// ValidateEntitlement checks that the entitlement applies to the object type.
+func ValidateEntitlement(objectType ObjectType, entitlement Entitlement) error {
+	entitlements, ok := objectTypeEntitlements[objectType]
+	if !ok {
+		return fmt.Errorf("Unknown object type %q", objectType)
+	}
+
+	if !slices.Contains(entitlements, entitlement) {
+		return fmt.Errorf("Entitlement %q doesn't apply to objects of type %q", entitlement, objectType)
+	}
+
+	return nil
+}
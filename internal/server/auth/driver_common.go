@@ -8,6 +8,7 @@ import (
 
 	"github.com/lxc/incus/v6/internal/server/auth/common"
 	"github.com/lxc/incus/v6/internal/server/request"
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/logger"
 	"github.com/lxc/incus/v6/shared/util"
 )
@@ -34,22 +35,29 @@ type requestDetails struct {
 
 	forwardedUsername string
 	forwardedProtocol string
+
+	identityProviderGroups          []string
+	forwardedIdentityProviderGroups []string
+
+	token *api.AuthToken
 }
 
-func (r *requestDetails) isInternalOrUnix() bool {
-	if r.Protocol == "unix" {
-		return true
-	}
+// isForwarded returns whether the request was made on behalf of another requestor, either by another cluster
+// member or by the local server through the unix socket.
+func (r *requestDetails) isForwarded() bool {
+	return r.Protocol == "cluster" || (r.Protocol == "unix" && r.forwardedProtocol != "")
+}
 
-	if r.Protocol == "cluster" && (r.forwardedProtocol == "unix" || r.forwardedProtocol == "cluster" || r.forwardedProtocol == "") {
-		return true
+func (r *requestDetails) isInternalOrUnix() bool {
+	if r.isForwarded() {
+		return r.forwardedProtocol == "unix" || r.forwardedProtocol == "cluster" || r.forwardedProtocol == ""
 	}
 
-	return false
+	return r.Protocol == "unix"
 }
 
 func (r *requestDetails) username() string {
-	if r.Protocol == "cluster" && r.forwardedUsername != "" {
+	if r.isForwarded() && r.forwardedUsername != "" {
 		return r.forwardedUsername
 	}
 
@@ -57,13 +65,21 @@ func (r *requestDetails) username() string {
 }
 
 func (r *requestDetails) authenticationProtocol() string {
-	if r.Protocol == "cluster" {
+	if r.isForwarded() {
 		return r.forwardedProtocol
 	}
 
 	return r.Protocol
 }
 
+func (r *requestDetails) groups() []string {
+	if r.isForwarded() {
+		return r.forwardedIdentityProviderGroups
+	}
+
+	return r.identityProviderGroups
+}
+
 func (r *requestDetails) actualDetails() *common.RequestDetails {
 	return &common.RequestDetails{
 		Username:             r.username(),
@@ -118,6 +134,10 @@ func (c *commonAuthorizer) requestDetails(r *http.Request) (*requestDetails, err
 		}
 	}
 
+	identityProviderGroups, _ := r.Context().Value(request.CtxIdentityProviderGroups).([]string)
+	forwardedIdentityProviderGroups, _ := r.Context().Value(request.CtxForwardedIdentityProviderGroups).([]string)
+	token, _ := r.Context().Value(request.CtxAuthToken).(*api.AuthToken)
+
 	values, err := url.ParseQuery(r.URL.RawQuery)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to parse request query parameters: %w", err)
@@ -133,6 +153,11 @@ func (c *commonAuthorizer) requestDetails(r *http.Request) (*requestDetails, err
 
 		forwardedUsername: forwardedUsername,
 		forwardedProtocol: forwardedProtocol,
+
+		identityProviderGroups:          identityProviderGroups,
+		forwardedIdentityProviderGroups: forwardedIdentityProviderGroups,
+
+		token: token,
 	}, nil
 }
 
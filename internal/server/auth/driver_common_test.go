@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/internal/server/auth/common"
+)
+
+func TestRequestDetailsForwarded(t *testing.T) {
+	// Requests through the unix socket are trusted unless made on behalf of another requestor.
+	unix := requestDetails{RequestDetails: common.RequestDetails{Username: "root", Protocol: "unix"}}
+	assert.True(t, unix.isInternalOrUnix())
+	assert.Equal(t, "root", unix.username())
+
+	onBehalf := requestDetails{RequestDetails: common.RequestDetails{Username: "root", Protocol: "unix"}, forwardedUsername: "abcdef", forwardedProtocol: "tls"}
+	assert.False(t, onBehalf.isInternalOrUnix())
+	assert.Equal(t, "abcdef", onBehalf.username())
+	assert.Equal(t, "tls", onBehalf.authenticationProtocol())
+
+	// Requests from other cluster members are trusted unless forwarded from a remote client.
+	member := requestDetails{RequestDetails: common.RequestDetails{Username: "fedcba", Protocol: "cluster"}}
+	assert.True(t, member.isInternalOrUnix())
+
+	forwarded := requestDetails{RequestDetails: common.RequestDetails{Username: "fedcba", Protocol: "cluster"}, forwardedUsername: "jane@example.com", forwardedProtocol: "oidc", forwardedIdentityProviderGroups: []string{"admins"}}
+	assert.False(t, forwarded.isInternalOrUnix())
+	assert.Equal(t, "jane@example.com", forwarded.username())
+	assert.Equal(t, []string{"admins"}, forwarded.groups())
+}
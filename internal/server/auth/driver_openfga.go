@@ -276,6 +276,15 @@ func (f *FGA) CheckPermission(ctx context.Context, r *http.Request, object Objec
 		return nil
 	}
 
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		if !tokenAllowed(details.token, object, entitlement) {
+			return api.StatusErrorf(http.StatusForbidden, "API token doesn't have entitlement %q on object %q", entitlement, object)
+		}
+
+		return nil
+	}
+
 	// Use the TLS driver if the user authenticated with TLS.
 	if details.authenticationProtocol() == api.AuthenticationMethodTLS {
 		return f.tls.CheckPermission(ctx, r, object, entitlement)
@@ -330,6 +339,13 @@ func (f *FGA) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 		return allowFunc(true), nil
 	}
 
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		return func(object Object) bool {
+			return tokenAllowed(details.token, object, entitlement)
+		}, nil
+	}
+
 	// Use the TLS driver if the user authenticated with TLS.
 	if details.authenticationProtocol() == api.AuthenticationMethodTLS {
 		return f.tls.GetPermissionChecker(ctx, r, entitlement, objectType)
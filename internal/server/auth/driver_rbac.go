@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/lxc/incus/v6/internal/server/certificate"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// RBACGrant is an entitlement granted on all the objects of a type, optionally limited to a project.
+type RBACGrant struct {
+	ObjectType  ObjectType
+	Entitlement Entitlement
+	Project     string
+}
+
+// RBACGroup is a group of identities along with the entitlements granted to them through its roles.
+type RBACGroup struct {
+	Name string
+
+	// Identities maps authentication methods to the identifiers of the group members.
+	Identities map[string][]string
+
+	// IdentityProviderGroups lists the OpenID Connect groups whose users are members of the group.
+	IdentityProviderGroups []string
+
+	Grants []RBACGrant
+}
+
+// hasMember returns whether the identity is a member of the group, either directly or through its identity provider groups.
+func (g RBACGroup) hasMember(authenticationMethod string, identifier string, identityProviderGroups []string) bool {
+	if slices.Contains(g.Identities[authenticationMethod], identifier) {
+		return true
+	}
+
+	if authenticationMethod != api.AuthenticationMethodOIDC {
+		return false
+	}
+
+	for _, name := range identityProviderGroups {
+		if slices.Contains(g.IdentityProviderGroups, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allows returns whether the grant allows the entitlement on the object.
+func (g RBACGrant) allows(object Object, entitlement Entitlement) bool {
+	if g.ObjectType == ObjectTypeAll {
+		// Being able to edit the objects of all types implies all their entitlements.
+		if g.Entitlement != entitlement && g.Entitlement != EntitlementCanEdit {
+			return false
+		}
+	} else {
+		if g.ObjectType != object.Type() {
+			return false
+		}
+
+		// Being able to edit an object implies being able to view it.
+		if g.Entitlement != entitlement && (g.Entitlement != EntitlementCanEdit || entitlement != EntitlementCanView) {
+			return false
+		}
+	}
+
+	if g.Project == "" {
+		return true
+	}
+
+	// Objects which don't belong to a project are only granted by grants that aren't limited to one.
+	if !objectValidators[object.Type()].requireProject {
+		return false
+	}
+
+	return object.Project() == g.Project
+}
+
+// RBAC represents the built-in role-based access control authorizer.
+type RBAC struct {
+	commonAuthorizer
+	tls *TLS
+
+	groupsFunc func(ctx context.Context) ([]RBACGroup, error)
+
+	groupsMu sync.RWMutex
+	groups   []RBACGroup
+}
+
+func (r *RBAC) load(ctx context.Context, certificateCache *certificate.Cache, opts Opts) error {
+	if opts.rbacGroupsFunc == nil {
+		return errors.New("Role-based access control authorization driver requires a groups function")
+	}
+
+	r.groupsFunc = opts.rbacGroupsFunc
+
+	r.tls = &TLS{}
+	err := r.tls.load(ctx, certificateCache, opts)
+	if err != nil {
+		return err
+	}
+
+	return r.Refresh(ctx)
+}
+
+// Refresh reloads the groups, roles and identities from the database.
+func (r *RBAC) Refresh(ctx context.Context) error {
+	groups, err := r.groupsFunc(ctx)
+	if err != nil {
+		return fmt.Errorf("Failed loading authorization groups: %w", err)
+	}
+
+	r.groupsMu.Lock()
+	r.groups = groups
+	r.groupsMu.Unlock()
+
+	return nil
+}
+
+// identityGrants returns the grants of an identity and whether it belongs to any group.
+func (r *RBAC) identityGrants(authenticationMethod string, identifier string, identityProviderGroups []string) ([]RBACGrant, bool) {
+	r.groupsMu.RLock()
+	defer r.groupsMu.RUnlock()
+
+	var grants []RBACGrant
+	member := false
+
+	for _, group := range r.groups {
+		if !group.hasMember(authenticationMethod, identifier, identityProviderGroups) {
+			continue
+		}
+
+		member = true
+		grants = append(grants, group.Grants...)
+	}
+
+	return grants, member
+}
+
+// rbacAllowed returns whether the grants allow the entitlement on the object.
+func rbacAllowed(grants []RBACGrant, object Object, entitlement Entitlement) bool {
+	// Entitlements available to all authenticated identities.
+	switch object.Type() {
+	case ObjectTypeServer:
+		if slices.Contains([]Entitlement{EntitlementCanView, EntitlementCanViewResources, EntitlementCanViewMetrics}, entitlement) {
+			return true
+		}
+
+	case ObjectTypeStoragePool:
+		if entitlement == EntitlementCanView {
+			return true
+		}
+	}
+
+	for _, grant := range grants {
+		if grant.allows(object, entitlement) {
+			return true
+		}
+
+		// Any grant within a project allows viewing that project.
+		if object.Type() == ObjectTypeProject && entitlement == EntitlementCanView && objectValidators[grant.ObjectType].requireProject && (grant.Project == "" || grant.Project == object.Project()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckPermission returns an error if the user does not have the given Entitlement on the given Object.
+func (r *RBAC) CheckPermission(ctx context.Context, req *http.Request, object Object, entitlement Entitlement) error {
+	details, err := r.requestDetails(req)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return nil
+	}
+
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		if !tokenAllowed(details.token, object, entitlement) {
+			return api.StatusErrorf(http.StatusForbidden, "API token doesn't have entitlement %q on object %q", entitlement, object)
+		}
+
+		return nil
+	}
+
+	authenticationMethod := details.authenticationProtocol()
+	grants, member := r.identityGrants(authenticationMethod, details.username(), details.groups())
+
+	// Use the TLS driver for certificates which don't belong to any group.
+	if !member && authenticationMethod == api.AuthenticationMethodTLS {
+		return r.tls.CheckPermission(ctx, req, object, entitlement)
+	}
+
+	if !rbacAllowed(grants, object, entitlement) {
+		return api.StatusErrorf(http.StatusForbidden, "User does not have entitlement %q on object %q", entitlement, object)
+	}
+
+	return nil
+}
+
+// GetPermissionChecker returns a function that can be used to check whether a user has the required entitlement on an authorization object.
+func (r *RBAC) GetPermissionChecker(ctx context.Context, req *http.Request, entitlement Entitlement, objectType ObjectType) (PermissionChecker, error) {
+	details, err := r.requestDetails(req)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusForbidden, "Failed to extract request details: %v", err)
+	}
+
+	if details.isInternalOrUnix() {
+		return func(Object) bool { return true }, nil
+	}
+
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		return func(object Object) bool {
+			return tokenAllowed(details.token, object, entitlement)
+		}, nil
+	}
+
+	authenticationMethod := details.authenticationProtocol()
+	grants, member := r.identityGrants(authenticationMethod, details.username(), details.groups())
+
+	// Use the TLS driver for certificates which don't belong to any group.
+	if !member && authenticationMethod == api.AuthenticationMethodTLS {
+		return r.tls.GetPermissionChecker(ctx, req, entitlement, objectType)
+	}
+
+	return func(object Object) bool {
+		return rbacAllowed(grants, object, entitlement)
+	}, nil
+}
+
+// access returns the group members allowed to view objects of the given type in the project.
+func (r *RBAC) access(objectType ObjectType, projectName string) api.Access {
+	r.groupsMu.RLock()
+	defer r.groupsMu.RUnlock()
+
+	// Only the type and project of the object matter to the grants.
+	var elements []string
+	if objectType != ObjectTypeProject {
+		elements = []string{"*"}
+	}
+
+	object, err := NewObject(objectType, projectName, elements...)
+	if err != nil {
+		return nil
+	}
+
+	var access api.Access
+	for _, group := range r.groups {
+		if !rbacAllowed(group.Grants, object, EntitlementCanView) {
+			continue
+		}
+
+		for authenticationMethod, identifiers := range group.Identities {
+			for _, identifier := range identifiers {
+				access = append(access, api.AccessEntry{
+					Identifier: identifier,
+					Role:       group.Name,
+					Provider:   authenticationMethod,
+				})
+			}
+		}
+	}
+
+	return access
+}
+
+// tlsAccess filters out the certificates which belong to a group from the access list of the TLS driver.
+func (r *RBAC) tlsAccess(access *api.Access) api.Access {
+	if access == nil {
+		return nil
+	}
+
+	var result api.Access
+	for _, entry := range *access {
+		_, member := r.identityGrants(api.AuthenticationMethodTLS, entry.Identifier, nil)
+		if member {
+			continue
+		}
+
+		result = append(result, entry)
+	}
+
+	return result
+}
+
+// GetInstanceAccess returns the list of entities who have access to the instance.
+func (r *RBAC) GetInstanceAccess(ctx context.Context, projectName string, instanceName string) (*api.Access, error) {
+	tlsAccess, err := r.tls.GetInstanceAccess(ctx, projectName, instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	access := append(r.tlsAccess(tlsAccess), r.access(ObjectTypeInstance, projectName)...)
+	return &access, nil
+}
+
+// GetProjectAccess returns the list of entities who have access to the project.
+func (r *RBAC) GetProjectAccess(ctx context.Context, projectName string) (*api.Access, error) {
+	tlsAccess, err := r.tls.GetProjectAccess(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	access := append(r.tlsAccess(tlsAccess), r.access(ObjectTypeProject, projectName)...)
+	return &access, nil
+}
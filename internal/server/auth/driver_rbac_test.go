@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBACAllowed(t *testing.T) {
+	grants := []RBACGrant{
+		{ObjectType: ObjectTypeInstance, Entitlement: EntitlementCanExec, Project: "foo"},
+		{ObjectType: ObjectTypeProfile, Entitlement: EntitlementCanEdit},
+		{ObjectType: ObjectTypeCertificate, Entitlement: EntitlementCanView, Project: "foo"},
+	}
+
+	cases := []struct {
+		object      Object
+		entitlement Entitlement
+		allowed     bool
+	}{
+		// Grants limited to a project only apply within it.
+		{ObjectInstance("foo", "c1"), EntitlementCanExec, true},
+		{ObjectInstance("bar", "c1"), EntitlementCanExec, false},
+		{ObjectInstance("foo", "c1"), EntitlementCanEdit, false},
+
+		// Editing implies viewing.
+		{ObjectProfile("bar", "default"), EntitlementCanEdit, true},
+		{ObjectProfile("bar", "default"), EntitlementCanView, true},
+
+		// Any grant within a project allows viewing it.
+		{ObjectProject("foo"), EntitlementCanView, true},
+		{ObjectProject("bar"), EntitlementCanView, true},
+		{ObjectProject("foo"), EntitlementCanEdit, false},
+
+		// Server level objects aren't granted by grants limited to a project.
+		{ObjectCertificate("abcdef"), EntitlementCanView, false},
+
+		// Some entitlements are granted to all authenticated identities.
+		{ObjectServer(), EntitlementCanView, true},
+		{ObjectServer(), EntitlementCanEdit, false},
+		{ObjectStoragePool("local"), EntitlementCanView, true},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.allowed, rbacAllowed(grants, c.object, c.entitlement), "%s %s", c.entitlement, c.object)
+	}
+}
+
+// Grants on the server or on a project don't extend to other entity types.
+func TestRBACAllowedNoCascade(t *testing.T) {
+	admin := []RBACGrant{{ObjectType: ObjectTypeServer, Entitlement: EntitlementCanEdit}}
+	assert.True(t, rbacAllowed(admin, ObjectServer(), EntitlementCanEdit))
+	assert.False(t, rbacAllowed(admin, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.False(t, rbacAllowed(admin, ObjectCertificate("abcdef"), EntitlementCanEdit))
+
+	operator := []RBACGrant{{ObjectType: ObjectTypeProject, Entitlement: EntitlementCanEdit, Project: "foo"}}
+	assert.True(t, rbacAllowed(operator, ObjectProject("foo"), EntitlementCanEdit))
+	assert.False(t, rbacAllowed(operator, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.False(t, rbacAllowed(operator, ObjectInstance("foo", "c1"), EntitlementCanEdit))
+	assert.False(t, rbacAllowed(operator, ObjectProject("bar"), EntitlementCanEdit))
+
+	viewer := []RBACGrant{{ObjectType: ObjectTypeProject, Entitlement: EntitlementCanView}}
+	assert.True(t, rbacAllowed(viewer, ObjectProject("foo"), EntitlementCanView))
+	assert.False(t, rbacAllowed(viewer, ObjectInstance("foo", "c1"), EntitlementCanView))
+}
+
+// Wildcard grants apply to the objects of all types.
+func TestRBACAllowedWildcard(t *testing.T) {
+	admin := []RBACGrant{{ObjectType: ObjectTypeAll, Entitlement: EntitlementCanEdit}}
+	assert.True(t, rbacAllowed(admin, ObjectServer(), EntitlementCanEdit))
+	assert.True(t, rbacAllowed(admin, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.True(t, rbacAllowed(admin, ObjectCertificate("abcdef"), EntitlementCanView))
+
+	projectAdmin := []RBACGrant{{ObjectType: ObjectTypeAll, Entitlement: EntitlementCanEdit, Project: "foo"}}
+	assert.True(t, rbacAllowed(projectAdmin, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.True(t, rbacAllowed(projectAdmin, ObjectProject("foo"), EntitlementCanCreateInstances))
+	assert.False(t, rbacAllowed(projectAdmin, ObjectInstance("bar", "c1"), EntitlementCanExec))
+	assert.False(t, rbacAllowed(projectAdmin, ObjectServer(), EntitlementCanEdit))
+
+	viewer := []RBACGrant{{ObjectType: ObjectTypeAll, Entitlement: EntitlementCanView}}
+	assert.True(t, rbacAllowed(viewer, ObjectInstance("foo", "c1"), EntitlementCanView))
+	assert.True(t, rbacAllowed(viewer, ObjectCertificate("abcdef"), EntitlementCanView))
+	assert.False(t, rbacAllowed(viewer, ObjectInstance("foo", "c1"), EntitlementCanEdit))
+	assert.False(t, rbacAllowed(viewer, ObjectInstance("foo", "c1"), EntitlementCanExec))
+}
+
+func TestRBACGroupHasMember(t *testing.T) {
+	group := RBACGroup{
+		Identities:             map[string][]string{"oidc": {"jane@example.com"}},
+		IdentityProviderGroups: []string{"admins"},
+	}
+
+	assert.True(t, group.hasMember("oidc", "jane@example.com", nil))
+	assert.True(t, group.hasMember("oidc", "john@example.com", []string{"users", "admins"}))
+	assert.False(t, group.hasMember("oidc", "john@example.com", []string{"users"}))
+	assert.False(t, group.hasMember("tls", "abcdef", []string{"admins"}))
+}
+
+func TestValidateEntitlement(t *testing.T) {
+	assert.NoError(t, ValidateEntitlement(ObjectTypeInstance, EntitlementCanExec))
+	assert.Error(t, ValidateEntitlement(ObjectTypeProfile, EntitlementCanExec))
+	assert.Error(t, ValidateEntitlement(ObjectType("foo"), EntitlementCanView))
+	assert.NoError(t, ValidateEntitlement(ObjectTypeAll, EntitlementCanEdit))
+	assert.Error(t, ValidateEntitlement(ObjectTypeAll, EntitlementCanExec))
+}
@@ -26,6 +26,15 @@ func (s *Scriptlet) CheckPermission(ctx context.Context, r *http.Request, object
 		return nil
 	}
 
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		if !tokenAllowed(details.token, object, entitlement) {
+			return api.StatusErrorf(http.StatusForbidden, "API token doesn't have entitlement %q on object %q", entitlement, object)
+		}
+
+		return nil
+	}
+
 	authorized, err := authScriptlet.AuthorizationRun(logger.Log, details.actualDetails(), object.String(), string(entitlement))
 	if err != nil {
 		return api.StatusErrorf(http.StatusForbidden, "Authorization scriptlet execution failed with error: %v", err)
@@ -60,6 +69,13 @@ func (s *Scriptlet) GetPermissionChecker(ctx context.Context, r *http.Request, e
 		return allowFunc(true), nil
 	}
 
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		return func(object Object) bool {
+			return tokenAllowed(details.token, object, entitlement)
+		}, nil
+	}
+
 	permissionChecker := func(o Object) bool {
 		authorized, err := authScriptlet.AuthorizationRun(logger.Log, details.actualDetails(), o.String(), string(entitlement))
 		if err != nil {
@@ -39,6 +39,15 @@ func (t *TLS) CheckPermission(ctx context.Context, r *http.Request, object Objec
 		return nil
 	}
 
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		if !tokenAllowed(details.token, object, entitlement) {
+			return api.StatusErrorf(http.StatusForbidden, "API token doesn't have entitlement %q on object %q", entitlement, object)
+		}
+
+		return nil
+	}
+
 	authenticationProtocol := details.authenticationProtocol()
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
@@ -117,6 +126,13 @@ func (t *TLS) GetPermissionChecker(ctx context.Context, r *http.Request, entitle
 		return allowFunc(true), nil
 	}
 
+	// API tokens are limited to their own scope.
+	if details.token != nil {
+		return func(object Object) bool {
+			return tokenAllowed(details.token, object, entitlement)
+		}, nil
+	}
+
 	authenticationProtocol := details.authenticationProtocol()
 	if authenticationProtocol != api.AuthenticationMethodTLS {
 		t.logger.Warn("Authentication protocol is not compatible with authorization driver", logger.Ctx{"protocol": authenticationProtocol})
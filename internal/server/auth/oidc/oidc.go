@@ -2,10 +2,13 @@ package oidc
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,12 +25,24 @@ import (
 type Verifier struct {
 	accessTokenVerifier *op.AccessTokenVerifier
 
-	clientID  string
-	issuer    string
-	scopes    []string
-	audience  string
-	claim     string
-	cookieKey []byte
+	clientID    string
+	issuer      string
+	scopes      []string
+	audience    string
+	claim       string
+	groupsClaim string
+	cookieKey   []byte
+
+	// tokens caches the identity of verified access tokens until they expire.
+	tokensMu sync.Mutex
+	tokens   map[string]verifiedToken
+}
+
+// verifiedToken holds the identity extracted from a verified access token.
+type verifiedToken struct {
+	username string
+	groups   []string
+	expiry   time.Time
 }
 
 // AuthError represents an authentication error.
@@ -43,8 +58,8 @@ func (e AuthError) Unwrap() error {
 	return e.Err
 }
 
-// Auth extracts the token, validates it and returns the user information.
-func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, error) {
+// Auth extracts the token, validates it and returns the user name along with the identity provider groups of the user.
+func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Request) (string, []string, error) {
 	var token string
 
 	auth := r.Header.Get("Authorization")
@@ -57,7 +72,7 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// Both returned errors contain information which are needed for the client to authenticate.
 		parts := strings.Split(auth, "Bearer ")
 		if len(parts) != 2 {
-			return "", &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
+			return "", nil, &AuthError{fmt.Errorf("Bad authorization token, expected a Bearer token")}
 		}
 
 		token = parts[1]
@@ -65,18 +80,23 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// When not using a Bearer token, fetch the equivalent from a cookie and move on with it.
 		cookie, err := r.Cookie("oidc_access")
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		token = cookie.Value
 	}
 
+	cached, ok := o.cachedToken(token)
+	if ok {
+		return cached.username, cached.groups, nil
+	}
+
 	if o.accessTokenVerifier == nil {
 		var err error
 
 		o.accessTokenVerifier, err = getAccessTokenVerifier(o.issuer)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 	}
 
@@ -85,25 +105,26 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		// See if we can refresh the access token.
 		cookie, cookieErr := r.Cookie("oidc_refresh")
 		if cookieErr != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Get the provider.
 		provider, err := o.getProvider(r)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Attempt the refresh.
 		tokens, err := rp.RefreshTokens[*oidc.IDTokenClaims](context.TODO(), provider, cookie.Value, "", "")
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// Validate the refreshed token.
-		claims, err = o.VerifyAccessToken(ctx, tokens.AccessToken)
+		token = tokens.AccessToken
+		claims, err = o.VerifyAccessToken(ctx, token)
 		if err != nil {
-			return "", &AuthError{err}
+			return "", nil, &AuthError{err}
 		}
 
 		// If we have a ResponseWriter, refresh the cookies.
@@ -136,6 +157,23 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		}
 	}
 
+	username, err := o.username(claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	groups, err := o.groups(claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	o.cacheToken(token, verifiedToken{username: username, groups: groups, expiry: claims.GetExpiration()})
+
+	return username, groups, nil
+}
+
+// username returns the user name from the claims of an access token.
+func (o *Verifier) username(claims *oidc.AccessTokenClaims) (string, error) {
 	if o.claim != "" {
 		claim := claims.Claims[o.claim]
 		username, ok := claim.(string)
@@ -154,6 +192,77 @@ func (o *Verifier) Auth(ctx context.Context, w http.ResponseWriter, r *http.Requ
 	return claims.Subject, nil
 }
 
+// groups returns the identity provider groups from the claims of an access token.
+func (o *Verifier) groups(claims *oidc.AccessTokenClaims) ([]string, error) {
+	if o.groupsClaim == "" {
+		return nil, nil
+	}
+
+	switch claim := claims.Claims[o.groupsClaim].(type) {
+	case nil:
+		// Users which don't belong to any group may not get the claim at all.
+		return nil, nil
+	case string:
+		return []string{claim}, nil
+	case []any:
+		groups := make([]string, 0, len(claim))
+		for _, value := range claim {
+			group, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("OIDC claim %q isn't a list of strings", o.groupsClaim)
+			}
+
+			groups = append(groups, group)
+		}
+
+		return groups, nil
+	default:
+		return nil, fmt.Errorf("OIDC claim %q isn't a list of strings", o.groupsClaim)
+	}
+}
+
+// cachedToken returns the identity of a previously verified access token which hasn't expired yet.
+func (o *Verifier) cachedToken(token string) (verifiedToken, bool) {
+	o.tokensMu.Lock()
+	defer o.tokensMu.Unlock()
+
+	cached, ok := o.tokens[tokenKey(token)]
+	if !ok || !time.Now().Before(cached.expiry) {
+		return verifiedToken{}, false
+	}
+
+	return cached, true
+}
+
+// cacheToken records the identity of a verified access token, dropping the expired ones.
+func (o *Verifier) cacheToken(token string, verified verifiedToken) {
+	o.tokensMu.Lock()
+	defer o.tokensMu.Unlock()
+
+	now := time.Now()
+	for key, cached := range o.tokens {
+		if !now.Before(cached.expiry) {
+			delete(o.tokens, key)
+		}
+	}
+
+	if !now.Before(verified.expiry) {
+		return
+	}
+
+	if o.tokens == nil {
+		o.tokens = map[string]verifiedToken{}
+	}
+
+	o.tokens[tokenKey(token)] = verified
+}
+
+// tokenKey returns the key under which an access token is cached, avoiding keeping the tokens themselves in memory.
+func tokenKey(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
 func (o *Verifier) Login(w http.ResponseWriter, r *http.Request) {
 	// Get the provider.
 	provider, err := o.getProvider(r)
@@ -351,14 +460,14 @@ func getAccessTokenVerifier(issuer string) (*op.AccessTokenVerifier, error) {
 }
 
 // NewVerifier returns a Verifier.
-func NewVerifier(issuer string, clientid string, scope string, audience string, claim string) (*Verifier, error) {
+func NewVerifier(issuer string, clientid string, scope string, audience string, claim string, groupsClaim string) (*Verifier, error) {
 	cookieKey, err := uuid.New().MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create UUID: %w", err)
 	}
 
 	scopes := util.SplitNTrimSpace(scope, ",", -1, false)
-	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim}
+	verifier := &Verifier{issuer: issuer, clientID: clientid, scopes: scopes, audience: audience, cookieKey: cookieKey, claim: claim, groupsClaim: groupsClaim}
 	verifier.accessTokenVerifier, _ = getAccessTokenVerifier(issuer)
 
 	return verifier, nil
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"slices"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// tokenAllowed returns whether the scope of an API token allows the entitlement on the object.
+// API tokens are authorized by their own scope, whichever authorization driver is in use.
+func tokenAllowed(token *api.AuthToken, object Object, entitlement Entitlement) bool {
+	if token.Restricted {
+		// Outside of their projects, restricted tokens only get what's available to all authenticated identities.
+		if !objectValidators[object.Type()].requireProject || !slices.Contains(token.Projects, object.Project()) {
+			return rbacAllowed(nil, object, entitlement)
+		}
+
+		// Like restricted certificates, restricted tokens can't change the configuration of their projects.
+		if object.Type() == ObjectTypeProject && entitlement == EntitlementCanEdit {
+			return false
+		}
+	}
+
+	// Tokens without permissions aren't limited any further.
+	if len(token.Permissions) == 0 {
+		return true
+	}
+
+	grants := make([]RBACGrant, 0, len(token.Permissions))
+	for _, permission := range token.Permissions {
+		grants = append(grants, RBACGrant{ObjectType: ObjectType(permission.EntityType), Entitlement: Entitlement(permission.Entitlement)})
+	}
+
+	return rbacAllowed(grants, object, entitlement)
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// API tokens are limited to their projects and permissions.
+func TestTokenAllowed(t *testing.T) {
+	unrestricted := &api.AuthToken{}
+	assert.True(t, tokenAllowed(unrestricted, ObjectServer(), EntitlementCanEdit))
+	assert.True(t, tokenAllowed(unrestricted, ObjectInstance("foo", "c1"), EntitlementCanExec))
+
+	restricted := &api.AuthToken{Restricted: true, Projects: []string{"foo"}}
+	assert.True(t, tokenAllowed(restricted, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.True(t, tokenAllowed(restricted, ObjectProject("foo"), EntitlementCanCreateInstances))
+	assert.False(t, tokenAllowed(restricted, ObjectProject("foo"), EntitlementCanEdit))
+	assert.False(t, tokenAllowed(restricted, ObjectInstance("bar", "c1"), EntitlementCanView))
+	assert.False(t, tokenAllowed(restricted, ObjectServer(), EntitlementCanEdit))
+	assert.True(t, tokenAllowed(restricted, ObjectServer(), EntitlementCanView))
+
+	scoped := &api.AuthToken{Restricted: true, Projects: []string{"foo"}, Permissions: []api.AuthPermission{{EntityType: "instance", Entitlement: "can_update_state"}}}
+	assert.True(t, tokenAllowed(scoped, ObjectInstance("foo", "c1"), EntitlementCanUpdateState))
+	assert.True(t, tokenAllowed(scoped, ObjectProject("foo"), EntitlementCanView))
+	assert.False(t, tokenAllowed(scoped, ObjectInstance("foo", "c1"), EntitlementCanExec))
+	assert.False(t, tokenAllowed(scoped, ObjectInstance("bar", "c1"), EntitlementCanUpdateState))
+}
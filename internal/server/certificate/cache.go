@@ -3,6 +3,7 @@ package certificate
 import (
 	"crypto/x509"
 	"sync"
+	"time"
 )
 
 // Cache represents an thread-safe in-memory cache of the certificates in the database.
@@ -14,6 +15,10 @@ type Cache struct {
 	// If a certificate fingerprint is present in certificates, but not present in projects, it means the certificate is
 	// not restricted.
 	projects map[string][]string
+
+	// expiries is a map of certificate fingerprint to the date the trust relationship expires.
+	// Certificates not present in expiries never expire.
+	expiries map[string]time.Time
 	mu       sync.RWMutex
 }
 
@@ -26,6 +31,14 @@ func (c *Cache) SetCertificatesAndProjects(certificates map[Type]map[string]x509
 	c.projects = projects
 }
 
+// SetExpiries sets the trust expiry dates on the Cache.
+func (c *Cache) SetExpiries(expiries map[string]time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expiries = expiries
+}
+
 // SetCertificates sets the certificates on the Cache.
 func (c *Cache) SetCertificates(certificates map[Type]map[string]x509.Certificate) {
 	c.mu.Lock()
@@ -95,3 +108,16 @@ func (c *Cache) GetProjects() map[string][]string {
 
 	return projects
 }
+
+// GetExpiries returns a read-only copy of the trust expiry map.
+func (c *Cache) GetExpiries() map[string]time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	expiries := make(map[string]time.Time, len(c.expiries))
+	for f, expiry := range c.expiries {
+		expiries[f] = expiry
+	}
+
+	return expiries
+}
@@ -0,0 +1,345 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/instance/drivers/qemudefault"
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/internal/server/resources"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/units"
+)
+
+// resourcesCacheExpiry is how long the resources of a cluster member are cached for.
+const resourcesCacheExpiry = 5 * time.Minute
+
+// Limits represents the capacity limits of a cluster member.
+type Limits struct {
+	CPUOvercommit    float64
+	MemoryOvercommit float64
+	Instances        int64
+}
+
+// Merge applies the stricter limits of another set of limits.
+func (l *Limits) Merge(other *Limits) {
+	if other.CPUOvercommit > 0 && (l.CPUOvercommit == 0 || other.CPUOvercommit < l.CPUOvercommit) {
+		l.CPUOvercommit = other.CPUOvercommit
+	}
+
+	if other.MemoryOvercommit > 0 && (l.MemoryOvercommit == 0 || other.MemoryOvercommit < l.MemoryOvercommit) {
+		l.MemoryOvercommit = other.MemoryOvercommit
+	}
+
+	if other.Instances > 0 && (l.Instances == 0 || other.Instances < l.Instances) {
+		l.Instances = other.Instances
+	}
+}
+
+// Resources represents the number of CPU threads and the memory of a cluster member.
+type Resources struct {
+	CPU    int64
+	Memory int64
+}
+
+type cachedResources struct {
+	resources Resources
+	expiry    time.Time
+}
+
+var resourcesCache = map[string]cachedResources{}
+var resourcesCacheMu sync.Mutex
+
+// MemberResources returns the resources of a cluster member, calling fetch when they aren't cached.
+func MemberResources(name string, fetch func() (Resources, error)) (Resources, error) {
+	resourcesCacheMu.Lock()
+	cached, ok := resourcesCache[name]
+	resourcesCacheMu.Unlock()
+
+	if ok && time.Now().Before(cached.expiry) {
+		return cached.resources, nil
+	}
+
+	res, err := fetch()
+	if err != nil {
+		return Resources{}, err
+	}
+
+	resourcesCacheMu.Lock()
+	resourcesCache[name] = cachedResources{resources: res, expiry: time.Now().Add(resourcesCacheExpiry)}
+	resourcesCacheMu.Unlock()
+
+	return res, nil
+}
+
+// LocalResources returns the resources of the local server.
+func LocalResources() (Resources, error) {
+	cpu, err := resources.GetCPU()
+	if err != nil {
+		return Resources{}, err
+	}
+
+	memory, err := resources.GetMemory()
+	if err != nil {
+		return Resources{}, err
+	}
+
+	return Resources{CPU: int64(cpu.Total), Memory: int64(memory.Total)}, nil
+}
+
+// ParseLimits returns the capacity limits set in a cluster group configuration or nil if there are none.
+func ParseLimits(config map[string]string) (*Limits, error) {
+	var err error
+	limits := Limits{}
+
+	if config["limits.cpu.overcommit"] != "" {
+		limits.CPUOvercommit, err = strconv.ParseFloat(config["limits.cpu.overcommit"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CPU overcommit ratio: %w", err)
+		}
+	}
+
+	if config["limits.memory.overcommit"] != "" {
+		limits.MemoryOvercommit, err = strconv.ParseFloat(config["limits.memory.overcommit"], 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid memory overcommit ratio: %w", err)
+		}
+	}
+
+	if config["limits.instances"] != "" {
+		limits.Instances, err = strconv.ParseInt(config["limits.instances"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid instance limit: %w", err)
+		}
+	}
+
+	if limits == (Limits{}) {
+		return nil, nil
+	}
+
+	return &limits, nil
+}
+
+// MemberLimits returns the capacity limits of the cluster members in a group with limits.
+// Members of several groups get the strictest limits of all of them.
+func MemberLimits(ctx context.Context, tx *db.ClusterTx) (map[string]*Limits, error) {
+	groups, err := dbCluster.GetClusterGroups(ctx, tx.Tx())
+	if err != nil {
+		return nil, err
+	}
+
+	memberLimits := map[string]*Limits{}
+	for _, group := range groups {
+		config, err := dbCluster.GetClusterGroupConfig(ctx, tx.Tx(), group.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		groupLimits, err := ParseLimits(config)
+		if err != nil {
+			return nil, fmt.Errorf("Cluster group %q: %w", group.Name, err)
+		}
+
+		if groupLimits == nil {
+			continue
+		}
+
+		nodeClusterGroups, err := dbCluster.GetNodeClusterGroups(ctx, tx.Tx(), dbCluster.NodeClusterGroupFilter{GroupID: &group.ID})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range nodeClusterGroups {
+			limits, ok := memberLimits[node.Node]
+			if !ok {
+				limits = &Limits{}
+				memberLimits[node.Node] = limits
+			}
+
+			limits.Merge(groupLimits)
+		}
+	}
+
+	return memberLimits, nil
+}
+
+// InstanceAllocation returns the CPUs and memory allocated to an instance.
+// A value of -1 is returned when the instance isn't limited.
+func InstanceAllocation(instanceType instancetype.Type, config map[string]string, memoryTotal int64) (int64, int64, error) {
+	var err error
+
+	cpu := int64(-1)
+	memory := int64(-1)
+
+	// Virtual machines always get a default allocation.
+	if instanceType == instancetype.VM {
+		cpu = qemudefault.CPUCores
+
+		memory, err = units.ParseByteSizeString(qemudefault.MemSize)
+		if err != nil {
+			return -1, -1, err
+		}
+	}
+
+	cpuLimit := config["limits.cpu"]
+	if cpuLimit != "" {
+		if strings.Contains(cpuLimit, ",") || strings.Contains(cpuLimit, "-") {
+			cpus, err := resources.ParseCpuset(cpuLimit)
+			if err != nil {
+				return -1, -1, fmt.Errorf("Invalid limits.cpu: %w", err)
+			}
+
+			cpu = int64(len(cpus))
+		} else {
+			cpu, err = strconv.ParseInt(cpuLimit, 10, 64)
+			if err != nil {
+				return -1, -1, fmt.Errorf("Invalid limits.cpu: %w", err)
+			}
+		}
+	}
+
+	memoryLimit := config["limits.memory"]
+	if memoryLimit != "" {
+		if strings.HasSuffix(memoryLimit, "%") {
+			percent, err := strconv.ParseInt(strings.TrimSuffix(memoryLimit, "%"), 10, 64)
+			if err != nil {
+				return -1, -1, fmt.Errorf("Invalid limits.memory: %w", err)
+			}
+
+			memory = memoryTotal * percent / 100
+		} else {
+			memory, err = units.ParseByteSizeString(memoryLimit)
+			if err != nil {
+				return -1, -1, fmt.Errorf("Invalid limits.memory: %w", err)
+			}
+		}
+	}
+
+	return cpu, memory, nil
+}
+
+// Members returns the resource allocation of cluster members against their capacity limits, loading the instances
+// of all the members at once. The instance matching skipProject and skipName isn't accounted for.
+func Members(ctx context.Context, tx *db.ClusterTx, memberResources map[string]Resources, memberLimits map[string]*Limits, skipProject string, skipName string) (map[string]*api.ClusterGroupMemberCapacity, error) {
+	capacities := make(map[string]*api.ClusterGroupMemberCapacity, len(memberResources))
+	filters := make([]dbCluster.InstanceFilter, 0, len(memberResources))
+
+	for name, res := range memberResources {
+		limits, ok := memberLimits[name]
+		if !ok {
+			continue
+		}
+
+		capacity := &api.ClusterGroupMemberCapacity{
+			Member:         name,
+			CPUTotal:       res.CPU,
+			MemoryTotal:    res.Memory,
+			InstancesLimit: limits.Instances,
+		}
+
+		if limits.CPUOvercommit > 0 {
+			capacity.CPULimit = int64(float64(res.CPU) * limits.CPUOvercommit)
+		}
+
+		if limits.MemoryOvercommit > 0 {
+			capacity.MemoryLimit = int64(float64(res.Memory) * limits.MemoryOvercommit)
+		}
+
+		capacities[name] = capacity
+		filters = append(filters, dbCluster.InstanceFilter{Node: &name})
+	}
+
+	if len(filters) == 0 {
+		return capacities, nil
+	}
+
+	err := tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+		if inst.Project == skipProject && inst.Name == skipName {
+			return nil
+		}
+
+		capacity := capacities[inst.Node]
+
+		cpu, memory, err := InstanceAllocation(inst.Type, db.ExpandInstanceConfig(inst.Config, inst.Profiles), capacity.MemoryTotal)
+		if err != nil {
+			return fmt.Errorf("Failed getting allocation of instance %q in project %q: %w", inst.Name, inst.Project, err)
+		}
+
+		capacity.Instances++
+		capacity.CPUAllocated += max(cpu, 0)
+		capacity.MemoryAllocated += max(memory, 0)
+
+		return nil
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return capacities, nil
+}
+
+// Check checks whether a cluster member has enough capacity left for an instance.
+func Check(capacity *api.ClusterGroupMemberCapacity, instanceType instancetype.Type, config map[string]string) error {
+	cpu, memory, err := InstanceAllocation(instanceType, config, capacity.MemoryTotal)
+	if err != nil {
+		return err
+	}
+
+	if capacity.CPULimit > 0 {
+		if cpu < 0 {
+			return fmt.Errorf("Instances on cluster member %q must set \"limits.cpu\"", capacity.Member)
+		}
+
+		if capacity.CPUAllocated+cpu > capacity.CPULimit {
+			return fmt.Errorf("Cluster member %q doesn't have enough CPU capacity left (%d of %d allocated, %d requested)", capacity.Member, capacity.CPUAllocated, capacity.CPULimit, cpu)
+		}
+	}
+
+	if capacity.MemoryLimit > 0 {
+		if memory < 0 {
+			return fmt.Errorf("Instances on cluster member %q must set \"limits.memory\"", capacity.Member)
+		}
+
+		if capacity.MemoryAllocated+memory > capacity.MemoryLimit {
+			return fmt.Errorf("Cluster member %q doesn't have enough memory capacity left (%s of %s allocated, %s requested)", capacity.Member, units.GetByteSizeStringIEC(capacity.MemoryAllocated, 2), units.GetByteSizeStringIEC(capacity.MemoryLimit, 2), units.GetByteSizeStringIEC(memory, 2))
+		}
+	}
+
+	if capacity.InstancesLimit > 0 && capacity.Instances >= capacity.InstancesLimit {
+		return fmt.Errorf("Cluster member %q already has the maximum number of instances (%d)", capacity.Member, capacity.InstancesLimit)
+	}
+
+	return nil
+}
+
+// CheckCreated checks that the local cluster member still has enough capacity for an instance it just recorded.
+// It's called from the transaction creating the instance so that concurrent placements on the same member can't
+// exceed its limits.
+func CheckCreated(ctx context.Context, tx *db.ClusterTx, member string, projectName string, instanceName string, instanceType instancetype.Type, config map[string]string) error {
+	memberLimits, err := MemberLimits(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("Failed loading cluster group limits: %w", err)
+	}
+
+	if memberLimits[member] == nil {
+		return nil
+	}
+
+	res, err := MemberResources(member, LocalResources)
+	if err != nil {
+		return fmt.Errorf("Failed getting resources of cluster member %q: %w", member, err)
+	}
+
+	capacities, err := Members(ctx, tx, map[string]Resources{member: res}, memberLimits, projectName, instanceName)
+	if err != nil {
+		return err
+	}
+
+	return Check(capacities[member], instanceType, config)
+}
@@ -0,0 +1,129 @@
+package capacity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]string
+		expected *Limits
+		err      bool
+	}{
+		{name: "No limits", config: map[string]string{"user.foo": "bar"}, expected: nil},
+		{name: "CPU overcommit", config: map[string]string{"limits.cpu.overcommit": "2.5"}, expected: &Limits{CPUOvercommit: 2.5}},
+		{name: "All limits", config: map[string]string{"limits.cpu.overcommit": "4", "limits.memory.overcommit": "1", "limits.instances": "20"}, expected: &Limits{CPUOvercommit: 4, MemoryOvercommit: 1, Instances: 20}},
+		{name: "Invalid CPU overcommit", config: map[string]string{"limits.cpu.overcommit": "lots"}, err: true},
+		{name: "Invalid memory overcommit", config: map[string]string{"limits.memory.overcommit": "1.5x"}, err: true},
+		{name: "Invalid instance limit", config: map[string]string{"limits.instances": "1.5"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits, err := ParseLimits(tt.config)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, limits)
+		})
+	}
+}
+
+func TestLimitsMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   Limits
+		other    Limits
+		expected Limits
+	}{
+		{name: "Into empty", limits: Limits{}, other: Limits{CPUOvercommit: 2, Instances: 10}, expected: Limits{CPUOvercommit: 2, Instances: 10}},
+		{name: "Stricter wins", limits: Limits{CPUOvercommit: 2, MemoryOvercommit: 1, Instances: 10}, other: Limits{CPUOvercommit: 4, MemoryOvercommit: 0.5, Instances: 5}, expected: Limits{CPUOvercommit: 2, MemoryOvercommit: 0.5, Instances: 5}},
+		{name: "Unset doesn't remove", limits: Limits{CPUOvercommit: 2, Instances: 10}, other: Limits{MemoryOvercommit: 1}, expected: Limits{CPUOvercommit: 2, MemoryOvercommit: 1, Instances: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.limits.Merge(&tt.other)
+			assert.Equal(t, tt.expected, tt.limits)
+		})
+	}
+}
+
+func TestInstanceAllocation(t *testing.T) {
+	const memoryTotal = 64 * 1024 * 1024 * 1024
+
+	tests := []struct {
+		name           string
+		instanceType   instancetype.Type
+		config         map[string]string
+		expectedCPU    int64
+		expectedMemory int64
+		err            bool
+	}{
+		{name: "Unlimited container", instanceType: instancetype.Container, config: map[string]string{}, expectedCPU: -1, expectedMemory: -1},
+		{name: "Container with CPU count", instanceType: instancetype.Container, config: map[string]string{"limits.cpu": "4"}, expectedCPU: 4, expectedMemory: -1},
+		{name: "Container with cpuset", instanceType: instancetype.Container, config: map[string]string{"limits.cpu": "0-3,8,10-11"}, expectedCPU: 7, expectedMemory: -1},
+		{name: "Container with single CPU pinned", instanceType: instancetype.Container, config: map[string]string{"limits.cpu": "2-2"}, expectedCPU: 1, expectedMemory: -1},
+		{name: "Container with memory size", instanceType: instancetype.Container, config: map[string]string{"limits.memory": "2GiB"}, expectedCPU: -1, expectedMemory: 2 * 1024 * 1024 * 1024},
+		{name: "Container with memory percentage", instanceType: instancetype.Container, config: map[string]string{"limits.memory": "25%"}, expectedCPU: -1, expectedMemory: 16 * 1024 * 1024 * 1024},
+		{name: "VM defaults", instanceType: instancetype.VM, config: map[string]string{}, expectedCPU: 1, expectedMemory: 1024 * 1024 * 1024},
+		{name: "VM with limits", instanceType: instancetype.VM, config: map[string]string{"limits.cpu": "8", "limits.memory": "50%"}, expectedCPU: 8, expectedMemory: 32 * 1024 * 1024 * 1024},
+		{name: "Invalid CPU limit", instanceType: instancetype.Container, config: map[string]string{"limits.cpu": "many"}, err: true},
+		{name: "Invalid cpuset", instanceType: instancetype.Container, config: map[string]string{"limits.cpu": "0-x"}, err: true},
+		{name: "Invalid memory percentage", instanceType: instancetype.Container, config: map[string]string{"limits.memory": "half%"}, err: true},
+		{name: "Invalid memory size", instanceType: instancetype.Container, config: map[string]string{"limits.memory": "2 potatoes"}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu, memory, err := InstanceAllocation(tt.instanceType, tt.config, memoryTotal)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedCPU, cpu)
+			assert.Equal(t, tt.expectedMemory, memory)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	capacity := &api.ClusterGroupMemberCapacity{
+		Member:          "server01",
+		CPUAllocated:    12,
+		CPULimit:        16,
+		MemoryAllocated: 8 * 1024 * 1024 * 1024,
+		MemoryTotal:     16 * 1024 * 1024 * 1024,
+		MemoryLimit:     16 * 1024 * 1024 * 1024,
+		Instances:       4,
+		InstancesLimit:  5,
+	}
+
+	assert.NoError(t, Check(capacity, instancetype.Container, map[string]string{"limits.cpu": "4", "limits.memory": "50%"}))
+
+	// Instances must be limited on members with overcommit limits.
+	assert.Error(t, Check(capacity, instancetype.Container, map[string]string{"limits.memory": "1GiB"}))
+	assert.Error(t, Check(capacity, instancetype.Container, map[string]string{"limits.cpu": "1"}))
+
+	// The allocation can't exceed the limits.
+	assert.Error(t, Check(capacity, instancetype.Container, map[string]string{"limits.cpu": "5", "limits.memory": "1GiB"}))
+	assert.Error(t, Check(capacity, instancetype.Container, map[string]string{"limits.cpu": "1", "limits.memory": "9GiB"}))
+
+	// Virtual machines are accounted with their default allocation.
+	assert.NoError(t, Check(capacity, instancetype.VM, nil))
+
+	capacity.Instances = 5
+	assert.Error(t, Check(capacity, instancetype.Container, map[string]string{"limits.cpu": "1", "limits.memory": "1GiB"}))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/config"
 	"github.com/lxc/incus/v6/internal/server/db"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/units"
 	"github.com/lxc/incus/v6/shared/validate"
 )
 
@@ -80,6 +81,11 @@ func (c *Config) TrustCACertificates() bool {
 	return c.m.GetBool("core.trust_ca_certificates")
 }
 
+// UsageRetentionDays returns the number of days after which the project usage records are removed (0 to keep them).
+func (c *Config) UsageRetentionDays() int64 {
+	return c.m.GetInt64("core.usage_retention")
+}
+
 // ProxyHTTPS returns the configured HTTPS proxy, if any.
 func (c *Config) ProxyHTTPS() string {
 	return c.m.GetString("core.proxy_https")
@@ -146,6 +152,26 @@ func (c *Config) ClusterRebalanceThreshold() int64 {
 	return c.m.GetInt64("cluster.rebalance.threshold")
 }
 
+// ClusterRebalanceColdMigration returns whether instances which can't be live-migrated may be moved with a restart.
+func (c *Config) ClusterRebalanceColdMigration() bool {
+	return c.m.GetBool("cluster.rebalance.cold_migration")
+}
+
+// ClusterRebalanceScope returns whether load is balanced across the whole cluster or within each cluster group.
+func (c *Config) ClusterRebalanceScope() string {
+	return c.m.GetString("cluster.rebalance.scope")
+}
+
+// ClusterRebalanceScriptlet returns the cluster re-balancing scriptlet.
+func (c *Config) ClusterRebalanceScriptlet() string {
+	return c.m.GetString("cluster.rebalance.scriptlet")
+}
+
+// ClusterRebalanceWeight returns the weight of the given resource (cpu, memory, disk or network) in the load score.
+func (c *Config) ClusterRebalanceWeight(resource string) int64 {
+	return c.m.GetInt64("cluster.rebalance.weight." + resource)
+}
+
 // NetworkOVNIntegrationBridge returns the integration OVS bridge to use for OVN networks.
 func (c *Config) NetworkOVNIntegrationBridge() string {
 	return c.m.GetString("network.ovn.integration_bridge")
@@ -208,6 +234,11 @@ func (c *Config) InstancesNICHostname() string {
 	return c.m.GetString("instances.nic.host_name")
 }
 
+// InstancesAdmissionScriptlet returns the instances admission scriptlet source code.
+func (c *Config) InstancesAdmissionScriptlet() string {
+	return c.m.GetString("instances.admission.scriptlet")
+}
+
 // InstancesPlacementScriptlet returns the instances placement scriptlet source code.
 func (c *Config) InstancesPlacementScriptlet() string {
 	return c.m.GetString("instances.placement.scriptlet")
@@ -218,6 +249,19 @@ func (c *Config) AuthorizationScriptlet() string {
 	return c.m.GetString("authorization.scriptlet")
 }
 
+// Audit returns whether audit records are written to a local file and to syslog, along with the rotation settings of the file.
+func (c *Config) Audit() (bool, int64, int64, bool) {
+	// Validated above.
+	maxSize, _ := units.ParseByteSizeString(c.m.GetString("audit.file.max_size"))
+
+	return c.m.GetBool("audit.file"), maxSize, c.m.GetInt64("audit.file.max_files"), c.m.GetBool("audit.syslog")
+}
+
+// AuthorizationRBAC returns whether the built-in role-based access control is enabled.
+func (c *Config) AuthorizationRBAC() bool {
+	return c.m.GetBool("authorization.rbac")
+}
+
 // InstancesLXCFSPerInstance returns whether LXCFS should be run on a per-instance basis.
 func (c *Config) InstancesLXCFSPerInstance() bool {
 	return c.m.GetBool("instances.lxcfs.per_instance")
@@ -293,8 +337,8 @@ func (c *Config) RemoteTokenExpiry() string {
 }
 
 // OIDCServer returns all the OpenID Connect settings needed to connect to a server.
-func (c *Config) OIDCServer() (string, string, string, string, string) {
-	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim")
+func (c *Config) OIDCServer() (string, string, string, string, string, string) {
+	return c.m.GetString("oidc.issuer"), c.m.GetString("oidc.client.id"), c.m.GetString("oidc.scopes"), c.m.GetString("oidc.audience"), c.m.GetString("oidc.claim"), c.m.GetString("oidc.groups.claim")
 }
 
 // ClusterHealingThreshold returns the configured healing threshold, i.e. the
@@ -317,6 +361,11 @@ func (c *Config) ClusterHealingThreshold() time.Duration {
 	return healingThreshold
 }
 
+// ClusterHealingRequireFencing returns whether offline cluster members must be fenced before being healed.
+func (c *Config) ClusterHealingRequireFencing() bool {
+	return c.m.GetBool("cluster.healing_require_fencing")
+}
+
 // OpenFGA returns all OpenFGA settings need to interact with an OpenFGA server.
 func (c *Config) OpenFGA() (apiURL string, apiToken string, storeID string) {
 	return c.m.GetString("openfga.api.url"), c.m.GetString("openfga.api.token"), c.m.GetString("openfga.store.id")
@@ -442,6 +491,52 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Port and interface for HTTP server (used by HTTP-01)
 	"acme.http.port": {Default: ":80", Validator: validate.Optional(validate.IsListenAddress(true, true, false))},
 
+	// gendoc:generate(entity=server, group=audit, key=audit.file)
+	// When enabled, audit records are appended to `audit.log` in the Incus log directory.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to write audit records to a local file
+	"audit.file": {Type: config.Bool, Default: "false"},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.file.max_files)
+	//
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `10`
+	//  shortdesc: Number of rotated audit files to keep
+	"audit.file.max_files": {Type: config.Int64, Default: "10", Validator: validate.IsUint32},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.file.max_size)
+	// The file is rotated once it reaches this size.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `100MiB`
+	//  shortdesc: Maximum size of the audit file
+	"audit.file.max_size": {Default: "100MiB", Validator: validate.IsSize},
+
+	// gendoc:generate(entity=server, group=audit, key=audit.syslog)
+	//
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to send audit records to syslog
+	"audit.syslog": {Type: config.Bool, Default: "false"},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.rbac)
+	// When enabled, access is controlled by the groups and roles defined under `/1.0/auth`.
+	// Trusted TLS clients which aren't part of any group keep their regular access.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to use the built-in role-based access control
+	"authorization.rbac": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=authorization.scriptlet)
 	// When using scriptlet-based authorization, this option stores the scriptlet.
 	// ---
@@ -488,6 +583,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Threshold when to evacuate an offline cluster member
 	"cluster.healing_threshold": {Type: config.Int64, Default: "0"},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.healing_require_fencing)
+	// Whether to only heal offline cluster members which were successfully fenced.
+	// When disabled, members without fencing configuration are healed if they don't respond to ICMP packets.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to require fencing before healing
+	"cluster.healing_require_fencing": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.join_token_expiry)
 	//
 	// ---
@@ -526,6 +631,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Maximum number of instances to move during one re-balancing run
 	"cluster.rebalance.batch": {Type: config.Int64, Default: "1"},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.cold_migration)
+	// When enabled, instances which can't be live-migrated but whose `cluster.evacuate` mode allows a migration
+	// are moved by stopping them, migrating them and starting them again on the target.
+	// ---
+	//  type: bool
+	//  scope: global
+	//  defaultdesc: `false`
+	//  shortdesc: Whether to move instances which can't be live-migrated
+	"cluster.rebalance.cold_migration": {Type: config.Bool, Default: "false"},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.cooldown)
 	//
 	// ---
@@ -544,6 +659,25 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: How often (in minutes) to consider re-balancing things. 0 to disable (default)
 	"cluster.rebalance.interval": {Type: config.Int64, Default: "0"},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.scope)
+	// Possible values are `cluster` to balance the load across all cluster members with the same architecture
+	// and `group` to balance it separately within each cluster group.
+	// ---
+	//  type: string
+	//  scope: global
+	//  defaultdesc: `cluster`
+	//  shortdesc: Set of cluster members across which the load is balanced
+	"cluster.rebalance.scope": {Type: config.String, Default: "cluster", Validator: validate.Optional(validate.IsOneOf("cluster", "group"))},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.scriptlet)
+	// When using custom re-balancing logic, this option stores the scriptlet.
+	// See {ref}`clustering-rebalance-scriptlet` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Scriptlet deciding which instances to move when re-balancing
+	"cluster.rebalance.scriptlet": {Validator: validate.Optional(scriptletLoad.ClusterRebalanceValidate)},
+
 	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.threshold)
 	//
 	// ---
@@ -553,6 +687,42 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Percentage load difference between most and least busy server needed to trigger a migration
 	"cluster.rebalance.threshold": {Type: config.Int64, Default: "20", Validator: validate.Optional(rebalanceThresholdValidator)},
 
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.cpu)
+	// Relative weight of the CPU load in the load score of a cluster member. `0` ignores it.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `1`
+	//  shortdesc: Weight of the CPU load when re-balancing
+	"cluster.rebalance.weight.cpu": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsInRange(0, 100))},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.disk)
+	// Relative weight of the storage pool usage in the load score of a cluster member. `0` ignores it.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Weight of the storage pool usage when re-balancing
+	"cluster.rebalance.weight.disk": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 100))},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.memory)
+	// Relative weight of the memory usage in the load score of a cluster member. `0` ignores it.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `1`
+	//  shortdesc: Weight of the memory usage when re-balancing
+	"cluster.rebalance.weight.memory": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsInRange(0, 100))},
+
+	// gendoc:generate(entity=server, group=cluster, key=cluster.rebalance.weight.network)
+	// Relative weight of the network bandwidth allocation in the load score of a cluster member. `0` ignores it.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `0`
+	//  shortdesc: Weight of the network bandwidth allocation when re-balancing
+	"cluster.rebalance.weight.network": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 100))},
+
 	// gendoc:generate(entity=server, group=core, key=core.metrics_authentication)
 	//
 	// ---
@@ -664,6 +834,16 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to automatically trust clients signed by the CA
 	"core.trust_ca_certificates": {Type: config.Bool, Default: "false"},
 
+	// gendoc:generate(entity=server, group=core, key=core.usage_retention)
+	// Specify the number of days after which the project usage records are removed.
+	// Set this option to `0` to keep them forever.
+	// ---
+	//  type: integer
+	//  scope: global
+	//  defaultdesc: `365`
+	//  shortdesc: How long to keep the project usage records
+	"core.usage_retention": {Type: config.Int64, Default: "365", Validator: validate.IsUint32},
+
 	// gendoc:generate(entity=server, group=images, key=images.auto_update_cached)
 	//
 	// ---
@@ -708,6 +888,15 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: When an unused cached remote image is flushed
 	"images.remote_cache_expiry": {Type: config.Int64, Default: "10"},
 
+	// gendoc:generate(entity=server, group=miscellaneous, key=instances.admission.scriptlet)
+	// When using custom admission policies for instances, this option stores the scriptlet.
+	// See {ref}`project-admission-scriptlet` for more information.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: Instance admission scriptlet for instance creation and updates
+	"instances.admission.scriptlet": {Validator: validate.Optional(scriptletLoad.InstanceAdmissionValidate)},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=instances.lxcfs.per_instance)
 	// LXCFS is used to provide overlays for common `/proc` and `/sys`
 	// files which reflect the resource limits applied to the container.
@@ -806,13 +995,13 @@ var ConfigSchema = config.Schema{
 
 	// gendoc:generate(entity=server, group=loki, key=loki.types)
 	// Specify a comma-separated list of events to send to the Loki server.
-	// The events can be any combination of `lifecycle`, `logging`, and `network-acl`.
+	// The events can be any combination of `lifecycle`, `logging`, `network-acl` and `audit`.
 	// ---
 	//  type: string
 	//  scope: global
 	//  defaultdesc: `lifecycle,logging`
 	//  shortdesc: Events to send to the Loki server
-	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl"))), Default: "lifecycle,logging"},
+	"loki.types": {Validator: validate.Optional(validate.IsListOf(validate.IsOneOf("lifecycle", "logging", "network-acl", "audit"))), Default: "lifecycle,logging"},
 
 	// gendoc:generate(entity=server, group=openfga, key=openfga.api.token)
 	//
@@ -878,6 +1067,14 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: OpenID Connect claim to use as the username
 	"oidc.claim": {},
 
+	// gendoc:generate(entity=server, group=oidc, key=oidc.groups.claim)
+	// The identity provider groups found in this claim are matched against the groups of the built-in role-based access control.
+	// ---
+	//  type: string
+	//  scope: global
+	//  shortdesc: OpenID Connect claim holding the groups of the user
+	"oidc.groups.claim": {},
+
 	// OVN networking global keys.
 
 	// gendoc:generate(entity=server, group=miscellaneous, key=network.ovn.integration_bridge)
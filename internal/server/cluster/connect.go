@@ -70,6 +70,18 @@ func Connect(address string, networkCert *localtls.CertInfo, serverCert *localtl
 				req.Header.Add(request.HeaderForwardedProtocol, val)
 			}
 
+			groups, ok := ctx.Value(request.CtxIdentityProviderGroups).([]string)
+			if ok {
+				for _, group := range groups {
+					req.Header.Add(request.HeaderForwardedIdentityProviderGroups, group)
+				}
+			}
+
+			token, ok := ctx.Value(request.CtxAuthToken).(*api.AuthToken)
+			if ok && token != nil {
+				req.Header.Add(request.HeaderForwardedAuthToken, token.Name)
+			}
+
 			req.Header.Add(request.HeaderForwardedAddress, r.RemoteAddr)
 
 			return proxy.FromEnvironment(req)
@@ -0,0 +1,32 @@
+package fencing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// command fences servers through an external command.
+//
+// The command is called with the cluster member name as its last argument
+// and must only exit successfully once the server is confirmed off.
+type command struct{}
+
+func (d *command) fence(ctx context.Context, name string, config map[string]string) error {
+	fields := strings.Fields(config["fencing.command"])
+	if len(fields) == 0 {
+		return fmt.Errorf("Missing fencing command")
+	}
+
+	env := append(os.Environ(), "INCUS_FENCING_MEMBER="+name, "INCUS_FENCING_ADDRESS="+config["fencing.address"])
+
+	_, _, err := subprocess.RunCommandSplit(ctx, env, nil, fields[0], append(fields[1:], name)...)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package fencing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/subprocess"
+)
+
+// ipmi fences servers through their BMC using ipmitool.
+type ipmi struct{}
+
+// run runs an ipmitool chassis command against the BMC.
+func (d *ipmi) run(ctx context.Context, config map[string]string, args ...string) (string, error) {
+	if config["fencing.address"] == "" {
+		return "", fmt.Errorf("Missing BMC address")
+	}
+
+	// Pass the password through the environment so it doesn't show up in the process list.
+	env := append(os.Environ(), "IPMI_PASSWORD="+config["fencing.password"])
+
+	ipmiArgs := []string{"-I", "lanplus", "-H", config["fencing.address"], "-E"}
+	if config["fencing.username"] != "" {
+		ipmiArgs = append(ipmiArgs, "-U", config["fencing.username"])
+	}
+
+	stdout, _, err := subprocess.RunCommandSplit(ctx, env, nil, "ipmitool", append(ipmiArgs, args...)...)
+	if err != nil {
+		return "", err
+	}
+
+	return stdout, nil
+}
+
+func (d *ipmi) fence(ctx context.Context, name string, config map[string]string) error {
+	_, err := d.run(ctx, config, "chassis", "power", "off")
+	if err != nil {
+		return fmt.Errorf("Failed powering off: %w", err)
+	}
+
+	return waitOff(ctx, func(ctx context.Context) (bool, error) {
+		status, err := d.run(ctx, config, "chassis", "power", "status")
+		if err != nil {
+			return false, err
+		}
+
+		return strings.Contains(strings.ToLower(status), "is off"), nil
+	})
+}
@@ -0,0 +1,162 @@
+package fencing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	localUtil "github.com/lxc/incus/v6/internal/server/util"
+)
+
+// redfish fences servers through the Redfish API of their BMC.
+type redfish struct {
+	client  *http.Client
+	baseURL string
+	config  map[string]string
+}
+
+// redfishSystem represents the fields of a Redfish computer system used for fencing.
+type redfishSystem struct {
+	PowerState string `json:"PowerState"`
+	Actions    struct {
+		Reset struct {
+			Target string `json:"target"`
+		} `json:"#ComputerSystem.Reset"`
+	} `json:"Actions"`
+}
+
+// request performs a Redfish API request and decodes the response into target if not nil.
+func (d *redfish) request(ctx context.Context, method string, path string, body any, target any) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, d.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if d.config["fencing.username"] != "" {
+		req.SetBasicAuth(d.config["fencing.username"], d.config["fencing.password"])
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Unexpected %s response from %q: %s", method, path, resp.Status)
+	}
+
+	if target == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// systemPath returns the path of the computer system to fence.
+func (d *redfish) systemPath(ctx context.Context) (string, error) {
+	if d.config["fencing.redfish.system"] != "" {
+		return "/redfish/v1/Systems/" + d.config["fencing.redfish.system"], nil
+	}
+
+	// Use the only system of the BMC.
+	systems := struct {
+		Members []struct {
+			ID string `json:"@odata.id"`
+		} `json:"Members"`
+	}{}
+
+	err := d.request(ctx, http.MethodGet, "/redfish/v1/Systems", nil, &systems)
+	if err != nil {
+		return "", err
+	}
+
+	if len(systems.Members) != 1 {
+		return "", fmt.Errorf("Found %d systems, \"fencing.redfish.system\" must be set", len(systems.Members))
+	}
+
+	return systems.Members[0].ID, nil
+}
+
+func (d *redfish) fence(ctx context.Context, name string, config map[string]string) error {
+	address := strings.TrimSuffix(config["fencing.address"], "/")
+	if address == "" {
+		return fmt.Errorf("Missing BMC address")
+	}
+
+	if !strings.HasPrefix(address, "https://") && !strings.HasPrefix(address, "http://") {
+		address = "https://" + address
+	}
+
+	client, err := localUtil.HTTPClient(config["fencing.certificate"], nil)
+	if err != nil {
+		return err
+	}
+
+	d.client = client
+	d.baseURL = address
+	d.config = config
+
+	path, err := d.systemPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	getSystem := func(ctx context.Context) (*redfishSystem, error) {
+		system := redfishSystem{}
+
+		err := d.request(ctx, http.MethodGet, path, nil, &system)
+		if err != nil {
+			return nil, err
+		}
+
+		return &system, nil
+	}
+
+	system, err := getSystem(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only power off the system if it's not already off.
+	if system.PowerState != "Off" {
+		target := system.Actions.Reset.Target
+		if target == "" {
+			target = path + "/Actions/ComputerSystem.Reset"
+		}
+
+		err = d.request(ctx, http.MethodPost, target, map[string]string{"ResetType": "ForceOff"}, nil)
+		if err != nil {
+			return fmt.Errorf("Failed powering off: %w", err)
+		}
+	}
+
+	return waitOff(ctx, func(ctx context.Context) (bool, error) {
+		system, err := getSystem(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		return system.PowerState == "Off", nil
+	})
+}
@@ -0,0 +1,135 @@
+package fencing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedfishServer returns a mock Redfish BMC with a single system in the given power state.
+func newRedfishServer(t *testing.T, powerState string) (*httptest.Server, *[]string) {
+	resets := []string{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redfish/v1/Systems", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"Members": []map[string]string{{"@odata.id": "/redfish/v1/Systems/1"}},
+		})
+	})
+
+	mux.HandleFunc("/redfish/v1/Systems/1", func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "admin" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"PowerState": powerState,
+			"Actions": map[string]any{
+				"#ComputerSystem.Reset": map[string]string{"target": "/redfish/v1/Systems/1/Actions/ComputerSystem.Reset"},
+			},
+		})
+	})
+
+	mux.HandleFunc("/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]string{}
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		resets = append(resets, req["ResetType"])
+		if req["ResetType"] == "ForceOff" {
+			powerState = "Off"
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server, &resets
+}
+
+func TestFence_Redfish(t *testing.T) {
+	server, resets := newRedfishServer(t, "On")
+
+	config := map[string]string{
+		"fencing.driver":   "redfish",
+		"fencing.address":  server.URL,
+		"fencing.username": "admin",
+		"fencing.password": "secret",
+	}
+
+	err := Fence(context.Background(), "server01", config)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ForceOff"}, *resets)
+}
+
+func TestFence_RedfishAlreadyOff(t *testing.T) {
+	server, resets := newRedfishServer(t, "Off")
+
+	config := map[string]string{
+		"fencing.driver":         "redfish",
+		"fencing.address":        server.URL,
+		"fencing.username":       "admin",
+		"fencing.password":       "secret",
+		"fencing.redfish.system": "1",
+	}
+
+	err := Fence(context.Background(), "server01", config)
+	require.NoError(t, err)
+	assert.Empty(t, *resets)
+}
+
+func TestFence_RedfishUnauthorized(t *testing.T) {
+	server, _ := newRedfishServer(t, "On")
+
+	config := map[string]string{
+		"fencing.driver":   "redfish",
+		"fencing.address":  server.URL,
+		"fencing.username": "admin",
+		"fencing.password": "wrong",
+	}
+
+	err := Fence(context.Background(), "server01", config)
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestFence_RedfishNotConfirmed(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/redfish/v1/Systems/1", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"PowerState": "On"})
+	})
+
+	mux.HandleFunc("/redfish/v1/Systems/1/Actions/ComputerSystem.Reset", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	oldInterval, oldTimeout := pollInterval, powerOffTimeout
+	pollInterval, powerOffTimeout = 10*time.Millisecond, 100*time.Millisecond
+	defer func() { pollInterval, powerOffTimeout = oldInterval, oldTimeout }()
+
+	config := map[string]string{
+		"fencing.driver":         "redfish",
+		"fencing.address":        server.URL,
+		"fencing.redfish.system": "1",
+	}
+
+	err := Fence(context.Background(), "server01", config)
+	assert.ErrorContains(t, err, "wasn't confirmed off")
+}
+
+func TestFence_UnknownDriver(t *testing.T) {
+	err := Fence(context.Background(), "server01", map[string]string{"fencing.driver": "pdu"})
+	assert.ErrorContains(t, err, "Unknown fencing driver")
+}
@@ -0,0 +1,87 @@
+package fencing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// pollInterval is the delay between power state checks while waiting for a server to be off.
+var pollInterval = 5 * time.Second
+
+// powerOffTimeout is the maximum time to wait for a server to be confirmed off.
+var powerOffTimeout = 2 * time.Minute
+
+// driver represents a fencing driver.
+type driver interface {
+	// fence powers off the server and only returns once it's confirmed to be off.
+	fence(ctx context.Context, name string, config map[string]string) error
+}
+
+var drivers = map[string]func() driver{
+	"command": func() driver { return &command{} },
+	"ipmi":    func() driver { return &ipmi{} },
+	"redfish": func() driver { return &redfish{} },
+}
+
+// Drivers returns the names of the supported fencing drivers.
+func Drivers() []string {
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// IsConfigured returns whether fencing is configured in the given cluster member configuration.
+func IsConfigured(config map[string]string) bool {
+	return config["fencing.driver"] != ""
+}
+
+// Fence powers off the cluster member with the given name and configuration and confirms it's off.
+func Fence(ctx context.Context, name string, config map[string]string) error {
+	driverName := config["fencing.driver"]
+
+	newDriver, ok := drivers[driverName]
+	if !ok {
+		return fmt.Errorf("Unknown fencing driver %q", driverName)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, powerOffTimeout)
+	defer cancel()
+
+	err := newDriver().fence(ctx, name, config)
+	if err != nil {
+		return fmt.Errorf("Failed fencing cluster member %q using %q: %w", name, driverName, err)
+	}
+
+	return nil
+}
+
+// waitOff calls the given function until it reports the server being off.
+func waitOff(ctx context.Context, isOff func(ctx context.Context) (bool, error)) error {
+	for {
+		off, err := isOff(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("Server wasn't confirmed off: %w", ctx.Err())
+			}
+
+			return fmt.Errorf("Failed getting power state: %w", err)
+		}
+
+		if off {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Server wasn't confirmed off: %w", ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"sort"
 	"sync"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	internalUtil "github.com/lxc/incus/v6/internal/util"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/revert"
 	localtls "github.com/lxc/incus/v6/shared/tls"
 	"github.com/lxc/incus/v6/shared/util"
 )
@@ -267,6 +269,121 @@ func Accept(state *state.State, gateway *Gateway, name, address string, schema,
 		return nil, err
 	}
 
+	return acceptRaftNode(state, gateway, id, name, address)
+}
+
+// Replace takes over an existing offline member with a new node, keeping the
+// member's ID, name, configuration and instances.
+//
+// The fingerprint is the one of the new node's server certificate, any other
+// server certificate of the member is removed from the trust store.
+//
+// This instance must already be clustered and be the leader.
+//
+// Return an updated list raft database nodes (possibly including the new
+// node).
+func Replace(state *state.State, gateway *Gateway, name, address string, schema, api, arch int) ([]db.RaftNode, error) {
+	// Check parameters
+	if name == "" {
+		return nil, fmt.Errorf("Member name must not be empty")
+	}
+
+	if address == "" {
+		return nil, fmt.Errorf("Member address must not be empty")
+	}
+
+	// Check that the member can be replaced and track its current address.
+	var member db.NodeInfo
+	err := state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		member, err = membershipCheckClusterStateForReplace(ctx, tx, name, address, schema, api, state.GlobalConfig.OfflineThreshold())
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// Point the member to the new node. Its storage pool and network configuration, images and
+	// server certificate are only dropped once the new node has joined, so that the replacement
+	// can be retried if it fails.
+	err = state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.ResetNode(ctx, member.ID, address, arch)
+		if err != nil {
+			return fmt.Errorf("Failed to update the replaced member: %w", err)
+		}
+
+		// Mark the node as pending, so it will be skipped when
+		// performing heartbeats or sending cluster
+		// notifications.
+		err = tx.SetNodePendingFlag(member.ID, true)
+		if err != nil {
+			return fmt.Errorf("Failed to mark the new node as pending: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Add(func() {
+		err := state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+			err := tx.ResetNode(ctx, member.ID, member.Address, member.Architecture)
+			if err != nil {
+				return err
+			}
+
+			err = tx.SetNodeVersion(member.ID, [2]int{member.Schema, member.APIExtensions})
+			if err != nil {
+				return err
+			}
+
+			return tx.SetNodePendingFlag(member.ID, false)
+		})
+		if err != nil {
+			logger.Warn("Failed restoring replaced member", logger.Ctx{"name": name, "err": err})
+		}
+	})
+
+	// Remove the member from the raft cluster, the new node gets its own role below.
+	nodes, err := gateway.currentRaftNodes()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get raft nodes from the log: %w", err)
+	}
+
+	for _, node := range nodes {
+		if node.ID != uint64(member.ID) {
+			continue
+		}
+
+		logger.Info("Remove replaced member from dqlite raft cluster", logger.Ctx{"id": node.ID, "address": node.Address})
+
+		err = RemoveRaftNode(gateway, node.Address)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to remove replaced member from the raft cluster: %w", err)
+		}
+
+		break
+	}
+
+	nodes, err = acceptRaftNode(state, gateway, member.ID, name, address)
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Success()
+
+	return nodes, nil
+}
+
+// acceptRaftNode returns the current list of raft nodes along with the newly
+// accepted node, with the role it should be given.
+func acceptRaftNode(state *state.State, gateway *Gateway, id int64, name string, address string) ([]db.RaftNode, error) {
 	// Possibly insert the new node into the raft_nodes table (if we have
 	// less than 3 database nodes).
 	nodes, err := gateway.currentRaftNodes()
@@ -477,6 +594,29 @@ func Join(state *state.State, gateway *Gateway, networkCert *localtls.CertInfo,
 		state.DB.Cluster.NodeID(node.ID)
 		tx.NodeID(node.ID)
 
+		// A node replacing an existing member takes over its ID, drop what belonged to the replaced server.
+		err = tx.ClearNodeLocalData(ctx, node.ID)
+		if err != nil {
+			return fmt.Errorf("Failed to clear the local data of the replaced member: %w", err)
+		}
+
+		certType := certificate.TypeServer
+		certs, err := cluster.GetCertificates(ctx, tx.Tx(), cluster.CertificateFilter{Name: &name, Type: &certType})
+		if err != nil {
+			return fmt.Errorf("Failed to get member %q certificates: %w", name, err)
+		}
+
+		for _, cert := range certs {
+			if cert.Fingerprint == serverCert.Fingerprint() {
+				continue
+			}
+
+			err = cluster.DeleteCertificate(ctx, tx.Tx(), cert.Fingerprint)
+			if err != nil {
+				return fmt.Errorf("Failed to remove replaced member certificate from trust store: %w", err)
+			}
+		}
+
 		// Storage pools.
 		ids, err := tx.GetNonPendingStoragePoolsNamesToIDs(ctx)
 		if err != nil {
@@ -690,6 +830,13 @@ func Rebalance(state *state.State, gateway *Gateway, unavailableMembers []string
 
 	role, candidates := roles.Adjust(gateway.info.ID)
 
+	// Spread the database roles across failure domains.
+	if role == client.Spare {
+		sortDemotionCandidates(roles, candidates)
+	} else if role == -1 {
+		role, candidates = spreadFailureDomains(roles)
+	}
+
 	if role == -1 {
 		// No node to promote
 		return "", nodes, nil
@@ -1059,6 +1206,94 @@ func newRolesChanges(state *state.State, gateway *Gateway, nodes []db.RaftNode,
 	return roles, nil
 }
 
+// sortDemotionCandidates sorts the nodes to demote so that the ones sharing their failure domain with another
+// online node holding the same role come first.
+func sortDemotionCandidates(roles *app.RolesChanges, candidates []client.NodeInfo) {
+	if len(candidates) < 2 {
+		return
+	}
+
+	domainCounts := map[uint64]int{}
+	for node, metadata := range roles.State {
+		if metadata != nil && node.Role == candidates[0].Role {
+			domainCounts[metadata.FailureDomain]++
+		}
+	}
+
+	shared := func(node client.NodeInfo) bool {
+		metadata := roles.State[node]
+		return metadata != nil && domainCounts[metadata.FailureDomain] > 1
+	}
+
+	sort.SliceStable(candidates, func(i int, j int) bool {
+		return shared(candidates[i]) && !shared(candidates[j])
+	})
+}
+
+// spreadFailureDomains checks whether the voters or stand-bys could be spread across more failure domains. If so,
+// it returns the role to promote and the candidates for it, the next rebalance then demotes one of the nodes
+// sharing a failure domain.
+func spreadFailureDomains(roles *app.RolesChanges) (client.NodeRole, []client.NodeInfo) {
+	online := func(role client.NodeRole) []client.NodeInfo {
+		nodes := []client.NodeInfo{}
+		for node, metadata := range roles.State {
+			if metadata != nil && node.Role == role {
+				nodes = append(nodes, node)
+			}
+		}
+
+		sort.Slice(nodes, func(i int, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+		return nodes
+	}
+
+	for _, role := range []client.NodeRole{client.Voter, client.StandBy} {
+		holders := online(role)
+
+		// Only spread a fully staffed role.
+		wanted := roles.Config.Voters
+		candidates := append(online(client.StandBy), online(client.Spare)...)
+		if role == client.StandBy {
+			wanted = roles.Config.StandBys
+			candidates = online(client.Spare)
+		}
+
+		if wanted == 0 || len(holders) < wanted {
+			continue
+		}
+
+		// Check whether any failure domain holds the role more than once.
+		domains := map[uint64]int{}
+		shared := false
+		for _, node := range holders {
+			domain := roles.State[node].FailureDomain
+			domains[domain]++
+
+			if domains[domain] > 1 {
+				shared = true
+			}
+		}
+
+		if !shared {
+			continue
+		}
+
+		// Look for online nodes in a failure domain which doesn't hold the role yet.
+		spread := []client.NodeInfo{}
+		for _, node := range candidates {
+			if domains[roles.State[node].FailureDomain] == 0 {
+				spread = append(spread, node)
+			}
+		}
+
+		if len(spread) > 0 {
+			return role, spread
+		}
+	}
+
+	return -1, nil
+}
+
 // Purge removes a node entirely from the cluster database.
 func Purge(c *db.Cluster, name string) error {
 	logger.Debugf("Remove node %s from the database", name)
@@ -1194,6 +1429,45 @@ func membershipCheckClusterStateForAccept(ctx context.Context, tx *db.ClusterTx,
 	return nil
 }
 
+// Check that cluster-related preconditions are met for replacing an existing
+// member with a new node, returning the member to replace.
+func membershipCheckClusterStateForReplace(ctx context.Context, tx *db.ClusterTx, name string, address string, schema int, api int, offlineThreshold time.Duration) (db.NodeInfo, error) {
+	members, err := tx.GetNodes(ctx)
+	if err != nil {
+		return db.NodeInfo{}, fmt.Errorf("Failed getting cluster members: %w", err)
+	}
+
+	var replaced *db.NodeInfo
+	for i, member := range members {
+		if member.Name == name {
+			replaced = &members[i]
+			continue
+		}
+
+		if member.Address == address {
+			return db.NodeInfo{}, fmt.Errorf("The cluster already has a member with address: %s", address)
+		}
+
+		if member.Schema != schema {
+			return db.NodeInfo{}, fmt.Errorf("The joining server version doesn't match (expected %s with DB schema %v)", version.Version, schema)
+		}
+
+		if member.APIExtensions != api {
+			return db.NodeInfo{}, fmt.Errorf("The joining server version doesn't match (expected %s with API count %v)", version.Version, api)
+		}
+	}
+
+	if replaced == nil {
+		return db.NodeInfo{}, fmt.Errorf("The cluster has no member with name: %s", name)
+	}
+
+	if !replaced.IsOffline(offlineThreshold) {
+		return db.NodeInfo{}, fmt.Errorf("Cluster member %q is still online", name)
+	}
+
+	return *replaced, nil
+}
+
 // Check that cluster-related preconditions are met for leaving a cluster.
 func membershipCheckClusterStateForLeave(ctx context.Context, tx *db.ClusterTx, nodeID int64) error {
 	// Check that it has no containers or images.
@@ -0,0 +1,16 @@
+package cluster
+
+import (
+	"github.com/cowsql/go-cowsql/app"
+	"github.com/cowsql/go-cowsql/client"
+)
+
+// SpreadFailureDomains exposes spreadFailureDomains to the tests.
+func SpreadFailureDomains(roles *app.RolesChanges) (client.NodeRole, []client.NodeInfo) {
+	return spreadFailureDomains(roles)
+}
+
+// SortDemotionCandidates exposes sortDemotionCandidates to the tests.
+func SortDemotionCandidates(roles *app.RolesChanges, candidates []client.NodeInfo) {
+	sortDemotionCandidates(roles, candidates)
+}
@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cowsql/go-cowsql/app"
+	"github.com/cowsql/go-cowsql/client"
 	"github.com/cowsql/go-cowsql/driver"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -509,3 +511,65 @@ func (h *membershipFixtures) ClusterNode(address string) {
 	})
 	require.NoError(h.t, err)
 }
+
+// If two voters share a failure domain while an online node is in a domain
+// without voters, that node gets promoted.
+func TestSpreadFailureDomains(t *testing.T) {
+	node := func(id uint64, role client.NodeRole) client.NodeInfo {
+		return client.NodeInfo{ID: id, Address: fmt.Sprintf("1.2.3.%d:8443", id), Role: role}
+	}
+
+	roles := &app.RolesChanges{
+		Config: app.RolesConfig{Voters: 3, StandBys: 1},
+		State: map[client.NodeInfo]*client.NodeMetadata{
+			node(1, client.Voter):   {FailureDomain: 1},
+			node(2, client.Voter):   {FailureDomain: 1},
+			node(3, client.Voter):   {FailureDomain: 2},
+			node(4, client.StandBy): {FailureDomain: 3},
+			node(5, client.Spare):   {FailureDomain: 1},
+		},
+	}
+
+	role, candidates := cluster.SpreadFailureDomains(roles)
+	assert.Equal(t, client.Voter, role)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, uint64(4), candidates[0].ID)
+
+	// Once the voters are spread, there's nothing left to do.
+	delete(roles.State, node(2, client.Voter))
+	delete(roles.State, node(4, client.StandBy))
+	roles.State[node(2, client.StandBy)] = &client.NodeMetadata{FailureDomain: 1}
+	roles.State[node(4, client.Voter)] = &client.NodeMetadata{FailureDomain: 3}
+
+	role, _ = cluster.SpreadFailureDomains(roles)
+	assert.Equal(t, client.NodeRole(-1), role)
+}
+
+// When demoting, prefer the nodes sharing a failure domain with another node
+// holding the same role.
+func TestSortDemotionCandidates(t *testing.T) {
+	nodes := []client.NodeInfo{
+		{ID: 1, Address: "1.2.3.1:8443", Role: client.Voter},
+		{ID: 2, Address: "1.2.3.2:8443", Role: client.Voter},
+		{ID: 3, Address: "1.2.3.3:8443", Role: client.Voter},
+		{ID: 4, Address: "1.2.3.4:8443", Role: client.Voter},
+	}
+
+	roles := &app.RolesChanges{
+		Config: app.RolesConfig{Voters: 3},
+		State: map[client.NodeInfo]*client.NodeMetadata{
+			nodes[0]: {FailureDomain: 1},
+			nodes[1]: {FailureDomain: 2},
+			nodes[2]: {FailureDomain: 3},
+			nodes[3]: {FailureDomain: 3},
+		},
+	}
+
+	// The leader (node 1) isn't a candidate.
+	candidates := []client.NodeInfo{nodes[1], nodes[2], nodes[3]}
+	cluster.SortDemotionCandidates(roles, candidates)
+
+	assert.Equal(t, uint64(3), candidates[0].ID)
+	assert.Equal(t, uint64(4), candidates[1].ID)
+	assert.Equal(t, uint64(2), candidates[2].ID)
+}
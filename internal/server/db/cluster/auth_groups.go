@@ -0,0 +1,202 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target auth_groups.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e auth_group objects table=auth_groups
+//generate-database:mapper stmt -e auth_group objects-by-Name table=auth_groups
+//generate-database:mapper stmt -e auth_group id table=auth_groups
+//generate-database:mapper stmt -e auth_group create table=auth_groups
+//generate-database:mapper stmt -e auth_group rename table=auth_groups
+//generate-database:mapper stmt -e auth_group update table=auth_groups
+//generate-database:mapper stmt -e auth_group delete-by-Name table=auth_groups
+//
+//generate-database:mapper method -i -e auth_group GetMany table=auth_groups
+//generate-database:mapper method -i -e auth_group GetOne table=auth_groups
+//generate-database:mapper method -i -e auth_group ID table=auth_groups
+//generate-database:mapper method -i -e auth_group Exists table=auth_groups
+//generate-database:mapper method -i -e auth_group Create table=auth_groups
+//generate-database:mapper method -i -e auth_group Rename table=auth_groups
+//generate-database:mapper method -i -e auth_group Update table=auth_groups
+//generate-database:mapper method -i -e auth_group DeleteOne-by-Name table=auth_groups
+
+// AuthGroup is a value object holding db-related details about a group of the built-in role-based access control.
+type AuthGroup struct {
+	ID          int
+	Name        string `db:"primary=yes"`
+	Description string `db:"coalesce=''"`
+}
+
+// AuthGroupFilter specifies potential query parameter fields.
+type AuthGroupFilter struct {
+	ID   *int
+	Name *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (g *AuthGroup) ToAPI(ctx context.Context, tx *sql.Tx) (*api.AuthGroup, error) {
+	identities, err := GetAuthGroupIdentities(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := GetAuthGroupRoles(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	identityProviderGroups, err := GetAuthGroupIdentityProviderGroups(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AuthGroup{
+		Name: g.Name,
+		AuthGroupPut: api.AuthGroupPut{
+			Description:            g.Description,
+			Identities:             identities,
+			IdentityProviderGroups: identityProviderGroups,
+			Roles:                  roles,
+		},
+	}, nil
+}
+
+// GetAuthGroupIdentities returns the identities belonging to a group.
+func GetAuthGroupIdentities(ctx context.Context, tx *sql.Tx, groupID int) ([]api.AuthIdentity, error) {
+	identities := []api.AuthIdentity{}
+
+	stmt := "SELECT authentication_method, identifier FROM auth_groups_identities WHERE auth_group_id = ? ORDER BY authentication_method, identifier"
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		identity := api.AuthIdentity{}
+
+		err := scan(&identity.AuthenticationMethod, &identity.Identifier)
+		if err != nil {
+			return err
+		}
+
+		identities = append(identities, identity)
+
+		return nil
+	}, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching group identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+// UpdateAuthGroupIdentities replaces the identities belonging to a group.
+func UpdateAuthGroupIdentities(ctx context.Context, tx *sql.Tx, groupID int, identities []api.AuthIdentity) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM auth_groups_identities WHERE auth_group_id = ?", groupID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting group identities: %w", err)
+	}
+
+	for _, identity := range identities {
+		_, err := tx.ExecContext(ctx, "INSERT INTO auth_groups_identities (auth_group_id, authentication_method, identifier) VALUES (?, ?, ?)", groupID, identity.AuthenticationMethod, identity.Identifier)
+		if err != nil {
+			return fmt.Errorf("Failed adding group identity: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetAuthGroupIdentityProviderGroups returns the identity provider groups mapped to a group.
+func GetAuthGroupIdentityProviderGroups(ctx context.Context, tx *sql.Tx, groupID int) ([]string, error) {
+	names, err := query.SelectStrings(ctx, tx, "SELECT name FROM auth_groups_identity_provider_groups WHERE auth_group_id = ? ORDER BY name", groupID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching group identity provider groups: %w", err)
+	}
+
+	return names, nil
+}
+
+// UpdateAuthGroupIdentityProviderGroups replaces the identity provider groups mapped to a group.
+func UpdateAuthGroupIdentityProviderGroups(ctx context.Context, tx *sql.Tx, groupID int, names []string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM auth_groups_identity_provider_groups WHERE auth_group_id = ?", groupID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting group identity provider groups: %w", err)
+	}
+
+	for _, name := range names {
+		_, err := tx.ExecContext(ctx, "INSERT INTO auth_groups_identity_provider_groups (auth_group_id, name) VALUES (?, ?)", groupID, name)
+		if err != nil {
+			return fmt.Errorf("Failed adding group identity provider group: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetAuthGroupRoles returns the roles bound to a group.
+func GetAuthGroupRoles(ctx context.Context, tx *sql.Tx, groupID int) ([]api.AuthGroupRole, error) {
+	roles := []api.AuthGroupRole{}
+
+	stmt := `
+SELECT auth_roles.name, coalesce(projects.name, '') FROM auth_groups_roles
+JOIN auth_roles ON auth_roles.id = auth_groups_roles.auth_role_id
+LEFT JOIN projects ON projects.id = auth_groups_roles.project_id
+WHERE auth_groups_roles.auth_group_id = ?
+ORDER BY auth_roles.name, projects.name`
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		role := api.AuthGroupRole{}
+
+		err := scan(&role.Role, &role.Project)
+		if err != nil {
+			return err
+		}
+
+		roles = append(roles, role)
+
+		return nil
+	}, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching group roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// UpdateAuthGroupRoles replaces the roles bound to a group.
+func UpdateAuthGroupRoles(ctx context.Context, tx *sql.Tx, groupID int, roles []api.AuthGroupRole) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM auth_groups_roles WHERE auth_group_id = ?", groupID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting group roles: %w", err)
+	}
+
+	for _, role := range roles {
+		roleID, err := GetAuthRoleID(ctx, tx, role.Role)
+		if err != nil {
+			return err
+		}
+
+		var projectID any
+		if role.Project != "" {
+			projectID, err = GetProjectID(ctx, tx, role.Project)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO auth_groups_roles (auth_group_id, auth_role_id, project_id) VALUES (?, ?, ?)", groupID, roleID, projectID)
+		if err != nil {
+			return fmt.Errorf("Failed adding group role: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// AuthGroupGenerated is an interface of generated methods for AuthGroup.
+type AuthGroupGenerated interface {
+	// GetAuthGroups returns all available auth_groups.
+	// generator: auth_group GetMany
+	GetAuthGroups(ctx context.Context, db dbtx, filters ...AuthGroupFilter) ([]AuthGroup, error)
+
+	// GetAuthGroup returns the auth_group with the given key.
+	// generator: auth_group GetOne
+	GetAuthGroup(ctx context.Context, db dbtx, name string) (*AuthGroup, error)
+
+	// GetAuthGroupID return the ID of the auth_group with the given key.
+	// generator: auth_group ID
+	GetAuthGroupID(ctx context.Context, db tx, name string) (int64, error)
+
+	// AuthGroupExists checks if a auth_group with the given key exists.
+	// generator: auth_group Exists
+	AuthGroupExists(ctx context.Context, db dbtx, name string) (bool, error)
+
+	// CreateAuthGroup adds a new auth_group to the database.
+	// generator: auth_group Create
+	CreateAuthGroup(ctx context.Context, db dbtx, object AuthGroup) (int64, error)
+
+	// RenameAuthGroup renames the auth_group matching the given key parameters.
+	// generator: auth_group Rename
+	RenameAuthGroup(ctx context.Context, db dbtx, name string, to string) error
+
+	// UpdateAuthGroup updates the auth_group matching the given key parameters.
+	// generator: auth_group Update
+	UpdateAuthGroup(ctx context.Context, db tx, name string, object AuthGroup) error
+
+	// DeleteAuthGroup deletes the auth_group matching the given key parameters.
+	// generator: auth_group DeleteOne-by-Name
+	DeleteAuthGroup(ctx context.Context, db dbtx, name string) error
+}
@@ -0,0 +1,74 @@
+//go:build linux && cgo && !agent
+
+package cluster_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Groups are bound to roles either on all projects or on a single one.
+func TestAuthGroupRoles(t *testing.T) {
+	db := newDB(t)
+
+	var err error
+	cluster.PreparedStmts, err = cluster.PrepareStmts(db, false)
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO projects (name, description) VALUES ('default', '')")
+	require.NoError(t, err)
+
+	var group *api.AuthGroup
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := cluster.CreateAuthRole(ctx, tx, cluster.AuthRole{Name: "viewer"})
+		require.NoError(t, err)
+
+		groupID, err := cluster.CreateAuthGroup(ctx, tx, cluster.AuthGroup{Name: "ops"})
+		require.NoError(t, err)
+
+		roles := []api.AuthGroupRole{{Role: "viewer"}, {Role: "viewer", Project: "default"}}
+		err = cluster.UpdateAuthGroupRoles(ctx, tx, int(groupID), roles)
+		require.NoError(t, err)
+
+		identities := []api.AuthIdentity{{AuthenticationMethod: api.AuthenticationMethodOIDC, Identifier: "jane@example.com"}}
+		err = cluster.UpdateAuthGroupIdentities(ctx, tx, int(groupID), identities)
+		require.NoError(t, err)
+
+		err = cluster.UpdateAuthGroupIdentityProviderGroups(ctx, tx, int(groupID), []string{"sre", "incus-admins"})
+		require.NoError(t, err)
+
+		dbGroup, err := cluster.GetAuthGroup(ctx, tx, "ops")
+		require.NoError(t, err)
+
+		group, err = dbGroup.ToAPI(ctx, tx)
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []api.AuthGroupRole{{Role: "viewer"}, {Role: "viewer", Project: "default"}}, group.Roles)
+	assert.Equal(t, []api.AuthIdentity{{AuthenticationMethod: "oidc", Identifier: "jane@example.com"}}, group.Identities)
+	assert.Equal(t, []string{"incus-admins", "sre"}, group.IdentityProviderGroups)
+
+	// Deleting the role removes it from the group.
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		return cluster.DeleteAuthRole(ctx, tx, "viewer")
+	})
+	require.NoError(t, err)
+
+	var roles []api.AuthGroupRole
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		roles, err = cluster.GetAuthGroupRoles(ctx, tx, 1)
+		return err
+	})
+	require.NoError(t, err)
+	assert.Empty(t, roles)
+}
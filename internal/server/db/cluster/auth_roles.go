@@ -0,0 +1,120 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target auth_roles.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e auth_role objects table=auth_roles
+//generate-database:mapper stmt -e auth_role objects-by-Name table=auth_roles
+//generate-database:mapper stmt -e auth_role id table=auth_roles
+//generate-database:mapper stmt -e auth_role create table=auth_roles
+//generate-database:mapper stmt -e auth_role rename table=auth_roles
+//generate-database:mapper stmt -e auth_role update table=auth_roles
+//generate-database:mapper stmt -e auth_role delete-by-Name table=auth_roles
+//
+//generate-database:mapper method -i -e auth_role GetMany table=auth_roles
+//generate-database:mapper method -i -e auth_role GetOne table=auth_roles
+//generate-database:mapper method -i -e auth_role ID table=auth_roles
+//generate-database:mapper method -i -e auth_role Exists table=auth_roles
+//generate-database:mapper method -i -e auth_role Create table=auth_roles
+//generate-database:mapper method -i -e auth_role Rename table=auth_roles
+//generate-database:mapper method -i -e auth_role Update table=auth_roles
+//generate-database:mapper method -i -e auth_role DeleteOne-by-Name table=auth_roles
+
+// AuthRole is a value object holding db-related details about a role of the built-in role-based access control.
+type AuthRole struct {
+	ID          int
+	Name        string `db:"primary=yes"`
+	Description string `db:"coalesce=''"`
+}
+
+// AuthRoleFilter specifies potential query parameter fields.
+type AuthRoleFilter struct {
+	ID   *int
+	Name *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (r *AuthRole) ToAPI(ctx context.Context, tx *sql.Tx) (*api.AuthRole, error) {
+	permissions, err := GetAuthRolePermissions(ctx, tx, r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := query.SelectStrings(ctx, tx, `
+SELECT DISTINCT auth_groups.name FROM auth_groups
+JOIN auth_groups_roles ON auth_groups_roles.auth_group_id = auth_groups.id
+WHERE auth_groups_roles.auth_role_id = ?
+ORDER BY auth_groups.name`, r.ID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching groups of role %q: %w", r.Name, err)
+	}
+
+	usedBy := make([]string, 0, len(groups))
+	for _, group := range groups {
+		usedBy = append(usedBy, api.NewURL().Path(version.APIVersion, "auth", "groups", group).String())
+	}
+
+	return &api.AuthRole{
+		Name: r.Name,
+		AuthRolePut: api.AuthRolePut{
+			Description: r.Description,
+			Permissions: permissions,
+		},
+		UsedBy: usedBy,
+	}, nil
+}
+
+// GetAuthRolePermissions returns the permissions of a role.
+func GetAuthRolePermissions(ctx context.Context, tx *sql.Tx, roleID int) ([]api.AuthPermission, error) {
+	permissions := []api.AuthPermission{}
+
+	stmt := "SELECT entity_type, entitlement FROM auth_roles_permissions WHERE auth_role_id = ? ORDER BY entity_type, entitlement"
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		permission := api.AuthPermission{}
+
+		err := scan(&permission.EntityType, &permission.Entitlement)
+		if err != nil {
+			return err
+		}
+
+		permissions = append(permissions, permission)
+
+		return nil
+	}, roleID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching role permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// UpdateAuthRolePermissions replaces the permissions of a role.
+func UpdateAuthRolePermissions(ctx context.Context, tx *sql.Tx, roleID int, permissions []api.AuthPermission) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM auth_roles_permissions WHERE auth_role_id = ?", roleID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting role permissions: %w", err)
+	}
+
+	for _, permission := range permissions {
+		_, err := tx.ExecContext(ctx, "INSERT INTO auth_roles_permissions (auth_role_id, entity_type, entitlement) VALUES (?, ?, ?)", roleID, permission.EntityType, permission.Entitlement)
+		if err != nil {
+			return fmt.Errorf("Failed adding role permission: %w", err)
+		}
+	}
+
+	return nil
+}
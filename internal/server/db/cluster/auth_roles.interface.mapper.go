@@ -0,0 +1,40 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// AuthRoleGenerated is an interface of generated methods for AuthRole.
+type AuthRoleGenerated interface {
+	// GetAuthRoles returns all available auth_roles.
+	// generator: auth_role GetMany
+	GetAuthRoles(ctx context.Context, db dbtx, filters ...AuthRoleFilter) ([]AuthRole, error)
+
+	// GetAuthRole returns the auth_role with the given key.
+	// generator: auth_role GetOne
+	GetAuthRole(ctx context.Context, db dbtx, name string) (*AuthRole, error)
+
+	// GetAuthRoleID return the ID of the auth_role with the given key.
+	// generator: auth_role ID
+	GetAuthRoleID(ctx context.Context, db tx, name string) (int64, error)
+
+	// AuthRoleExists checks if a auth_role with the given key exists.
+	// generator: auth_role Exists
+	AuthRoleExists(ctx context.Context, db dbtx, name string) (bool, error)
+
+	// CreateAuthRole adds a new auth_role to the database.
+	// generator: auth_role Create
+	CreateAuthRole(ctx context.Context, db dbtx, object AuthRole) (int64, error)
+
+	// RenameAuthRole renames the auth_role matching the given key parameters.
+	// generator: auth_role Rename
+	RenameAuthRole(ctx context.Context, db dbtx, name string, to string) error
+
+	// UpdateAuthRole updates the auth_role matching the given key parameters.
+	// generator: auth_role Update
+	UpdateAuthRole(ctx context.Context, db tx, name string, object AuthRole) error
+
+	// DeleteAuthRole deletes the auth_role matching the given key parameters.
+	// generator: auth_role DeleteOne-by-Name
+	DeleteAuthRole(ctx context.Context, db dbtx, name string) error
+}
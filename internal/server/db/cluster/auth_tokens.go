@@ -0,0 +1,152 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target auth_tokens.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e auth_token objects table=auth_tokens
+//generate-database:mapper stmt -e auth_token objects-by-Name table=auth_tokens
+//generate-database:mapper stmt -e auth_token objects-by-SecretHash table=auth_tokens
+//generate-database:mapper stmt -e auth_token id table=auth_tokens
+//generate-database:mapper stmt -e auth_token create table=auth_tokens
+//generate-database:mapper stmt -e auth_token delete-by-Name table=auth_tokens
+//
+//generate-database:mapper method -i -e auth_token GetMany table=auth_tokens
+//generate-database:mapper method -i -e auth_token GetOne table=auth_tokens
+//generate-database:mapper method -i -e auth_token ID table=auth_tokens
+//generate-database:mapper method -i -e auth_token Exists table=auth_tokens
+//generate-database:mapper method -i -e auth_token Create table=auth_tokens
+//generate-database:mapper method -i -e auth_token DeleteOne-by-Name table=auth_tokens
+
+// AuthToken is a value object holding db-related details about an API token.
+type AuthToken struct {
+	ID           int
+	Name         string `db:"primary=yes"`
+	Description  string `db:"coalesce=''"`
+	Identity     string
+	SecretHash   string
+	Restricted   bool
+	CreationDate time.Time
+	ExpiryDate   time.Time
+}
+
+// AuthTokenFilter specifies potential query parameter fields.
+type AuthTokenFilter struct {
+	ID         *int
+	Name       *string
+	SecretHash *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (t *AuthToken) ToAPI(ctx context.Context, tx *sql.Tx) (*api.AuthToken, error) {
+	projects, err := GetAuthTokenProjects(ctx, tx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := GetAuthTokenPermissions(ctx, tx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AuthToken{
+		Name:        t.Name,
+		Description: t.Description,
+		Identity:    t.Identity,
+		Restricted:  t.Restricted,
+		Projects:    projects,
+		Permissions: permissions,
+		CreatedAt:   t.CreationDate,
+		ExpiresAt:   t.ExpiryDate,
+	}, nil
+}
+
+// GetAuthTokenProjects returns the projects an API token is restricted to.
+func GetAuthTokenProjects(ctx context.Context, tx *sql.Tx, tokenID int) ([]string, error) {
+	projects, err := query.SelectStrings(ctx, tx, `
+SELECT projects.name FROM projects
+JOIN auth_tokens_projects ON auth_tokens_projects.project_id = projects.id
+WHERE auth_tokens_projects.auth_token_id = ?
+ORDER BY projects.name`, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching API token projects: %w", err)
+	}
+
+	return projects, nil
+}
+
+// UpdateAuthTokenProjects replaces the projects an API token is restricted to.
+func UpdateAuthTokenProjects(ctx context.Context, tx *sql.Tx, tokenID int, projectNames []string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM auth_tokens_projects WHERE auth_token_id = ?", tokenID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting API token projects: %w", err)
+	}
+
+	for _, projectName := range projectNames {
+		projectID, err := GetProjectID(ctx, tx, projectName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO auth_tokens_projects (auth_token_id, project_id) VALUES (?, ?)", tokenID, projectID)
+		if err != nil {
+			return fmt.Errorf("Failed adding API token project: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetAuthTokenPermissions returns the permissions an API token is restricted to.
+func GetAuthTokenPermissions(ctx context.Context, tx *sql.Tx, tokenID int) ([]api.AuthPermission, error) {
+	permissions := []api.AuthPermission{}
+
+	stmt := "SELECT entity_type, entitlement FROM auth_tokens_permissions WHERE auth_token_id = ? ORDER BY entity_type, entitlement"
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		permission := api.AuthPermission{}
+
+		err := scan(&permission.EntityType, &permission.Entitlement)
+		if err != nil {
+			return err
+		}
+
+		permissions = append(permissions, permission)
+
+		return nil
+	}, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching API token permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// UpdateAuthTokenPermissions replaces the permissions an API token is restricted to.
+func UpdateAuthTokenPermissions(ctx context.Context, tx *sql.Tx, tokenID int, permissions []api.AuthPermission) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM auth_tokens_permissions WHERE auth_token_id = ?", tokenID)
+	if err != nil {
+		return fmt.Errorf("Failed deleting API token permissions: %w", err)
+	}
+
+	for _, permission := range permissions {
+		_, err := tx.ExecContext(ctx, "INSERT INTO auth_tokens_permissions (auth_token_id, entity_type, entitlement) VALUES (?, ?, ?)", tokenID, permission.EntityType, permission.Entitlement)
+		if err != nil {
+			return fmt.Errorf("Failed adding API token permission: %w", err)
+		}
+	}
+
+	return nil
+}
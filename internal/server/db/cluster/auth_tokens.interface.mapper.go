@@ -0,0 +1,32 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// AuthTokenGenerated is an interface of generated methods for AuthToken.
+type AuthTokenGenerated interface {
+	// GetAuthTokens returns all available auth_tokens.
+	// generator: auth_token GetMany
+	GetAuthTokens(ctx context.Context, db dbtx, filters ...AuthTokenFilter) ([]AuthToken, error)
+
+	// GetAuthToken returns the auth_token with the given key.
+	// generator: auth_token GetOne
+	GetAuthToken(ctx context.Context, db dbtx, name string) (*AuthToken, error)
+
+	// GetAuthTokenID return the ID of the auth_token with the given key.
+	// generator: auth_token ID
+	GetAuthTokenID(ctx context.Context, db tx, name string) (int64, error)
+
+	// AuthTokenExists checks if a auth_token with the given key exists.
+	// generator: auth_token Exists
+	AuthTokenExists(ctx context.Context, db dbtx, name string) (bool, error)
+
+	// CreateAuthToken adds a new auth_token to the database.
+	// generator: auth_token Create
+	CreateAuthToken(ctx context.Context, db dbtx, object AuthToken) (int64, error)
+
+	// DeleteAuthToken deletes the auth_token matching the given key parameters.
+	// generator: auth_token DeleteOne-by-Name
+	DeleteAuthToken(ctx context.Context, db dbtx, name string) error
+}
@@ -0,0 +1,109 @@
+package cluster
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+)
+
+// backupHeader is the first line of every global database backup.
+const backupHeader = "-- Incus global database backup"
+
+// backupSchemaPrefix precedes the schema version in the header of a global database backup.
+const backupSchemaPrefix = "-- Schema version: "
+
+// Backup returns a SQL dump of the global database content.
+//
+// The dump is taken within the given transaction, so it's consistent as long as the transaction is.
+// It's a regular SQL text dump which can be loaded into an empty SQLite database, preceded by a header
+// recording the schema version it was taken at, as needed by RestorePatch.
+func Backup(ctx context.Context, tx *sql.Tx) (string, error) {
+	dump, err := query.Dump(ctx, tx, false)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s\n%s%d\n%s", backupHeader, backupSchemaPrefix, SchemaVersion, dump), nil
+}
+
+// BackupSchemaVersion returns the schema version recorded in the header of a global database backup.
+func BackupSchemaVersion(backup string) (int, error) {
+	scanner := bufio.NewScanner(strings.NewReader(backup))
+
+	if !scanner.Scan() || scanner.Text() != backupHeader {
+		return -1, fmt.Errorf("Not a global database backup")
+	}
+
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), backupSchemaPrefix) {
+		return -1, fmt.Errorf("Global database backup is missing its schema version")
+	}
+
+	version, err := strconv.Atoi(strings.TrimPrefix(scanner.Text(), backupSchemaPrefix))
+	if err != nil {
+		return -1, fmt.Errorf("Invalid schema version in global database backup: %w", err)
+	}
+
+	return version, nil
+}
+
+// RestorePatch turns a global database backup into queries replacing the content of the global database
+// with the one of the backup.
+//
+// The queries are meant to be run in a single transaction against a database using the same schema
+// version as the backup, typically by writing them to the patch.global.sql file.
+func RestorePatch(backup string) (string, error) {
+	version, err := BackupSchemaVersion(backup)
+	if err != nil {
+		return "", err
+	}
+
+	if version != SchemaVersion {
+		return "", fmt.Errorf("Global database backup uses schema version %d but version %d is required", version, SchemaVersion)
+	}
+
+	var tables []string
+	var inserts []string
+
+	for _, line := range strings.Split(backup, "\n") {
+		if strings.HasPrefix(line, "INSERT INTO ") {
+			inserts = append(inserts, line)
+			continue
+		}
+
+		name, ok := strings.CutPrefix(line, "CREATE TABLE ")
+		if !ok {
+			continue
+		}
+
+		name = strings.TrimPrefix(name, "IF NOT EXISTS ")
+		name, _, _ = strings.Cut(name, " ")
+		name, _, _ = strings.Cut(name, "(")
+		tables = append(tables, strings.Trim(name, `"`))
+	}
+
+	if len(tables) == 0 {
+		return "", fmt.Errorf("Global database backup doesn't contain any table")
+	}
+
+	var builder strings.Builder
+
+	// Foreign keys can only be checked once all the rows are back.
+	builder.WriteString("PRAGMA defer_foreign_keys=ON;\n")
+
+	for _, table := range tables {
+		builder.WriteString(fmt.Sprintf("DELETE FROM %q;\n", table))
+	}
+
+	builder.WriteString("DELETE FROM sqlite_sequence;\n")
+
+	for _, insert := range inserts {
+		builder.WriteString(insert + "\n")
+	}
+
+	return builder.String(), nil
+}
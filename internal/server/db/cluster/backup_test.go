@@ -0,0 +1,64 @@
+package cluster_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
+)
+
+// A backup can be restored over a database whose content changed since.
+func TestRestorePatch(t *testing.T) {
+	db := newDB(t)
+	addNode(t, db, "1.2.3.4:666", 1, 1)
+
+	var backup string
+	err := query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		var err error
+		backup, err = cluster.Backup(ctx, tx)
+		return err
+	})
+	require.NoError(t, err)
+
+	version, err := cluster.BackupSchemaVersion(backup)
+	require.NoError(t, err)
+	assert.Equal(t, cluster.SchemaVersion, version)
+
+	addNode(t, db, "5.6.7.8:666", 1, 1)
+	_, err = db.Exec("UPDATE nodes SET description='changed' WHERE address='1.2.3.4:666'")
+	require.NoError(t, err)
+
+	patch, err := cluster.RestorePatch(backup)
+	require.NoError(t, err)
+
+	var addresses []string
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.Exec(patch)
+		if err != nil {
+			return err
+		}
+
+		addresses, err = query.SelectStrings(ctx, tx, "SELECT address || description FROM nodes")
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4:666"}, addresses)
+}
+
+// Backups taken at another schema version or which aren't backups at all are rejected.
+func TestRestorePatch_Invalid(t *testing.T) {
+	_, err := cluster.RestorePatch("PRAGMA foreign_keys=OFF;\n")
+	assert.EqualError(t, err, "Not a global database backup")
+
+	backup := fmt.Sprintf("-- Incus global database backup\n-- Schema version: %d\nCOMMIT;\n", cluster.SchemaVersion-1)
+	_, err = cluster.RestorePatch(backup)
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "schema version"))
+}
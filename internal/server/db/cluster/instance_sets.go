@@ -0,0 +1,64 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"encoding/json"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target instance_sets.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e instance_set objects table=instance_sets
+//generate-database:mapper stmt -e instance_set objects-by-Project table=instance_sets
+//generate-database:mapper stmt -e instance_set objects-by-Project-and-Name table=instance_sets
+//generate-database:mapper stmt -e instance_set id table=instance_sets
+//generate-database:mapper stmt -e instance_set create table=instance_sets
+//generate-database:mapper stmt -e instance_set update table=instance_sets
+//generate-database:mapper stmt -e instance_set delete-by-Project-and-Name table=instance_sets
+//
+//generate-database:mapper method -i -e instance_set GetMany table=instance_sets
+//generate-database:mapper method -i -e instance_set GetOne table=instance_sets
+//generate-database:mapper method -i -e instance_set Exists table=instance_sets
+//generate-database:mapper method -i -e instance_set ID table=instance_sets
+//generate-database:mapper method -i -e instance_set Create table=instance_sets
+//generate-database:mapper method -i -e instance_set Update table=instance_sets
+//generate-database:mapper method -i -e instance_set DeleteOne-by-Project-and-Name table=instance_sets
+
+// InstanceSet is a value object holding db-related details about an instance set.
+type InstanceSet struct {
+	ID          int
+	ProjectID   int    `db:"omit=create,update"`
+	Project     string `db:"primary=yes&join=projects.name"`
+	Name        string `db:"primary=yes"`
+	Description string `db:"coalesce=''"`
+	Spec        string
+}
+
+// InstanceSetFilter specifies potential query parameter fields.
+type InstanceSetFilter struct {
+	ID      *int
+	Project *string
+	Name    *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (s *InstanceSet) ToAPI() (*api.InstanceSet, error) {
+	resp := api.InstanceSet{
+		Name:    s.Name,
+		Project: s.Project,
+	}
+
+	err := json.Unmarshal([]byte(s.Spec), &resp.InstanceSetPut)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Description = s.Description
+
+	return &resp, nil
+}
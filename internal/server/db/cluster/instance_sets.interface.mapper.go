@@ -0,0 +1,36 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// InstanceSetGenerated is an interface of generated methods for InstanceSet.
+type InstanceSetGenerated interface {
+	// GetInstanceSets returns all available instance_sets.
+	// generator: instance_set GetMany
+	GetInstanceSets(ctx context.Context, db dbtx, filters ...InstanceSetFilter) ([]InstanceSet, error)
+
+	// GetInstanceSet returns the instance_set with the given key.
+	// generator: instance_set GetOne
+	GetInstanceSet(ctx context.Context, db dbtx, project string, name string) (*InstanceSet, error)
+
+	// InstanceSetExists checks if a instance_set with the given key exists.
+	// generator: instance_set Exists
+	InstanceSetExists(ctx context.Context, db dbtx, project string, name string) (bool, error)
+
+	// GetInstanceSetID return the ID of the instance_set with the given key.
+	// generator: instance_set ID
+	GetInstanceSetID(ctx context.Context, db tx, project string, name string) (int64, error)
+
+	// CreateInstanceSet adds a new instance_set to the database.
+	// generator: instance_set Create
+	CreateInstanceSet(ctx context.Context, db dbtx, object InstanceSet) (int64, error)
+
+	// UpdateInstanceSet updates the instance_set matching the given key parameters.
+	// generator: instance_set Update
+	UpdateInstanceSet(ctx context.Context, db tx, project string, name string, object InstanceSet) error
+
+	// DeleteInstanceSet deletes the instance_set matching the given key parameters.
+	// generator: instance_set DeleteOne-by-Project-and-Name
+	DeleteInstanceSet(ctx context.Context, db dbtx, project string, name string) error
+}
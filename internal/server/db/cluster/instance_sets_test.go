@@ -0,0 +1,87 @@
+//go:build linux && cgo && !agent
+
+package cluster_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
+)
+
+// Instance sets are created, listed, updated and deleted within their project.
+func TestInstanceSets(t *testing.T) {
+	db := newDB(t)
+
+	var err error
+	cluster.PreparedStmts, err = cluster.PrepareStmts(db, false)
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO projects (name, description) VALUES ('default', ''), ('tenant1', '')")
+	require.NoError(t, err)
+
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		for _, project := range []string{"default", "tenant1"} {
+			_, err := cluster.CreateInstanceSet(ctx, tx, cluster.InstanceSet{Project: project, Name: "web", Spec: `{"replicas": 2}`})
+			require.NoError(t, err)
+		}
+
+		_, err := cluster.CreateInstanceSet(ctx, tx, cluster.InstanceSet{Project: "tenant1", Name: "db", Description: "Databases", Spec: `{"replicas": 1}`})
+		require.NoError(t, err)
+
+		// Names are unique within a project.
+		_, err = cluster.CreateInstanceSet(ctx, tx, cluster.InstanceSet{Project: "tenant1", Name: "web", Spec: "{}"})
+		assert.Error(t, err)
+
+		sets, err := cluster.GetInstanceSets(ctx, tx)
+		require.NoError(t, err)
+		assert.Len(t, sets, 3)
+
+		project := "tenant1"
+		sets, err = cluster.GetInstanceSets(ctx, tx, cluster.InstanceSetFilter{Project: &project})
+		require.NoError(t, err)
+		require.Len(t, sets, 2)
+		assert.Equal(t, "db", sets[0].Name)
+		assert.Equal(t, "web", sets[1].Name)
+
+		exists, err := cluster.InstanceSetExists(ctx, tx, "default", "db")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		err = cluster.UpdateInstanceSet(ctx, tx, "tenant1", "web", cluster.InstanceSet{Project: "tenant1", Name: "web", Description: "Frontends", Spec: `{"replicas": 5}`})
+		require.NoError(t, err)
+
+		dbInstanceSet, err := cluster.GetInstanceSet(ctx, tx, "tenant1", "web")
+		require.NoError(t, err)
+
+		info, err := dbInstanceSet.ToAPI()
+		require.NoError(t, err)
+		assert.Equal(t, "Frontends", dbInstanceSet.Description)
+		assert.Equal(t, 5, info.Replicas)
+		assert.Equal(t, "tenant1", info.Project)
+
+		// The set of the same name in another project is left untouched.
+		dbInstanceSet, err = cluster.GetInstanceSet(ctx, tx, "default", "web")
+		require.NoError(t, err)
+		assert.Equal(t, `{"replicas": 2}`, dbInstanceSet.Spec)
+
+		err = cluster.DeleteInstanceSet(ctx, tx, "tenant1", "web")
+		require.NoError(t, err)
+
+		exists, err = cluster.InstanceSetExists(ctx, tx, "tenant1", "web")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = cluster.InstanceSetExists(ctx, tx, "default", "web")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -219,6 +219,12 @@ INSERT INTO nodes_cluster_groups (node_id, group_id) VALUES(1, 1);
 				return err
 			}
 
+			// Built-in authorization roles
+			_, err = tx.Exec(authRolesBuiltin)
+			if err != nil {
+				return err
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -0,0 +1,140 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target project_templates.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e project_template objects table=project_templates
+//generate-database:mapper stmt -e project_template objects-by-Name table=project_templates
+//generate-database:mapper stmt -e project_template id table=project_templates
+//generate-database:mapper stmt -e project_template create table=project_templates
+//generate-database:mapper stmt -e project_template update table=project_templates
+//generate-database:mapper stmt -e project_template delete-by-Name table=project_templates
+//
+//generate-database:mapper method -i -e project_template GetMany table=project_templates
+//generate-database:mapper method -i -e project_template GetOne table=project_templates
+//generate-database:mapper method -i -e project_template ID table=project_templates
+//generate-database:mapper method -i -e project_template Exists table=project_templates
+//generate-database:mapper method -i -e project_template Create table=project_templates
+//generate-database:mapper method -i -e project_template Update table=project_templates
+//generate-database:mapper method -i -e project_template DeleteOne-by-Name table=project_templates
+
+// ProjectTemplate is a value object holding db-related details about a project template.
+type ProjectTemplate struct {
+	ID          int
+	Name        string `db:"primary=yes"`
+	Description string `db:"coalesce=''"`
+	Spec        string
+}
+
+// ProjectTemplateFilter specifies potential query parameter fields.
+type ProjectTemplateFilter struct {
+	ID   *int
+	Name *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (t *ProjectTemplate) ToAPI(ctx context.Context, tx *sql.Tx) (*api.ProjectTemplate, error) {
+	resp := api.ProjectTemplate{
+		Name: t.Name,
+	}
+
+	err := json.Unmarshal([]byte(t.Spec), &resp.ProjectTemplatePut)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Description = t.Description
+
+	projects, err := GetProjectTemplateProjects(ctx, tx, t.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.UsedBy = make([]string, 0, len(projects))
+	for _, name := range projects {
+		resp.UsedBy = append(resp.UsedBy, api.NewURL().Path(version.APIVersion, "projects", name).String())
+	}
+
+	return &resp, nil
+}
+
+// GetProjectTemplateProjects returns the names of the projects created from a template.
+func GetProjectTemplateProjects(ctx context.Context, tx *sql.Tx, templateID int) ([]string, error) {
+	stmt := `
+SELECT projects.name FROM project_templates_projects
+JOIN projects ON projects.id = project_templates_projects.project_id
+WHERE project_templates_projects.project_template_id = ?
+ORDER BY projects.name`
+
+	names, err := query.SelectStrings(ctx, tx, stmt, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed fetching projects of the template: %w", err)
+	}
+
+	return names, nil
+}
+
+// GetProjectTemplateApplied returns the name of the template a project was created from and the template
+// definition last applied to it. An empty name is returned for projects not created from a template.
+func GetProjectTemplateApplied(ctx context.Context, tx *sql.Tx, projectName string) (string, *api.ProjectTemplatePut, error) {
+	stmt := `
+SELECT project_templates.name, project_templates_projects.spec FROM project_templates_projects
+JOIN project_templates ON project_templates.id = project_templates_projects.project_template_id
+JOIN projects ON projects.id = project_templates_projects.project_id
+WHERE projects.name = ?`
+
+	var name string
+	var spec string
+
+	err := tx.QueryRowContext(ctx, stmt, projectName).Scan(&name, &spec)
+	if err == sql.ErrNoRows {
+		return "", nil, nil
+	}
+
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed fetching template of the project: %w", err)
+	}
+
+	applied := api.ProjectTemplatePut{}
+
+	err = json.Unmarshal([]byte(spec), &applied)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, &applied, nil
+}
+
+// SetProjectTemplateApplied records the template definition applied to a project.
+func SetProjectTemplateApplied(ctx context.Context, tx *sql.Tx, templateID int, projectID int64, applied api.ProjectTemplatePut) error {
+	spec, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+
+	stmt := `
+INSERT INTO project_templates_projects (project_template_id, project_id, spec) VALUES (?, ?, ?)
+ON CONFLICT (project_id) DO UPDATE SET project_template_id = excluded.project_template_id, spec = excluded.spec`
+
+	_, err = tx.ExecContext(ctx, stmt, templateID, projectID, string(spec))
+	if err != nil {
+		return fmt.Errorf("Failed recording the template applied to the project: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+//go:build linux && cgo && !agent
+
+package cluster_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// The definition applied to a project is recorded along with the template it comes from.
+func TestProjectTemplateApplied(t *testing.T) {
+	db := newDB(t)
+
+	var err error
+	cluster.PreparedStmts, err = cluster.PrepareStmts(db, false)
+	require.NoError(t, err)
+
+	_, err = db.Exec("INSERT INTO projects (name, description) VALUES ('default', ''), ('tenant1', '')")
+	require.NoError(t, err)
+
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		id, err := cluster.CreateProjectTemplate(ctx, tx, cluster.ProjectTemplate{Name: "tenant", Spec: "{}"})
+		require.NoError(t, err)
+
+		name, applied, err := cluster.GetProjectTemplateApplied(ctx, tx, "tenant1")
+		require.NoError(t, err)
+		assert.Equal(t, "", name)
+		assert.Nil(t, applied)
+
+		for _, limit := range []string{"5", "10"} {
+			err = cluster.SetProjectTemplateApplied(ctx, tx, int(id), 2, api.ProjectTemplatePut{Config: map[string]string{"limits.instances": limit}})
+			require.NoError(t, err)
+		}
+
+		name, applied, err = cluster.GetProjectTemplateApplied(ctx, tx, "tenant1")
+		require.NoError(t, err)
+		assert.Equal(t, "tenant", name)
+		assert.Equal(t, "10", applied.Config["limits.instances"])
+
+		dbTemplate, err := cluster.GetProjectTemplate(ctx, tx, "tenant")
+		require.NoError(t, err)
+
+		info, err := dbTemplate.ToAPI(ctx, tx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/1.0/projects/tenant1"}, info.UsedBy)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,89 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ProjectUsage is a sample of the resources consumed by the instances of a project
+// running on a cluster member during the given duration.
+type ProjectUsage struct {
+	ProjectID               int
+	Date                    time.Time
+	Duration                time.Duration
+	Instances               int64
+	CPUSeconds              float64
+	MemoryBytes             int64
+	DiskBytes               int64
+	DiskReadBytes           int64
+	DiskWrittenBytes        int64
+	NetworkReceivedBytes    int64
+	NetworkTransmittedBytes int64
+}
+
+// CreateProjectUsage adds a new usage sample.
+func CreateProjectUsage(ctx context.Context, tx *sql.Tx, usage ProjectUsage) error {
+	q := `
+INSERT INTO projects_usage (project_id, date, duration, instances, cpu_seconds, memory_bytes, disk_bytes, disk_read_bytes, disk_written_bytes, network_received_bytes, network_transmitted_bytes)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+	_, err := tx.ExecContext(ctx, q, usage.ProjectID, usage.Date.UTC(), int64(usage.Duration.Seconds()), usage.Instances, usage.CPUSeconds, usage.MemoryBytes, usage.DiskBytes, usage.DiskReadBytes, usage.DiskWrittenBytes, usage.NetworkReceivedBytes, usage.NetworkTransmittedBytes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetProjectUsage returns the resources consumed by the project between the two dates.
+// Memory and disk space are summed over time, weighted by the duration of each sample.
+func GetProjectUsage(ctx context.Context, tx *sql.Tx, projectID int, from time.Time, to time.Time) (*api.ProjectUsage, error) {
+	q := `
+SELECT
+    IFNULL(SUM(instances * duration), 0),
+    IFNULL(SUM(cpu_seconds), 0),
+    IFNULL(SUM(CAST(memory_bytes AS REAL) * duration), 0),
+    IFNULL(SUM(CAST(disk_bytes AS REAL) * duration), 0),
+    IFNULL(SUM(disk_read_bytes), 0),
+    IFNULL(SUM(disk_written_bytes), 0),
+    IFNULL(SUM(network_received_bytes), 0),
+    IFNULL(SUM(network_transmitted_bytes), 0)
+FROM projects_usage
+WHERE project_id = ? AND date > ? AND date <= ?
+`
+	usage := api.ProjectUsage{
+		From: from,
+		To:   to,
+	}
+
+	err := tx.QueryRowContext(ctx, q, projectID, from.UTC(), to.UTC()).Scan(
+		&usage.InstanceSeconds,
+		&usage.CPUSeconds,
+		&usage.MemoryByteSeconds,
+		&usage.DiskByteSeconds,
+		&usage.DiskReadBytes,
+		&usage.DiskWrittenBytes,
+		&usage.NetworkReceivedBytes,
+		&usage.NetworkTransmittedBytes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// DeleteProjectUsageBefore removes the usage samples older than the given date.
+func DeleteProjectUsageBefore(ctx context.Context, tx *sql.Tx, date time.Time) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM projects_usage WHERE date <= ?", date.UTC())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
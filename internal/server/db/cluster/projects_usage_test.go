@@ -0,0 +1,73 @@
+//go:build linux && cgo && !agent
+
+package cluster_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Usage samples are summed over the requested period, with gauges weighted by the sample duration.
+func TestGetProjectUsage(t *testing.T) {
+	db := newDB(t)
+
+	_, err := db.Exec("INSERT INTO projects (name, description) VALUES ('default', '')")
+	require.NoError(t, err)
+
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	var usage *api.ProjectUsage
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		for i := 1; i <= 3; i++ {
+			err := cluster.CreateProjectUsage(ctx, tx, cluster.ProjectUsage{
+				ProjectID:               1,
+				Date:                    start.Add(time.Duration(i) * 5 * time.Minute),
+				Duration:                5 * time.Minute,
+				Instances:               2,
+				CPUSeconds:              1.5,
+				MemoryBytes:             1024,
+				DiskBytes:               2048,
+				DiskReadBytes:           10,
+				DiskWrittenBytes:        20,
+				NetworkReceivedBytes:    30,
+				NetworkTransmittedBytes: 40,
+			})
+			require.NoError(t, err)
+		}
+
+		// The first sample is outside of the period.
+		usage, err = cluster.GetProjectUsage(ctx, tx, 1, start.Add(5*time.Minute), start.Add(time.Hour))
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2*2*300), usage.InstanceSeconds)
+	assert.Equal(t, 3.0, usage.CPUSeconds)
+	assert.Equal(t, float64(2*1024*300), usage.MemoryByteSeconds)
+	assert.Equal(t, float64(2*2048*300), usage.DiskByteSeconds)
+	assert.Equal(t, int64(20), usage.DiskReadBytes)
+	assert.Equal(t, int64(40), usage.DiskWrittenBytes)
+	assert.Equal(t, int64(60), usage.NetworkReceivedBytes)
+	assert.Equal(t, int64(80), usage.NetworkTransmittedBytes)
+
+	// Old samples are pruned.
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		err := cluster.DeleteProjectUsageBefore(ctx, tx, start.Add(10*time.Minute))
+		require.NoError(t, err)
+
+		usage, err = cluster.GetProjectUsage(ctx, tx, 1, start, start.Add(time.Hour))
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1.5, usage.CPUSeconds)
+}
@@ -0,0 +1,60 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target replication_remotes.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e replication_remote objects
+//generate-database:mapper stmt -e replication_remote objects-by-Name
+//generate-database:mapper stmt -e replication_remote id
+//generate-database:mapper stmt -e replication_remote create
+//generate-database:mapper stmt -e replication_remote update
+//generate-database:mapper stmt -e replication_remote delete-by-Name
+//
+//generate-database:mapper method -i -e replication_remote GetMany
+//generate-database:mapper method -i -e replication_remote GetOne
+//generate-database:mapper method -i -e replication_remote Exists
+//generate-database:mapper method -i -e replication_remote ID
+//generate-database:mapper method -i -e replication_remote Create
+//generate-database:mapper method -i -e replication_remote Update
+//generate-database:mapper method -i -e replication_remote DeleteOne-by-Name
+
+// ReplicationRemote is a value object holding db-related details about a replication remote.
+// The client key is never converted to its API representation.
+type ReplicationRemote struct {
+	ID                int
+	Name              string `db:"primary=yes"`
+	Description       string `db:"coalesce=''"`
+	Address           string
+	Certificate       string `db:"coalesce=''"`
+	TargetProject     string `db:"coalesce=''"`
+	ClientCertificate string
+	ClientKey         string
+}
+
+// ReplicationRemoteFilter specifies potential query parameter fields.
+type ReplicationRemoteFilter struct {
+	ID   *int
+	Name *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (r *ReplicationRemote) ToAPI() *api.ReplicationRemote {
+	return &api.ReplicationRemote{
+		ReplicationRemotePut: api.ReplicationRemotePut{
+			Description:   r.Description,
+			Address:       r.Address,
+			Certificate:   r.Certificate,
+			TargetProject: r.TargetProject,
+		},
+		Name:              r.Name,
+		ClientCertificate: r.ClientCertificate,
+	}
+}
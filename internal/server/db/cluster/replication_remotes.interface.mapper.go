@@ -0,0 +1,36 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// ReplicationRemoteGenerated is an interface of generated methods for ReplicationRemote.
+type ReplicationRemoteGenerated interface {
+	// GetReplicationRemotes returns all available replication_remotes.
+	// generator: replication_remote GetMany
+	GetReplicationRemotes(ctx context.Context, db dbtx, filters ...ReplicationRemoteFilter) ([]ReplicationRemote, error)
+
+	// GetReplicationRemote returns the replication_remote with the given key.
+	// generator: replication_remote GetOne
+	GetReplicationRemote(ctx context.Context, db dbtx, name string) (*ReplicationRemote, error)
+
+	// ReplicationRemoteExists checks if a replication_remote with the given key exists.
+	// generator: replication_remote Exists
+	ReplicationRemoteExists(ctx context.Context, db dbtx, name string) (bool, error)
+
+	// GetReplicationRemoteID return the ID of the replication_remote with the given key.
+	// generator: replication_remote ID
+	GetReplicationRemoteID(ctx context.Context, db tx, name string) (int64, error)
+
+	// CreateReplicationRemote adds a new replication_remote to the database.
+	// generator: replication_remote Create
+	CreateReplicationRemote(ctx context.Context, db dbtx, object ReplicationRemote) (int64, error)
+
+	// UpdateReplicationRemote updates the replication_remote matching the given key parameters.
+	// generator: replication_remote Update
+	UpdateReplicationRemote(ctx context.Context, db tx, name string, object ReplicationRemote) error
+
+	// DeleteReplicationRemote deletes the replication_remote matching the given key parameters.
+	// generator: replication_remote DeleteOne-by-Name
+	DeleteReplicationRemote(ctx context.Context, db dbtx, name string) error
+}
@@ -0,0 +1,79 @@
+//go:build linux && cgo && !agent
+
+package cluster_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Replication remotes are created, listed, updated and deleted, without exposing their client key.
+func TestReplicationRemotes(t *testing.T) {
+	db := newDB(t)
+
+	var err error
+	cluster.PreparedStmts, err = cluster.PrepareStmts(db, false)
+	require.NoError(t, err)
+
+	err = query.Transaction(context.TODO(), db, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := cluster.CreateReplicationRemote(ctx, tx, cluster.ReplicationRemote{Name: "dr-site", Address: "https://dr.example.net:8443", ClientCertificate: "cert1", ClientKey: "key1"})
+		require.NoError(t, err)
+
+		_, err = cluster.CreateReplicationRemote(ctx, tx, cluster.ReplicationRemote{Name: "backup", Description: "Backup site", Address: "https://backup.example.net:8443", TargetProject: "replicas", ClientCertificate: "cert2", ClientKey: "key2"})
+		require.NoError(t, err)
+
+		// Names are unique.
+		_, err = cluster.CreateReplicationRemote(ctx, tx, cluster.ReplicationRemote{Name: "dr-site", Address: "https://other.example.net:8443"})
+		assert.Error(t, err)
+
+		remotes, err := cluster.GetReplicationRemotes(ctx, tx)
+		require.NoError(t, err)
+		require.Len(t, remotes, 2)
+		assert.Equal(t, "backup", remotes[0].Name)
+		assert.Equal(t, "dr-site", remotes[1].Name)
+
+		dbRemote, err := cluster.GetReplicationRemote(ctx, tx, "backup")
+		require.NoError(t, err)
+		assert.Equal(t, "replicas", dbRemote.TargetProject)
+		assert.Equal(t, "key2", dbRemote.ClientKey)
+
+		info := dbRemote.ToAPI()
+		assert.Equal(t, "Backup site", info.Description)
+		assert.Equal(t, "cert2", info.ClientCertificate)
+
+		dbRemote.Address = "https://backup2.example.net:8443"
+		err = cluster.UpdateReplicationRemote(ctx, tx, "backup", *dbRemote)
+		require.NoError(t, err)
+
+		dbRemote, err = cluster.GetReplicationRemote(ctx, tx, "backup")
+		require.NoError(t, err)
+		assert.Equal(t, "https://backup2.example.net:8443", dbRemote.Address)
+		assert.Equal(t, "key2", dbRemote.ClientKey)
+
+		err = cluster.DeleteReplicationRemote(ctx, tx, "backup")
+		require.NoError(t, err)
+
+		exists, err := cluster.ReplicationRemoteExists(ctx, tx, "backup")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = cluster.ReplicationRemoteExists(ctx, tx, "dr-site")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		err = cluster.DeleteReplicationRemote(ctx, tx, "backup")
+		assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target secrets.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e secret objects
+//generate-database:mapper stmt -e secret objects-by-Project
+//generate-database:mapper stmt -e secret objects-by-Project-and-Name
+//generate-database:mapper stmt -e secret id
+//generate-database:mapper stmt -e secret create
+//generate-database:mapper stmt -e secret update
+//generate-database:mapper stmt -e secret delete-by-Project-and-Name
+//
+//generate-database:mapper method -i -e secret GetMany
+//generate-database:mapper method -i -e secret GetOne
+//generate-database:mapper method -i -e secret Exists
+//generate-database:mapper method -i -e secret ID
+//generate-database:mapper method -i -e secret Create
+//generate-database:mapper method -i -e secret Update
+//generate-database:mapper method -i -e secret DeleteOne-by-Project-and-Name
+
+// Secret is a value object holding db-related details about a secret.
+// The value is stored encrypted and is never converted to its API representation.
+type Secret struct {
+	ID          int
+	ProjectID   int    `db:"omit=create,update"`
+	Project     string `db:"primary=yes&join=projects.name"`
+	Name        string `db:"primary=yes"`
+	Description string `db:"coalesce=''"`
+	Value       string
+}
+
+// SecretFilter specifies potential query parameter fields.
+type SecretFilter struct {
+	ID      *int
+	Project *string
+	Name    *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (s *Secret) ToAPI() *api.Secret {
+	return &api.Secret{
+		SecretPut: api.SecretPut{
+			Description: s.Description,
+		},
+		Name:    s.Name,
+		Project: s.Project,
+	}
+}
@@ -0,0 +1,36 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// SecretGenerated is an interface of generated methods for Secret.
+type SecretGenerated interface {
+	// GetSecrets returns all available secrets.
+	// generator: secret GetMany
+	GetSecrets(ctx context.Context, db dbtx, filters ...SecretFilter) ([]Secret, error)
+
+	// GetSecret returns the secret with the given key.
+	// generator: secret GetOne
+	GetSecret(ctx context.Context, db dbtx, project string, name string) (*Secret, error)
+
+	// SecretExists checks if a secret with the given key exists.
+	// generator: secret Exists
+	SecretExists(ctx context.Context, db dbtx, project string, name string) (bool, error)
+
+	// GetSecretID return the ID of the secret with the given key.
+	// generator: secret ID
+	GetSecretID(ctx context.Context, db tx, project string, name string) (int64, error)
+
+	// CreateSecret adds a new secret to the database.
+	// generator: secret Create
+	CreateSecret(ctx context.Context, db dbtx, object Secret) (int64, error)
+
+	// UpdateSecret updates the secret matching the given key parameters.
+	// generator: secret Update
+	UpdateSecret(ctx context.Context, db tx, project string, name string, object Secret) error
+
+	// DeleteSecret deletes the secret matching the given key parameters.
+	// generator: secret DeleteOne-by-Project-and-Name
+	DeleteSecret(ctx context.Context, db dbtx, project string, name string) error
+}
@@ -0,0 +1,64 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import (
+	"encoding/json"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// Code generation directives.
+//
+//generate-database:mapper target stacks.mapper.go
+//generate-database:mapper reset -i -b "//go:build linux && cgo && !agent"
+//
+//generate-database:mapper stmt -e stack objects
+//generate-database:mapper stmt -e stack objects-by-Project
+//generate-database:mapper stmt -e stack objects-by-Project-and-Name
+//generate-database:mapper stmt -e stack id
+//generate-database:mapper stmt -e stack create
+//generate-database:mapper stmt -e stack update
+//generate-database:mapper stmt -e stack delete-by-Project-and-Name
+//
+//generate-database:mapper method -i -e stack GetMany
+//generate-database:mapper method -i -e stack GetOne
+//generate-database:mapper method -i -e stack Exists
+//generate-database:mapper method -i -e stack ID
+//generate-database:mapper method -i -e stack Create
+//generate-database:mapper method -i -e stack Update
+//generate-database:mapper method -i -e stack DeleteOne-by-Project-and-Name
+
+// Stack is a value object holding db-related details about a stack.
+type Stack struct {
+	ID          int
+	ProjectID   int    `db:"omit=create,update"`
+	Project     string `db:"primary=yes&join=projects.name"`
+	Name        string `db:"primary=yes"`
+	Description string `db:"coalesce=''"`
+	Spec        string
+}
+
+// StackFilter specifies potential query parameter fields.
+type StackFilter struct {
+	ID      *int
+	Project *string
+	Name    *string
+}
+
+// ToAPI converts the DB record to an API record.
+func (s *Stack) ToAPI() (*api.Stack, error) {
+	resp := api.Stack{
+		Name:    s.Name,
+		Project: s.Project,
+	}
+
+	err := json.Unmarshal([]byte(s.Spec), &resp.StackPut)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Description = s.Description
+
+	return &resp, nil
+}
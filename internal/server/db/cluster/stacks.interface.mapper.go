@@ -0,0 +1,36 @@
+//go:build linux && cgo && !agent
+
+package cluster
+
+import "context"
+
+// StackGenerated is an interface of generated methods for Stack.
+type StackGenerated interface {
+	// GetStacks returns all available stacks.
+	// generator: stack GetMany
+	GetStacks(ctx context.Context, db dbtx, filters ...StackFilter) ([]Stack, error)
+
+	// GetStack returns the stack with the given key.
+	// generator: stack GetOne
+	GetStack(ctx context.Context, db dbtx, project string, name string) (*Stack, error)
+
+	// StackExists checks if a stack with the given key exists.
+	// generator: stack Exists
+	StackExists(ctx context.Context, db dbtx, project string, name string) (bool, error)
+
+	// GetStackID return the ID of the stack with the given key.
+	// generator: stack ID
+	GetStackID(ctx context.Context, db tx, project string, name string) (int64, error)
+
+	// CreateStack adds a new stack to the database.
+	// generator: stack Create
+	CreateStack(ctx context.Context, db dbtx, object Stack) (int64, error)
+
+	// UpdateStack updates the stack matching the given key parameters.
+	// generator: stack Update
+	UpdateStack(ctx context.Context, db tx, project string, name string, object Stack) error
+
+	// DeleteStack deletes the stack matching the given key parameters.
+	// generator: stack DeleteOne-by-Project-and-Name
+	DeleteStack(ctx context.Context, db dbtx, project string, name string) error
+}
@@ -112,6 +112,290 @@ var updates = map[int]schema.Update{
 	73: updateFromV72,
 	74: updateFromV73,
 	75: updateFromV74,
+	76: updateFromV75,
+	77: updateFromV76,
+	78: updateFromV77,
+	79: updateFromV78,
+	80: updateFromV79,
+	81: updateFromV80,
+	82: updateFromV81,
+	83: updateFromV82,
+	84: updateFromV83,
+	85: updateFromV84,
+}
+
+// updateFromV84 adds the secrets table.
+func updateFromV84(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE secrets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    value TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding secrets table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV83 adds the project templates tables.
+func updateFromV83(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE project_templates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    spec TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE project_templates_projects (
+    project_template_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    spec TEXT NOT NULL,
+    FOREIGN KEY (project_template_id) REFERENCES project_templates (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    UNIQUE (project_id)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding project templates tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV82 adds the table holding the samples of the resources consumed by the projects.
+func updateFromV82(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE projects_usage (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    date DATETIME NOT NULL,
+    duration INTEGER NOT NULL,
+    instances INTEGER NOT NULL DEFAULT 0,
+    cpu_seconds REAL NOT NULL DEFAULT 0,
+    memory_bytes INTEGER NOT NULL DEFAULT 0,
+    disk_bytes INTEGER NOT NULL DEFAULT 0,
+    disk_read_bytes INTEGER NOT NULL DEFAULT 0,
+    disk_written_bytes INTEGER NOT NULL DEFAULT 0,
+    network_received_bytes INTEGER NOT NULL DEFAULT 0,
+    network_transmitted_bytes INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE INDEX projects_usage_project_id_date ON projects_usage (project_id, date);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding projects usage table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV81 adds an optional expiry date to trusted certificates.
+func updateFromV81(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.Exec("ALTER TABLE certificates ADD COLUMN expiry_date DATETIME;")
+	if err != nil {
+		return fmt.Errorf("Failed adding expiry date to certificates: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV80 adds the API tokens tables.
+func updateFromV80(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_tokens (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    identity TEXT NOT NULL,
+    secret_hash TEXT NOT NULL,
+    restricted INTEGER NOT NULL DEFAULT 0,
+    creation_date DATETIME NOT NULL,
+    expiry_date DATETIME NOT NULL,
+    UNIQUE (name),
+    UNIQUE (secret_hash)
+);
+CREATE TABLE auth_tokens_projects (
+    auth_token_id INTEGER NOT NULL,
+    project_id INTEGER NOT NULL,
+    FOREIGN KEY (auth_token_id) REFERENCES auth_tokens (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE,
+    UNIQUE (auth_token_id, project_id)
+);
+CREATE TABLE auth_tokens_permissions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_token_id INTEGER NOT NULL,
+    entity_type TEXT NOT NULL,
+    entitlement TEXT NOT NULL,
+    UNIQUE (auth_token_id, entity_type, entitlement),
+    FOREIGN KEY (auth_token_id) REFERENCES auth_tokens (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding API tokens tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV79 adds the mapping of identity provider groups to authorization groups.
+func updateFromV79(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_groups_identity_provider_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE (auth_group_id, name),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding identity provider groups table: %w", err)
+	}
+
+	_, err = tx.Exec(authRolesBuiltin)
+	if err != nil {
+		return fmt.Errorf("Failed adding built-in authorization roles: %w", err)
+	}
+
+	return nil
+}
+
+// authRolesBuiltin creates the built-in admin and viewer roles, unless roles with those names already exist.
+const authRolesBuiltin = `
+INSERT OR IGNORE INTO auth_roles (name, description) VALUES ('admin', 'Full access to all entities');
+INSERT OR IGNORE INTO auth_roles (name, description) VALUES ('viewer', 'View all entities');
+INSERT INTO auth_roles_permissions (auth_role_id, entity_type, entitlement)
+    SELECT id, '*', 'can_edit' FROM auth_roles WHERE name = 'admin' AND NOT EXISTS (SELECT 1 FROM auth_roles_permissions WHERE auth_role_id = auth_roles.id);
+INSERT INTO auth_roles_permissions (auth_role_id, entity_type, entitlement)
+    SELECT id, '*', 'can_view' FROM auth_roles WHERE name = 'viewer' AND NOT EXISTS (SELECT 1 FROM auth_roles_permissions WHERE auth_role_id = auth_roles.id);
+`
+
+// updateFromV78 adds the tables of the built-in role-based access control.
+func updateFromV78(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE auth_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE auth_roles_permissions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_role_id INTEGER NOT NULL,
+    entity_type TEXT NOT NULL,
+    entitlement TEXT NOT NULL,
+    UNIQUE (auth_role_id, entity_type, entitlement),
+    FOREIGN KEY (auth_role_id) REFERENCES auth_roles (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_groups (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    UNIQUE (name)
+);
+CREATE TABLE auth_groups_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    authentication_method TEXT NOT NULL,
+    identifier TEXT NOT NULL,
+    UNIQUE (auth_group_id, authentication_method, identifier),
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+CREATE TABLE auth_groups_roles (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    auth_group_id INTEGER NOT NULL,
+    auth_role_id INTEGER NOT NULL,
+    project_id INTEGER,
+    FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE,
+    FOREIGN KEY (auth_role_id) REFERENCES auth_roles (id) ON DELETE CASCADE,
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX auth_groups_roles_unique_auth_group_id_auth_role_id_project_id ON auth_groups_roles(auth_group_id, auth_role_id, IFNULL(project_id, -1));
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding authorization tables: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV77 adds the replication_remotes table.
+func updateFromV77(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE replication_remotes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    address TEXT NOT NULL,
+    certificate TEXT NOT NULL,
+    target_project TEXT NOT NULL,
+    client_certificate TEXT NOT NULL,
+    client_key TEXT NOT NULL,
+    UNIQUE (name)
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding replication_remotes table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV76 adds the instance_sets table.
+func updateFromV76(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE instance_sets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    spec TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding instance_sets table: %w", err)
+	}
+
+	return nil
+}
+
+// updateFromV75 adds the stacks table.
+func updateFromV75(ctx context.Context, tx *sql.Tx) error {
+	q := `
+CREATE TABLE stacks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+    project_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    spec TEXT NOT NULL,
+    UNIQUE (project_id, name),
+    FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE CASCADE
+);
+`
+	_, err := tx.Exec(q)
+	if err != nil {
+		return fmt.Errorf("Failed adding stacks table: %w", err)
+	}
+
+	return nil
 }
 
 // updateFromV74 removes the index preventing the same integration to be used multiple times.
@@ -763,3 +763,25 @@ func TestUpdateFromV34(t *testing.T) {
 	assert.Equal(t, id, 2)
 	assert.Equal(t, nodeID, nil)
 }
+
+func TestUpdateFromV79(t *testing.T) {
+	schema := cluster.Schema()
+	db, err := schema.ExerciseUpdate(80, func(db *sql.DB) {
+		// A role named like a built-in one is left as is.
+		_, err := db.Exec("INSERT INTO auth_roles (name, description) VALUES ('viewer', 'Custom viewer')")
+		require.NoError(t, err)
+
+		_, err = db.Exec("INSERT INTO auth_roles_permissions (auth_role_id, entity_type, entitlement) VALUES (1, 'instance', 'can_view')")
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+
+	permissions, err := query.SelectStrings(context.Background(), tx, "SELECT auth_roles.name || ':' || entity_type || ':' || entitlement FROM auth_roles_permissions JOIN auth_roles ON auth_roles.id = auth_role_id ORDER BY auth_roles.name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin:*:can_edit", "viewer:instance:can_view"}, permissions)
+}
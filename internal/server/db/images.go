@@ -1092,14 +1092,15 @@ WHERE images.fingerprint = ? AND images.auto_update = ?
 }
 
 // GetNodesWithoutImage returns the addresses of online nodes which don't have the image.
+// Cordoned nodes are left out as they shouldn't receive new images.
 func (c *ClusterTx) GetNodesWithoutImage(ctx context.Context, fingerprint string) ([]string, error) {
-	q := `
-SELECT DISTINCT nodes.address FROM nodes WHERE nodes.address NOT IN (
+	q := fmt.Sprintf(`
+SELECT DISTINCT nodes.address FROM nodes WHERE nodes.state != %d AND nodes.address NOT IN (
   SELECT DISTINCT nodes.address FROM nodes
     LEFT JOIN images_nodes ON images_nodes.node_id = nodes.id
     LEFT JOIN images ON images_nodes.image_id = images.id
   WHERE images.fingerprint = ?)
-`
+`, ClusterMemberStateCordoned)
 	return c.getNodesByImageFingerprint(ctx, q, fingerprint, nil)
 }
 
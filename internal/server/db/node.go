@@ -54,6 +54,7 @@ const (
 	ClusterMemberStateCreated   = 0
 	ClusterMemberStatePending   = 1
 	ClusterMemberStateEvacuated = 2
+	ClusterMemberStateCordoned  = 3
 )
 
 // NodeInfo holds information about a single member in a cluster.
@@ -150,6 +151,9 @@ func (n NodeInfo) ToAPI(ctx context.Context, tx *ClusterTx, args NodeInfoArgs) (
 	if n.State == ClusterMemberStateEvacuated {
 		result.Status = "Evacuated"
 		result.Message = "Unavailable due to maintenance"
+	} else if n.State == ClusterMemberStateCordoned {
+		result.Status = "Cordoned"
+		result.Message = "Not accepting new instances"
 	} else if n.IsOffline(args.OfflineThreshold) {
 		result.Status = "Offline"
 		result.Message = fmt.Sprintf("No heartbeat for %s (%s)", time.Since(n.Heartbeat), n.Heartbeat)
@@ -1002,6 +1006,55 @@ func (c *ClusterTx) ClearNode(ctx context.Context, id int64) error {
 		return err
 	}
 
+	return c.clearNodeImages(ctx, id)
+}
+
+// ResetNode points the node with the given ID to the address and architecture of a
+// server replacing it.
+//
+// The node keeps its ID, name, configuration, groups and instances. Its node-specific
+// storage pool and network configuration is only removed by ClearNodeLocalData once the
+// replacing server has joined.
+func (c *ClusterTx) ResetNode(ctx context.Context, id int64, address string, arch int) error {
+	result, err := c.tx.Exec("UPDATE nodes SET address=?, arch=?, schema=?, api_extensions=? WHERE id=?", address, arch, cluster.SchemaVersion, version.APIExtensionsCount(), id)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n != 1 {
+		return fmt.Errorf("query updated %d rows instead of 1", n)
+	}
+
+	return nil
+}
+
+// ClearNodeLocalData removes the node-specific storage pool and network configuration
+// of the node with the given ID, along with the images it was hosting.
+//
+// This is used when a server replacing the node joins, since it provides its own
+// configuration.
+func (c *ClusterTx) ClearNodeLocalData(ctx context.Context, id int64) error {
+	_, err := c.tx.Exec("DELETE FROM storage_pools_config WHERE node_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.tx.Exec("DELETE FROM networks_config WHERE node_id=?", id)
+	if err != nil {
+		return err
+	}
+
+	return c.clearNodeImages(ctx, id)
+}
+
+// clearNodeImages removes the images associated with this node, deleting the
+// ones which aren't available on any other node.
+func (c *ClusterTx) clearNodeImages(ctx context.Context, id int64) error {
 	// Get the IDs of the images this node is hosting.
 	ids, err := query.SelectIntegers(ctx, c.tx, "SELECT image_id FROM images_nodes WHERE node_id=?", id)
 	if err != nil {
@@ -1059,11 +1112,13 @@ func (c *ClusterTx) GetNodeOfflineThreshold(ctx context.Context) (time.Duration,
 // GetCandidateMembers returns cluster members that are online, in created state and don't need manual targeting.
 // It excludes members that do not support any of the targetArchitectures (if non-nil) or not in targetClusterGroup
 // (if non-empty). It also takes into account any restrictions on allowedClusterGroups (if non-nil).
+// The members are sorted by the average number of instances per member of their failure domain and then by
+// their own number of instances, so the first member is the preferred placement target.
 func (c *ClusterTx) GetCandidateMembers(ctx context.Context, allMembers []NodeInfo, targetArchitectures []int, targetClusterGroup string, allowedClusterGroups []string, offlineThreshold time.Duration) ([]NodeInfo, error) {
 	var candidateMembers []NodeInfo
 
 	for _, member := range allMembers {
-		// Skip pending, evacuated or offline members.
+		// Skip pending, evacuated, cordoned or offline members.
 		if member.State != ClusterMemberStateCreated || member.IsOffline(offlineThreshold) {
 			continue
 		}
@@ -1135,11 +1190,34 @@ func (c *ClusterTx) GetCandidateMembers(ctx context.Context, allMembers []NodeIn
 		}
 	}
 
+	// Spread instances across failure domains first, then across the members of a domain.
+	domains, err := c.GetNodesFailureDomains(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	memberCounts := make(map[string]int, len(candidateMembers))
+	domainCounts := map[uint64]int{}
+	domainMembers := map[uint64]int{}
+	for _, member := range candidateMembers {
+		count, _ := c.GetInstancesCount(ctx, "", member.Name, true)
+		memberCounts[member.Name] = count
+		domainCounts[domains[member.Address]] += count
+		domainMembers[domains[member.Address]]++
+	}
+
 	sort.Slice(candidateMembers, func(i int, j int) bool {
-		iCount, _ := c.GetInstancesCount(ctx, "", candidateMembers[i].Name, true)
-		jCount, _ := c.GetInstancesCount(ctx, "", candidateMembers[j].Name, true)
+		// Compare the average number of instances per member of each domain, so that larger
+		// domains aren't penalized for having more members.
+		iDomain := domains[candidateMembers[i].Address]
+		jDomain := domains[candidateMembers[j].Address]
+		iDomainLoad := domainCounts[iDomain] * domainMembers[jDomain]
+		jDomainLoad := domainCounts[jDomain] * domainMembers[iDomain]
+		if iDomainLoad != jDomainLoad {
+			return iDomainLoad < jDomainLoad
+		}
 
-		return iCount < jCount
+		return memberCounts[candidateMembers[i].Name] < memberCounts[candidateMembers[j].Name]
 	})
 
 	return candidateMembers, nil
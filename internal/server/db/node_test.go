@@ -4,6 +4,7 @@ package db_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	"github.com/lxc/incus/v6/internal/server/db/operationtype"
+	"github.com/lxc/incus/v6/internal/server/db/query"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/version"
 	"github.com/lxc/incus/v6/shared/osarch"
@@ -201,6 +203,64 @@ func TestSetNodePendingFlag(t *testing.T) {
 	assert.Equal(t, id, node.ID)
 }
 
+// Point a node to the address of a replacing server.
+func TestResetNode(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	id, err := tx.CreateNode("buzz", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	_, err = tx.Tx().Exec(`
+INSERT INTO instances (id, node_id, name, architecture, type, project_id, description) VALUES (1, ?, 'foo', 1, 1, 1, '')
+`, id)
+	require.NoError(t, err)
+
+	err = tx.ResetNode(context.Background(), id, "5.6.7.8:666", 2)
+	require.NoError(t, err)
+
+	node, err := tx.GetNodeByName(context.Background(), "buzz")
+	require.NoError(t, err)
+	assert.Equal(t, id, node.ID)
+	assert.Equal(t, "5.6.7.8:666", node.Address)
+	assert.Equal(t, 2, node.Architecture)
+
+	// Instances are kept.
+	message, err := tx.NodeIsEmpty(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "Node still has the following instances: foo", message)
+}
+
+// Remove the node-specific configuration of a replaced node.
+func TestClearNodeLocalData(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	id, err := tx.CreateNode("buzz", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	poolID, err := tx.CreateStoragePool(context.Background(), "pool1", "", "dir", map[string]string{"source": "/foo"})
+	require.NoError(t, err)
+
+	err = tx.CreateStoragePoolConfig(poolID, id, map[string]string{"source": "/bar"})
+	require.NoError(t, err)
+
+	err = tx.ResetNode(context.Background(), id, "5.6.7.8:666", 2)
+	require.NoError(t, err)
+
+	// The configuration is kept until the replacing server joins.
+	count, err := query.Count(context.Background(), tx.Tx(), "storage_pools_config", "node_id=?", id)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	err = tx.ClearNodeLocalData(context.Background(), id)
+	require.NoError(t, err)
+
+	count, err = query.Count(context.Background(), tx.Tx(), "storage_pools_config", "node_id=?", id)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
+
 // Update the heartbeat of a node.
 func TestSetNodeHeartbeat(t *testing.T) {
 	tx, cleanup := db.NewTestClusterTx(t)
@@ -382,6 +442,68 @@ INSERT INTO operations (id, uuid, node_id, type, project_id) VALUES (1, 'abc', 1
 	assert.Equal(t, "buzz", members[0].Name)
 }
 
+// Cordoned members are never returned as candidates.
+func TestGetCandidateMembers_Cordoned(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	id, err := tx.CreateNode("buzz", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	err = tx.UpdateNodeStatus(id, db.ClusterMemberStateCordoned)
+	require.NoError(t, err)
+
+	allMembers, err := tx.GetNodes(context.Background())
+	require.NoError(t, err)
+
+	members, err := tx.GetCandidateMembers(context.Background(), allMembers, nil, "", nil, time.Duration(db.DefaultOfflineThreshold)*time.Second)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+
+	assert.Equal(t, "none", members[0].Name)
+}
+
+// If members are in different failure domains, prefer the domain with the
+// fewest instances per member, even if it has more instances in total.
+func TestGetCandidateMembers_FailureDomain(t *testing.T) {
+	tx, cleanup := db.NewTestClusterTx(t)
+	defer cleanup()
+
+	buzzID, err := tx.CreateNode("buzz", "1.2.3.4:666")
+	require.NoError(t, err)
+
+	ruspID, err := tx.CreateNode("rusp", "5.6.7.8:666")
+	require.NoError(t, err)
+
+	err = tx.UpdateNodeFailureDomain(context.Background(), 1, "rack1")
+	require.NoError(t, err)
+
+	err = tx.UpdateNodeFailureDomain(context.Background(), buzzID, "rack1")
+	require.NoError(t, err)
+
+	err = tx.UpdateNodeFailureDomain(context.Background(), ruspID, "rack2")
+	require.NoError(t, err)
+
+	// Add two instances to each member of rack1 (four in total) and three to the single member of rack2.
+	for i, nodeID := range []int64{1, 1, buzzID, buzzID, ruspID, ruspID, ruspID} {
+		_, err = tx.Tx().Exec(`
+INSERT INTO instances (id, node_id, name, architecture, type, project_id, description) VALUES (?, ?, ?, 1, 1, 1, '')
+`, i+1, nodeID, fmt.Sprintf("c%d", i+1))
+		require.NoError(t, err)
+	}
+
+	allMembers, err := tx.GetNodes(context.Background())
+	require.NoError(t, err)
+
+	members, err := tx.GetCandidateMembers(context.Background(), allMembers, nil, "", nil, time.Duration(db.DefaultOfflineThreshold)*time.Second)
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+
+	assert.NotEqual(t, "rusp", members[0].Name)
+	assert.NotEqual(t, "rusp", members[1].Name)
+	assert.Equal(t, "rusp", members[2].Name)
+}
+
 // If specific architectures were selected, return only nodes with those
 // architectures.
 func TestGetCandidateMembers_Architecture(t *testing.T) {
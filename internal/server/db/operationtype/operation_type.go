@@ -79,6 +79,21 @@ const (
 	BucketBackupRemove
 	BucketBackupRename
 	BucketBackupRestore
+	StackCreate
+	StackUpdate
+	StackDelete
+	InstanceSetCreate
+	InstanceSetUpdate
+	InstanceSetDelete
+	InstanceSetReconcile
+	ClusterRebalance
+	ClusterMaintenance
+	InstanceReplicate
+	InstanceFailover
+	ClusterMemberCordon
+	ClusterMemberUncordon
+	DatabaseBackupCreate
+	ProjectTemplateApply
 )
 
 // Description return a human-readable description of the operation type.
@@ -210,6 +225,36 @@ func (t Type) Description() string {
 		return "Renaming bucket backup"
 	case BucketBackupRestore:
 		return "Restoring bucket backup"
+	case StackCreate:
+		return "Creating stack"
+	case StackUpdate:
+		return "Updating stack"
+	case StackDelete:
+		return "Deleting stack"
+	case InstanceSetCreate:
+		return "Creating instance set"
+	case InstanceSetUpdate:
+		return "Updating instance set"
+	case InstanceSetDelete:
+		return "Deleting instance set"
+	case InstanceSetReconcile:
+		return "Reconciling instance sets"
+	case ClusterRebalance:
+		return "Re-balancing cluster"
+	case ClusterMaintenance:
+		return "Performing cluster maintenance"
+	case InstanceReplicate:
+		return "Replicating instance"
+	case InstanceFailover:
+		return "Failing over instance"
+	case ClusterMemberCordon:
+		return "Cordoning cluster member"
+	case ClusterMemberUncordon:
+		return "Uncordoning cluster member"
+	case DatabaseBackupCreate:
+		return "Backing up global database"
+	case ProjectTemplateApply:
+		return "Applying project template"
 	default:
 		return "Executing operation"
 	}
@@ -300,6 +345,30 @@ func (t Type) Permission() (auth.ObjectType, auth.Entitlement) {
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanManageBackups
 	case BucketBackupRestore:
 		return auth.ObjectTypeStorageVolume, auth.EntitlementCanEdit
+
+	case StackCreate:
+		return auth.ObjectTypeProject, auth.EntitlementCanEdit
+	case StackUpdate:
+		return auth.ObjectTypeProject, auth.EntitlementCanEdit
+	case StackDelete:
+		return auth.ObjectTypeProject, auth.EntitlementCanEdit
+
+	case InstanceSetCreate:
+		return auth.ObjectTypeProject, auth.EntitlementCanEdit
+	case InstanceSetUpdate:
+		return auth.ObjectTypeProject, auth.EntitlementCanEdit
+	case InstanceSetDelete:
+		return auth.ObjectTypeProject, auth.EntitlementCanEdit
+
+	case InstanceSetReconcile:
+		return auth.ObjectTypeServer, auth.EntitlementCanEdit
+	case InstanceReplicate:
+		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
+	case InstanceFailover:
+		return auth.ObjectTypeInstance, auth.EntitlementCanEdit
+
+	case ProjectTemplateApply:
+		return auth.ObjectTypeServer, auth.EntitlementCanEdit
 	}
 
 	return "", ""
@@ -6,6 +6,7 @@ import (
 	"context"
 
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/db/query"
 )
 
 // GetProject returns the project with the given key.
@@ -26,3 +27,38 @@ func (db *DB) GetProject(ctx context.Context, projectName string) (*cluster.Proj
 
 	return p, nil
 }
+
+// GetProjectSnapshotsCount returns the number of instance and custom volume snapshots in the given project.
+func (c *ClusterTx) GetProjectSnapshotsCount(ctx context.Context, projectName string) (int, error) {
+	instanceSnapshots, err := query.Count(ctx, c.tx, "instances_snapshots", "instance_id IN (SELECT instances.id FROM instances JOIN projects ON projects.id = instances.project_id WHERE projects.name = ?)", projectName)
+	if err != nil {
+		return -1, err
+	}
+
+	volumeSnapshots, err := query.Count(ctx, c.tx, "storage_volumes_snapshots", "storage_volume_id IN (SELECT storage_volumes.id FROM storage_volumes JOIN projects ON projects.id = storage_volumes.project_id WHERE projects.name = ? AND storage_volumes.type = ?)", projectName, StoragePoolVolumeTypeCustom)
+	if err != nil {
+		return -1, err
+	}
+
+	return instanceSnapshots + volumeSnapshots, nil
+}
+
+// GetProjectBackupsCount returns the number of instance, custom volume and bucket backups in the given project.
+func (c *ClusterTx) GetProjectBackupsCount(ctx context.Context, projectName string) (int, error) {
+	instanceBackups, err := query.Count(ctx, c.tx, "instances_backups", "instance_id IN (SELECT instances.id FROM instances JOIN projects ON projects.id = instances.project_id WHERE projects.name = ?)", projectName)
+	if err != nil {
+		return -1, err
+	}
+
+	volumeBackups, err := query.Count(ctx, c.tx, "storage_volumes_backups", "storage_volume_id IN (SELECT storage_volumes.id FROM storage_volumes JOIN projects ON projects.id = storage_volumes.project_id WHERE projects.name = ?)", projectName)
+	if err != nil {
+		return -1, err
+	}
+
+	bucketBackups, err := query.Count(ctx, c.tx, "storage_buckets_backups", "storage_bucket_id IN (SELECT storage_buckets.id FROM storage_buckets JOIN projects ON projects.id = storage_buckets.project_id WHERE projects.name = ?)", projectName)
+	if err != nil {
+		return -1, err
+	}
+
+	return instanceBackups + volumeBackups + bucketBackups, nil
+}
@@ -56,6 +56,10 @@ const (
 	StoragePoolUnvailable
 	// UnableToUpdateClusterCertificate represents the unable to update cluster certificate warning.
 	UnableToUpdateClusterCertificate
+	// ClusterMemberFencingFailed represents a failure to fence an offline cluster member.
+	ClusterMemberFencingFailed
+	// TrustedCertificateExpiring represents a trusted client certificate about to expire.
+	TrustedCertificateExpiring
 )
 
 // TypeNames associates a warning code to its name.
@@ -84,6 +88,8 @@ var TypeNames = map[Type]string{
 	InstanceTypeNotOperational:        "Instance type not operational",
 	StoragePoolUnvailable:             "Storage pool unavailable",
 	UnableToUpdateClusterCertificate:  "Unable to update cluster certificate",
+	ClusterMemberFencingFailed:        "Failed to fence offline cluster member",
+	TrustedCertificateExpiring:        "Trusted certificate expiring soon",
 }
 
 // Severity returns the severity of the warning type.
@@ -137,6 +143,10 @@ func (t Type) Severity() Severity {
 		return SeverityHigh
 	case UnableToUpdateClusterCertificate:
 		return SeverityLow
+	case ClusterMemberFencingFailed:
+		return SeverityHigh
+	case TrustedCertificateExpiring:
+		return SeverityModerate
 	}
 
 	return SeverityLow
@@ -0,0 +1,442 @@
+package device
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/network"
+	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/logger"
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// wakeTimeout is how long a held connection waits for the woken instance to become reachable.
+const wakeTimeout = 2 * time.Minute
+
+// wakeListener is a host-side listener held on behalf of a stopped instance's proxy device or network forward.
+type wakeListener struct {
+	listener net.Listener
+	network  string
+	address  string // Address to relay the held connections to once the instance is started.
+}
+
+// wakeConn is a connection received while the instance was stopped.
+type wakeConn struct {
+	conn    net.Conn
+	network string
+	address string
+}
+
+// wakeForward is a network forward target address redirected to the host while the instance is stopped.
+type wakeForward struct {
+	projectName string
+	networkName string
+	address     net.IP
+}
+
+// instanceWaker tracks the wake listeners and held connections of a stopped instance.
+type instanceWaker struct {
+	mu        sync.Mutex
+	state     *state.State
+	listeners map[string][]*wakeListener // Keyed by device name.
+	forwards  map[string][]wakeForward   // Keyed by NIC device name.
+	held      []wakeConn
+	wake      func() error
+	waking    bool
+	done      bool
+}
+
+// instanceWakers stores the active wakers keyed by project and instance name.
+var instanceWakers = map[string]*instanceWaker{}
+
+// instanceWakeMutex controls access to the instanceWakers map.
+var instanceWakeMutex sync.Mutex
+
+// instanceWakeKey returns the null delimited string of project name and instance name.
+func instanceWakeKey(projectName string, instanceName string) string {
+	return fmt.Sprintf("%s\000%s", projectName, instanceName)
+}
+
+// WakeListen sets up host-side listeners for the proxy devices of a stopped instance which have wake_on_connect
+// enabled and for the TCP ports of bridge network forwards pointing at the static addresses of its NICs.
+// The first incoming connection calls the wake function. Connections received until the instance is started are
+// held and then relayed through the proxy device or to the forward target once the instance is reachable.
+func WakeListen(s *state.State, inst instance.Instance, wake func() error) error {
+	key := instanceWakeKey(inst.Project().Name, inst.Name())
+
+	// Release any previous listeners for the instance first as they would conflict with the new ones.
+	instanceWakeMutex.Lock()
+	oldWaker := instanceWakers[key]
+	delete(instanceWakers, key)
+	instanceWakeMutex.Unlock()
+
+	if oldWaker != nil {
+		oldWaker.closeListeners()
+	}
+
+	waker := &instanceWaker{
+		state:     s,
+		listeners: map[string][]*wakeListener{},
+		forwards:  map[string][]wakeForward{},
+		wake:      wake,
+	}
+
+	for _, entry := range inst.ExpandedDevices().Sorted() {
+		if entry.Config["type"] != "proxy" || util.IsFalseOrEmpty(entry.Config["wake_on_connect"]) {
+			continue
+		}
+
+		listenAddr, err := network.ProxyParseAddr(entry.Config["listen"])
+		if err != nil {
+			waker.closeListeners()
+			return err
+		}
+
+		addresses := []string{listenAddr.Address}
+		if listenAddr.ConnType != "unix" {
+			addresses = make([]string, 0, len(listenAddr.Ports))
+			for _, port := range listenAddr.Ports {
+				addresses = append(addresses, net.JoinHostPort(listenAddr.Address, strconv.FormatUint(port, 10)))
+			}
+		}
+
+		for _, address := range addresses {
+			listener, err := net.Listen(listenAddr.ConnType, address)
+			if err != nil {
+				waker.closeListeners()
+				return fmt.Errorf("Failed listening on %q for device %q: %w", address, entry.Name, err)
+			}
+
+			waker.listeners[entry.Name] = append(waker.listeners[entry.Name], &wakeListener{
+				listener: listener,
+				network:  listenAddr.ConnType,
+				address:  address,
+			})
+
+			// Apply the same ownership and permissions the proxy device would use.
+			if listenAddr.ConnType == "unix" && !listenAddr.Abstract {
+				err = proxyWakeSetSocketPermissions(address, entry.Config)
+				if err != nil {
+					waker.closeListeners()
+					return fmt.Errorf("Failed setting up socket %q for device %q: %w", address, entry.Name, err)
+				}
+			}
+		}
+	}
+
+	err := waker.holdForwards(inst)
+	if err != nil {
+		waker.closeListeners()
+		return err
+	}
+
+	if len(waker.listeners) == 0 {
+		return nil
+	}
+
+	instanceWakeMutex.Lock()
+	instanceWakers[key] = waker
+	instanceWakeMutex.Unlock()
+
+	for _, listeners := range waker.listeners {
+		for _, listener := range listeners {
+			go waker.accept(key, listener)
+		}
+	}
+
+	return nil
+}
+
+// proxyWakeSetSocketPermissions applies the uid, gid and mode of a proxy device to a unix socket.
+func proxyWakeSetSocketPermissions(path string, config map[string]string) error {
+	mode := uint64(0o644)
+	if config["mode"] != "" {
+		var err error
+		mode, err = strconv.ParseUint(config["mode"], 8, 32)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := os.Chmod(path, os.FileMode(mode))
+	if err != nil {
+		return err
+	}
+
+	uid := -1
+	if config["uid"] != "" {
+		uid, err = strconv.Atoi(config["uid"])
+		if err != nil {
+			return err
+		}
+	}
+
+	gid := -1
+	if config["gid"] != "" {
+		gid, err = strconv.Atoi(config["gid"])
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.Chown(path, uid, gid)
+}
+
+// holdForwards redirects the network forwards pointing at the static addresses of the instance's NICs to wake listeners.
+func (w *instanceWaker) holdForwards(inst instance.Instance) error {
+	var networkProjectName string
+
+	for _, entry := range inst.ExpandedDevices().Sorted() {
+		if entry.Config["type"] != "nic" || entry.Config["network"] == "" {
+			continue
+		}
+
+		if networkProjectName == "" {
+			var err error
+
+			networkProjectName, _, err = project.NetworkProject(w.state.DB.Cluster, inst.Project().Name)
+			if err != nil {
+				return fmt.Errorf("Failed loading network project name: %w", err)
+			}
+		}
+
+		for _, key := range []string{"ipv4.address", "ipv6.address"} {
+			address := net.ParseIP(entry.Config[key])
+			if address == nil {
+				continue
+			}
+
+			listeners, err := network.BridgeForwardWakeHold(w.state, networkProjectName, entry.Config["network"], address)
+			if err != nil {
+				return fmt.Errorf("Failed holding network forwards for device %q: %w", entry.Name, err)
+			}
+
+			if len(listeners) == 0 {
+				continue
+			}
+
+			w.forwards[entry.Name] = append(w.forwards[entry.Name], wakeForward{
+				projectName: networkProjectName,
+				networkName: entry.Config["network"],
+				address:     address,
+			})
+
+			for _, listener := range listeners {
+				w.listeners[entry.Name] = append(w.listeners[entry.Name], &wakeListener{
+					listener: listener.Listener,
+					network:  "tcp",
+					address:  listener.Target,
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// wakeRelease closes the wake listeners of a device so that the proxy process can bind its address or the
+// network forwards reach the instance again. Connections already received are kept until the waker hands them over.
+func wakeRelease(projectName string, instanceName string, deviceName string) {
+	key := instanceWakeKey(projectName, instanceName)
+
+	instanceWakeMutex.Lock()
+	defer instanceWakeMutex.Unlock()
+
+	waker := instanceWakers[key]
+	if waker == nil {
+		return
+	}
+
+	waker.mu.Lock()
+	for _, listener := range waker.listeners[deviceName] {
+		_ = listener.listener.Close()
+	}
+
+	forwards := waker.forwards[deviceName]
+	delete(waker.listeners, deviceName)
+	delete(waker.forwards, deviceName)
+	unused := len(waker.listeners) == 0 && !waker.waking
+	waker.mu.Unlock()
+
+	waker.releaseForwards(forwards)
+
+	if unused {
+		delete(instanceWakers, key)
+	}
+}
+
+// releaseForwards restores the network forwards which were redirected to wake listeners.
+func (w *instanceWaker) releaseForwards(forwards []wakeForward) {
+	for _, forward := range forwards {
+		err := network.BridgeForwardWakeRelease(w.state, forward.projectName, forward.networkName, forward.address)
+		if err != nil {
+			logger.Warn("Failed restoring network forwards after wake", logger.Ctx{"network": forward.networkName, "address": forward.address.String(), "err": err})
+		}
+	}
+}
+
+// accept handles incoming connections on a wake listener until it gets closed.
+func (w *instanceWaker) accept(key string, listener *wakeListener) {
+	for {
+		conn, err := listener.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		held := wakeConn{
+			conn:    conn,
+			network: listener.network,
+			address: listener.address,
+		}
+
+		w.mu.Lock()
+		if w.done {
+			w.mu.Unlock()
+			go wakeHandover(held)
+			continue
+		}
+
+		w.held = append(w.held, held)
+		startWake := !w.waking
+		w.waking = true
+		w.mu.Unlock()
+
+		if startWake {
+			go w.run(key)
+		}
+	}
+}
+
+// run wakes up the instance and hands over the held connections.
+func (w *instanceWaker) run(key string) {
+	err := w.wake()
+
+	instanceWakeMutex.Lock()
+	if instanceWakers[key] == w {
+		delete(instanceWakers, key)
+	}
+
+	instanceWakeMutex.Unlock()
+
+	// Release the listeners which weren't taken over by a device.
+	w.closeListeners()
+
+	w.mu.Lock()
+	held := w.held
+	w.held = nil
+	w.done = true
+	w.mu.Unlock()
+
+	if err != nil {
+		logger.Warn("Failed waking up instance on incoming connection", logger.Ctx{"err": err})
+
+		for _, c := range held {
+			_ = c.conn.Close()
+		}
+
+		return
+	}
+
+	for _, c := range held {
+		go wakeHandover(c)
+	}
+}
+
+// closeListeners closes all remaining wake listeners and restores the network forwards.
+func (w *instanceWaker) closeListeners() {
+	w.mu.Lock()
+
+	var forwards []wakeForward
+	for deviceName, listeners := range w.listeners {
+		for _, listener := range listeners {
+			_ = listener.listener.Close()
+		}
+
+		delete(w.listeners, deviceName)
+	}
+
+	for deviceName, deviceForwards := range w.forwards {
+		forwards = append(forwards, deviceForwards...)
+		delete(w.forwards, deviceName)
+	}
+
+	w.mu.Unlock()
+
+	w.releaseForwards(forwards)
+}
+
+// wakeHandover relays a held connection through the proxy device or to the forward target once the instance is reachable.
+func wakeHandover(c wakeConn) {
+	defer func() { _ = c.conn.Close() }()
+
+	deadline := time.Now().Add(wakeTimeout)
+	for {
+		backend, initial, err := wakeDial(c.network, c.address)
+		if err == nil {
+			defer func() { _ = backend.Close() }()
+
+			if len(initial) > 0 {
+				_, err = c.conn.Write(initial)
+				if err != nil {
+					return
+				}
+			}
+
+			done := make(chan struct{}, 2)
+			go func() {
+				_, _ = io.Copy(backend, c.conn)
+				done <- struct{}{}
+			}()
+
+			go func() {
+				_, _ = io.Copy(c.conn, backend)
+				done <- struct{}{}
+			}()
+
+			<-done
+			return
+		}
+
+		if time.Now().After(deadline) {
+			logger.Warn("Instance didn't become reachable after waking up", logger.Ctx{"address": c.address, "err": err})
+			return
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// wakeDial connects to the proxy device or forward target and checks that the connection gets accepted inside
+// the instance. The proxy process closes the connection straight away while nothing listens on the instance side yet.
+// Any data already sent by the instance is returned so it can be passed on to the client.
+func wakeDial(network string, address string) (net.Conn, []byte, error) {
+	conn, err := net.DialTimeout(network, address, 5*time.Second)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	buf := make([]byte, 4096)
+
+	_ = conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	n, err := conn.Read(buf)
+	_ = conn.SetReadDeadline(time.Time{})
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return conn, nil, nil
+		}
+
+		_ = conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, buf[:n], nil
+}
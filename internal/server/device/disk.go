@@ -27,6 +27,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance"
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/project"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
 	"github.com/lxc/incus/v6/internal/server/warnings"
@@ -2669,7 +2670,10 @@ func (d *disk) generateVMConfigDrive() (string, error) {
 		return "", err
 	}
 
-	instanceConfig := d.inst.ExpandedConfig()
+	instanceConfig, err := secrets.ResolveConfig(context.TODO(), d.state, d.inst.Project().Name, d.inst.ExpandedConfig())
+	if err != nil {
+		return "", err
+	}
 
 	// Use an empty vendor-data file if no custom vendor-data supplied.
 	vendorData, ok := instanceConfig["cloud-init.vendor-data"]
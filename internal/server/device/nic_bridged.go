@@ -510,6 +510,9 @@ func (d *nicBridged) Start() (*deviceConfig.RunConfig, error) {
 		return nil, err
 	}
 
+	// Restore the network forwards if they were being held to wake up the instance.
+	wakeRelease(d.inst.Project().Name, d.inst.Name(), d.name)
+
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -891,6 +894,9 @@ func (d *nicBridged) postStop() error {
 
 // Remove is run when the device is removed from the instance or the instance is deleted.
 func (d *nicBridged) Remove() error {
+	// Restore the network forwards if they were being held to wake up the instance.
+	wakeRelease(d.inst.Project().Name, d.inst.Name(), d.name)
+
 	// Handle the case where validation fails but the device still must be removed.
 	bridgeName := d.config["parent"]
 	if bridgeName == "" && d.config["network"] != "" {
@@ -82,16 +82,17 @@ func (d *proxy) validateConfig(instConf instance.ConfigReader) error {
 	}
 
 	rules := map[string]func(string) error{
-		"listen":         validate.Required(validateAddr),
-		"connect":        validate.Required(validateAddr),
-		"bind":           validate.Optional(validateBind),
-		"mode":           validate.Optional(unixValidOctalFileMode),
-		"nat":            validate.Optional(validate.IsBool),
-		"gid":            validate.Optional(unixValidUserID),
-		"uid":            validate.Optional(unixValidUserID),
-		"security.uid":   validate.Optional(unixValidUserID),
-		"security.gid":   validate.Optional(unixValidUserID),
-		"proxy_protocol": validate.Optional(validate.IsBool),
+		"listen":          validate.Required(validateAddr),
+		"connect":         validate.Required(validateAddr),
+		"bind":            validate.Optional(validateBind),
+		"mode":            validate.Optional(unixValidOctalFileMode),
+		"nat":             validate.Optional(validate.IsBool),
+		"gid":             validate.Optional(unixValidUserID),
+		"uid":             validate.Optional(unixValidUserID),
+		"security.uid":    validate.Optional(unixValidUserID),
+		"security.gid":    validate.Optional(unixValidUserID),
+		"proxy_protocol":  validate.Optional(validate.IsBool),
+		"wake_on_connect": validate.Optional(validate.IsBool),
 	}
 
 	err := d.config.Validate(rules)
@@ -132,6 +133,20 @@ func (d *proxy) validateConfig(instConf instance.ConfigReader) error {
 		return fmt.Errorf("Only proxy devices for non-abstract unix sockets can carry uid, gid, or mode properties")
 	}
 
+	if util.IsTrue(d.config["wake_on_connect"]) {
+		if util.IsTrue(d.config["nat"]) {
+			return fmt.Errorf("Waking up the instance on connection cannot be used with NAT")
+		}
+
+		if d.config["bind"] != "" && d.config["bind"] != "host" {
+			return fmt.Errorf("Only host-bound proxies can wake up the instance on connection")
+		}
+
+		if listenAddr.ConnType == "udp" {
+			return fmt.Errorf("Waking up the instance on connection is only supported for tcp and unix listeners")
+		}
+	}
+
 	if util.IsTrue(d.config["nat"]) {
 		if d.inst != nil {
 			// Default project always has networks feature so don't bother loading the project config
@@ -239,6 +254,9 @@ func (d *proxy) Start() (*deviceConfig.RunConfig, error) {
 				return err
 			}
 
+			// Release the listen address if it was being held to wake up the instance.
+			wakeRelease(d.inst.Project().Name, d.inst.Name(), d.name)
+
 			devFileName := fmt.Sprintf("proxy.%s", d.name)
 			pidPath := filepath.Join(d.inst.DevicesPath(), devFileName)
 			logFileName := fmt.Sprintf("proxy.%s.log", d.name)
@@ -598,6 +616,9 @@ func (d *proxy) killProxyProc(pidPath string) error {
 }
 
 func (d *proxy) Remove() error {
+	// Release the listen address if it was being held to wake up the instance.
+	wakeRelease(d.inst.Project().Name, d.inst.Name(), d.name)
+
 	err := warnings.DeleteWarningsByLocalNodeAndProjectAndTypeAndEntity(d.state.DB.Cluster, d.inst.Project().Name, warningtype.ProxyBridgeNetfilterNotEnabled, cluster.TypeInstance, d.inst.ID())
 	if err != nil {
 		logger.Warn("Failed to delete warning", logger.Ctx{"err": err})
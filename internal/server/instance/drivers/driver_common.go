@@ -904,6 +904,15 @@ func (d *common) getStartupSnapNameAndExpiry(inst instance.Instance) (string, *t
 	return name, &expiry, nil
 }
 
+// validateDependencies checks that the instances listed in boot.depends_on don't depend on this instance in turn.
+func (d *common) validateDependencies() error {
+	if d.expandedConfig["boot.depends_on"] == "" {
+		return nil
+	}
+
+	return instance.DependencyCheckCycle(context.TODO(), d.state, d.project.Name, d.name, map[string]string{d.name: d.expandedConfig["boot.depends_on"]})
+}
+
 // validateStartup checks any constraints that would prevent start up from succeeding under normal circumstances.
 func (d *common) validateStartup(stateful bool, statusCode api.StatusCode) error {
 	// Because the root disk is special and is mounted before the root disk device is setup we duplicate the
@@ -924,6 +933,11 @@ func (d *common) validateStartup(stateful bool, statusCode api.StatusCode) error
 		return fmt.Errorf("Requested architecture isn't supported by this host")
 	}
 
+	// Replicas must be promoted before they can be started.
+	if d.expandedConfig["replication.role"] == "replica" {
+		return api.StatusErrorf(http.StatusBadRequest, "Replica instances can't be started, fail over to the replica first")
+	}
+
 	// Must happen before creating operation Start lock to avoid the status check returning Stopped due to the
 	// existence of a Start operation lock.
 	err = d.isStartableStatusCode(statusCode)
@@ -1025,6 +1039,14 @@ func (d *common) canMigrate(inst instance.Instance) string {
 func (d *common) recordLastState() error {
 	var err error
 
+	// Clear the inactivity marker now that the instance is running again.
+	if d.localConfig["volatile.last_state.idle"] != "" {
+		err = d.VolatileSet(map[string]string{"volatile.last_state.idle": ""})
+		if err != nil {
+			return err
+		}
+	}
+
 	// Record power state.
 	d.localConfig["volatile.last_state.power"] = instance.PowerStateRunning
 	d.expandedConfig["volatile.last_state.power"] = instance.PowerStateRunning
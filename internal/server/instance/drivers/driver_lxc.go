@@ -67,6 +67,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/seccomp"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
@@ -230,6 +231,11 @@ func lxcCreate(s *state.State, args db.InstanceArgs, p api.Project, op *operatio
 			return nil, nil, fmt.Errorf("Invalid config: %w", err)
 		}
 
+		err = d.validateDependencies()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid config: %w", err)
+		}
+
 		err = instance.ValidDevices(s, d.project, d.Type(), d.localDevices, d.expandedDevices)
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
@@ -1066,6 +1072,11 @@ func (d *lxc) initLXC(config bool) (*liblxc.Container, error) {
 		//  liveupdate: yes (exec)
 		//  shortdesc: Environment variables to export
 		if strings.HasPrefix(k, "environment.") {
+			// Variables referencing secrets are only resolved when starting the instance.
+			if len(secrets.References(v)) > 0 {
+				continue
+			}
+
 			err = lxcSetConfigItem(cc, "lxc.environment", fmt.Sprintf("%s=%s", strings.TrimPrefix(k, "environment."), v))
 			if err != nil {
 				return nil, err
@@ -2575,6 +2586,23 @@ ff02::2 ip6-allrouters
 		}
 	}
 
+	// Setup the environment variables referencing secrets.
+	resolvedConfig, err := secrets.ResolveConfig(context.TODO(), d.state, d.project.Name, d.expandedConfig)
+	if err != nil {
+		return "", nil, err
+	}
+
+	for k, v := range resolvedConfig {
+		if !strings.HasPrefix(k, "environment.") || len(secrets.References(d.expandedConfig[k])) == 0 {
+			continue
+		}
+
+		err = lxcSetConfigItem(cc, "lxc.environment", fmt.Sprintf("%s=%s", strings.TrimPrefix(k, "environment."), v))
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
 	// Load the LXC raw config.
 	err = d.loadRawLXCConfig(cc)
 	if err != nil {
@@ -2751,6 +2779,8 @@ func (d *lxc) Start(stateful bool) error {
 			return fmt.Errorf("Failed clearing instance stateful flag: %w", err)
 		}
 
+		healthcheckStart(d.state, d, true)
+
 		if op.Action() == "start" {
 			d.logger.Info("Started instance", ctxMap)
 			d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
@@ -2837,6 +2867,8 @@ func (d *lxc) Start(stateful bool) error {
 		return err
 	}
 
+	healthcheckStart(d.state, d, true)
+
 	if op.Action() == "start" {
 		d.logger.Info("Started instance", ctxMap)
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
@@ -3745,6 +3777,7 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 		status.Network = d.networkState(hostInterfaces)
 		status.Pid = int64(pid)
 		status.Processes = processesState
+		status.Health = healthcheckRender(d.id)
 
 		status.StartedAt, err = d.processStartedAt(d.InitPID())
 		if err != nil {
@@ -4684,6 +4717,11 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded config: %w", err)
 		}
 
+		err = d.validateDependencies()
+		if err != nil {
+			return fmt.Errorf("Invalid expanded config: %w", err)
+		}
+
 		// Do full expanded validation of the devices diff.
 		err = instance.ValidDevices(d.state, d.project, d.Type(), d.localDevices, d.expandedDevices)
 		if err != nil {
@@ -5208,6 +5246,11 @@ func (d *lxc) Update(args db.InstanceArgs, userRequested bool) error {
 	// Success, update the closure to mark that the changes should be kept.
 	undoChanges = false
 
+	// Apply the new health check configuration.
+	if isRunning && !d.isSnapshot {
+		healthcheckStart(d.state, d, false)
+	}
+
 	if userRequested {
 		if d.isSnapshot {
 			d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceSnapshotUpdated.Event(d, nil))
@@ -7037,8 +7080,14 @@ func (d *lxc) templateApplyNow(trigger instance.TemplateTrigger) error {
 				return fmt.Errorf("Failed to render template: %w", err)
 			}
 
+			// Secrets referenced from the configuration are only available through config_get.
+			resolvedConfig, err := secrets.ResolveConfig(context.TODO(), d.state, d.project.Name, d.expandedConfig)
+			if err != nil {
+				return err
+			}
+
 			configGet := func(confKey, confDefault *pongo2.Value) *pongo2.Value {
-				val, ok := d.expandedConfig[confKey.String()]
+				val, ok := resolvedConfig[confKey.String()]
 				if !ok {
 					return confDefault
 				}
@@ -7449,6 +7498,18 @@ func (d *lxc) ConsoleLog(opts liblxc.ConsoleLogOptions) (string, error) {
 
 // Exec executes a command inside the instance.
 func (d *lxc) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
+	cmd, err := d.execCommand(req, stdin, stdout, stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceExec.Event(d, logger.Ctx{"command": req.Command}))
+
+	return cmd, nil
+}
+
+// execCommand executes a command inside the instance without emitting a lifecycle event.
+func (d *lxc) execCommand(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	// Generate the LXC config if missing.
 	configPath := filepath.Join(d.RunPath(), "lxc.conf")
 	if !util.PathExists(configPath) {
@@ -7555,8 +7616,6 @@ func (d *lxc) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, st
 
 	d.logger.Debug("Retrieved PID of executing child process", logger.Ctx{"attachedPid": attachedPid})
 
-	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceExec.Event(d, logger.Ctx{"command": req.Command}))
-
 	instCmd := &lxcCmd{
 		cmd:              &cmd,
 		attachedChildPid: int(attachedPid),
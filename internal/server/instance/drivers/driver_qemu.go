@@ -71,6 +71,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/response"
 	"github.com/lxc/incus/v6/internal/server/scriptlet"
 	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/internal/server/secrets"
 	"github.com/lxc/incus/v6/internal/server/state"
 	storagePools "github.com/lxc/incus/v6/internal/server/storage"
 	storageDrivers "github.com/lxc/incus/v6/internal/server/storage/drivers"
@@ -262,6 +263,11 @@ func qemuCreate(s *state.State, args db.InstanceArgs, p api.Project, op *operati
 			return nil, nil, fmt.Errorf("Invalid config: %w", err)
 		}
 
+		err = d.validateDependencies()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid config: %w", err)
+		}
+
 		err = instance.ValidDevices(s, d.project, d.Type(), d.localDevices, d.expandedDevices)
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
@@ -2013,6 +2019,8 @@ func (d *qemu) start(stateful bool, op *operationlock.InstanceOperation) error {
 		return err
 	}
 
+	healthcheckStart(d.state, d, true)
+
 	if op.Action() == "start" {
 		d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceStarted.Event(d, nil))
 	}
@@ -3302,8 +3310,14 @@ func (d *qemu) templateApplyNow(trigger instance.TemplateTrigger, path string) e
 				return fmt.Errorf("Failed to render template: %w", err)
 			}
 
+			// Secrets referenced from the configuration are only available through config_get.
+			resolvedConfig, err := secrets.ResolveConfig(context.TODO(), d.state, d.project.Name, d.expandedConfig)
+			if err != nil {
+				return err
+			}
+
 			configGet := func(confKey, confDefault *pongo2.Value) *pongo2.Value {
-				val, ok := d.expandedConfig[confKey.String()]
+				val, ok := resolvedConfig[confKey.String()]
 				if !ok {
 					return confDefault
 				}
@@ -5810,6 +5824,11 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 			return fmt.Errorf("Invalid expanded config: %w", err)
 		}
 
+		err = d.validateDependencies()
+		if err != nil {
+			return fmt.Errorf("Invalid expanded config: %w", err)
+		}
+
 		// Do full expanded validation of the devices diff.
 		err = instance.ValidDevices(d.state, d.project, d.Type(), d.localDevices, d.expandedDevices)
 		if err != nil {
@@ -6097,6 +6116,11 @@ func (d *qemu) Update(args db.InstanceArgs, userRequested bool) error {
 		}
 	}
 
+	// Apply the new health check configuration.
+	if isRunning && !d.isSnapshot {
+		healthcheckStart(d.state, d, false)
+	}
+
 	if userRequested {
 		if d.isSnapshot {
 			d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceSnapshotUpdated.Event(d, nil))
@@ -7928,6 +7952,18 @@ func (d *qemu) Console(protocol string) (*os.File, chan error, error) {
 
 // Exec a command inside the instance.
 func (d *qemu) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
+	cmd, err := d.execCommand(req, stdin, stdout, stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceExec.Event(d, logger.Ctx{"command": req.Command}))
+
+	return cmd, nil
+}
+
+// execCommand executes a command inside the instance without emitting a lifecycle event.
+func (d *qemu) execCommand(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	revert := revert.New()
 	defer revert.Fail()
 
@@ -7992,8 +8028,6 @@ func (d *qemu) Exec(req api.InstanceExecPost, stdin *os.File, stdout *os.File, s
 		controlResCh:     controlResCh,
 	}
 
-	d.state.Events.SendLifecycle(d.project.Name, lifecycle.InstanceExec.Event(d, logger.Ctx{"command": req.Command}))
-
 	revert.Success()
 	return instCmd, nil
 }
@@ -8197,6 +8231,7 @@ func (d *qemu) renderState(statusCode api.StatusCode) (*api.InstanceState, error
 		}
 
 		status.Pid = int64(pid)
+		status.Health = healthcheckRender(d.id)
 		status.StartedAt, err = d.processStartedAt(d.InitPID())
 		if err != nil {
 			return status, err
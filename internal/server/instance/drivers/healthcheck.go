@@ -0,0 +1,438 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	"golang.org/x/sys/unix"
+
+	"github.com/lxc/incus/v6/internal/server/instance"
+	"github.com/lxc/incus/v6/internal/server/lifecycle"
+	"github.com/lxc/incus/v6/internal/server/secrets"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// Health check status values.
+const (
+	healthcheckStatusStarting  = "starting"
+	healthcheckStatusHealthy   = "healthy"
+	healthcheckStatusUnhealthy = "unhealthy"
+)
+
+// healthcheckExecer is implemented by the instance drivers to run commands without emitting lifecycle events.
+type healthcheckExecer interface {
+	execCommand(req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error)
+}
+
+// healthcheckMonitor holds the health check state of a running instance.
+type healthcheckMonitor struct {
+	status    string
+	failures  int
+	lastCheck time.Time
+	lastError string
+
+	// reset is used to wake up the monitor when the instance is started again or its configuration changes.
+	reset chan struct{}
+}
+
+// Track the health check monitors of running instances.
+var (
+	instancesHealth   = map[int]*healthcheckMonitor{}
+	muInstancesHealth sync.Mutex
+)
+
+// healthcheckConfigured returns true if any health check probe is configured.
+func healthcheckConfigured(config map[string]string) bool {
+	return config["healthcheck.command"] != "" || config["healthcheck.http"] != "" || config["healthcheck.tcp"] != ""
+}
+
+// healthcheckConfigSeconds returns the duration stored in a health check config key.
+func healthcheckConfigSeconds(config map[string]string, key string, defaultValue int) time.Duration {
+	return time.Duration(healthcheckConfigInt(config, key, defaultValue)) * time.Second
+}
+
+// healthcheckConfigInt returns the integer stored in a health check config key.
+func healthcheckConfigInt(config map[string]string, key string, defaultValue int) int {
+	value, err := strconv.Atoi(config[key])
+	if err != nil || value < 1 {
+		return defaultValue
+	}
+
+	return value
+}
+
+// HealthcheckStart starts monitoring the health of a running instance if health checks are configured for it.
+func HealthcheckStart(s *state.State, inst instance.Instance) {
+	healthcheckStart(s, inst, true)
+}
+
+// healthcheckStart starts or refreshes the health check monitor of an instance.
+// When restarted is true, the health state is reset as the instance was just (re)started.
+func healthcheckStart(s *state.State, inst instance.Instance, restarted bool) {
+	muInstancesHealth.Lock()
+	defer muInstancesHealth.Unlock()
+
+	hc, ok := instancesHealth[inst.ID()]
+
+	if !healthcheckConfigured(inst.ExpandedConfig()) {
+		if ok {
+			delete(instancesHealth, inst.ID())
+			hc.wake()
+		}
+
+		return
+	}
+
+	if ok {
+		if restarted {
+			hc.status = healthcheckStatusStarting
+			hc.failures = 0
+			hc.lastError = ""
+		}
+
+		hc.wake()
+		return
+	}
+
+	hc = &healthcheckMonitor{
+		status: healthcheckStatusStarting,
+		reset:  make(chan struct{}, 1),
+	}
+
+	instancesHealth[inst.ID()] = hc
+
+	go hc.run(s, inst.ID())
+}
+
+// healthcheckRender returns the health check state of an instance for use in its rendered state.
+func healthcheckRender(id int) *api.InstanceStateHealth {
+	muInstancesHealth.Lock()
+	defer muInstancesHealth.Unlock()
+
+	hc, ok := instancesHealth[id]
+	if !ok {
+		return nil
+	}
+
+	return &api.InstanceStateHealth{
+		Status:    hc.status,
+		Failures:  hc.failures,
+		LastCheck: hc.lastCheck,
+		LastError: hc.lastError,
+	}
+}
+
+// HealthcheckStatus returns the current health status of an instance or an empty string if it isn't monitored.
+func HealthcheckStatus(inst instance.Instance) string {
+	muInstancesHealth.Lock()
+	defer muInstancesHealth.Unlock()
+
+	hc, ok := instancesHealth[inst.ID()]
+	if !ok {
+		return ""
+	}
+
+	return hc.status
+}
+
+// wake interrupts the wait of the monitor loop. Must be called with muInstancesHealth held.
+func (hc *healthcheckMonitor) wake() {
+	select {
+	case hc.reset <- struct{}{}:
+	default:
+	}
+}
+
+// active returns whether the monitor is still the registered one for the instance.
+func (hc *healthcheckMonitor) active(id int) bool {
+	muInstancesHealth.Lock()
+	defer muInstancesHealth.Unlock()
+
+	return instancesHealth[id] == hc
+}
+
+// run periodically checks the health of the instance until it stops or health checks get disabled.
+func (hc *healthcheckMonitor) run(s *state.State, id int) {
+	defer func() {
+		muInstancesHealth.Lock()
+		if instancesHealth[id] == hc {
+			delete(instancesHealth, id)
+		}
+
+		muInstancesHealth.Unlock()
+	}()
+
+	for {
+		inst, err := instance.LoadByID(s, id)
+		if err != nil || !inst.IsRunning() || !healthcheckConfigured(inst.ExpandedConfig()) || !hc.active(id) {
+			return
+		}
+
+		select {
+		case <-s.ShutdownCtx.Done():
+			return
+		case <-hc.reset:
+			continue
+		case <-time.After(healthcheckConfigSeconds(inst.ExpandedConfig(), "healthcheck.interval", 30)):
+		}
+
+		// Reload the instance as it may have changed while waiting.
+		inst, err = instance.LoadByID(s, id)
+		if err != nil || !inst.IsRunning() || !healthcheckConfigured(inst.ExpandedConfig()) || !hc.active(id) {
+			return
+		}
+
+		config := inst.ExpandedConfig()
+
+		ctx, cancel := context.WithTimeout(s.ShutdownCtx, healthcheckConfigSeconds(config, "healthcheck.timeout", 5))
+		checkErr := healthcheckProbe(ctx, s, inst)
+		cancel()
+
+		if s.ShutdownCtx.Err() != nil {
+			return
+		}
+
+		// Record the result and figure out whether the health status changed.
+		muInstancesHealth.Lock()
+		oldStatus := hc.status
+		hc.record(checkErr, healthcheckConfigInt(config, "healthcheck.retries", 3), time.Now())
+		newStatus := hc.status
+		lastError := hc.lastError
+		muInstancesHealth.Unlock()
+
+		if newStatus == oldStatus {
+			continue
+		}
+
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "status": newStatus})
+		if newStatus == healthcheckStatusUnhealthy {
+			l.Warn("Instance health check failed", logger.Ctx{"err": lastError})
+		} else {
+			l.Info("Instance health status changed")
+		}
+
+		s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceHealthChanged.Event(inst, map[string]any{"status": newStatus, "error": lastError}))
+
+		if healthcheckRestartNeeded(config, oldStatus, newStatus) {
+			l.Info("Restarting unhealthy instance")
+
+			err = inst.Restart(0)
+			if err != nil {
+				l.Error("Failed restarting unhealthy instance", logger.Ctx{"err": err})
+			}
+		}
+	}
+}
+
+// record updates the monitor with the result of a check. The instance becomes unhealthy once the check failed
+// the given number of times in a row. Must be called with muInstancesHealth held.
+func (hc *healthcheckMonitor) record(checkErr error, retries int, now time.Time) {
+	hc.lastCheck = now
+
+	if checkErr == nil {
+		hc.failures = 0
+		hc.lastError = ""
+		hc.status = healthcheckStatusHealthy
+		return
+	}
+
+	hc.failures++
+	hc.lastError = checkErr.Error()
+
+	if hc.failures >= retries {
+		hc.status = healthcheckStatusUnhealthy
+	}
+}
+
+// healthcheckRestartNeeded returns whether the instance should be restarted following a health status change.
+func healthcheckRestartNeeded(config map[string]string, oldStatus string, newStatus string) bool {
+	return oldStatus != newStatus && newStatus == healthcheckStatusUnhealthy && config["healthcheck.action"] == "restart"
+}
+
+// healthcheckHTTPURL returns the URL to probe for a healthcheck.http value of the form "<port>[/<path>]".
+func healthcheckHTTPURL(address string, value string) string {
+	port, path, _ := strings.Cut(value, "/")
+
+	return fmt.Sprintf("http://%s/%s", net.JoinHostPort(address, port), path)
+}
+
+// healthcheckProbe runs all the configured probes against the instance.
+func healthcheckProbe(ctx context.Context, s *state.State, inst instance.Instance) error {
+	config := inst.ExpandedConfig()
+
+	if config["healthcheck.command"] != "" {
+		err := healthcheckCommand(ctx, s, inst, config["healthcheck.command"])
+		if err != nil {
+			return err
+		}
+	}
+
+	if config["healthcheck.http"] == "" && config["healthcheck.tcp"] == "" {
+		return nil
+	}
+
+	address, err := healthcheckAddress(inst)
+	if err != nil {
+		return err
+	}
+
+	if config["healthcheck.tcp"] != "" {
+		var dialer net.Dialer
+
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, config["healthcheck.tcp"]))
+		if err != nil {
+			return fmt.Errorf("TCP check failed: %w", err)
+		}
+
+		_ = conn.Close()
+	}
+
+	if config["healthcheck.http"] != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthcheckHTTPURL(address, config["healthcheck.http"]), nil)
+		if err != nil {
+			return err
+		}
+
+		client := &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("HTTP check failed: %w", err)
+		}
+
+		_ = resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+			return fmt.Errorf("HTTP check returned status %d", resp.StatusCode)
+		}
+	}
+
+	return nil
+}
+
+// healthcheckCommand runs the health check command inside the instance.
+func healthcheckCommand(ctx context.Context, s *state.State, inst instance.Instance, command string) error {
+	args, err := shellquote.Split(command)
+	if err != nil {
+		return err
+	}
+
+	req := api.InstanceExecPost{
+		Command:     args,
+		Environment: map[string]string{},
+	}
+
+	expandedConfig, err := secrets.ResolveConfig(ctx, s, inst.Project().Name, inst.ExpandedConfig())
+	if err != nil {
+		return err
+	}
+
+	for k, v := range expandedConfig {
+		envKey, ok := strings.CutPrefix(k, "environment.")
+		if ok {
+			req.Environment[envKey] = v
+		}
+	}
+
+	_, ok := req.Environment["PATH"]
+	if !ok {
+		req.Environment["PATH"] = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+	}
+
+	// The probe output isn't used but the command needs valid standard file descriptors.
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = devNull.Close() }()
+
+	// Run the command without emitting an exec lifecycle event for every probe.
+	execer, ok := inst.(healthcheckExecer)
+	if !ok {
+		return fmt.Errorf("Health check commands aren't supported for instance type %q", inst.Type().String())
+	}
+
+	cmd, err := execer.execCommand(req, devNull, devNull, devNull)
+	if err != nil {
+		return fmt.Errorf("Failed running health check command: %w", err)
+	}
+
+	chResult := make(chan error, 1)
+	go func() {
+		exitStatus, err := cmd.Wait()
+		if err == nil && exitStatus != 0 {
+			err = fmt.Errorf("Health check command exited with status %d", exitStatus)
+		}
+
+		chResult <- err
+	}()
+
+	select {
+	case err := <-chResult:
+		return err
+	case <-ctx.Done():
+		_ = cmd.Signal(unix.SIGKILL)
+		return errors.New("Health check command timed out")
+	}
+}
+
+// healthcheckAddress returns the IP address to use for network probes, preferring global IPv4 addresses.
+func healthcheckAddress(inst instance.Instance) (string, error) {
+	hostInterfaces, _ := net.Interfaces()
+
+	instState, err := inst.RenderState(hostInterfaces)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(instState.Network))
+	for name := range instState.Network {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	ipv6 := ""
+	for _, name := range names {
+		if name == "lo" {
+			continue
+		}
+
+		for _, addr := range instState.Network[name].Addresses {
+			if addr.Scope != "global" {
+				continue
+			}
+
+			if addr.Family == "inet" {
+				return addr.Address, nil
+			}
+
+			if ipv6 == "" {
+				ipv6 = addr.Address
+			}
+		}
+	}
+
+	if ipv6 == "" {
+		return "", errors.New("Instance has no global IP address")
+	}
+
+	return ipv6, nil
+}
@@ -0,0 +1,111 @@
+package drivers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthcheckConfigInt(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int
+	}{
+		{value: "", expected: 3},
+		{value: "5", expected: 5},
+		{value: "0", expected: 3},
+		{value: "-1", expected: 3},
+		{value: "foo", expected: 3},
+	}
+
+	for _, tt := range tests {
+		actual := healthcheckConfigInt(map[string]string{"healthcheck.retries": tt.value}, "healthcheck.retries", 3)
+		if actual != tt.expected {
+			t.Errorf("Value %q: expected %d, got %d", tt.value, tt.expected, actual)
+		}
+	}
+}
+
+func TestHealthcheckHTTPURL(t *testing.T) {
+	tests := []struct {
+		address  string
+		value    string
+		expected string
+	}{
+		{address: "10.0.0.2", value: "80", expected: "http://10.0.0.2:80/"},
+		{address: "10.0.0.2", value: "8080/healthz", expected: "http://10.0.0.2:8080/healthz"},
+		{address: "10.0.0.2", value: "8080/api/v1/status?full=1", expected: "http://10.0.0.2:8080/api/v1/status?full=1"},
+		{address: "fd42::2", value: "80/", expected: "http://[fd42::2]:80/"},
+	}
+
+	for _, tt := range tests {
+		actual := healthcheckHTTPURL(tt.address, tt.value)
+		if actual != tt.expected {
+			t.Errorf("Value %q: expected %q, got %q", tt.value, tt.expected, actual)
+		}
+	}
+}
+
+func TestHealthcheckRecord(t *testing.T) {
+	hc := &healthcheckMonitor{status: healthcheckStatusStarting}
+	checkErr := errors.New("Connection refused")
+	now := time.Now()
+
+	// Failures below the retry count keep the current status.
+	hc.record(checkErr, 3, now)
+	hc.record(checkErr, 3, now)
+	if hc.status != healthcheckStatusStarting || hc.failures != 2 || hc.lastError != checkErr.Error() {
+		t.Fatalf("Unexpected state after two failures: %+v", hc)
+	}
+
+	// Reaching the retry count marks the instance unhealthy.
+	hc.record(checkErr, 3, now)
+	if hc.status != healthcheckStatusUnhealthy || hc.failures != 3 {
+		t.Fatalf("Unexpected state after three failures: %+v", hc)
+	}
+
+	// A single success resets the failures.
+	hc.record(nil, 3, now)
+	if hc.status != healthcheckStatusHealthy || hc.failures != 0 || hc.lastError != "" || !hc.lastCheck.Equal(now) {
+		t.Fatalf("Unexpected state after success: %+v", hc)
+	}
+
+	// A failure on a healthy instance doesn't change its status until the retry count is reached.
+	hc.record(checkErr, 2, now)
+	if hc.status != healthcheckStatusHealthy {
+		t.Fatalf("Unexpected state after failure of healthy instance: %+v", hc)
+	}
+
+	hc.record(checkErr, 2, now)
+	if hc.status != healthcheckStatusUnhealthy {
+		t.Fatalf("Unexpected state after repeated failures of healthy instance: %+v", hc)
+	}
+}
+
+func TestHealthcheckRestartNeeded(t *testing.T) {
+	restart := map[string]string{"healthcheck.action": "restart"}
+	none := map[string]string{}
+
+	tests := []struct {
+		name      string
+		config    map[string]string
+		oldStatus string
+		newStatus string
+		expected  bool
+	}{
+		{name: "Became unhealthy", config: restart, oldStatus: healthcheckStatusHealthy, newStatus: healthcheckStatusUnhealthy, expected: true},
+		{name: "Unhealthy while starting", config: restart, oldStatus: healthcheckStatusStarting, newStatus: healthcheckStatusUnhealthy, expected: true},
+		{name: "Still unhealthy", config: restart, oldStatus: healthcheckStatusUnhealthy, newStatus: healthcheckStatusUnhealthy, expected: false},
+		{name: "Became healthy", config: restart, oldStatus: healthcheckStatusUnhealthy, newStatus: healthcheckStatusHealthy, expected: false},
+		{name: "No action", config: none, oldStatus: healthcheckStatusHealthy, newStatus: healthcheckStatusUnhealthy, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := healthcheckRestartNeeded(tt.config, tt.oldStatus, tt.newStatus)
+			if actual != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
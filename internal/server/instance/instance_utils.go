@@ -23,6 +23,7 @@ import (
 	"github.com/lxc/incus/v6/internal/instance"
 	"github.com/lxc/incus/v6/internal/migration"
 	"github.com/lxc/incus/v6/internal/server/backup"
+	"github.com/lxc/incus/v6/internal/server/cluster/capacity"
 	"github.com/lxc/incus/v6/internal/server/db"
 	"github.com/lxc/incus/v6/internal/server/db/cluster"
 	deviceConfig "github.com/lxc/incus/v6/internal/server/device/config"
@@ -30,6 +31,7 @@ import (
 	"github.com/lxc/incus/v6/internal/server/instance/instancetype"
 	"github.com/lxc/incus/v6/internal/server/instance/operationlock"
 	"github.com/lxc/incus/v6/internal/server/operations"
+	"github.com/lxc/incus/v6/internal/server/project"
 	"github.com/lxc/incus/v6/internal/server/resources"
 	"github.com/lxc/incus/v6/internal/server/seccomp"
 	"github.com/lxc/incus/v6/internal/server/state"
@@ -383,6 +385,67 @@ func LoadByProjectAndName(s *state.State, projectName string, instanceName strin
 	return inst, nil
 }
 
+// DependencyCheckCycle checks that following boot.depends_on from an instance doesn't lead back to it.
+// The boot.depends_on values given in overrides take precedence over those of the database, so that changes can be
+// checked before being applied. Dependencies which don't exist are ignored.
+func DependencyCheckCycle(ctx context.Context, s *state.State, projectName string, instanceName string, overrides map[string]string) error {
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dependencies := func(name string) ([]string, error) {
+			value, ok := overrides[name]
+			if !ok {
+				dbInst, err := LoadInstanceDatabaseObject(ctx, tx, projectName, name)
+				if err != nil {
+					if api.StatusErrorCheck(err, http.StatusNotFound) {
+						return nil, nil
+					}
+
+					return nil, fmt.Errorf("Failed loading dependency %q: %w", name, err)
+				}
+
+				instArgs, err := tx.InstancesToInstanceArgs(ctx, true, *dbInst)
+				if err != nil {
+					return nil, err
+				}
+
+				args := instArgs[dbInst.ID]
+				value = db.ExpandInstanceConfig(args.Config, args.Profiles)["boot.depends_on"]
+			}
+
+			return util.SplitNTrimSpace(value, ",", -1, true), nil
+		}
+
+		pending, err := dependencies(instanceName)
+		if err != nil {
+			return err
+		}
+
+		visited := map[string]bool{instanceName: true}
+		for len(pending) > 0 {
+			name := pending[0]
+			pending = pending[1:]
+
+			if name == instanceName {
+				return fmt.Errorf("Dependency cycle detected involving instance %q", instanceName)
+			}
+
+			if visited[name] {
+				continue
+			}
+
+			visited[name] = true
+
+			deps, err := dependencies(name)
+			if err != nil {
+				return err
+			}
+
+			pending = append(pending, deps...)
+		}
+
+		return nil
+	})
+}
+
 // LoadNodeAll loads all instances on this server.
 func LoadNodeAll(s *state.State, instanceType instancetype.Type) ([]Instance, error) {
 	var err error
@@ -891,6 +954,12 @@ func CreateInternal(s *state.State, args db.InstanceArgs, op *operations.Operati
 				return fmt.Errorf("Get instance %q in project %q", instanceName, args.Project)
 			}
 
+			// Enforce the project snapshot limit for snapshots that are copied, migrated or imported.
+			err = project.AllowSnapshotsImport(tx, args.Project, 1)
+			if err != nil {
+				return err
+			}
+
 			snapshot := cluster.InstanceSnapshot{
 				Project:      args.Project,
 				Instance:     instanceName,
@@ -978,6 +1047,13 @@ func CreateInternal(s *state.State, args db.InstanceArgs, op *operations.Operati
 			return err
 		}
 
+		// Check the capacity limits of the cluster member again now that the instance is recorded, so that
+		// concurrent placements can't exceed them.
+		err = capacity.CheckCreated(ctx, tx, s.ServerName, args.Project, args.Name, args.Type, db.ExpandInstanceConfig(args.Config, args.Profiles))
+		if err != nil {
+			return err
+		}
+
 		// Read back the instance, to get ID and creation time.
 		dbRow, err := cluster.GetInstance(ctx, tx.Tx(), args.Project, args.Name)
 		if err != nil {
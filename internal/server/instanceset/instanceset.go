@@ -0,0 +1,388 @@
+package instanceset
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/internal/server/stack"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
+)
+
+// addressTimeout is how long to wait for a new instance to get an address before registering it as a backend.
+const addressTimeout = 30 * time.Second
+
+// InstanceName returns the name of the n-th instance of a set, starting at 1.
+func InstanceName(name string, n int) string {
+	return fmt.Sprintf("%s-%d", name, n)
+}
+
+// InstanceNames returns the names of the instances of a set with the given number of replicas.
+func InstanceNames(name string, replicas int) []string {
+	names := make([]string, 0, replicas)
+	for n := 1; n <= replicas; n++ {
+		names = append(names, InstanceName(name, n))
+	}
+
+	return names
+}
+
+// Validate checks that an instance set definition is consistent.
+func Validate(spec api.InstanceSetPut) error {
+	if spec.Replicas < 0 {
+		return fmt.Errorf("The number of replicas can't be negative")
+	}
+
+	if strings.HasPrefix(spec.ClusterGroup, "@") {
+		return fmt.Errorf("The cluster group must be provided without the @ prefix")
+	}
+
+	if spec.LoadBalancer != nil && (spec.LoadBalancer.Network == "" || spec.LoadBalancer.ListenAddress == "") {
+		return fmt.Errorf("Load balancers require a network and a listen address")
+	}
+
+	return nil
+}
+
+// Changes returns the changes to the resources of a set needed to go from the current to the desired definition.
+// Instances are expected to match the current definition.
+func Changes(name string, current *api.InstanceSetPut, desired api.InstanceSetPut) []api.StackChange {
+	previous := api.InstanceSetPut{}
+	if current != nil {
+		previous = *current
+	}
+
+	changes := []api.StackChange{}
+
+	// The instances are only updated when their definition changed.
+	updated := previous.Description != desired.Description || !reflect.DeepEqual(previous.Config, desired.Config) || !reflect.DeepEqual(previous.Devices, desired.Devices) || (desired.Profiles != nil && !slices.Equal(previous.Profiles, desired.Profiles))
+
+	for n := 1; n <= desired.Replicas; n++ {
+		if n > previous.Replicas {
+			changes = append(changes, api.StackChange{Action: stack.ActionCreate, Type: stack.TypeInstance, Name: InstanceName(name, n)})
+		} else if updated {
+			changes = append(changes, api.StackChange{Action: stack.ActionUpdate, Type: stack.TypeInstance, Name: InstanceName(name, n)})
+		}
+	}
+
+	// The previous load balancer is updated when the set moves away from it, the desired one on every change.
+	if previous.LoadBalancer != nil && (desired.LoadBalancer == nil || *previous.LoadBalancer != *desired.LoadBalancer) {
+		changes = append(changes, loadBalancerChange(*previous.LoadBalancer))
+	}
+
+	if desired.LoadBalancer != nil {
+		changes = append(changes, loadBalancerChange(*desired.LoadBalancer))
+	}
+
+	for n := previous.Replicas; n > desired.Replicas; n-- {
+		changes = append(changes, api.StackChange{Action: stack.ActionDelete, Type: stack.TypeInstance, Name: InstanceName(name, n)})
+	}
+
+	return changes
+}
+
+// loadBalancerChange returns the change to a load balancer used by a set.
+func loadBalancerChange(lb api.InstanceSetLoadBalancer) api.StackChange {
+	return api.StackChange{Action: stack.ActionUpdate, Type: stack.TypeNetworkLoadBalancer, Name: lb.Network + "/" + lb.ListenAddress}
+}
+
+// Apply brings the instances of a set to the desired definition. The current definition is nil for a set which
+// doesn't exist yet. Every change returned by Changes is first passed to check, when set. On failure, all changes
+// made so far are reverted. On success, a function which reverts the changes is returned for use if a later step
+// fails. Instances are deleted last but can't be restored.
+func Apply(client incus.InstanceServer, name string, current *api.InstanceSetPut, desired api.InstanceSetPut, check stack.CheckFunc) (revert.Hook, error) {
+	err := Validate(desired)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "%v", err)
+	}
+
+	if check != nil {
+		for _, change := range Changes(name, current, desired) {
+			err := check(change)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	previous := api.InstanceSetPut{}
+	if current != nil {
+		previous = *current
+	}
+
+	// Instances are placed using the placement logic of the server, restricted to the cluster group if set.
+	createClient := client
+	if desired.ClusterGroup != "" {
+		createClient = client.UseTarget("@" + desired.ClusterGroup)
+	}
+
+	for n := 1; n <= desired.Replicas; n++ {
+		instName := InstanceName(name, n)
+
+		inst, etag, err := client.GetInstance(instName)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, err
+		}
+
+		if inst != nil && n > previous.Replicas {
+			return nil, api.StatusErrorf(http.StatusConflict, "Instance %q already exists and isn't part of the set", instName)
+		}
+
+		if inst == nil {
+			err = instanceCreate(createClient, instName, desired)
+			if err != nil {
+				return nil, fmt.Errorf("Failed creating instance %q: %w", instName, err)
+			}
+
+			reverter.Add(func() { _ = stack.DeleteInstance(client, instName) })
+			continue
+		}
+
+		undo, err := instanceUpdate(client, inst, etag, previous, desired)
+		if err != nil {
+			return nil, fmt.Errorf("Failed updating instance %q: %w", instName, err)
+		}
+
+		if undo != nil {
+			reverter.Add(undo)
+		}
+	}
+
+	undo, err := loadBalancerSync(client, name, previous, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if undo != nil {
+		reverter.Add(undo)
+	}
+
+	// Remove the extra instances, newest first.
+	for n := previous.Replicas; n > desired.Replicas; n-- {
+		instName := InstanceName(name, n)
+
+		err := stack.DeleteInstance(client, instName)
+		if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return nil, fmt.Errorf("Failed deleting instance %q: %w", instName, err)
+		}
+	}
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+
+	return cleanup, nil
+}
+
+// Delete removes all the instances of a set and unregisters them from the load balancer.
+func Delete(client incus.InstanceServer, name string, current api.InstanceSetPut, check stack.CheckFunc) (revert.Hook, error) {
+	return Apply(client, name, &current, api.InstanceSetPut{}, check)
+}
+
+// instanceCreate creates and starts a new instance of the set.
+func instanceCreate(client incus.InstanceServer, name string, spec api.InstanceSetPut) error {
+	req := api.InstancesPost{
+		Name:   name,
+		Type:   spec.Type,
+		Source: spec.Source,
+		Start:  true,
+		InstancePut: api.InstancePut{
+			Description: spec.Description,
+			Profiles:    spec.Profiles,
+			Config:      spec.Config,
+			Devices:     spec.Devices,
+		},
+	}
+
+	op, err := client.CreateInstance(req)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// instanceUpdate applies the changes between the previous and new definition of the set to an existing instance
+// and returns a function restoring its previous configuration.
+func instanceUpdate(client incus.InstanceServer, inst *api.Instance, etag string, previous api.InstanceSetPut, desired api.InstanceSetPut) (revert.Hook, error) {
+	put := inst.Writable()
+	put.Description = desired.Description
+	put.Config = stack.MergeMap(inst.Config, previous.Config, desired.Config)
+	put.Devices = stack.MergeMap(inst.Devices, previous.Devices, desired.Devices)
+
+	// Instances created without a list of profiles get the default profile, keep it.
+	if desired.Profiles != nil {
+		put.Profiles = desired.Profiles
+	}
+
+	old := inst.Writable()
+	if reflect.DeepEqual(old, put) {
+		return nil, nil
+	}
+
+	op, err := client.UpdateInstance(inst.Name, put, etag)
+	if err != nil {
+		return nil, err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		op, err := client.UpdateInstance(inst.Name, old, "")
+		if err == nil {
+			_ = op.Wait()
+		}
+	}, nil
+}
+
+// instanceAddress waits for an instance to have a global address and returns it, preferring IPv4.
+func instanceAddress(client incus.InstanceServer, name string) (string, error) {
+	deadline := time.Now().Add(addressTimeout)
+
+	for {
+		state, _, err := client.GetInstanceState(name)
+		if err != nil {
+			return "", err
+		}
+
+		var ipv6 string
+		for ifName, network := range state.Network {
+			if ifName == "lo" {
+				continue
+			}
+
+			for _, addr := range network.Addresses {
+				if addr.Scope != "global" {
+					continue
+				}
+
+				if addr.Family == "inet" {
+					return addr.Address, nil
+				}
+
+				if ipv6 == "" {
+					ipv6 = addr.Address
+				}
+			}
+		}
+
+		if ipv6 != "" {
+			return ipv6, nil
+		}
+
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("Timed out waiting for instance %q to get an address", name)
+		}
+
+		time.Sleep(time.Second)
+	}
+}
+
+// loadBalancerSync registers the instances of the set as backends of its load balancer, removing those no longer
+// part of the set. A function restoring the previous load balancer configuration is returned.
+func loadBalancerSync(client incus.InstanceServer, name string, previous api.InstanceSetPut, desired api.InstanceSetPut) (revert.Hook, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	// All the backend names the set may have registered.
+	managed := InstanceNames(name, max(previous.Replicas, desired.Replicas))
+
+	// Unregister from the previous load balancer if it changed.
+	if previous.LoadBalancer != nil && (desired.LoadBalancer == nil || *previous.LoadBalancer != *desired.LoadBalancer) {
+		undo, err := loadBalancerUpdate(client, *previous.LoadBalancer, managed, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		reverter.Add(undo)
+	}
+
+	if desired.LoadBalancer != nil {
+		backends := make([]api.NetworkLoadBalancerBackend, 0, desired.Replicas)
+		for _, instName := range InstanceNames(name, desired.Replicas) {
+			address, err := instanceAddress(client, instName)
+			if err != nil {
+				return nil, err
+			}
+
+			backends = append(backends, api.NetworkLoadBalancerBackend{
+				Name:          instName,
+				Description:   fmt.Sprintf("Instance set %s", name),
+				TargetAddress: address,
+				TargetPort:    desired.LoadBalancer.TargetPort,
+			})
+		}
+
+		undo, err := loadBalancerUpdate(client, *desired.LoadBalancer, managed, backends)
+		if err != nil {
+			return nil, err
+		}
+
+		reverter.Add(undo)
+	}
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+
+	return cleanup, nil
+}
+
+// loadBalancerUpdate replaces the managed backends of a load balancer and makes all its ports target them.
+func loadBalancerUpdate(client incus.InstanceServer, lb api.InstanceSetLoadBalancer, managed []string, backends []api.NetworkLoadBalancerBackend) (revert.Hook, error) {
+	current, etag, err := client.GetNetworkLoadBalancer(lb.Network, lb.ListenAddress)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) && len(backends) == 0 {
+			// Nothing to unregister from.
+			return func() {}, nil
+		}
+
+		return nil, fmt.Errorf("Failed loading load balancer %q on network %q: %w", lb.ListenAddress, lb.Network, err)
+	}
+
+	old := current.Writable()
+	put := current.Writable()
+
+	names := make([]string, 0, len(backends))
+	for _, backend := range backends {
+		names = append(names, backend.Name)
+	}
+
+	put.Backends = []api.NetworkLoadBalancerBackend{}
+	for _, backend := range current.Backends {
+		if !slices.Contains(managed, backend.Name) {
+			put.Backends = append(put.Backends, backend)
+		}
+	}
+
+	put.Backends = append(put.Backends, backends...)
+
+	put.Ports = make([]api.NetworkLoadBalancerPort, 0, len(current.Ports))
+	for _, port := range current.Ports {
+		targets := []string{}
+		for _, target := range port.TargetBackend {
+			if !slices.Contains(managed, target) {
+				targets = append(targets, target)
+			}
+		}
+
+		port.TargetBackend = append(targets, names...)
+		put.Ports = append(put.Ports, port)
+	}
+
+	err = client.UpdateNetworkLoadBalancer(lb.Network, lb.ListenAddress, put, etag)
+	if err != nil {
+		return nil, fmt.Errorf("Failed updating load balancer %q on network %q: %w", lb.ListenAddress, lb.Network, err)
+	}
+
+	return func() { _ = client.UpdateNetworkLoadBalancer(lb.Network, lb.ListenAddress, old, "") }, nil
+}
@@ -0,0 +1,60 @@
+package instanceset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+func TestInstanceNames(t *testing.T) {
+	assert.Equal(t, []string{"web-1", "web-2", "web-3"}, InstanceNames("web", 3))
+	assert.Empty(t, InstanceNames("web", 0))
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(api.InstanceSetPut{Replicas: 2, ClusterGroup: "frontends"}))
+	assert.Error(t, Validate(api.InstanceSetPut{Replicas: -1}))
+	assert.Error(t, Validate(api.InstanceSetPut{Replicas: 1, ClusterGroup: "@frontends"}))
+	assert.Error(t, Validate(api.InstanceSetPut{Replicas: 1, LoadBalancer: &api.InstanceSetLoadBalancer{Network: "ovn0"}}))
+}
+
+func TestChanges(t *testing.T) {
+	lb := &api.InstanceSetLoadBalancer{Network: "ovn0", ListenAddress: "10.0.0.10", TargetPort: "80"}
+	current := api.InstanceSetPut{Replicas: 3, Config: map[string]string{"limits.cpu": "1"}, LoadBalancer: lb}
+
+	// Creating a set creates all its instances.
+	assert.Equal(t, []api.StackChange{
+		{Action: "create", Type: "instance", Name: "web-1"},
+		{Action: "create", Type: "instance", Name: "web-2"},
+	}, Changes("web", nil, api.InstanceSetPut{Replicas: 2}))
+
+	// Scaling down only deletes the extra instances, newest first, and updates the load balancer.
+	scaled := current
+	scaled.Replicas = 1
+	assert.Equal(t, []api.StackChange{
+		{Action: "update", Type: "network-load-balancer", Name: "ovn0/10.0.0.10"},
+		{Action: "delete", Type: "instance", Name: "web-3"},
+		{Action: "delete", Type: "instance", Name: "web-2"},
+	}, Changes("web", &current, scaled))
+
+	// Changing the configuration updates all the instances.
+	updated := current
+	updated.Config = map[string]string{"limits.cpu": "2"}
+	updated.LoadBalancer = nil
+	assert.Equal(t, []api.StackChange{
+		{Action: "update", Type: "instance", Name: "web-1"},
+		{Action: "update", Type: "instance", Name: "web-2"},
+		{Action: "update", Type: "instance", Name: "web-3"},
+		{Action: "update", Type: "network-load-balancer", Name: "ovn0/10.0.0.10"},
+	}, Changes("web", &current, updated))
+
+	// Deleting a set deletes all its instances.
+	assert.Equal(t, []api.StackChange{
+		{Action: "update", Type: "network-load-balancer", Name: "ovn0/10.0.0.10"},
+		{Action: "delete", Type: "instance", Name: "web-3"},
+		{Action: "delete", Type: "instance", Name: "web-2"},
+		{Action: "delete", Type: "instance", Name: "web-1"},
+	}, Changes("web", &current, api.InstanceSetPut{}))
+}
@@ -0,0 +1,28 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// InstanceSetAction represents a lifecycle event action for instance sets.
+type InstanceSetAction string
+
+// All supported lifecycle events for instance sets.
+const (
+	InstanceSetCreated = InstanceSetAction(api.EventLifecycleInstanceSetCreated)
+	InstanceSetDeleted = InstanceSetAction(api.EventLifecycleInstanceSetDeleted)
+	InstanceSetUpdated = InstanceSetAction(api.EventLifecycleInstanceSetUpdated)
+)
+
+// Event creates the lifecycle event for an action on an instance set.
+func (a InstanceSetAction) Event(name string, projectName string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "instance-sets", name).Project(projectName)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
@@ -0,0 +1,29 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ProjectTemplateAction represents a lifecycle event action for project templates.
+type ProjectTemplateAction string
+
+// All supported lifecycle events for project templates.
+const (
+	ProjectTemplateApplied = ProjectTemplateAction(api.EventLifecycleProjectTemplateApplied)
+	ProjectTemplateCreated = ProjectTemplateAction(api.EventLifecycleProjectTemplateCreated)
+	ProjectTemplateDeleted = ProjectTemplateAction(api.EventLifecycleProjectTemplateDeleted)
+	ProjectTemplateUpdated = ProjectTemplateAction(api.EventLifecycleProjectTemplateUpdated)
+)
+
+// Event creates the lifecycle event for an action on a project template.
+func (a ProjectTemplateAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "project-templates", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
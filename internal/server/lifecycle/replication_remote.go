@@ -0,0 +1,28 @@
+package lifecycle
+
+import (
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// ReplicationRemoteAction represents a lifecycle event action for replication remotes.
+type ReplicationRemoteAction string
+
+// All supported lifecycle events for replication remotes.
+const (
+	ReplicationRemoteCreated = ReplicationRemoteAction(api.EventLifecycleReplicationRemoteCreated)
+	ReplicationRemoteDeleted = ReplicationRemoteAction(api.EventLifecycleReplicationRemoteDeleted)
+	ReplicationRemoteUpdated = ReplicationRemoteAction(api.EventLifecycleReplicationRemoteUpdated)
+)
+
+// Event creates the lifecycle event for an action on a replication remote.
+func (a ReplicationRemoteAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := api.NewURL().Path(version.APIVersion, "replication-remotes", name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
@@ -374,6 +374,60 @@ func (c *Client) HandleEvent(event api.Event) {
 
 		message.WriteString(logEvent.Message)
 
+		entry.Line = message.String()
+	} else if event.Type == api.EventTypeAudit {
+		auditEvent := api.EventAudit{}
+
+		err := json.Unmarshal(event.Metadata, &auditEvent)
+		if err != nil {
+			return
+		}
+
+		// Build map. These key-value pairs will either be added as labels, or be part of the
+		// log message itself.
+		context["method"] = auditEvent.Method
+		context["status-code"] = strconv.Itoa(auditEvent.StatusCode)
+		context["outcome"] = auditEvent.Outcome
+		context["authorization"] = auditEvent.Authorization
+
+		if auditEvent.BodyDigest != "" {
+			context["body-digest"] = auditEvent.BodyDigest
+		}
+
+		if auditEvent.Requestor != nil {
+			context["requester-address"] = auditEvent.Requestor.Address
+			context["requester-protocol"] = auditEvent.Requestor.Protocol
+			context["requester-username"] = auditEvent.Requestor.Username
+		}
+
+		keys := make([]string, 0, len(context))
+
+		for k := range context {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		var message strings.Builder
+
+		// Add key-value pairs as labels but don't override any labels, and the remaining ones as the message prefix.
+		for _, k := range keys {
+			v := context[k]
+
+			if slices.Contains(c.cfg.labels, k) {
+				_, ok := entry.labels[k]
+				if !ok {
+					// Label names may not contain any hyphens.
+					entry.labels[strings.ReplaceAll(k, "-", "_")] = v
+					continue
+				}
+			}
+
+			message.WriteString(fmt.Sprintf("%s=%q ", k, v))
+		}
+
+		message.WriteString(auditEvent.URL)
+
 		entry.Line = message.String()
 	}
 
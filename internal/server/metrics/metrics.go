@@ -65,6 +65,11 @@ func (m *MetricSet) AddSamples(metricType MetricType, samples ...Sample) {
 	m.set[metricType] = append(m.set[metricType], samples...)
 }
 
+// GetSamples returns the samples of the type metricType.
+func (m *MetricSet) GetSamples(metricType MetricType) []Sample {
+	return m.set[metricType]
+}
+
 // Merge merges two MetricSets. Missing labels from m's samples are added to all samples in n.
 func (m *MetricSet) Merge(metricSet *MetricSet) {
 	if metricSet == nil {
@@ -2455,6 +2455,9 @@ func (n *bridge) forwardSetupFirewall() error {
 		fwForwards = append(fwForwards, n.forwardConvertToFirewallForwards(listenAddressNet.IP, net.ParseIP(forward.Config["target_address"]), portMaps)...)
 	}
 
+	// Send the connections for instances stopped due to inactivity to the host instead.
+	n.forwardApplyWakeRedirects(fwForwards)
+
 	if len(forwards) > 0 {
 		// Check if br_netfilter is enabled to, and warn if not.
 		brNetfilterWarning := false
@@ -2489,6 +2492,162 @@ func (n *bridge) forwardSetupFirewall() error {
 	return nil
 }
 
+// forwardWakePorts returns the TCP ports on the target address that the address forwards of this member point to.
+func (n *bridge) forwardWakePorts(targetAddress net.IP) ([]uint64, error) {
+	var forwards map[int64]*api.NetworkForward
+
+	err := n.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		forwards, err = tx.GetNetworkForwards(ctx, n.ID(), true)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading network forwards: %w", err)
+	}
+
+	var ports []uint64
+	for _, forward := range forwards {
+		listenAddressNet, err := ParseIPToNet(forward.ListenAddress)
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing address forward listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		portMaps, err := n.forwardValidate(listenAddressNet.IP, &forward.NetworkForwardPut)
+		if err != nil {
+			return nil, fmt.Errorf("Failed validating address forward for listen address %q: %w", forward.ListenAddress, err)
+		}
+
+		for _, portMap := range portMaps {
+			if portMap.protocol != "tcp" || !portMap.target.address.Equal(targetAddress) {
+				continue
+			}
+
+			for _, port := range forwardTargetPorts(portMap.listenPorts, portMap.target.ports) {
+				if !slices.Contains(ports, port) {
+					ports = append(ports, port)
+				}
+			}
+		}
+	}
+
+	slices.Sort(ports)
+
+	return ports, nil
+}
+
+// forwardWakeHold redirects the TCP port forwards pointing at the target address to listeners on the bridge address.
+func (n *bridge) forwardWakeHold(targetAddress net.IP) ([]BridgeForwardWakeListener, error) {
+	hostAddress := n.forwardWakeHostAddress(targetAddress)
+	if hostAddress == nil {
+		return nil, nil
+	}
+
+	ports, err := n.forwardWakePorts(targetAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ports) == 0 {
+		return nil, nil
+	}
+
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	redirects := make(map[uint64]uint64, len(ports))
+	listeners := make([]BridgeForwardWakeListener, 0, len(ports))
+	for _, port := range ports {
+		listener, err := net.Listen("tcp", net.JoinHostPort(hostAddress.String(), "0"))
+		if err != nil {
+			return nil, fmt.Errorf("Failed listening on bridge address %q: %w", hostAddress.String(), err)
+		}
+
+		reverter.Add(func() { _ = listener.Close() })
+
+		tcpAddr, ok := listener.Addr().(*net.TCPAddr)
+		if !ok {
+			return nil, fmt.Errorf("Unexpected listener address %q", listener.Addr().String())
+		}
+
+		redirects[port] = uint64(tcpAddr.Port)
+		listeners = append(listeners, BridgeForwardWakeListener{
+			Listener: listener,
+			Target:   net.JoinHostPort(targetAddress.String(), strconv.FormatUint(port, 10)),
+		})
+	}
+
+	bridgeForwardWakeSet(n.name, targetAddress, redirects)
+	reverter.Add(func() { bridgeForwardWakeSet(n.name, targetAddress, nil) })
+
+	err = n.forwardSetupFirewall()
+	if err != nil {
+		return nil, err
+	}
+
+	reverter.Success()
+
+	return listeners, nil
+}
+
+// forwardWakeHostAddress returns the bridge address of the same family as the target address.
+func (n *bridge) forwardWakeHostAddress(targetAddress net.IP) net.IP {
+	key := "ipv4.address"
+	if targetAddress.To4() == nil {
+		key = "ipv6.address"
+	}
+
+	hostAddress, _, err := net.ParseCIDR(n.config[key])
+	if err != nil {
+		return nil
+	}
+
+	return hostAddress
+}
+
+// forwardApplyWakeRedirects points the TCP forwards held for instances stopped due to inactivity at the bridge
+// address and the ports of their wake listeners.
+func (n *bridge) forwardApplyWakeRedirects(fwForwards []firewallDrivers.AddressForward) {
+	bridgeForwardWakeMu.Lock()
+	defer bridgeForwardWakeMu.Unlock()
+
+	targets := bridgeForwardWakeRedirects[n.name]
+	if len(targets) == 0 {
+		return
+	}
+
+	for i := range fwForwards {
+		fwForward := &fwForwards[i]
+		if fwForward.Protocol != "tcp" {
+			continue
+		}
+
+		redirects := targets[fwForward.TargetAddress.String()]
+		hostAddress := n.forwardWakeHostAddress(fwForward.TargetAddress)
+		if redirects == nil || hostAddress == nil {
+			continue
+		}
+
+		heldPorts := make([]uint64, 0, len(fwForward.ListenPorts))
+		for _, port := range forwardTargetPorts(fwForward.ListenPorts, fwForward.TargetPorts) {
+			heldPort, ok := redirects[port]
+			if !ok {
+				break
+			}
+
+			heldPorts = append(heldPorts, heldPort)
+		}
+
+		if len(heldPorts) != len(fwForward.ListenPorts) {
+			continue
+		}
+
+		fwForward.TargetAddress = hostAddress
+		fwForward.TargetPorts = heldPorts
+	}
+}
+
 // Leases returns a list of leases for the bridged network. It will reach out to other cluster members as needed.
 // The projectName passed here refers to the initial project from the API request which may differ from the network's project.
 func (n *bridge) Leases(projectName string, clientType request.ClientType) ([]api.NetworkLease, error) {
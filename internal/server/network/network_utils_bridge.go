@@ -3,8 +3,10 @@ package network
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/lxc/incus/v6/internal/server/ip"
 	"github.com/lxc/incus/v6/internal/server/state"
@@ -101,3 +103,96 @@ func DetachInterface(s *state.State, bridgeName string, devName string) error {
 
 	return nil
 }
+
+// BridgeForwardWakeListener is a host-side listener standing in for a TCP port targeted by a network forward.
+type BridgeForwardWakeListener struct {
+	Listener net.Listener
+	Target   string // Address and port of the instance the forward points to.
+}
+
+// bridgeForwardWakeRedirects holds the forward target ports redirected to wake listeners, keyed by network name,
+// then target address and then target port.
+var bridgeForwardWakeRedirects = map[string]map[string]map[uint64]uint64{}
+
+// bridgeForwardWakeMu controls access to the bridgeForwardWakeRedirects map.
+var bridgeForwardWakeMu sync.Mutex
+
+// bridgeForwardWakeSet records the wake listener ports for a target address, or clears them if redirects is nil.
+func bridgeForwardWakeSet(networkName string, targetAddress net.IP, redirects map[uint64]uint64) {
+	bridgeForwardWakeMu.Lock()
+	defer bridgeForwardWakeMu.Unlock()
+
+	if redirects == nil {
+		delete(bridgeForwardWakeRedirects[networkName], targetAddress.String())
+		if len(bridgeForwardWakeRedirects[networkName]) == 0 {
+			delete(bridgeForwardWakeRedirects, networkName)
+		}
+
+		return
+	}
+
+	if bridgeForwardWakeRedirects[networkName] == nil {
+		bridgeForwardWakeRedirects[networkName] = map[string]map[uint64]uint64{}
+	}
+
+	bridgeForwardWakeRedirects[networkName][targetAddress.String()] = redirects
+}
+
+// forwardTargetPorts returns the target port used for each of the listen ports of a forward.
+func forwardTargetPorts(listenPorts []uint64, targetPorts []uint64) []uint64 {
+	switch len(targetPorts) {
+	case 0:
+		return listenPorts
+	case 1:
+		ports := make([]uint64, 0, len(listenPorts))
+		for range listenPorts {
+			ports = append(ports, targetPorts[0])
+		}
+
+		return ports
+	default:
+		return targetPorts
+	}
+}
+
+// BridgeForwardWakeHold redirects the TCP port forwards of a bridge network that point at the target address to
+// host-side listeners on the bridge address, so that connections reach the host while the instance is stopped.
+// It returns nothing for other network types or if no forward points at the address.
+func BridgeForwardWakeHold(s *state.State, projectName string, networkName string, targetAddress net.IP) ([]BridgeForwardWakeListener, error) {
+	n, err := LoadByName(s, projectName, networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	b, ok := n.(*bridge)
+	if !ok {
+		return nil, nil
+	}
+
+	return b.forwardWakeHold(targetAddress)
+}
+
+// BridgeForwardWakeRelease restores the network forwards pointing at the target address.
+func BridgeForwardWakeRelease(s *state.State, projectName string, networkName string, targetAddress net.IP) error {
+	bridgeForwardWakeMu.Lock()
+	_, found := bridgeForwardWakeRedirects[networkName][targetAddress.String()]
+	bridgeForwardWakeMu.Unlock()
+
+	if !found {
+		return nil
+	}
+
+	bridgeForwardWakeSet(networkName, targetAddress, nil)
+
+	n, err := LoadByName(s, projectName, networkName)
+	if err != nil {
+		return err
+	}
+
+	b, ok := n.(*bridge)
+	if !ok {
+		return nil
+	}
+
+	return b.forwardSetupFirewall()
+}
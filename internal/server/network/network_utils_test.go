@@ -137,3 +137,13 @@ func Example_ipRangesOverlap() {
 	// Range1: 10.1.1.4, Range2: 10.1.1.8-10.1.1.9, overlapped: false
 	// Range1: 10.1.1.8-10.1.1.9, Range2: 10.1.1.4, overlapped: false
 }
+
+func Example_forwardTargetPorts() {
+	fmt.Println(forwardTargetPorts([]uint64{80, 81}, nil))
+	fmt.Println(forwardTargetPorts([]uint64{80, 81, 82}, []uint64{8080}))
+	fmt.Println(forwardTargetPorts([]uint64{80, 81}, []uint64{8080, 8081}))
+
+	// Output: [80 81]
+	// [8080 8080 8080]
+	// [8080 8081]
+}
@@ -65,6 +65,16 @@ func (c *Config) ClusterAddress() string {
 	return clusterAddress
 }
 
+// DatabaseBackupsSchedule returns the schedule for automatic global database backups.
+func (c *Config) DatabaseBackupsSchedule() string {
+	return c.m.GetString("database.backups.schedule")
+}
+
+// DatabaseBackupsRetention returns the number of automatic global database backups to keep.
+func (c *Config) DatabaseBackupsRetention() int64 {
+	return c.m.GetInt64("database.backups.retention")
+}
+
 // DebugAddress returns the address and port to setup the pprof listener on.
 func (c *Config) DebugAddress() string {
 	debugAddress := c.m.GetString("core.debug_address")
@@ -253,6 +263,26 @@ var ConfigSchema = config.Schema{
 	//  shortdesc: Whether to enable the syslog unixgram socket listener
 	"core.syslog_socket": {Validator: validate.Optional(validate.IsBool), Type: config.Bool},
 
+	// Global database backups
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=database.backups.schedule)
+	// Specify either a cron expression (`<minute> <hour> <dom> <month> <dow>`), a comma-and-space-separated list of schedule aliases (`@hourly`, `@daily`, `@midnight`, `@weekly`, `@monthly`, `@annually`, `@yearly`), or leave empty to disable automatic backups.
+	// See {ref}`backup-database`.
+	// ---
+	//  type: string
+	//  scope: local
+	//  shortdesc: Schedule for automatic backups of the global database
+	"database.backups.schedule": {Validator: validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly", "@never"}))},
+
+	// gendoc:generate(entity=server, group=miscellaneous, key=database.backups.retention)
+	// Older automatic backups are deleted once a new one is taken. Set to `0` to keep all of them.
+	// ---
+	//  type: integer
+	//  scope: local
+	//  defaultdesc: `7`
+	//  shortdesc: Number of automatic backups of the global database to keep
+	"database.backups.retention": {Type: config.Int64, Default: "7", Validator: validate.Optional(validate.IsUint32)},
+
 	// gendoc:generate(entity=server, group=miscellaneous, key=network.ovs.connection)
 	//
 	// ---
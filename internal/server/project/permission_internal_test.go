@@ -4,7 +4,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/lxc/incus/v6/shared/api"
 	"github.com/lxc/incus/v6/shared/idmap"
 )
 
@@ -75,3 +77,74 @@ func TestParseHostIDMapRange(t *testing.T) {
 		assert.Equal(t, idmaps, expected)
 	}
 }
+
+func TestCheckRestrictions_Replication(t *testing.T) {
+	instances := []api.Instance{{
+		Name: "c1",
+		Type: "container",
+		InstancePut: api.InstancePut{
+			Config: map[string]string{"replication.remote": "dr-site"},
+		},
+	}}
+
+	profiles := []api.Profile{{
+		Name: "default",
+		ProfilePut: api.ProfilePut{
+			Config: map[string]string{"replication.remote": "dr-site"},
+		},
+	}}
+
+	p := api.Project{Name: "p1", ProjectPut: api.ProjectPut{Config: map[string]string{"restricted": "true"}}}
+
+	// Replication is blocked by default.
+	err := checkRestrictions(p, instances, nil)
+	assert.ErrorContains(t, err, "Replication of container")
+
+	err = checkRestrictions(p, nil, profiles)
+	assert.ErrorContains(t, err, "Replication of profile")
+
+	// Replicas don't have a remote unless one is set on their own server.
+	err = checkRestrictions(p, []api.Instance{{Name: "c2", Type: "container", InstancePut: api.InstancePut{Config: map[string]string{"replication.role": "replica"}}}}, nil)
+	assert.NoError(t, err)
+
+	p.Config["restricted.replication"] = "allow"
+
+	err = checkRestrictions(p, instances, profiles)
+	assert.NoError(t, err)
+}
+
+func TestGetInstanceLimits_Devices(t *testing.T) {
+	inst := api.Instance{
+		Name:    "c1",
+		Project: "p1",
+		InstancePut: api.InstancePut{
+			Devices: map[string]map[string]string{
+				"eth0":  {"type": "nic", "limits.ingress": "10Mbit", "limits.egress": "20Mbit"},
+				"eth1":  {"type": "nic", "limits.max": "1Gbit"},
+				"root":  {"type": "disk", "path": "/", "pool": "default", "limits.read": "100iops", "limits.write": "50iops"},
+				"data":  {"type": "disk", "path": "/data", "source": "/srv", "limits.max": "200iops"},
+				"cloud": {"type": "disk", "source": "cloud-init:config"},
+			},
+		},
+	}
+
+	keys := []string{"limits.ingress", "limits.egress", "limits.disk.iops.read", "limits.disk.iops.write"}
+	limits, err := getInstanceLimits(inst, keys, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1010*1000*1000), limits["limits.ingress"])
+	assert.Equal(t, int64(1020*1000*1000), limits["limits.egress"])
+	assert.Equal(t, int64(300), limits["limits.disk.iops.read"])
+	assert.Equal(t, int64(250), limits["limits.disk.iops.write"])
+
+	// Devices without the limit aren't allowed unless unset values are skipped.
+	inst.Devices["eth2"] = map[string]string{"type": "nic"}
+	inst.Devices["root"]["limits.read"] = "10MB"
+
+	_, err = getInstanceLimits(inst, keys, false)
+	assert.Error(t, err)
+
+	limits, err = getInstanceLimits(inst, keys, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1010*1000*1000), limits["limits.ingress"])
+	assert.Equal(t, int64(200), limits["limits.disk.iops.read"])
+}
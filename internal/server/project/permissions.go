@@ -481,6 +481,7 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 
 	allowContainerLowLevel := false
 	allowVMLowLevel := false
+	allowReplication := false
 	var allowedIDMapHostUIDs, allowedIDMapHostGIDs []idmap.Entry
 
 	for i := range allRestrictions {
@@ -542,6 +543,11 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 				allowVMLowLevel = true
 			}
 
+		case "restricted.replication":
+			if restrictionValue == "allow" {
+				allowReplication = true
+			}
+
 		case "restricted.devices.unix-char":
 			devicesChecks["unix-char"] = func(device map[string]string) error {
 				if restrictionValue != "allow" {
@@ -723,6 +729,10 @@ func checkRestrictions(project api.Project, instances []api.Instance, profiles [
 				continue
 			}
 
+			if !allowReplication && key == "replication.remote" {
+				return fmt.Errorf("Replication of %s %q of project %q is forbidden", entityTypeLabel, entityName, project.Name)
+			}
+
 			if isContainerOrProfile && !allowContainerLowLevel && isContainerLowLevelOptionForbidden(key) {
 				return fmt.Errorf("Use of low-level config %q on %s %q of project %q is forbidden", key, entityTypeLabel, entityName, project.Name)
 			}
@@ -828,10 +838,39 @@ func CheckRestrictedDevicesDiskPaths(projectConfig map[string]string, sourcePath
 var allAggregateLimits = []string{
 	"limits.cpu",
 	"limits.disk",
+	"limits.disk.iops.read",
+	"limits.disk.iops.write",
+	"limits.egress",
+	"limits.ingress",
 	"limits.memory",
 	"limits.processes",
 }
 
+// deviceAggregateLimit describes where the instance-level values of an aggregate limit are found.
+type deviceAggregateLimit struct {
+	deviceType string
+	configKeys []string
+}
+
+// deviceAggregateLimits lists the aggregate limits which are summed across the instance devices
+// rather than the instance config, along with the device keys holding the value (by precedence).
+var deviceAggregateLimits = map[string]deviceAggregateLimit{
+	"limits.disk.iops.read":  {deviceType: "disk", configKeys: []string{"limits.max", "limits.read"}},
+	"limits.disk.iops.write": {deviceType: "disk", configKeys: []string{"limits.max", "limits.write"}},
+	"limits.egress":          {deviceType: "nic", configKeys: []string{"limits.max", "limits.egress"}},
+	"limits.ingress":         {deviceType: "nic", configKeys: []string{"limits.max", "limits.ingress"}},
+}
+
+// projectCountLimits lists the limits on the number of snapshots and backups along with the function counting them.
+var projectCountLimits = map[string]func(ctx context.Context, tx *db.ClusterTx, projectName string) (int, error){
+	"limits.backups": func(ctx context.Context, tx *db.ClusterTx, projectName string) (int, error) {
+		return tx.GetProjectBackupsCount(ctx, projectName)
+	},
+	"limits.snapshots": func(ctx context.Context, tx *db.ClusterTx, projectName string) (int, error) {
+		return tx.GetProjectSnapshotsCount(ctx, projectName)
+	},
+}
+
 // allRestrictions lists all available 'restrict.*' config keys along with their default setting.
 var allRestrictions = map[string]string{
 	"restricted.backups":                   "block",
@@ -856,6 +895,7 @@ var allRestrictions = map[string]string{
 	"restricted.idmap.uid":                 "",
 	"restricted.idmap.gid":                 "",
 	"restricted.networks.access":           "",
+	"restricted.replication":               "block",
 	"restricted.snapshots":                 "block",
 }
 
@@ -1072,12 +1112,28 @@ func AllowProjectUpdate(tx *db.ClusterTx, projectName string, config map[string]
 				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
 			}
 
+		case "limits.backups":
+			fallthrough
+		case "limits.snapshots":
+			err := validateProjectCountLimit(tx, key, config[key], projectName)
+			if err != nil {
+				return fmt.Errorf("Can't change %q in project %q: %w", key, projectName, err)
+			}
+
 		case "limits.processes":
 			fallthrough
 		case "limits.cpu":
 			fallthrough
 		case "limits.memory":
 			fallthrough
+		case "limits.disk.iops.read":
+			fallthrough
+		case "limits.disk.iops.write":
+			fallthrough
+		case "limits.egress":
+			fallthrough
+		case "limits.ingress":
+			fallthrough
 		case "limits.disk":
 			aggregateKeys = append(aggregateKeys, key)
 		}
@@ -1153,6 +1209,29 @@ func validateInstanceCountLimit(instances []api.Instance, key, value, project st
 	return nil
 }
 
+// Check that limits.snapshots or limits.backups is equal or above the current count.
+func validateProjectCountLimit(tx *db.ClusterTx, key, value, projectName string) error {
+	if value == "" {
+		return nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	count, err := projectCountLimits[key](context.Background(), tx, projectName)
+	if err != nil {
+		return err
+	}
+
+	if limit < count {
+		return fmt.Errorf("%q is too low: there currently are %d in project %q", key, count, projectName)
+	}
+
+	return nil
+}
+
 var countConfigInstanceType = map[string]api.InstanceType{
 	"limits.containers":       api.InstanceTypeContainer,
 	"limits.virtual-machines": api.InstanceTypeVM,
@@ -1461,6 +1540,11 @@ func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[st
 
 				limit += sizeStateLimit
 			}
+		} else if deviceLimit, ok := deviceAggregateLimits[key]; ok {
+			limit, err = getInstanceDevicesLimit(inst, key, deviceLimit, parser, skipUnset)
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			value, ok := inst.Config[key]
 			if !ok || value == "" {
@@ -1487,6 +1571,61 @@ func getInstanceLimits(inst api.Instance, keys []string, skipUnset bool) (map[st
 	return limits, nil
 }
 
+// getInstanceDevicesLimit returns the sum of the values of the given limit across the devices of the instance.
+func getInstanceDevicesLimit(inst api.Instance, key string, deviceLimit deviceAggregateLimit, parser func(string) (int64, error), skipUnset bool) (int64, error) {
+	var total int64
+
+	for name, device := range inst.Devices {
+		if device["type"] != deviceLimit.deviceType {
+			continue
+		}
+
+		// Skip the disks generated by Incus itself.
+		if deviceLimit.deviceType == "disk" && (strings.HasPrefix(device["source"], "cloud-init:") || strings.HasPrefix(device["source"], "agent:")) {
+			continue
+		}
+
+		value := ""
+		for _, configKey := range deviceLimit.configKeys {
+			value = device[configKey]
+			if value != "" {
+				break
+			}
+		}
+
+		if value == "" {
+			if skipUnset {
+				continue
+			}
+
+			return -1, fmt.Errorf("Device %q of instance %q in project %q has no %q config, either directly or via a profile", name, inst.Name, inst.Project, deviceLimit.configKeys[len(deviceLimit.configKeys)-1])
+		}
+
+		limit, err := parser(value)
+		if err != nil {
+			if skipUnset {
+				continue
+			}
+
+			return -1, fmt.Errorf("Failed parsing %q for device %q of instance %q in project %q: %w", key, name, inst.Name, inst.Project, err)
+		}
+
+		total += limit
+	}
+
+	return total, nil
+}
+
+// parseIOPSLimit parses an I/O limit expressed in IOPS.
+func parseIOPSLimit(value string) (int64, error) {
+	limit, err := strconv.ParseInt(strings.TrimSuffix(value, "iops"), 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("Value must be a number of IOPS")
+	}
+
+	return limit, nil
+}
+
 var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.memory": func(value string) (int64, error) {
 		if strings.HasSuffix(value, "%") {
@@ -1518,6 +1657,10 @@ var aggregateLimitConfigValueParsers = map[string]func(string) (int64, error){
 	"limits.disk": func(value string) (int64, error) {
 		return units.ParseByteSizeString(value)
 	},
+	"limits.disk.iops.read":  parseIOPSLimit,
+	"limits.disk.iops.write": parseIOPSLimit,
+	"limits.egress":          units.ParseBitSizeString,
+	"limits.ingress":         units.ParseBitSizeString,
 }
 
 var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
@@ -1533,6 +1676,18 @@ var aggregateLimitConfigValuePrinters = map[string]func(int64) string{
 	"limits.disk": func(limit int64) string {
 		return units.GetByteSizeStringIEC(limit, 1)
 	},
+	"limits.disk.iops.read": func(limit int64) string {
+		return fmt.Sprintf("%diops", limit)
+	},
+	"limits.disk.iops.write": func(limit int64) string {
+		return fmt.Sprintf("%diops", limit)
+	},
+	"limits.egress": func(limit int64) string {
+		return units.GetBitSizeString(limit, 1)
+	},
+	"limits.ingress": func(limit int64) string {
+		return units.GetBitSizeString(limit, 1)
+	},
 }
 
 // FilterUsedBy filters a UsedBy list based on project access.
@@ -1627,16 +1782,78 @@ func AllowBackupCreation(tx *db.ClusterTx, projectName string) error {
 		return fmt.Errorf("Project %q doesn't allow for backup creation", projectName)
 	}
 
-	return nil
+	return checkProjectCountLimit(tx, project, "limits.backups", 1)
 }
 
-// AllowSnapshotCreation returns an error if any project-specific restriction is violated
+// AllowSnapshotCreation returns an error if any project-specific limit or restriction is violated
 // when creating a new snapshot in a project.
-func AllowSnapshotCreation(p *api.Project) error {
+func AllowSnapshotCreation(tx *db.ClusterTx, p *api.Project) error {
 	if projectHasRestriction(p, "restricted.snapshots", "block") {
 		return fmt.Errorf("Project %q doesn't allow for snapshot creation", p.Name)
 	}
 
+	return checkProjectCountLimit(tx, p, "limits.snapshots", 1)
+}
+
+// AllowSnapshotsImport returns an error if adding the given number of snapshots to a project, for example
+// when copying, migrating or importing an instance or custom volume along with its snapshots, would exceed
+// its limits.snapshots.
+func AllowSnapshotsImport(tx *db.ClusterTx, projectName string, count int) error {
+	if count == 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	dbProject, err := cluster.GetProject(ctx, tx.Tx(), projectName)
+	if err != nil {
+		return err
+	}
+
+	project, err := dbProject.ToAPI(ctx, tx.Tx())
+	if err != nil {
+		return err
+	}
+
+	return checkProjectCountLimit(tx, project, "limits.snapshots", count)
+}
+
+// getProjectCountLimit returns the current count and the limit (-1 if unset) for limits.snapshots or limits.backups.
+func getProjectCountLimit(tx *db.ClusterTx, p *api.Project, key string) (int, int, error) {
+	limit := -1
+
+	value := p.Config[key]
+	if value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			return -1, -1, err
+		}
+	}
+
+	count, err := projectCountLimits[key](context.Background(), tx, p.Name)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return count, limit, nil
+}
+
+// checkProjectCountLimit returns an error if creating the given number of snapshots or backups would exceed the
+// project limit.
+func checkProjectCountLimit(tx *db.ClusterTx, p *api.Project, key string, added int) error {
+	if p.Config[key] == "" {
+		return nil
+	}
+
+	count, limit, err := getProjectCountLimit(tx, p, key)
+	if err != nil {
+		return err
+	}
+
+	if count+added > limit {
+		return fmt.Errorf("Reached maximum value %q for %q in project %q", p.Config[key], key, p.Name)
+	}
+
 	return nil
 }
 
@@ -48,6 +48,10 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 
 	result["cpu"] = raw["limits.cpu"]
 	result["disk"] = raw["limits.disk"]
+	result["egress"] = raw["limits.egress"]
+	result["ingress"] = raw["limits.ingress"]
+	result["iops.read"] = raw["limits.disk.iops.read"]
+	result["iops.write"] = raw["limits.disk.iops.write"]
 	result["memory"] = raw["limits.memory"]
 	result["networks"] = raw["limits.networks"]
 	result["processes"] = raw["limits.processes"]
@@ -90,6 +94,19 @@ func GetCurrentAllocations(ctx context.Context, tx *db.ClusterTx, projectName st
 		Usage: int64(count),
 	}
 
+	// Get the snapshot and backup count values.
+	for key := range projectCountLimits {
+		count, limit, err := getProjectCountLimit(tx, &info.Project, key)
+		if err != nil {
+			return nil, err
+		}
+
+		result[strings.TrimPrefix(key, "limits.")] = api.ProjectStateResource{
+			Limit: int64(limit),
+			Usage: int64(count),
+		}
+	}
+
 	// Get the network limit and usage.
 	overallValue, ok := info.Project.Config["limits.networks"]
 	limit = -1
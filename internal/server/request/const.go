@@ -20,6 +20,9 @@ const (
 	// CtxProtocol is the protocol field in request context.
 	CtxProtocol CtxKey = "protocol"
 
+	// CtxIdentityProviderGroups is the identity provider groups field in request context.
+	CtxIdentityProviderGroups CtxKey = "identity_provider_groups"
+
 	// CtxForwardedAddress is the forwarded address field in request context.
 	CtxForwardedAddress CtxKey = "forwarded_address"
 
@@ -28,6 +31,12 @@ const (
 
 	// CtxForwardedProtocol is the forwarded protocol field in request context.
 	CtxForwardedProtocol CtxKey = "forwarded_protocol"
+
+	// CtxForwardedIdentityProviderGroups is the forwarded identity provider groups field in request context.
+	CtxForwardedIdentityProviderGroups CtxKey = "forwarded_identity_provider_groups"
+
+	// CtxAuthToken is the API token field in request context, set for requests made with (or forwarded on behalf of) an API token.
+	CtxAuthToken CtxKey = "auth_token"
 )
 
 // Headers.
@@ -40,4 +49,10 @@ const (
 
 	// HeaderForwardedProtocol is the forwarded protocol field in request header.
 	HeaderForwardedProtocol = "X-Incus-forwarded-protocol"
+
+	// HeaderForwardedIdentityProviderGroups is the forwarded identity provider groups field in request header, repeated for each group.
+	HeaderForwardedIdentityProviderGroups = "X-Incus-forwarded-identity-provider-groups"
+
+	// HeaderForwardedAuthToken is the forwarded API token name field in request header.
+	HeaderForwardedAuthToken = "X-Incus-forwarded-auth-token"
 )
@@ -51,6 +51,42 @@ func CreateRequestor(r *http.Request) *api.EventLifecycleRequestor {
 	return requestor
 }
 
+// ForwardRequestor sets the headers identifying the requestor of r on req, a request made on its behalf to the
+// local server through the unix socket, so that it's authorized and recorded as coming from that requestor.
+func ForwardRequestor(r *http.Request, req *http.Request) {
+	ctx := r.Context()
+
+	username, _ := ctx.Value(CtxUsername).(string)
+	protocol, _ := ctx.Value(CtxProtocol).(string)
+	groups, _ := ctx.Value(CtxIdentityProviderGroups).([]string)
+	address := r.RemoteAddr
+
+	// Requests forwarded by another cluster member carry the original requestor.
+	forwardedProtocol, _ := ctx.Value(CtxForwardedProtocol).(string)
+	if forwardedProtocol != "" {
+		username, _ = ctx.Value(CtxForwardedUsername).(string)
+		protocol = forwardedProtocol
+		groups, _ = ctx.Value(CtxForwardedIdentityProviderGroups).([]string)
+		address, _ = ctx.Value(CtxForwardedAddress).(string)
+	}
+
+	req.Header.Set(HeaderForwardedUsername, username)
+	req.Header.Set(HeaderForwardedProtocol, protocol)
+	req.Header.Set(HeaderForwardedAddress, address)
+
+	req.Header.Del(HeaderForwardedIdentityProviderGroups)
+	for _, group := range groups {
+		req.Header.Add(HeaderForwardedIdentityProviderGroups, group)
+	}
+
+	// Requests made with an API token remain limited to its scope.
+	req.Header.Del(HeaderForwardedAuthToken)
+	token, ok := ctx.Value(CtxAuthToken).(*api.AuthToken)
+	if ok && token != nil {
+		req.Header.Set(HeaderForwardedAuthToken, token.Name)
+	}
+}
+
 // SaveConnectionInContext can be set as the ConnContext field of a http.Server to set the connection
 // in the request context for later use.
 func SaveConnectionInContext(ctx context.Context, connection net.Conn) context.Context {
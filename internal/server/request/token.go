@@ -0,0 +1,32 @@
+package request
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// AuthTokenPrefix is the prefix of the secrets of API tokens, telling them apart from other bearer tokens.
+const AuthTokenPrefix = "incus_"
+
+// AuthToken returns the API token secret passed as a bearer token, or an empty string if the request doesn't have one.
+func AuthToken(r *http.Request) string {
+	scheme, secret, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "bearer") {
+		return ""
+	}
+
+	secret = strings.TrimSpace(secret)
+	if !strings.HasPrefix(secret, AuthTokenPrefix) {
+		return ""
+	}
+
+	return secret
+}
+
+// HashAuthToken returns the hash of an API token secret, which is all that gets stored.
+func HashAuthToken(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,140 @@
+package scriptlet
+
+import (
+	"context"
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/log"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/marshal"
+	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// ClusterRebalanceRun runs the cluster re-balancing scriptlet and returns the instance moves it requested.
+func ClusterRebalanceRun(ctx context.Context, l logger.Logger, members []api.ClusterRebalanceMember, instances []apiScriptlet.ClusterRebalanceInstance) ([]api.ClusterRebalanceMove, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logFunc := log.CreateLogger(l, "Cluster re-balancing scriptlet")
+
+	moves := []api.ClusterRebalanceMove{}
+
+	moveInstanceFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var projectName string
+		var instanceName string
+		var memberName string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "project", &projectName, "name", &instanceName, "member_name", &memberName)
+		if err != nil {
+			return nil, err
+		}
+
+		var inst *apiScriptlet.ClusterRebalanceInstance
+		for i := range instances {
+			if instances[i].Project == projectName && instances[i].Name == instanceName {
+				inst = &instances[i]
+				break
+			}
+		}
+
+		if inst == nil {
+			return nil, fmt.Errorf("Instance %q in project %q can't be moved", instanceName, projectName)
+		}
+
+		found := false
+		for _, member := range members {
+			if member.Name == memberName {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("Invalid member name: %s", memberName)
+		}
+
+		for _, move := range moves {
+			if move.Project == projectName && move.Instance == instanceName {
+				return nil, fmt.Errorf("Instance %q in project %q is already being moved", instanceName, projectName)
+			}
+		}
+
+		l.Info("Cluster re-balancing scriptlet moved instance", logger.Ctx{"project": projectName, "instance": instanceName, "member": memberName})
+
+		moves = append(moves, api.ClusterRebalanceMove{
+			Instance: instanceName,
+			Project:  projectName,
+			Source:   inst.Location,
+			Target:   memberName,
+			Live:     inst.Live,
+		})
+
+		return starlark.None, nil
+	}
+
+	// Remember to match the entries in scriptletLoad.ClusterRebalanceCompile() with this list so Starlark can
+	// perform compile time validation of functions used.
+	env := starlark.StringDict{
+		"log_info":      starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":      starlark.NewBuiltin("log_warn", logFunc),
+		"log_error":     starlark.NewBuiltin("log_error", logFunc),
+		"move_instance": starlark.NewBuiltin("move_instance", moveInstanceFunc),
+	}
+
+	prog, thread, err := scriptletLoad.ClusterRebalanceProgram()
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		<-ctx.Done()
+		thread.Cancel("Request finished")
+	}()
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		return nil, fmt.Errorf("Failed initializing: %w", err)
+	}
+
+	globals.Freeze()
+
+	// Retrieve a global variable from starlark environment.
+	clusterRebalance := globals["cluster_rebalance"]
+	if clusterRebalance == nil {
+		return nil, fmt.Errorf("Scriptlet missing cluster_rebalance function")
+	}
+
+	membersv, err := marshal.StarlarkMarshal(members)
+	if err != nil {
+		return nil, fmt.Errorf("Marshalling members failed: %w", err)
+	}
+
+	instancesv, err := marshal.StarlarkMarshal(instances)
+	if err != nil {
+		return nil, fmt.Errorf("Marshalling instances failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, clusterRebalance, nil, []starlark.Tuple{
+		{
+			starlark.String("members"),
+			membersv,
+		}, {
+			starlark.String("instances"),
+			instancesv,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to run: %w", err)
+	}
+
+	if v.Type() != "NoneType" {
+		return nil, fmt.Errorf("Failed with unexpected return value: %v", v)
+	}
+
+	return moves, nil
+}
@@ -0,0 +1,191 @@
+package scriptlet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.starlark.net/starlark"
+
+	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/log"
+	"github.com/lxc/incus/v6/internal/server/scriptlet/marshal"
+	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// InstanceAdmissionRun runs the instance admission scriptlet against the request.
+// Changes made by the scriptlet are applied to the local config and devices of the request.
+// A request rejected by the scriptlet results in a Forbidden status error.
+func InstanceAdmissionRun(ctx context.Context, l logger.Logger, req *apiScriptlet.InstanceAdmission) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	logFunc := log.CreateLogger(l, "Instance admission scriptlet")
+
+	var rejection string
+
+	rejectFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var reason string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "reason", &reason)
+		if err != nil {
+			return nil, err
+		}
+
+		if reason == "" {
+			reason = "No reason provided"
+		}
+
+		rejection = reason
+
+		return starlark.None, nil
+	}
+
+	setConfigFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var key string
+		var value string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "key", &key, "value", &value)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		if value == "" {
+			delete(req.Config, key)
+		} else {
+			req.Config[key] = value
+		}
+
+		l.Debug("Instance admission scriptlet set config key", logger.Ctx{"key": key, "value": value})
+
+		return starlark.None, nil
+	}
+
+	setDeviceFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+		var device *starlark.Dict
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "device", &device)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := marshal.StarlarkUnmarshal(device)
+		if err != nil {
+			return nil, err
+		}
+
+		fields, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("Invalid device %q", name)
+		}
+
+		dev := make(map[string]string, len(fields))
+		for k, v := range fields {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("Invalid value for %q in device %q, must be a string", k, name)
+			}
+
+			dev[k] = s
+		}
+
+		if dev["type"] == "" {
+			return nil, fmt.Errorf("Missing type for device %q", name)
+		}
+
+		if req.Devices == nil {
+			req.Devices = map[string]map[string]string{}
+		}
+
+		req.Devices[name] = dev
+
+		l.Debug("Instance admission scriptlet set device", logger.Ctx{"device": name})
+
+		return starlark.None, nil
+	}
+
+	removeDeviceFunc := func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+		var name string
+
+		err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name)
+		if err != nil {
+			return nil, err
+		}
+
+		delete(req.Devices, name)
+
+		l.Debug("Instance admission scriptlet removed device", logger.Ctx{"device": name})
+
+		return starlark.None, nil
+	}
+
+	// Remember to match the entries in scriptletLoad.InstanceAdmissionCompile() with this list so Starlark can
+	// perform compile time validation of functions used.
+	env := starlark.StringDict{
+		"log_info":      starlark.NewBuiltin("log_info", logFunc),
+		"log_warn":      starlark.NewBuiltin("log_warn", logFunc),
+		"log_error":     starlark.NewBuiltin("log_error", logFunc),
+		"reject":        starlark.NewBuiltin("reject", rejectFunc),
+		"set_config":    starlark.NewBuiltin("set_config", setConfigFunc),
+		"set_device":    starlark.NewBuiltin("set_device", setDeviceFunc),
+		"remove_device": starlark.NewBuiltin("remove_device", removeDeviceFunc),
+	}
+
+	prog, thread, err := scriptletLoad.InstanceAdmissionProgram()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		thread.Cancel("Request finished")
+	}()
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		return fmt.Errorf("Failed initializing: %w", err)
+	}
+
+	globals.Freeze()
+
+	// Retrieve a global variable from starlark environment.
+	instanceAdmission := globals["instance_admission"]
+	if instanceAdmission == nil {
+		return fmt.Errorf("Scriptlet missing instance_admission function")
+	}
+
+	// The scriptlet gets its own copy of the request so changes are only made through the provided functions.
+	requestv, err := marshal.StarlarkMarshal(req)
+	if err != nil {
+		return fmt.Errorf("Marshalling request failed: %w", err)
+	}
+
+	// Call starlark function from Go.
+	v, err := starlark.Call(thread, instanceAdmission, nil, []starlark.Tuple{
+		{
+			starlark.String("request"),
+			requestv,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to run: %w", err)
+	}
+
+	if v.Type() != "NoneType" {
+		return fmt.Errorf("Failed with unexpected return value: %v", v)
+	}
+
+	if rejection != "" {
+		l.Info("Instance admission scriptlet rejected request", logger.Ctx{"project": req.Project, "instance": req.Name, "reason": rejection})
+		return api.StatusErrorf(http.StatusForbidden, "Rejected by instance admission scriptlet: %s", rejection)
+	}
+
+	return nil
+}
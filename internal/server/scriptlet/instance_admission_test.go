@@ -0,0 +1,71 @@
+package scriptlet
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	scriptletLoad "github.com/lxc/incus/v6/internal/server/scriptlet/load"
+	"github.com/lxc/incus/v6/shared/api"
+	apiScriptlet "github.com/lxc/incus/v6/shared/api/scriptlet"
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+const instanceAdmissionTestScriptlet = `
+def instance_admission(request):
+    if request.type == "virtual-machine" and request.expanded_config.get("security.secureboot", "true") != "true":
+        reject("Virtual machines must use secure boot")
+        return
+
+    if request.reason == "create":
+        set_config("user.owner", request.project)
+
+    set_config("user.unwanted", "")
+    set_device("agent", {"type": "disk", "source": "agent:config"})
+    remove_device("legacy")
+`
+
+func TestInstanceAdmissionRun(t *testing.T) {
+	err := scriptletLoad.InstanceAdmissionValidate(instanceAdmissionTestScriptlet)
+	require.NoError(t, err)
+
+	err = scriptletLoad.InstanceAdmissionSet(instanceAdmissionTestScriptlet)
+	require.NoError(t, err)
+
+	defer func() { _ = scriptletLoad.InstanceAdmissionSet("") }()
+
+	req := &apiScriptlet.InstanceAdmission{
+		InstancePut: api.InstancePut{
+			Config: map[string]string{"user.unwanted": "yes"},
+			Devices: map[string]map[string]string{
+				"legacy": {"type": "none"},
+			},
+		},
+		Name:           "c1",
+		Type:           "container",
+		Project:        "tenant",
+		Reason:         apiScriptlet.InstanceAdmissionReasonCreate,
+		ExpandedConfig: map[string]string{"user.unwanted": "yes"},
+	}
+
+	err = InstanceAdmissionRun(context.Background(), logger.Log, req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"user.owner": "tenant"}, req.Config)
+	assert.Equal(t, map[string]map[string]string{"agent": {"type": "disk", "source": "agent:config"}}, req.Devices)
+
+	req = &apiScriptlet.InstanceAdmission{
+		Name:           "v1",
+		Type:           "virtual-machine",
+		Project:        "tenant",
+		Reason:         apiScriptlet.InstanceAdmissionReasonUpdate,
+		ExpandedConfig: map[string]string{"security.secureboot": "false"},
+	}
+
+	err = InstanceAdmissionRun(context.Background(), logger.Log, req)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+	assert.ErrorContains(t, err, "Virtual machines must use secure boot")
+	assert.Nil(t, req.Config)
+}
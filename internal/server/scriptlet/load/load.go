@@ -9,6 +9,12 @@ import (
 // nameInstancePlacement is the name used in Starlark for the instance placement scriptlet.
 const nameInstancePlacement = "instance_placement"
 
+// nameInstanceAdmission is the name used in Starlark for the instance admission scriptlet.
+const nameInstanceAdmission = "instance_admission"
+
+// nameClusterRebalance is the name used in Starlark for the cluster re-balancing scriptlet.
+const nameClusterRebalance = "cluster_rebalance"
+
 // prefixQEMU is the prefix used in Starlark for the QEMU scriptlet.
 const prefixQEMU = "qemu"
 
@@ -55,6 +61,65 @@ func InstancePlacementProgram() (*starlark.Program, *starlark.Thread, error) {
 	return program("Instance placement", nameInstancePlacement)
 }
 
+// InstanceAdmissionCompile compiles the instance admission scriptlet.
+func InstanceAdmissionCompile(name string, src string) (*starlark.Program, error) {
+	return compile(name, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+		"reject",
+		"set_config",
+		"set_device",
+		"remove_device",
+	})
+}
+
+// InstanceAdmissionValidate validates the instance admission scriptlet.
+func InstanceAdmissionValidate(src string) error {
+	return validate(InstanceAdmissionCompile, nameInstanceAdmission, src, declaration{
+		required("instance_admission"): {"request"},
+	})
+}
+
+// InstanceAdmissionSet compiles the instance admission scriptlet into memory for use with InstanceAdmissionRun.
+// If empty src is provided the current program is deleted.
+func InstanceAdmissionSet(src string) error {
+	return set(InstanceAdmissionCompile, nameInstanceAdmission, src)
+}
+
+// InstanceAdmissionProgram returns the precompiled instance admission scriptlet program.
+func InstanceAdmissionProgram() (*starlark.Program, *starlark.Thread, error) {
+	return program("Instance admission", nameInstanceAdmission)
+}
+
+// ClusterRebalanceCompile compiles the cluster re-balancing scriptlet.
+func ClusterRebalanceCompile(name string, src string) (*starlark.Program, error) {
+	return compile(name, src, []string{
+		"log_info",
+		"log_warn",
+		"log_error",
+		"move_instance",
+	})
+}
+
+// ClusterRebalanceValidate validates the cluster re-balancing scriptlet.
+func ClusterRebalanceValidate(src string) error {
+	return validate(ClusterRebalanceCompile, nameClusterRebalance, src, declaration{
+		required("cluster_rebalance"): {"members", "instances"},
+	})
+}
+
+// ClusterRebalanceSet compiles the cluster re-balancing scriptlet into memory for use with ClusterRebalanceRun.
+// If empty src is provided the current program is deleted.
+func ClusterRebalanceSet(src string) error {
+	return set(ClusterRebalanceCompile, nameClusterRebalance, src)
+}
+
+// ClusterRebalanceProgram returns the precompiled cluster re-balancing scriptlet program.
+func ClusterRebalanceProgram() (*starlark.Program, *starlark.Thread, error) {
+	return program("Cluster re-balancing", nameClusterRebalance)
+}
+
 // QEMUCompile compiles the QEMU scriptlet.
 func QEMUCompile(name string, src string) (*starlark.Program, error) {
 	return compile(name, src, []string{
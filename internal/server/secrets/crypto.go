@@ -0,0 +1,97 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// key derives the key used to encrypt the secrets from the private key of the cluster certificate.
+func key(cert *localtls.CertInfo) ([]byte, error) {
+	privateKey := cert.PrivateKey()
+	if len(privateKey) == 0 {
+		return nil, errors.New("No private key available to derive the secrets key from")
+	}
+
+	k := make([]byte, 32)
+
+	_, err := io.ReadFull(hkdf.New(sha256.New, privateKey, nil, []byte("incus secrets")), k)
+	if err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// aead returns the authenticated cipher used to encrypt the secrets.
+func aead(cert *localtls.CertInfo) (cipher.AEAD, error) {
+	k, err := key(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// additionalData ties an encrypted value to the secret it belongs to.
+func additionalData(projectName string, name string) []byte {
+	return []byte(projectName + "/" + name)
+}
+
+// Encrypt encrypts the value of a secret with a key derived from the cluster certificate.
+// The result is base64 encoded and only decrypts for the same project and secret name.
+func Encrypt(cert *localtls.CertInfo, projectName string, name string, value string) (string, error) {
+	gcm, err := aead(cert)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	data := gcm.Seal(nonce, nonce, []byte(value), additionalData(projectName, name))
+
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// Decrypt decrypts the value of a secret encrypted with Encrypt.
+func Decrypt(cert *localtls.CertInfo, projectName string, name string, value string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := aead(cert)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("Invalid encrypted value for secret %q", name)
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], additionalData(projectName, name))
+	if err != nil {
+		return "", fmt.Errorf("Failed decrypting secret %q: %w", name, err)
+	}
+
+	return string(plaintext), nil
+}
@@ -0,0 +1,189 @@
+//go:build linux && cgo && !agent
+
+package secrets
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/lxc/incus/v6/internal/server/db"
+	dbCluster "github.com/lxc/incus/v6/internal/server/db/cluster"
+	"github.com/lxc/incus/v6/internal/server/state"
+	"github.com/lxc/incus/v6/internal/version"
+	"github.com/lxc/incus/v6/shared/api"
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+// load returns the decrypted values of the given secrets of a project.
+func load(ctx context.Context, s *state.State, projectName string, names []string) (map[string]string, error) {
+	values := make(map[string]string, len(names))
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, name := range names {
+			secret, err := dbCluster.GetSecret(ctx, tx.Tx(), projectName, name)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					return api.StatusErrorf(http.StatusNotFound, "Secret %q not found in project %q", name, projectName)
+				}
+
+				return err
+			}
+
+			values[name], err = Decrypt(s.Endpoints.NetworkCert(), projectName, name, secret.Value)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// Resolve returns the value of an instance configuration key with the references to secrets resolved.
+// The value is returned unchanged for keys where references aren't resolved.
+func Resolve(ctx context.Context, s *state.State, projectName string, key string, value string) (string, error) {
+	if !IsResolvable(key) {
+		return value, nil
+	}
+
+	names := References(value)
+	if len(names) == 0 {
+		return value, nil
+	}
+
+	values, err := load(ctx, s, projectName, names)
+	if err != nil {
+		return "", err
+	}
+
+	return replace(value, values)
+}
+
+// ResolveConfig returns the instance configuration with the references to secrets resolved.
+// The configuration is returned as is when it doesn't reference any secret.
+func ResolveConfig(ctx context.Context, s *state.State, projectName string, config map[string]string) (map[string]string, error) {
+	names := []string{}
+	for k, v := range config {
+		if !IsResolvable(k) {
+			continue
+		}
+
+		for _, name := range References(v) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return config, nil
+	}
+
+	values, err := load(ctx, s, projectName, names)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]string, len(config))
+	for k, v := range config {
+		if IsResolvable(k) {
+			v, err = replace(v, values)
+			if err != nil {
+				return nil, fmt.Errorf("Failed resolving %q: %w", k, err)
+			}
+		}
+
+		resolved[k] = v
+	}
+
+	return resolved, nil
+}
+
+// UsedBy returns the URLs of the instances and profiles of a project referencing each secret.
+func UsedBy(ctx context.Context, tx *sql.Tx, projectName string) (map[string][]string, error) {
+	usedBy := map[string][]string{}
+
+	record := func(config map[string]string, u *api.URL) {
+		for k, v := range config {
+			if !IsResolvable(k) {
+				continue
+			}
+
+			for _, name := range References(v) {
+				if !slices.Contains(usedBy[name], u.String()) {
+					usedBy[name] = append(usedBy[name], u.String())
+				}
+			}
+		}
+	}
+
+	instances, err := dbCluster.GetInstances(ctx, tx, dbCluster.InstanceFilter{Project: &projectName})
+	if err != nil {
+		return nil, err
+	}
+
+	instanceConfigs, err := dbCluster.GetAllInstanceConfigs(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, inst := range instances {
+		record(instanceConfigs[inst.ID], api.NewURL().Path(version.APIVersion, "instances", inst.Name).Project(projectName))
+	}
+
+	profiles, err := dbCluster.GetProfiles(ctx, tx, dbCluster.ProfileFilter{Project: &projectName})
+	if err != nil {
+		return nil, err
+	}
+
+	profileConfigs, err := dbCluster.GetAllProfileConfigs(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range profiles {
+		record(profileConfigs[profile.ID], api.NewURL().Path(version.APIVersion, "profiles", profile.Name).Project(projectName))
+	}
+
+	for name := range usedBy {
+		sort.Strings(usedBy[name])
+	}
+
+	return usedBy, nil
+}
+
+// Reencrypt re-encrypts all the secrets with the key derived from a new cluster certificate.
+func Reencrypt(ctx context.Context, tx *sql.Tx, oldCert *localtls.CertInfo, newCert *localtls.CertInfo) error {
+	dbSecrets, err := dbCluster.GetSecrets(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range dbSecrets {
+		value, err := Decrypt(oldCert, secret.Project, secret.Name, secret.Value)
+		if err != nil {
+			return err
+		}
+
+		secret.Value, err = Encrypt(newCert, secret.Project, secret.Name, value)
+		if err != nil {
+			return err
+		}
+
+		err = dbCluster.UpdateSecret(ctx, tx, secret.Project, secret.Name, secret)
+		if err != nil {
+			return fmt.Errorf("Failed updating secret %q in project %q: %w", secret.Name, secret.Project, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// referenceRegexp matches references to secrets in configuration values.
+var referenceRegexp = regexp.MustCompile(`\$\{secret:([^}]*)\}`)
+
+// nameRegexp matches valid secret names.
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// IsResolvable returns whether references to secrets are resolved in the given instance configuration key.
+func IsResolvable(key string) bool {
+	return util.StringHasPrefix(key, "cloud-init.", "environment.", "user.")
+}
+
+// ValidName checks that the name can be used for a secret and referenced from configuration values.
+func ValidName(name string) error {
+	if name == "" {
+		return errors.New("No name provided")
+	}
+
+	if !nameRegexp.MatchString(name) {
+		return errors.New("Secret names may only contain alphanumeric characters, dots, dashes and underscores and must start with an alphanumeric character")
+	}
+
+	return nil
+}
+
+// References returns the names of the secrets referenced by the value.
+func References(value string) []string {
+	if !strings.Contains(value, "${secret:") {
+		return nil
+	}
+
+	names := []string{}
+	for _, match := range referenceRegexp.FindAllStringSubmatch(value, -1) {
+		if !slices.Contains(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// replace substitutes the references to secrets in the value with the given secret values.
+func replace(value string, values map[string]string) (string, error) {
+	var err error
+
+	result := referenceRegexp.ReplaceAllStringFunc(value, func(ref string) string {
+		name := referenceRegexp.FindStringSubmatch(ref)[1]
+
+		secret, ok := values[name]
+		if !ok && err == nil {
+			err = fmt.Errorf("Secret %q not found", name)
+		}
+
+		return secret
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,45 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	localtls "github.com/lxc/incus/v6/shared/tls"
+)
+
+func TestEncrypt(t *testing.T) {
+	cert := localtls.TestingKeyPair()
+
+	encrypted, err := Encrypt(cert, "default", "db-password", "s3cr3t")
+	require.NoError(t, err)
+	assert.NotContains(t, encrypted, "s3cr3t")
+
+	value, err := Decrypt(cert, "default", "db-password", encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", value)
+
+	// The value is bound to its project and name.
+	_, err = Decrypt(cert, "other", "db-password", encrypted)
+	assert.Error(t, err)
+
+	_, err = Decrypt(cert, "default", "api-token", encrypted)
+	assert.Error(t, err)
+
+	// A different certificate can't decrypt it.
+	_, err = Decrypt(localtls.TestingAltKeyPair(), "default", "db-password", encrypted)
+	assert.Error(t, err)
+}
+
+func TestReferences(t *testing.T) {
+	assert.Nil(t, References("password: s3cr3t"))
+	assert.Equal(t, []string{"db-password", "api-token"}, References("${secret:db-password} ${secret:api-token} ${secret:db-password}"))
+
+	value, err := replace("postgres://app:${secret:db-password}@db/app", map[string]string{"db-password": "s3cr3t"})
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://app:s3cr3t@db/app", value)
+
+	_, err = replace("${secret:missing}", map[string]string{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,367 @@
+package stack
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
+)
+
+// notFound converts a not found error into a false existence result.
+func notFound(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	if api.StatusErrorCheck(err, http.StatusNotFound) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// MergeMap applies the changes between the previous and new definition of a map on top of its current value.
+// Keys not coming from the stack definition, like those generated by the server, are kept.
+func MergeMap[T any](current map[string]T, oldMap map[string]T, newMap map[string]T) map[string]T {
+	result := make(map[string]T, len(current))
+	maps.Copy(result, current)
+
+	for k := range oldMap {
+		_, ok := newMap[k]
+		if !ok {
+			delete(result, k)
+		}
+	}
+
+	maps.Copy(result, newMap)
+
+	return result
+}
+
+// resourceExists checks whether the resource currently exists.
+func resourceExists(client incus.InstanceServer, e entry) (bool, error) {
+	var err error
+
+	switch spec := e.spec.(type) {
+	case api.ProfilesPost:
+		_, _, err = client.GetProfile(spec.Name)
+	case api.NetworkACLsPost:
+		_, _, err = client.GetNetworkACL(spec.Name)
+	case api.NetworksPost:
+		_, _, err = client.GetNetwork(spec.Name)
+	case api.StackNetworkForward:
+		_, _, err = client.GetNetworkForward(spec.Network, spec.ListenAddress)
+	case api.StackStorageVolume:
+		_, _, err = client.GetStoragePoolVolume(spec.Pool, "custom", spec.Name)
+	case api.InstancesPost:
+		_, _, err = client.GetInstance(spec.Name)
+	default:
+		return false, fmt.Errorf("Unsupported resource type %q", e.kind)
+	}
+
+	return notFound(err)
+}
+
+// resourceCreate creates the resource and returns a function removing it again.
+func resourceCreate(client incus.InstanceServer, e entry) (revert.Hook, error) {
+	switch spec := e.spec.(type) {
+	case api.ProfilesPost:
+		err := client.CreateProfile(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.DeleteProfile(spec.Name) }, nil
+
+	case api.NetworkACLsPost:
+		err := client.CreateNetworkACL(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.DeleteNetworkACL(spec.Name) }, nil
+
+	case api.NetworksPost:
+		err := client.CreateNetwork(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.DeleteNetwork(spec.Name) }, nil
+
+	case api.StackNetworkForward:
+		err := client.CreateNetworkForward(spec.Network, spec.NetworkForwardsPost)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.DeleteNetworkForward(spec.Network, spec.ListenAddress) }, nil
+
+	case api.StackStorageVolume:
+		spec.Type = "custom"
+
+		err := client.CreateStoragePoolVolume(spec.Pool, spec.StorageVolumesPost)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.DeleteStoragePoolVolume(spec.Pool, "custom", spec.Name) }, nil
+
+	case api.InstancesPost:
+		op, err := client.CreateInstance(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = DeleteInstance(client, spec.Name) }, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported resource type %q", e.kind)
+}
+
+// resourceUpdate applies the changes between the previous and new definition of the resource and returns a
+// function restoring its previous configuration.
+func resourceUpdate(client incus.InstanceServer, oldEntry entry, e entry) (revert.Hook, error) {
+	switch spec := e.spec.(type) {
+	case api.ProfilesPost:
+		oldSpec, _ := oldEntry.spec.(api.ProfilesPost)
+
+		current, etag, err := client.GetProfile(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		put := current.Writable()
+		put.Description = spec.Description
+		put.Config = MergeMap(current.Config, oldSpec.Config, spec.Config)
+		put.Devices = MergeMap(current.Devices, oldSpec.Devices, spec.Devices)
+
+		err = client.UpdateProfile(spec.Name, put, etag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.UpdateProfile(spec.Name, current.Writable(), "") }, nil
+
+	case api.NetworkACLsPost:
+		oldSpec, _ := oldEntry.spec.(api.NetworkACLsPost)
+
+		current, etag, err := client.GetNetworkACL(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		put := current.Writable()
+		put.Description = spec.Description
+		put.Config = MergeMap(current.Config, oldSpec.Config, spec.Config)
+		put.Ingress = spec.Ingress
+		put.Egress = spec.Egress
+
+		err = client.UpdateNetworkACL(spec.Name, put, etag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.UpdateNetworkACL(spec.Name, current.Writable(), "") }, nil
+
+	case api.NetworksPost:
+		oldSpec, _ := oldEntry.spec.(api.NetworksPost)
+
+		current, etag, err := client.GetNetwork(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		put := current.Writable()
+		put.Description = spec.Description
+		put.Config = MergeMap(current.Config, oldSpec.Config, spec.Config)
+
+		err = client.UpdateNetwork(spec.Name, put, etag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.UpdateNetwork(spec.Name, current.Writable(), "") }, nil
+
+	case api.StackNetworkForward:
+		oldSpec, _ := oldEntry.spec.(api.StackNetworkForward)
+
+		current, etag, err := client.GetNetworkForward(spec.Network, spec.ListenAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		put := current.Writable()
+		put.Description = spec.Description
+		put.Config = MergeMap(current.Config, oldSpec.Config, spec.Config)
+		put.Ports = spec.Ports
+
+		err = client.UpdateNetworkForward(spec.Network, spec.ListenAddress, put, etag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.UpdateNetworkForward(spec.Network, spec.ListenAddress, current.Writable(), "") }, nil
+
+	case api.StackStorageVolume:
+		oldSpec, _ := oldEntry.spec.(api.StackStorageVolume)
+
+		current, etag, err := client.GetStoragePoolVolume(spec.Pool, "custom", spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		put := current.Writable()
+		put.Description = spec.Description
+		put.Config = MergeMap(current.Config, oldSpec.Config, spec.Config)
+
+		err = client.UpdateStoragePoolVolume(spec.Pool, "custom", spec.Name, put, etag)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() { _ = client.UpdateStoragePoolVolume(spec.Pool, "custom", spec.Name, current.Writable(), "") }, nil
+
+	case api.InstancesPost:
+		oldSpec, _ := oldEntry.spec.(api.InstancesPost)
+
+		current, etag, err := client.GetInstance(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		put := current.Writable()
+		put.Description = spec.Description
+		put.Profiles = spec.Profiles
+		put.Config = MergeMap(current.Config, oldSpec.Config, spec.Config)
+		put.Devices = MergeMap(current.Devices, oldSpec.Devices, spec.Devices)
+
+		op, err := client.UpdateInstance(spec.Name, put, etag)
+		if err != nil {
+			return nil, err
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return nil, err
+		}
+
+		return func() {
+			op, err := client.UpdateInstance(spec.Name, current.Writable(), "")
+			if err == nil {
+				_ = op.Wait()
+			}
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported resource type %q", e.kind)
+}
+
+// resourceDelete removes the resource. For resources which can be restored, a function re-creating it with its
+// previous configuration is returned.
+func resourceDelete(client incus.InstanceServer, e entry) (revert.Hook, error) {
+	switch spec := e.spec.(type) {
+	case api.ProfilesPost:
+		current, _, err := client.GetProfile(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = client.DeleteProfile(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() {
+			_ = client.CreateProfile(api.ProfilesPost{Name: current.Name, ProfilePut: current.Writable()})
+		}, nil
+
+	case api.NetworkACLsPost:
+		current, _, err := client.GetNetworkACL(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = client.DeleteNetworkACL(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() {
+			_ = client.CreateNetworkACL(api.NetworkACLsPost{NetworkACLPost: api.NetworkACLPost{Name: current.Name}, NetworkACLPut: current.Writable()})
+		}, nil
+
+	case api.NetworksPost:
+		current, _, err := client.GetNetwork(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = client.DeleteNetwork(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() {
+			_ = client.CreateNetwork(api.NetworksPost{Name: current.Name, Type: current.Type, NetworkPut: current.Writable()})
+		}, nil
+
+	case api.StackNetworkForward:
+		current, _, err := client.GetNetworkForward(spec.Network, spec.ListenAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		err = client.DeleteNetworkForward(spec.Network, spec.ListenAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() {
+			_ = client.CreateNetworkForward(spec.Network, api.NetworkForwardsPost{ListenAddress: current.ListenAddress, NetworkForwardPut: current.Writable()})
+		}, nil
+
+	case api.StackStorageVolume:
+		return nil, client.DeleteStoragePoolVolume(spec.Pool, "custom", spec.Name)
+
+	case api.InstancesPost:
+		return nil, DeleteInstance(client, spec.Name)
+	}
+
+	return nil, fmt.Errorf("Unsupported resource type %q", e.kind)
+}
+
+// DeleteInstance stops and deletes an instance.
+func DeleteInstance(client incus.InstanceServer, name string) error {
+	current, _, err := client.GetInstance(name)
+	if err != nil {
+		return err
+	}
+
+	if current.StatusCode != api.Stopped {
+		op, err := client.UpdateInstanceState(name, api.InstanceStatePut{Action: "stop", Timeout: -1, Force: true}, "")
+		if err != nil {
+			return err
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return err
+		}
+	}
+
+	op, err := client.DeleteInstance(name)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
@@ -0,0 +1,426 @@
+package stack
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/shared/api"
+	"github.com/lxc/incus/v6/shared/revert"
+)
+
+// Resource types managed by a stack.
+const (
+	TypeProfile        = "profile"
+	TypeNetworkACL     = "network-acl"
+	TypeNetwork        = "network"
+	TypeNetworkForward = "network-forward"
+	TypeStorageVolume  = "storage-volume"
+	TypeInstance       = "instance"
+
+	// TypeNetworkLoadBalancer is only used to report changes made by instance sets.
+	TypeNetworkLoadBalancer = "network-load-balancer"
+)
+
+// Actions taken on the resources of a stack.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// entry is a single resource of a stack definition.
+type entry struct {
+	kind string
+	name string
+	spec any
+}
+
+// key returns the unique identifier of the resource within a stack.
+func (e entry) key() string {
+	return e.kind + "/" + e.name
+}
+
+// entries returns the resources of a stack definition in the order they need to be created.
+func entries(spec api.StackPut) []entry {
+	result := []entry{}
+
+	for _, profile := range spec.Profiles {
+		result = append(result, entry{kind: TypeProfile, name: profile.Name, spec: profile})
+	}
+
+	for _, acl := range spec.NetworkACLs {
+		result = append(result, entry{kind: TypeNetworkACL, name: acl.Name, spec: acl})
+	}
+
+	for _, network := range spec.Networks {
+		result = append(result, entry{kind: TypeNetwork, name: network.Name, spec: network})
+	}
+
+	for _, forward := range spec.NetworkForwards {
+		result = append(result, entry{kind: TypeNetworkForward, name: forward.Network + "/" + forward.ListenAddress, spec: forward})
+	}
+
+	for _, volume := range spec.StorageVolumes {
+		result = append(result, entry{kind: TypeStorageVolume, name: volume.Pool + "/" + volume.Name, spec: volume})
+	}
+
+	for _, inst := range spec.Instances {
+		result = append(result, entry{kind: TypeInstance, name: inst.Name, spec: inst})
+	}
+
+	return result
+}
+
+// Validate checks that a stack definition is consistent.
+func Validate(spec api.StackPut) error {
+	seen := map[string]bool{}
+
+	for _, e := range entries(spec) {
+		switch e.kind {
+		case TypeNetworkForward:
+			forward, _ := e.spec.(api.StackNetworkForward)
+			if forward.Network == "" || forward.ListenAddress == "" {
+				return fmt.Errorf("Network forwards require a network and a listen address")
+			}
+
+		case TypeStorageVolume:
+			volume, _ := e.spec.(api.StackStorageVolume)
+			if volume.Pool == "" || volume.Name == "" {
+				return fmt.Errorf("Storage volumes require a pool and a name")
+			}
+
+			if volume.Type != "" && volume.Type != "custom" {
+				return fmt.Errorf("Only custom storage volumes can be part of a stack")
+			}
+
+		default:
+			if e.name == "" {
+				return fmt.Errorf("A %s is missing its name", e.kind)
+			}
+		}
+
+		if seen[e.key()] {
+			return fmt.Errorf("The %s %q is defined multiple times", e.kind, e.name)
+		}
+
+		seen[e.key()] = true
+	}
+
+	return nil
+}
+
+// diffMap returns the prefixed keys which differ between two maps.
+func diffMap[T any](prefix string, oldMap map[string]T, newMap map[string]T) []string {
+	fields := []string{}
+
+	for k, v := range newMap {
+		oldValue, ok := oldMap[k]
+		if !ok || !reflect.DeepEqual(oldValue, v) {
+			fields = append(fields, prefix+"."+k)
+		}
+	}
+
+	for k := range oldMap {
+		_, ok := newMap[k]
+		if !ok {
+			fields = append(fields, prefix+"."+k)
+		}
+	}
+
+	return fields
+}
+
+// diff returns the fields modified between the previous and the new definition of a resource.
+// An error is returned if a field which can't be changed on an existing resource was modified.
+func diff(oldEntry entry, newEntry entry) ([]string, error) {
+	fields := []string{}
+	immutable := []string{}
+
+	switch newEntry.kind {
+	case TypeProfile:
+		oldSpec, _ := oldEntry.spec.(api.ProfilesPost)
+		newSpec, _ := newEntry.spec.(api.ProfilesPost)
+
+		if oldSpec.Description != newSpec.Description {
+			fields = append(fields, "description")
+		}
+
+		fields = append(fields, diffMap("config", oldSpec.Config, newSpec.Config)...)
+		fields = append(fields, diffMap("devices", oldSpec.Devices, newSpec.Devices)...)
+
+	case TypeNetworkACL:
+		oldSpec, _ := oldEntry.spec.(api.NetworkACLsPost)
+		newSpec, _ := newEntry.spec.(api.NetworkACLsPost)
+
+		if oldSpec.Description != newSpec.Description {
+			fields = append(fields, "description")
+		}
+
+		fields = append(fields, diffMap("config", oldSpec.Config, newSpec.Config)...)
+
+		if !reflect.DeepEqual(oldSpec.Ingress, newSpec.Ingress) {
+			fields = append(fields, "ingress")
+		}
+
+		if !reflect.DeepEqual(oldSpec.Egress, newSpec.Egress) {
+			fields = append(fields, "egress")
+		}
+
+	case TypeNetwork:
+		oldSpec, _ := oldEntry.spec.(api.NetworksPost)
+		newSpec, _ := newEntry.spec.(api.NetworksPost)
+
+		if oldSpec.Type != newSpec.Type {
+			immutable = append(immutable, "type")
+		}
+
+		if oldSpec.Description != newSpec.Description {
+			fields = append(fields, "description")
+		}
+
+		fields = append(fields, diffMap("config", oldSpec.Config, newSpec.Config)...)
+
+	case TypeNetworkForward:
+		oldSpec, _ := oldEntry.spec.(api.StackNetworkForward)
+		newSpec, _ := newEntry.spec.(api.StackNetworkForward)
+
+		if oldSpec.Description != newSpec.Description {
+			fields = append(fields, "description")
+		}
+
+		fields = append(fields, diffMap("config", oldSpec.Config, newSpec.Config)...)
+
+		if !reflect.DeepEqual(oldSpec.Ports, newSpec.Ports) {
+			fields = append(fields, "ports")
+		}
+
+	case TypeStorageVolume:
+		oldSpec, _ := oldEntry.spec.(api.StackStorageVolume)
+		newSpec, _ := newEntry.spec.(api.StackStorageVolume)
+
+		if oldSpec.ContentType != newSpec.ContentType {
+			immutable = append(immutable, "content_type")
+		}
+
+		if !reflect.DeepEqual(oldSpec.Source, newSpec.Source) {
+			immutable = append(immutable, "source")
+		}
+
+		if oldSpec.Description != newSpec.Description {
+			fields = append(fields, "description")
+		}
+
+		fields = append(fields, diffMap("config", oldSpec.Config, newSpec.Config)...)
+
+	case TypeInstance:
+		oldSpec, _ := oldEntry.spec.(api.InstancesPost)
+		newSpec, _ := newEntry.spec.(api.InstancesPost)
+
+		if oldSpec.Type != newSpec.Type {
+			immutable = append(immutable, "type")
+		}
+
+		if !reflect.DeepEqual(oldSpec.Source, newSpec.Source) {
+			immutable = append(immutable, "source")
+		}
+
+		if oldSpec.Description != newSpec.Description {
+			fields = append(fields, "description")
+		}
+
+		if !reflect.DeepEqual(oldSpec.Profiles, newSpec.Profiles) {
+			fields = append(fields, "profiles")
+		}
+
+		fields = append(fields, diffMap("config", oldSpec.Config, newSpec.Config)...)
+		fields = append(fields, diffMap("devices", oldSpec.Devices, newSpec.Devices)...)
+	}
+
+	if len(immutable) > 0 {
+		return nil, fmt.Errorf("Changing the %s of the %s %q requires removing it from the stack first", immutable[0], newEntry.kind, newEntry.name)
+	}
+
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+// Plan computes the changes needed to go from the current definition of a stack to the desired one.
+// The current definition is nil for a stack which doesn't exist yet.
+func Plan(client incus.InstanceServer, current *api.StackPut, desired api.StackPut) (*api.StackPlan, error) {
+	changes, err := plan(client, current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &api.StackPlan{Changes: []api.StackChange{}}
+	for _, c := range changes {
+		result.Changes = append(result.Changes, c.toAPI())
+	}
+
+	return result, nil
+}
+
+// change is a planned modification of a resource.
+type change struct {
+	action   string
+	entry    entry
+	oldEntry entry
+	fields   []string
+}
+
+// toAPI returns the API representation of the change.
+func (c change) toAPI() api.StackChange {
+	return api.StackChange{
+		Action: c.action,
+		Type:   c.entry.kind,
+		Name:   c.entry.name,
+		Fields: c.fields,
+	}
+}
+
+// plan returns the ordered list of changes needed to apply the desired definition.
+func plan(client incus.InstanceServer, current *api.StackPut, desired api.StackPut) ([]change, error) {
+	err := Validate(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	managed := map[string]entry{}
+	var oldEntries []entry
+	if current != nil {
+		oldEntries = entries(*current)
+		for _, e := range oldEntries {
+			managed[e.key()] = e
+		}
+	}
+
+	changes := []change{}
+	wanted := map[string]bool{}
+
+	for _, e := range entries(desired) {
+		wanted[e.key()] = true
+
+		exists, err := resourceExists(client, e)
+		if err != nil {
+			return nil, err
+		}
+
+		oldEntry, ok := managed[e.key()]
+		if !ok {
+			if exists {
+				return nil, api.StatusErrorf(http.StatusConflict, "The %s %q already exists and isn't managed by the stack", e.kind, e.name)
+			}
+
+			changes = append(changes, change{action: ActionCreate, entry: e})
+			continue
+		}
+
+		if !exists {
+			// The resource was removed outside of the stack, create it again.
+			changes = append(changes, change{action: ActionCreate, entry: e})
+			continue
+		}
+
+		fields, err := diff(oldEntry, e)
+		if err != nil {
+			return nil, api.StatusErrorf(http.StatusBadRequest, "%v", err)
+		}
+
+		if len(fields) > 0 {
+			changes = append(changes, change{action: ActionUpdate, entry: e, oldEntry: oldEntry, fields: fields})
+		}
+	}
+
+	// Remove the resources which are no longer part of the stack, in reverse order. This comes after all other
+	// changes as deleted instances and storage volumes can't be restored if a later change fails.
+	for i := len(oldEntries) - 1; i >= 0; i-- {
+		e := oldEntries[i]
+		if wanted[e.key()] {
+			continue
+		}
+
+		exists, err := resourceExists(client, e)
+		if err != nil {
+			return nil, err
+		}
+
+		if exists {
+			changes = append(changes, change{action: ActionDelete, entry: e})
+		}
+	}
+
+	return changes, nil
+}
+
+// CheckFunc is called with every planned change before any of them is applied. Returning an error aborts the
+// apply without making any change.
+type CheckFunc func(change api.StackChange) error
+
+// Apply brings the resources of a stack to the desired definition. Every planned change is first passed to check,
+// when set.
+// On failure, all changes made so far are reverted. On success, a function which reverts the changes is returned
+// for use if a later step fails.
+//
+// Removals are applied last, once all creations and updates succeeded. Deleted instances and storage volumes
+// can't be restored though, so a failure while removing resources may leave some of them deleted.
+func Apply(client incus.InstanceServer, current *api.StackPut, desired api.StackPut, check CheckFunc) (revert.Hook, error) {
+	changes, err := plan(client, current, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range changes {
+		if check == nil {
+			break
+		}
+
+		err := check(c.toAPI())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return applyChanges(client, changes)
+}
+
+// Delete removes all the resources of a stack, in reverse order of creation.
+func Delete(client incus.InstanceServer, current api.StackPut, check CheckFunc) (revert.Hook, error) {
+	return Apply(client, &current, api.StackPut{}, check)
+}
+
+// applyChanges applies the planned changes in order.
+func applyChanges(client incus.InstanceServer, changes []change) (revert.Hook, error) {
+	reverter := revert.New()
+	defer reverter.Fail()
+
+	for _, c := range changes {
+		var undo revert.Hook
+		var err error
+
+		switch c.action {
+		case ActionCreate:
+			undo, err = resourceCreate(client, c.entry)
+		case ActionUpdate:
+			undo, err = resourceUpdate(client, c.oldEntry, c.entry)
+		case ActionDelete:
+			undo, err = resourceDelete(client, c.entry)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to %s %s %q: %w", c.action, c.entry.kind, c.entry.name, err)
+		}
+
+		if undo != nil {
+			reverter.Add(undo)
+		}
+	}
+
+	cleanup := reverter.Clone().Fail
+	reverter.Success()
+
+	return cleanup, nil
+}
@@ -0,0 +1,193 @@
+package stack
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	incus "github.com/lxc/incus/v6/client"
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// fakeServer implements the profile and storage volume functions of a server used by stacks.
+type fakeServer struct {
+	incus.InstanceServer
+
+	profiles map[string]*api.Profile
+	volumes  map[string]bool
+	calls    []string
+}
+
+func (f *fakeServer) GetProfile(name string) (*api.Profile, string, error) {
+	profile, ok := f.profiles[name]
+	if !ok {
+		return nil, "", api.StatusErrorf(http.StatusNotFound, "Profile not found")
+	}
+
+	return profile, "", nil
+}
+
+func (f *fakeServer) CreateProfile(profile api.ProfilesPost) error {
+	f.calls = append(f.calls, "create profile/"+profile.Name)
+	f.profiles[profile.Name] = &api.Profile{Name: profile.Name, ProfilePut: profile.ProfilePut}
+
+	return nil
+}
+
+func (f *fakeServer) UpdateProfile(name string, profile api.ProfilePut, ETag string) error {
+	f.calls = append(f.calls, "update profile/"+name)
+	f.profiles[name].ProfilePut = profile
+
+	return nil
+}
+
+func (f *fakeServer) DeleteProfile(name string) error {
+	f.calls = append(f.calls, "delete profile/"+name)
+	delete(f.profiles, name)
+
+	return nil
+}
+
+func (f *fakeServer) GetStoragePoolVolume(pool string, volType string, name string) (*api.StorageVolume, string, error) {
+	if !f.volumes[pool+"/"+name] {
+		return nil, "", api.StatusErrorf(http.StatusNotFound, "Storage volume not found")
+	}
+
+	return &api.StorageVolume{Name: name, Type: volType}, "", nil
+}
+
+func (f *fakeServer) DeleteStoragePoolVolume(pool string, volType string, name string) error {
+	f.calls = append(f.calls, "delete storage-volume/"+pool+"/"+name)
+	delete(f.volumes, pool+"/"+name)
+
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	spec := api.StackPut{
+		Profiles:  []api.ProfilesPost{{Name: "web"}},
+		Instances: []api.InstancesPost{{Name: "web"}},
+	}
+
+	// The same name can be used for different resource types.
+	require.NoError(t, Validate(spec))
+
+	spec.Instances = append(spec.Instances, api.InstancesPost{Name: "web"})
+	assert.Error(t, Validate(spec))
+
+	spec = api.StackPut{StorageVolumes: []api.StackStorageVolume{{Pool: "default", StorageVolumesPost: api.StorageVolumesPost{Name: "data", Type: "container"}}}}
+	assert.Error(t, Validate(spec))
+
+	spec = api.StackPut{NetworkForwards: []api.StackNetworkForward{{NetworkForwardsPost: api.NetworkForwardsPost{ListenAddress: "192.0.2.1"}}}}
+	assert.Error(t, Validate(spec))
+}
+
+func TestEntriesOrder(t *testing.T) {
+	spec := api.StackPut{
+		Instances:       []api.InstancesPost{{Name: "web"}},
+		StorageVolumes:  []api.StackStorageVolume{{Pool: "default", StorageVolumesPost: api.StorageVolumesPost{Name: "data"}}},
+		NetworkForwards: []api.StackNetworkForward{{Network: "br0", NetworkForwardsPost: api.NetworkForwardsPost{ListenAddress: "192.0.2.1"}}},
+		Networks:        []api.NetworksPost{{Name: "br0"}},
+		NetworkACLs:     []api.NetworkACLsPost{{NetworkACLPost: api.NetworkACLPost{Name: "web"}}},
+		Profiles:        []api.ProfilesPost{{Name: "web"}},
+	}
+
+	keys := []string{}
+	for _, e := range entries(spec) {
+		keys = append(keys, e.key())
+	}
+
+	assert.Equal(t, []string{"profile/web", "network-acl/web", "network/br0", "network-forward/br0/192.0.2.1", "storage-volume/default/data", "instance/web"}, keys)
+}
+
+func TestDiff(t *testing.T) {
+	oldInst := api.InstancesPost{
+		Name: "web",
+		Type: api.InstanceTypeContainer,
+		InstancePut: api.InstancePut{
+			Config:  map[string]string{"limits.cpu": "1", "user.foo": "bar"},
+			Devices: map[string]map[string]string{"eth0": {"type": "nic", "network": "br0"}},
+		},
+	}
+
+	newInst := oldInst
+	newInst.Config = map[string]string{"limits.cpu": "2"}
+	newInst.Devices = map[string]map[string]string{"eth0": {"type": "nic", "network": "br1"}}
+
+	fields, err := diff(entry{kind: TypeInstance, name: "web", spec: oldInst}, entry{kind: TypeInstance, name: "web", spec: newInst})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config.limits.cpu", "config.user.foo", "devices.eth0"}, fields)
+
+	// Unchanged definitions have no fields.
+	fields, err = diff(entry{kind: TypeInstance, name: "web", spec: oldInst}, entry{kind: TypeInstance, name: "web", spec: oldInst})
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+
+	// The instance type can't be changed.
+	newInst.Type = api.InstanceTypeVM
+	_, err = diff(entry{kind: TypeInstance, name: "web", spec: oldInst}, entry{kind: TypeInstance, name: "web", spec: newInst})
+	assert.Error(t, err)
+}
+
+func TestMergeMap(t *testing.T) {
+	current := map[string]string{"ipv4.address": "10.0.0.1/24", "ipv6.nat": "true", "dns.domain": "old"}
+	oldMap := map[string]string{"ipv6.nat": "true", "dns.domain": "old"}
+	newMap := map[string]string{"dns.domain": "new"}
+
+	// Keys set by the server are kept, keys removed from the definition are removed.
+	assert.Equal(t, map[string]string{"ipv4.address": "10.0.0.1/24", "dns.domain": "new"}, MergeMap(current, oldMap, newMap))
+}
+
+func TestApply(t *testing.T) {
+	current := api.StackPut{
+		Profiles: []api.ProfilesPost{
+			{Name: "web", ProfilePut: api.ProfilePut{Description: "Web"}},
+			{Name: "old"},
+		},
+		StorageVolumes: []api.StackStorageVolume{{Pool: "default", StorageVolumesPost: api.StorageVolumesPost{Name: "data"}}},
+	}
+
+	desired := api.StackPut{
+		Profiles: []api.ProfilesPost{
+			{Name: "web", ProfilePut: api.ProfilePut{Description: "Web servers"}},
+			{Name: "new"},
+		},
+	}
+
+	newServer := func() *fakeServer {
+		return &fakeServer{
+			profiles: map[string]*api.Profile{"web": {Name: "web", ProfilePut: api.ProfilePut{Description: "Web"}}, "old": {Name: "old"}},
+			volumes:  map[string]bool{"default/data": true},
+		}
+	}
+
+	// A rejected change aborts the apply before anything is changed.
+	client := newServer()
+	_, err := Apply(client, &current, desired, func(change api.StackChange) error {
+		if change.Action == ActionDelete && change.Type == TypeStorageVolume {
+			return errors.New("Not allowed")
+		}
+
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.Empty(t, client.calls)
+
+	// Removals are applied last, in reverse order of creation.
+	client = newServer()
+	checked := []string{}
+	_, err = Apply(client, &current, desired, func(change api.StackChange) error {
+		checked = append(checked, change.Action+" "+change.Type+"/"+change.Name)
+		return nil
+	})
+
+	require.NoError(t, err)
+
+	expected := []string{"update profile/web", "create profile/new", "delete storage-volume/default/data", "delete profile/old"}
+	assert.Equal(t, expected, checked)
+	assert.Equal(t, expected, client.calls)
+}
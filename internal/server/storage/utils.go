@@ -289,6 +289,14 @@ func VolumeDBCreate(pool Pool, projectName string, volumeName string, volumeDesc
 	err = p.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the database entry for the storage volume.
 		if snapshot {
+			// Enforce the project snapshot limit for custom volume snapshots that are copied, migrated or imported.
+			if volumeType == drivers.VolumeTypeCustom {
+				err = project.AllowSnapshotsImport(tx, projectName, 1)
+				if err != nil {
+					return err
+				}
+			}
+
 			_, err = tx.CreateStorageVolumeSnapshot(ctx, projectName, volumeName, volumeDescription, volDBType, pool.ID(), vol.Config(), creationDate, expiryDate)
 		} else {
 			_, err = tx.CreateStoragePoolVolume(ctx, projectName, volumeName, volumeDescription, volDBType, pool.ID(), vol.Config(), volDBContentType, creationDate)
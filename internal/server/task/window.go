@@ -0,0 +1,119 @@
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/lxc/incus/v6/shared/util"
+)
+
+// Window is a recurring time range, opening at each activation of a cron
+// schedule and staying open for a fixed duration.
+type Window struct {
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// Contains returns whether the window is open at the given time.
+func (w Window) Contains(t time.Time) bool {
+	return !w.schedule.Next(t.Add(-w.duration)).After(t)
+}
+
+// Windows is a set of windows, open whenever one of them is.
+type Windows []Window
+
+// ParseWindows parses a comma-and-space-separated list of windows, each made
+// of a cron expression (or alias) followed by a duration, for example
+// "0 22 * * 1-5 8h, @weekly 24h".
+func ParseWindows(value string) (Windows, error) {
+	windows := Windows{}
+
+	for _, entry := range util.SplitNTrimSpace(value, ", ", -1, true) {
+		fields := strings.Fields(entry)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("Window %q must be a cron expression followed by a duration", entry)
+		}
+
+		spec := strings.Join(fields[:len(fields)-1], " ")
+		schedule, err := cron.ParseStandard(spec)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid cron expression %q: %w", spec, err)
+		}
+
+		duration, err := time.ParseDuration(fields[len(fields)-1])
+		if err != nil {
+			return nil, fmt.Errorf("Invalid window duration %q: %w", fields[len(fields)-1], err)
+		}
+
+		if duration <= 0 {
+			return nil, fmt.Errorf("Window duration %q must be positive", fields[len(fields)-1])
+		}
+
+		windows = append(windows, Window{schedule: schedule, duration: duration})
+	}
+
+	return windows, nil
+}
+
+// Allow returns whether one of the windows is open at the given time, or if
+// there are no windows at all.
+func (ws Windows) Allow(t time.Time) bool {
+	if len(ws) == 0 {
+		return true
+	}
+
+	for _, w := range ws {
+		if w.Contains(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Next returns when the next window opens after the given time.
+//
+// It returns the zero time if there are no windows.
+func (ws Windows) Next(t time.Time) time.Time {
+	var next time.Time
+
+	for _, w := range ws {
+		opening := w.schedule.Next(t)
+		if next.IsZero() || opening.Before(next) {
+			next = opening
+		}
+	}
+
+	return next
+}
+
+// Windowed returns a Schedule that defers the executions of the given
+// schedule falling outside of the windows returned by the given function,
+// until one of them opens.
+//
+// The windows are evaluated again at least once per interval of the given
+// schedule, so that changes to them get picked up.
+func Windowed(schedule Schedule, windows func() Windows) Schedule {
+	return func() (time.Duration, error) {
+		interval, err := schedule()
+		if err != nil || interval <= 0 {
+			return interval, err
+		}
+
+		ws := windows()
+		now := time.Now()
+		if ws.Allow(now) {
+			return interval, nil
+		}
+
+		delay := ws.Next(now).Sub(now)
+		if delay > interval {
+			delay = interval
+		}
+
+		return delay, ErrSkip
+	}
+}
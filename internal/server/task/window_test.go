@@ -0,0 +1,73 @@
+package task_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lxc/incus/v6/internal/server/task"
+)
+
+// A window is open from each activation of its schedule and for its duration.
+func TestWindows_Allow(t *testing.T) {
+	windows, err := task.ParseWindows("0 22 * * * 4h, @weekly 1h")
+	require.NoError(t, err)
+
+	cases := map[string]bool{
+		"2024-01-02T21:59:00": false,
+		"2024-01-02T22:00:00": true,
+		"2024-01-03T01:59:00": true,
+		"2024-01-03T02:00:00": false,
+		"2024-01-07T00:30:00": true,
+		"2024-01-07T12:00:00": false,
+	}
+
+	for value, allowed := range cases {
+		now, err := time.ParseInLocation("2006-01-02T15:04:05", value, time.Local)
+		require.NoError(t, err)
+		assert.Equal(t, allowed, windows.Allow(now), value)
+	}
+
+	now, err := time.ParseInLocation("2006-01-02T15:04:05", "2024-01-03T12:00:00", time.Local)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Hour), windows.Next(now))
+}
+
+// Without windows, everything is allowed.
+func TestWindows_AllowEmpty(t *testing.T) {
+	windows, err := task.ParseWindows("")
+	require.NoError(t, err)
+	assert.True(t, windows.Allow(time.Now()))
+}
+
+// Invalid windows are rejected.
+func TestParseWindows_Invalid(t *testing.T) {
+	for _, value := range []string{"@daily", "0 22 * * * -1h", "0 22 * * 4h", "0 22 * * * forever"} {
+		_, err := task.ParseWindows(value)
+		assert.Error(t, err, value)
+	}
+}
+
+// Executions outside of the windows are skipped until the next window opens.
+func TestWindowed_Skip(t *testing.T) {
+	closed := func() task.Windows {
+		windows, _ := task.ParseWindows("@yearly 1m")
+		return windows
+	}
+
+	schedule := task.Windowed(task.Every(time.Hour), closed)
+	interval, err := schedule()
+	if closed().Allow(time.Now()) {
+		t.Skip("Running within the window")
+	}
+
+	assert.Equal(t, task.ErrSkip, err)
+	assert.True(t, interval > 0 && interval <= time.Hour)
+
+	schedule = task.Windowed(task.Every(time.Hour), func() task.Windows { return nil })
+	interval, err = schedule()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, interval)
+}
@@ -181,6 +181,11 @@ func CheckTrustState(cert x509.Certificate, trustedCerts map[string]x509.Certifi
 				}
 			}
 
+			// Check whether the certificate has been revoked through OCSP.
+			if isRevokedOCSP(&cert, ca) {
+				return false, ""
+			}
+
 			// Certificate not revoked, so trust it as is signed by CA cert.
 			return true, localtls.CertFingerprint(&cert)
 		}
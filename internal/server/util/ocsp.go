@@ -0,0 +1,166 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/lxc/incus/v6/shared/logger"
+)
+
+// ocspCacheEntry holds the known OCSP status of a certificate.
+type ocspCacheEntry struct {
+	revoked    bool
+	expiry     time.Time
+	refreshing bool
+}
+
+var (
+	ocspCache    = map[string]ocspCacheEntry{}
+	ocspInflight = map[string]chan struct{}{}
+	ocspCacheMu  sync.Mutex
+)
+
+// ocspTimeout is the time allowed to get an answer from the OCSP responders of a certificate.
+const ocspTimeout = 5 * time.Second
+
+// ocspClient is the HTTP client used to query the OCSP responders.
+var ocspClient = &http.Client{}
+
+// isRevokedOCSP returns whether the certificate has been revoked according to the OCSP responders it lists.
+//
+// Responses are cached until their next update. Once expired, the cached status keeps being used while it's
+// refreshed in the background, so only the first check of a certificate waits for its responders. Responders
+// which can't be reached are ignored and retried after a few minutes, so the certificate is only rejected when
+// a responder positively reports it as revoked.
+func isRevokedOCSP(cert *x509.Certificate, issuer *x509.Certificate) bool {
+	if len(cert.OCSPServer) == 0 {
+		return false
+	}
+
+	key := fmt.Sprintf("%x/%s", issuer.SubjectKeyId, cert.SerialNumber.String())
+
+	ocspCacheMu.Lock()
+	entry, found := ocspCache[key]
+	if found {
+		if time.Now().After(entry.expiry) && !entry.refreshing {
+			entry.refreshing = true
+			ocspCache[key] = entry
+
+			go ocspRefresh(key, cert, issuer)
+		}
+
+		ocspCacheMu.Unlock()
+
+		return entry.revoked
+	}
+
+	// Only query the responders once when the certificate is checked concurrently.
+	inflight, waiting := ocspInflight[key]
+	if !waiting {
+		inflight = make(chan struct{})
+		ocspInflight[key] = inflight
+	}
+
+	ocspCacheMu.Unlock()
+
+	if waiting {
+		<-inflight
+
+		ocspCacheMu.Lock()
+		entry = ocspCache[key]
+		ocspCacheMu.Unlock()
+
+		return entry.revoked
+	}
+
+	entry = ocspRefresh(key, cert, issuer)
+
+	ocspCacheMu.Lock()
+	delete(ocspInflight, key)
+	ocspCacheMu.Unlock()
+
+	close(inflight)
+
+	return entry.revoked
+}
+
+// ocspRefresh queries the OCSP responders of the certificate and records its status in the cache.
+// If none of them can be reached, the previously known status is kept.
+func ocspRefresh(key string, cert *x509.Certificate, issuer *x509.Certificate) ocspCacheEntry {
+	ctx, cancel := context.WithTimeout(context.Background(), ocspTimeout)
+	defer cancel()
+
+	ocspCacheMu.Lock()
+	entry := ocspCache[key]
+	ocspCacheMu.Unlock()
+
+	entry.refreshing = false
+	entry.expiry = time.Now().Add(5 * time.Minute)
+	for _, server := range cert.OCSPServer {
+		resp, err := ocspQuery(ctx, server, cert, issuer)
+		if err != nil {
+			logger.Warn("Failed querying OCSP responder", logger.Ctx{"server": server, "serial": cert.SerialNumber.String(), "err": err})
+			continue
+		}
+
+		entry.revoked = resp.Status == ocsp.Revoked
+		entry.expiry = resp.NextUpdate
+		if entry.expiry.IsZero() {
+			entry.expiry = time.Now().Add(time.Hour)
+		}
+
+		break
+	}
+
+	ocspCacheMu.Lock()
+	ocspCache[key] = entry
+	ocspCacheMu.Unlock()
+
+	return entry
+}
+
+// ocspQuery asks the OCSP responder for the status of the certificate.
+func ocspQuery(ctx context.Context, server string, cert *x509.Certificate, issuer *x509.Certificate) (*ocsp.Response, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating OCSP request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+
+	httpResp, err := ocspClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = httpResp.Body.Close() }()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Unexpected OCSP responder status %q", httpResp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(httpResp.Body, 1024*1024))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing OCSP response: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+// Certificates reported as revoked by their OCSP responder aren't trusted.
+func TestIsRevokedOCSP(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		SubjectKeyId:          []byte{1, 2, 3},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	revoked := map[int64]bool{2: true}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		status := ocsp.Good
+		if revoked[req.SerialNumber.Int64()] {
+			status = ocsp.Revoked
+		}
+
+		resp, err := ocsp.CreateResponse(ca, ca, ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now(),
+		}, crypto.Signer(caKey))
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	newCert := func(serial int64) *x509.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "client"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			OCSPServer:   []string{server.URL},
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+
+		return cert
+	}
+
+	assert.True(t, isRevokedOCSP(newCert(2), ca))
+	assert.False(t, isRevokedOCSP(newCert(3), ca))
+
+	// Unreachable responders don't reject the certificate.
+	unreachable := newCert(4)
+	unreachable.OCSPServer = []string{"http://127.0.0.1:1"}
+	assert.False(t, isRevokedOCSP(unreachable, ca))
+
+	// Expired responses are still used while being refreshed in the background.
+	cert := newCert(5)
+	assert.False(t, isRevokedOCSP(cert, ca))
+
+	key := fmt.Sprintf("%x/%s", ca.SubjectKeyId, cert.SerialNumber.String())
+	ocspCacheMu.Lock()
+	ocspCache[key] = ocspCacheEntry{expiry: time.Now().Add(-time.Minute)}
+	ocspCacheMu.Unlock()
+
+	revoked[5] = true
+	assert.False(t, isRevokedOCSP(cert, ca))
+	assert.Eventually(t, func() bool { return isRevokedOCSP(cert, ca) }, 5*time.Second, 10*time.Millisecond)
+
+	// A revoked certificate stays revoked if its responder can't be reached anymore.
+	cert.OCSPServer = []string{"http://127.0.0.1:1"}
+	ocspCacheMu.Lock()
+	ocspCache[key] = ocspCacheEntry{revoked: true, expiry: time.Now().Add(-time.Minute)}
+	ocspCacheMu.Unlock()
+
+	assert.True(t, isRevokedOCSP(cert, ca))
+	assert.Eventually(t, func() bool {
+		ocspCacheMu.Lock()
+		defer ocspCacheMu.Unlock()
+
+		return !ocspCache[key].refreshing
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, isRevokedOCSP(cert, ca))
+}
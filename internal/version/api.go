@@ -476,6 +476,30 @@ var APIExtensions = []string{
 	"disk_io_bus_usb",
 	"storage_driver_linstor",
 	"instance_oci_entrypoint",
+	"instance_idle_stop",
+	"instance_healthcheck",
+	"instance_boot_depends_on",
+	"stacks",
+	"instance_sets",
+	"cluster_rebalance_policy",
+	"cluster_rolling_maintenance",
+	"cluster_group_limits",
+	"cluster_healing_fencing",
+	"instance_replication",
+	"clustering_cordon",
+	"database_backup",
+	"clustering_replace",
+	"maintenance_windows",
+	"auth_rbac",
+	"auth_oidc_groups",
+	"audit_log",
+	"auth_tokens",
+	"certificate_expiry",
+	"projects_limits_io",
+	"projects_usage_accounting",
+	"project_templates",
+	"instances_admission_scriptlet",
+	"secrets",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
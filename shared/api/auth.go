@@ -6,4 +6,7 @@ const (
 
 	// AuthenticationMethodOIDC is a token based authentication method.
 	AuthenticationMethodOIDC = "oidc"
+
+	// AuthenticationMethodToken is the authentication method of API tokens.
+	AuthenticationMethodToken = "token"
 )
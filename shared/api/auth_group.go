@@ -0,0 +1,96 @@
+package api
+
+// AuthGroupsPost represents the fields of a new group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupsPost struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// Name of the group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthGroupPost represents the fields required to rename a group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupPost struct {
+	// New name of the group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthGroupPut represents the modifiable fields of a group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupPut struct {
+	// Description of the group
+	// Example: Instance operators
+	Description string `json:"description" yaml:"description"`
+
+	// Identities belonging to the group
+	Identities []AuthIdentity `json:"identities" yaml:"identities"`
+
+	// OpenID Connect groups whose users belong to the group
+	// Example: ["incus-admins"]
+	//
+	// API extension: auth_oidc_groups
+	IdentityProviderGroups []string `json:"identity_provider_groups" yaml:"identity_provider_groups"`
+
+	// Roles granted to the group
+	Roles []AuthGroupRole `json:"roles" yaml:"roles"`
+}
+
+// AuthGroup represents a group of identities which are granted roles.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroup struct {
+	AuthGroupPut `yaml:",inline"`
+
+	// Name of the group
+	// Example: operators
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full AuthGroup struct into a AuthGroupPut struct (filters read-only fields).
+func (g *AuthGroup) Writable() AuthGroupPut {
+	return g.AuthGroupPut
+}
+
+// AuthIdentity represents an authenticated user or client.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthIdentity struct {
+	// Authentication method (tls or oidc)
+	// Example: oidc
+	AuthenticationMethod string `json:"authentication_method" yaml:"authentication_method"`
+
+	// Identifier of the user or client (certificate fingerprint for tls, user name for oidc)
+	// Example: jane@example.com
+	Identifier string `json:"identifier" yaml:"identifier"`
+}
+
+// AuthGroupRole represents a role granted to a group.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthGroupRole struct {
+	// Name of the role
+	// Example: instance-operator
+	Role string `json:"role" yaml:"role"`
+
+	// Project the role is limited to (all projects and the server if empty)
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
@@ -0,0 +1,76 @@
+package api
+
+// AuthRolesPost represents the fields of a new role.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthRolesPost struct {
+	AuthRolePut `yaml:",inline"`
+
+	// Name of the role
+	// Example: instance-operator
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthRolePost represents the fields required to rename a role.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthRolePost struct {
+	// New name of the role
+	// Example: instance-operator
+	Name string `json:"name" yaml:"name"`
+}
+
+// AuthRolePut represents the modifiable fields of a role.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthRolePut struct {
+	// Description of the role
+	// Example: Operate instances
+	Description string `json:"description" yaml:"description"`
+
+	// Permissions granted by the role
+	Permissions []AuthPermission `json:"permissions" yaml:"permissions"`
+}
+
+// AuthRole represents a set of permissions which can be granted to groups.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthRole struct {
+	AuthRolePut `yaml:",inline"`
+
+	// Name of the role
+	// Example: instance-operator
+	Name string `json:"name" yaml:"name"`
+
+	// List of URLs of groups bound to the role
+	// Example: ["/1.0/auth/groups/operators"]
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+}
+
+// Writable converts a full AuthRole struct into a AuthRolePut struct (filters read-only fields).
+func (r *AuthRole) Writable() AuthRolePut {
+	return r.AuthRolePut
+}
+
+// AuthPermission represents an entitlement on all the entities of a type.
+//
+// swagger:model
+//
+// API extension: auth_rbac.
+type AuthPermission struct {
+	// Type of the entities (`*` for all types)
+	// Example: instance
+	EntityType string `json:"entity_type" yaml:"entity_type"`
+
+	// Entitlement on the entities
+	// Example: can_exec
+	Entitlement string `json:"entitlement" yaml:"entitlement"`
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"time"
+)
+
+// AuthTokensPost represents the fields of a new API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokensPost struct {
+	// Name of the token
+	// Example: ci-deploy
+	Name string `json:"name" yaml:"name"`
+
+	// Description of the token
+	// Example: Deployments from the CI
+	Description string `json:"description" yaml:"description"`
+
+	// Identity the requests made with the token are attributed to
+	// Example: ci
+	Identity string `json:"identity" yaml:"identity"`
+
+	// Whether the token is restricted to a list of projects
+	// Example: true
+	Restricted bool `json:"restricted" yaml:"restricted"`
+
+	// List of allowed projects (applies when restricted)
+	// Example: ["default", "foo"]
+	Projects []string `json:"projects" yaml:"projects"`
+
+	// Permissions the token is limited to (all when empty)
+	Permissions []AuthPermission `json:"permissions" yaml:"permissions"`
+
+	// When the token expires
+	// Example: 2026-03-23T20:00:00-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
+
+// AuthTokenSecret represents the secret of a newly created API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthTokenSecret struct {
+	// Name of the token
+	// Example: ci-deploy
+	Name string `json:"name" yaml:"name"`
+
+	// Secret to pass as a bearer token (only returned on creation)
+	// Example: incus_5f1a9e2e0c5e4e8f8a4e1f0b6f2a3c7d9e8b1a0c4d6f2e3b5a7c9d1e0f2a4b6c
+	Secret string `json:"secret" yaml:"secret"`
+}
+
+// AuthToken represents an API token.
+//
+// swagger:model
+//
+// API extension: auth_tokens.
+type AuthToken struct {
+	// Name of the token
+	// Example: ci-deploy
+	Name string `json:"name" yaml:"name"`
+
+	// Description of the token
+	// Example: Deployments from the CI
+	Description string `json:"description" yaml:"description"`
+
+	// Identity the requests made with the token are attributed to
+	// Example: ci
+	Identity string `json:"identity" yaml:"identity"`
+
+	// Whether the token is restricted to a list of projects
+	// Example: true
+	Restricted bool `json:"restricted" yaml:"restricted"`
+
+	// List of allowed projects (applies when restricted)
+	// Example: ["default", "foo"]
+	Projects []string `json:"projects" yaml:"projects"`
+
+	// Permissions the token is limited to (all when empty)
+	Permissions []AuthPermission `json:"permissions" yaml:"permissions"`
+
+	// When the token was created
+	// Example: 2025-03-23T20:00:00-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// When the token expires
+	// Example: 2026-03-23T20:00:00-04:00
+	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+}
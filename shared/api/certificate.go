@@ -72,6 +72,12 @@ type CertificatePut struct {
 	//
 	// API extension: certificate_description
 	Description string `json:"description" yaml:"description"`
+
+	// When the trust relationship expires (never when unset)
+	// Example: 2026-03-23T20:00:00-04:00
+	//
+	// API extension: certificate_expiry
+	ExpiresAt *time.Time `json:"expires_at" yaml:"expires_at"`
 }
 
 // Certificate represents a certificate
@@ -97,6 +97,12 @@ type ClusterMembersPost struct {
 	// The name of the new cluster member
 	// Example: server02
 	ServerName string `json:"server_name" yaml:"server_name"`
+
+	// Whether the new server replaces the existing (offline) cluster member of that name
+	// Example: false
+	//
+	// API extension: clustering_replace
+	Replace bool `json:"replace" yaml:"replace"`
 }
 
 // ClusterMemberJoinToken represents the fields contained within an encoded cluster member join token.
@@ -124,6 +130,12 @@ type ClusterMemberJoinToken struct {
 	// The token's expiry date.
 	// Example: 2021-03-23T17:38:37.753398689-04:00
 	ExpiresAt time.Time `json:"expires_at" yaml:"expires_at"`
+
+	// Whether the new server replaces the existing (offline) cluster member of that name
+	// Example: false
+	//
+	// API extension: clustering_replace
+	Replace bool `json:"replace" yaml:"replace"`
 }
 
 // String encodes the cluster member join token as JSON and then base64.
@@ -245,7 +257,7 @@ type ClusterCertificatePut struct {
 //
 // API extension: clustering_evacuation.
 type ClusterMemberStatePost struct {
-	// The action to be performed. Valid actions are "evacuate" and "restore".
+	// The action to be performed. Valid actions are "evacuate", "restore", "cordon" and "uncordon".
 	// Example: evacuate
 	Action string `json:"action" yaml:"action"`
 
@@ -277,6 +289,54 @@ type ClusterGroupsPost struct {
 type ClusterGroup struct {
 	ClusterGroupPut  `yaml:",inline"`
 	ClusterGroupPost `yaml:",inline"`
+
+	// Resource allocation of the group members when capacity limits are set
+	//
+	// API extension: cluster_group_limits.
+	Capacity []ClusterGroupMemberCapacity `json:"capacity,omitempty" yaml:"capacity,omitempty"`
+}
+
+// ClusterGroupMemberCapacity represents the resource allocation of a cluster group member.
+//
+// swagger:model
+//
+// API extension: cluster_group_limits.
+type ClusterGroupMemberCapacity struct {
+	// Name of the cluster member
+	// Example: server01
+	Member string `json:"member" yaml:"member"`
+
+	// Number of CPUs allocated to instances
+	// Example: 12
+	CPUAllocated int64 `json:"cpu_allocated" yaml:"cpu_allocated"`
+
+	// Number of CPU threads of the server
+	// Example: 16
+	CPUTotal int64 `json:"cpu_total" yaml:"cpu_total"`
+
+	// Maximum number of CPUs which can be allocated (0 when unlimited)
+	// Example: 32
+	CPULimit int64 `json:"cpu_limit" yaml:"cpu_limit"`
+
+	// Memory allocated to instances (in bytes)
+	// Example: 17179869184
+	MemoryAllocated int64 `json:"memory_allocated" yaml:"memory_allocated"`
+
+	// Total memory of the server (in bytes)
+	// Example: 68719476736
+	MemoryTotal int64 `json:"memory_total" yaml:"memory_total"`
+
+	// Maximum memory which can be allocated (in bytes, 0 when unlimited)
+	// Example: 68719476736
+	MemoryLimit int64 `json:"memory_limit" yaml:"memory_limit"`
+
+	// Number of instances on the server
+	// Example: 10
+	Instances int64 `json:"instances" yaml:"instances"`
+
+	// Maximum number of instances (0 when unlimited)
+	// Example: 20
+	InstancesLimit int64 `json:"instances_limit" yaml:"instances_limit"`
 }
 
 // ClusterGroupPost represents the fields required to rename a cluster group.
@@ -0,0 +1,49 @@
+package api
+
+// ClusterMaintenancePost represents the fields required to perform a rolling maintenance of cluster members.
+//
+// swagger:model
+//
+// API extension: cluster_rolling_maintenance.
+type ClusterMaintenancePost struct {
+	// List of cluster members to maintain (defaults to all members)
+	// Example: ["server01", "server02"]
+	Members []string `json:"members" yaml:"members"`
+
+	// Only maintain the members of this cluster group
+	// Example: default
+	Group string `json:"group" yaml:"group"`
+
+	// Number of members of each cluster group maintained at the same time (defaults to 1)
+	// Example: 1
+	Parallel int `json:"parallel" yaml:"parallel"`
+
+	// Override the configured evacuation mode
+	// Example: live-migrate
+	Mode string `json:"mode" yaml:"mode"`
+
+	// URL called (POST) once a member is evacuated, a non-2xx response stops the maintenance
+	// Example: https://automation.example.net/patch
+	Hook string `json:"hook" yaml:"hook"`
+
+	// What to wait for before restoring a member ("restart" or "version", empty to not wait)
+	// Example: restart
+	Wait string `json:"wait" yaml:"wait"`
+
+	// How long to wait for each member to be ready to be restored (seconds, defaults to 3600)
+	// Example: 1800
+	Timeout int `json:"timeout" yaml:"timeout"`
+}
+
+// ClusterMaintenanceHook represents the payload sent to the maintenance hook.
+//
+// API extension: cluster_rolling_maintenance.
+type ClusterMaintenanceHook struct {
+	// Name of the evacuated cluster member
+	// Example: server01
+	Member string `json:"member" yaml:"member"`
+
+	// Address of the evacuated cluster member
+	// Example: 10.0.0.30:8443
+	Address string `json:"address" yaml:"address"`
+}
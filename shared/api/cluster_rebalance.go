@@ -0,0 +1,80 @@
+package api
+
+// ClusterRebalanceMember represents the load of a cluster member as seen by the re-balancing logic.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_policy.
+type ClusterRebalanceMember struct {
+	// Name of the cluster member
+	// Example: server01
+	Name string `json:"name" yaml:"name"`
+
+	// Architecture of the cluster member
+	// Example: x86_64
+	Architecture string `json:"architecture" yaml:"architecture"`
+
+	// List of cluster groups the member belongs to
+	// Example: ["default"]
+	Groups []string `json:"groups" yaml:"groups"`
+
+	// Weighted load score (percentage)
+	// Example: 42
+	Score uint64 `json:"score" yaml:"score"`
+
+	// CPU load (percentage)
+	// Example: 35
+	CPU uint64 `json:"cpu" yaml:"cpu"`
+
+	// Memory usage (percentage)
+	// Example: 50
+	Memory uint64 `json:"memory" yaml:"memory"`
+
+	// Storage pool usage (percentage)
+	// Example: 20
+	Disk uint64 `json:"disk" yaml:"disk"`
+
+	// Allocated network bandwidth (percentage)
+	// Example: 10
+	Network uint64 `json:"network" yaml:"network"`
+}
+
+// ClusterRebalanceMove represents an instance migration decided by the re-balancing logic.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_policy.
+type ClusterRebalanceMove struct {
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Cluster member the instance is currently on
+	// Example: server01
+	Source string `json:"source" yaml:"source"`
+
+	// Cluster member the instance is moved to
+	// Example: server02
+	Target string `json:"target" yaml:"target"`
+
+	// Whether the instance is live-migrated (otherwise it is restarted on the target)
+	// Example: true
+	Live bool `json:"live" yaml:"live"`
+}
+
+// ClusterRebalancePlan represents the outcome of a re-balancing run.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance_policy.
+type ClusterRebalancePlan struct {
+	// Load of the cluster members taken into account
+	Members []ClusterRebalanceMember `json:"members" yaml:"members"`
+
+	// Instance migrations
+	Moves []ClusterRebalanceMove `json:"moves" yaml:"moves"`
+}
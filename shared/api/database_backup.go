@@ -0,0 +1,39 @@
+package api
+
+import (
+	"time"
+)
+
+// DatabaseBackup represents a backup of the global database.
+//
+// swagger:model
+//
+// API extension: database_backup.
+type DatabaseBackup struct {
+	// Backup name
+	// Example: backup0
+	Name string `json:"name" yaml:"name"`
+
+	// When the backup was created
+	// Example: 2021-03-23T16:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Size of the backup in bytes
+	// Example: 524288
+	Size int64 `json:"size" yaml:"size"`
+
+	// Database schema version the backup was taken at
+	// Example: 74
+	SchemaVersion int `json:"schema_version" yaml:"schema_version"`
+}
+
+// DatabaseBackupsPost represents the fields available for a new backup of the global database.
+//
+// swagger:model
+//
+// API extension: database_backup.
+type DatabaseBackupsPost struct {
+	// Backup name (generated from the current time if empty)
+	// Example: backup0
+	Name string `json:"name" yaml:"name"`
+}
@@ -12,6 +12,7 @@ const (
 	EventTypeLogging    = "logging"
 	EventTypeOperation  = "operation"
 	EventTypeNetworkACL = "network-acl"
+	EventTypeAudit      = "audit"
 )
 
 // Event represents an event entry (over websocket)
@@ -117,6 +118,28 @@ func (event *Event) ToLogging() (EventLogRecord, error) {
 			},
 		}
 
+		return record, nil
+	} else if event.Type == EventTypeAudit {
+		e := &EventAudit{}
+		err := json.Unmarshal(event.Metadata, &e)
+		if err != nil {
+			return EventLogRecord{}, err
+		}
+
+		record := EventLogRecord{
+			Time: event.Timestamp,
+			Lvl:  "info",
+			Msg:  fmt.Sprintf("Method: %s, URL: %s, Outcome: %s, Authorization: %s", e.Method, e.URL, e.Outcome, e.Authorization),
+			Ctx: []any{
+				"StatusCode", e.StatusCode,
+				"BodyDigest", e.BodyDigest,
+			},
+		}
+
+		if e.Requestor != nil {
+			record.Ctx = append(record.Ctx, "Requestor", fmt.Sprintf("%s/%s (%s)", e.Requestor.Protocol, e.Requestor.Username, e.Requestor.Address))
+		}
+
 		return record, nil
 	}
 
@@ -167,3 +190,48 @@ type EventLifecycleRequestor struct {
 	// API extension: event_lifecycle_requestor_address
 	Address string `yaml:"address" json:"address"`
 }
+
+// Audit outcomes.
+const (
+	EventAuditOutcomeSuccess = "success"
+	EventAuditOutcomeFailure = "failure"
+)
+
+// Audit authorization decisions.
+const (
+	EventAuditAuthorizationAllowed         = "allowed"
+	EventAuditAuthorizationDenied          = "denied"
+	EventAuditAuthorizationUnauthenticated = "unauthenticated"
+)
+
+// EventAudit represents an audit type event entry (admin only).
+//
+// API extension: audit_log.
+type EventAudit struct {
+	// Identity which made the request
+	Requestor *EventLifecycleRequestor `yaml:"requestor,omitempty" json:"requestor,omitempty"`
+
+	// HTTP method of the request
+	// Example: POST
+	Method string `yaml:"method" json:"method"`
+
+	// URL of the request
+	// Example: /1.0/instances?project=default
+	URL string `yaml:"url" json:"url"`
+
+	// SHA-256 digest of the request body (only for JSON requests)
+	// Example: 2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae
+	BodyDigest string `yaml:"body_digest,omitempty" json:"body_digest,omitempty"`
+
+	// HTTP status code of the response
+	// Example: 202
+	StatusCode int `yaml:"status_code" json:"status_code"`
+
+	// Outcome of the request (success or failure)
+	// Example: success
+	Outcome string `yaml:"outcome" json:"outcome"`
+
+	// Authorization decision (allowed, denied or unauthenticated)
+	// Example: allowed
+	Authorization string `yaml:"authorization" json:"authorization"`
+}
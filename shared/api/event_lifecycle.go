@@ -2,6 +2,16 @@ package api
 
 // Define consts for all the lifecycle events.
 const (
+	EventLifecycleAuthGroupCreated                  = "auth-group-created"
+	EventLifecycleAuthGroupDeleted                  = "auth-group-deleted"
+	EventLifecycleAuthGroupRenamed                  = "auth-group-renamed"
+	EventLifecycleAuthGroupUpdated                  = "auth-group-updated"
+	EventLifecycleAuthRoleCreated                   = "auth-role-created"
+	EventLifecycleAuthRoleDeleted                   = "auth-role-deleted"
+	EventLifecycleAuthRoleRenamed                   = "auth-role-renamed"
+	EventLifecycleAuthRoleUpdated                   = "auth-role-updated"
+	EventLifecycleAuthTokenCreated                  = "auth-token-created"
+	EventLifecycleAuthTokenDeleted                  = "auth-token-deleted"
 	EventLifecycleCertificateCreated                = "certificate-created"
 	EventLifecycleCertificateDeleted                = "certificate-deleted"
 	EventLifecycleCertificateUpdated                = "certificate-updated"
@@ -13,11 +23,16 @@ const (
 	EventLifecycleClusterGroupRenamed               = "cluster-group-renamed"
 	EventLifecycleClusterGroupUpdated               = "cluster-group-updated"
 	EventLifecycleClusterMemberAdded                = "cluster-member-added"
+	EventLifecycleClusterMemberCordoned             = "cluster-member-cordoned"
 	EventLifecycleClusterMemberEvacuated            = "cluster-member-evacuated"
+	EventLifecycleClusterMemberFenced               = "cluster-member-fenced"
+	EventLifecycleClusterMemberFencingFailed        = "cluster-member-fencing-failed"
 	EventLifecycleClusterMemberHealed               = "cluster-member-healed"
 	EventLifecycleClusterMemberRemoved              = "cluster-member-removed"
 	EventLifecycleClusterMemberRenamed              = "cluster-member-renamed"
+	EventLifecycleClusterMemberReplaced             = "cluster-member-replaced"
 	EventLifecycleClusterMemberRestored             = "cluster-member-restored"
+	EventLifecycleClusterMemberUncordoned           = "cluster-member-uncordoned"
 	EventLifecycleClusterMemberUpdated              = "cluster-member-updated"
 	EventLifecycleClusterTokenCreated               = "cluster-token-created"
 	EventLifecycleConfigUpdated                     = "config-updated"
@@ -44,6 +59,7 @@ const (
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
 	EventLifecycleInstanceFileRetrieved             = "instance-file-retrieved"
+	EventLifecycleInstanceHealthChanged             = "instance-health-changed"
 	EventLifecycleInstanceLogDeleted                = "instance-log-deleted"
 	EventLifecycleInstanceLogRetrieved              = "instance-log-retrieved"
 	EventLifecycleInstanceMetadataRetrieved         = "instance-metadata-retrieved"
@@ -58,6 +74,9 @@ const (
 	EventLifecycleInstanceRestarted                 = "instance-restarted"
 	EventLifecycleInstanceRestored                  = "instance-restored"
 	EventLifecycleInstanceResumed                   = "instance-resumed"
+	EventLifecycleInstanceSetCreated                = "instance-set-created"
+	EventLifecycleInstanceSetDeleted                = "instance-set-deleted"
+	EventLifecycleInstanceSetUpdated                = "instance-set-updated"
 	EventLifecycleInstanceShutdown                  = "instance-shutdown"
 	EventLifecycleInstanceSnapshotCreated           = "instance-snapshot-created"
 	EventLifecycleInstanceSnapshotDeleted           = "instance-snapshot-deleted"
@@ -101,7 +120,20 @@ const (
 	EventLifecycleProjectCreated                    = "project-created"
 	EventLifecycleProjectDeleted                    = "project-deleted"
 	EventLifecycleProjectRenamed                    = "project-renamed"
+	EventLifecycleProjectTemplateApplied            = "project-template-applied"
+	EventLifecycleProjectTemplateCreated            = "project-template-created"
+	EventLifecycleProjectTemplateDeleted            = "project-template-deleted"
+	EventLifecycleProjectTemplateUpdated            = "project-template-updated"
 	EventLifecycleProjectUpdated                    = "project-updated"
+	EventLifecycleReplicationRemoteCreated          = "replication-remote-created"
+	EventLifecycleReplicationRemoteDeleted          = "replication-remote-deleted"
+	EventLifecycleReplicationRemoteUpdated          = "replication-remote-updated"
+	EventLifecycleSecretCreated                     = "secret-created"
+	EventLifecycleSecretDeleted                     = "secret-deleted"
+	EventLifecycleSecretUpdated                     = "secret-updated"
+	EventLifecycleStackCreated                      = "stack-created"
+	EventLifecycleStackDeleted                      = "stack-deleted"
+	EventLifecycleStackUpdated                      = "stack-updated"
 	EventLifecycleStorageBucketBackupCreated        = "storage-bucket-backup-created"
 	EventLifecycleStorageBucketBackupDeleted        = "storage-bucket-backup-deleted"
 	EventLifecycleStorageBucketBackupRenamed        = "storage-bucket-backup-renamed"
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"time"
+)
+
+// InstanceReplication represents the replication status of an instance.
+//
+// swagger:model
+//
+// API extension: instance_replication.
+type InstanceReplication struct {
+	// Replication role of the instance (primary or replica)
+	// Example: primary
+	Role string `json:"role" yaml:"role"`
+
+	// Name of the replication remote the instance is replicated to
+	// Example: dr-site
+	Remote string `json:"remote" yaml:"remote"`
+
+	// Schedule for automatic replication
+	// Example: @hourly
+	Schedule string `json:"schedule" yaml:"schedule"`
+
+	// When the last replication was attempted
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastAttempt time.Time `json:"last_attempt" yaml:"last_attempt"`
+
+	// When the last replication succeeded
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastSuccess time.Time `json:"last_success" yaml:"last_success"`
+
+	// Error returned by the last failed replication
+	// Example: Failed connecting to remote server
+	LastError string `json:"last_error" yaml:"last_error"`
+
+	// Age in seconds of the most recent replicated state (-1 if never replicated)
+	// Example: 125
+	Lag int64 `json:"lag" yaml:"lag"`
+
+	// List of replication snapshots
+	// Example: ["replication-20210323-213837"]
+	Snapshots []string `json:"snapshots" yaml:"snapshots"`
+}
+
+// InstanceReplicationPost represents a replication action on an instance.
+//
+// swagger:model
+//
+// API extension: instance_replication.
+type InstanceReplicationPost struct {
+	// Action to perform (replicate or failover)
+	// Example: replicate
+	Action string `json:"action" yaml:"action"`
+
+	// Whether to fail over even if the primary can't be reached
+	// Example: false
+	Force bool `json:"force" yaml:"force"`
+}
@@ -0,0 +1,102 @@
+package api
+
+// InstanceSetsPost represents the fields of a new instance set.
+//
+// swagger:model
+//
+// API extension: instance_sets.
+type InstanceSetsPost struct {
+	InstanceSetPut `yaml:",inline"`
+
+	// Name of the instance set
+	// Example: web
+	Name string `json:"name" yaml:"name"`
+}
+
+// InstanceSetPut represents the modifiable fields of an instance set.
+//
+// swagger:model
+//
+// API extension: instance_sets.
+type InstanceSetPut struct {
+	// Description of the instance set
+	// Example: Web frontends
+	Description string `json:"description" yaml:"description"`
+
+	// Type of the instances (container or virtual-machine)
+	// Example: container
+	Type InstanceType `json:"type" yaml:"type"`
+
+	// Source of the instances, only used when creating new instances
+	Source InstanceSource `json:"source" yaml:"source"`
+
+	// List of profiles applied to the instances
+	// Example: ["default"]
+	Profiles []string `json:"profiles" yaml:"profiles"`
+
+	// Instance configuration (see doc/instances.md)
+	// Example: {"limits.cpu": "2"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// Instance devices (see doc/instances.md)
+	// Example: {"root": {"type": "disk", "pool": "default", "path": "/"}}
+	Devices map[string]map[string]string `json:"devices" yaml:"devices"`
+
+	// Number of instances in the set
+	// Example: 3
+	Replicas int `json:"replicas" yaml:"replicas"`
+
+	// Cluster group the instances are placed in
+	// Example: frontends
+	ClusterGroup string `json:"cluster_group" yaml:"cluster_group"`
+
+	// Network load balancer the instances are registered with as backends
+	LoadBalancer *InstanceSetLoadBalancer `json:"load_balancer" yaml:"load_balancer"`
+}
+
+// InstanceSet represents a group of identical instances.
+//
+// swagger:model
+//
+// API extension: instance_sets.
+type InstanceSet struct {
+	InstanceSetPut `yaml:",inline"`
+
+	// Name of the instance set
+	// Example: web
+	Name string `json:"name" yaml:"name"`
+
+	// Project the instance set belongs to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// List of URLs of the instances in the set
+	// Example: ["/1.0/instances/web-1", "/1.0/instances/web-2"]
+	//
+	// Read only: true
+	Instances []string `json:"instances" yaml:"instances"`
+}
+
+// Writable converts a full InstanceSet struct into an InstanceSetPut struct (filters read-only fields).
+func (s *InstanceSet) Writable() InstanceSetPut {
+	return s.InstanceSetPut
+}
+
+// InstanceSetLoadBalancer represents the network load balancer an instance set is registered with.
+//
+// swagger:model
+//
+// API extension: instance_sets.
+type InstanceSetLoadBalancer struct {
+	// Name of the network the load balancer belongs to
+	// Example: ovn0
+	Network string `json:"network" yaml:"network"`
+
+	// Listen address of the load balancer
+	// Example: 192.0.2.10
+	ListenAddress string `json:"listen_address" yaml:"listen_address"`
+
+	// Target port(s) of the backends (optional, defaults to the listen ports)
+	// Example: 8080
+	TargetPort string `json:"target_port" yaml:"target_port"`
+}
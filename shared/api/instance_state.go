@@ -70,6 +70,11 @@ type InstanceState struct {
 	//
 	// API extension: instances_state_os_info.
 	OSInfo *InstanceStateOSInfo `json:"os_info" yaml:"os_info"`
+
+	// Health check information.
+	//
+	// API extension: instance_healthcheck.
+	Health *InstanceStateHealth `json:"health,omitempty" yaml:"health,omitempty"`
 }
 
 // InstanceStateDisk represents the disk information section of an instance's state.
@@ -258,3 +263,26 @@ type InstanceStateOSInfo struct {
 	// Example: myhost.mydomain.local
 	FQDN string `json:"fqdn" yaml:"fqdn"`
 }
+
+// InstanceStateHealth represents the health check section of an instance's state.
+//
+// swagger:model
+//
+// API extension: instance_healthcheck.
+type InstanceStateHealth struct {
+	// Current health status (starting, healthy or unhealthy)
+	// Example: healthy
+	Status string `json:"status" yaml:"status"`
+
+	// Number of consecutive failed checks
+	// Example: 0
+	Failures int `json:"failures" yaml:"failures"`
+
+	// Time of the last check
+	// Example: 2024-11-20T18:31:22Z
+	LastCheck time.Time `json:"last_check" yaml:"last_check"`
+
+	// Error returned by the last failed check
+	// Example: Health check command exited with status 1
+	LastError string `json:"last_error" yaml:"last_error"`
+}
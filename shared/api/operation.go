@@ -157,12 +157,16 @@ func (op *Operation) ToClusterJoinToken() (*ClusterMemberJoinToken, error) {
 		return nil, err
 	}
 
+	// Only set for tokens replacing an existing member.
+	replace, _ := op.Metadata["replace"].(bool)
+
 	joinToken := ClusterMemberJoinToken{
 		ServerName:  serverName,
 		Secret:      secret,
 		Fingerprint: fingerprint,
 		Addresses:   make([]string, 0, len(addresses)),
 		ExpiresAt:   expiresAt,
+		Replace:     replace,
 	}
 
 	for i, address := range addresses {
@@ -1,5 +1,9 @@
 package api
 
+import (
+	"time"
+)
+
 // ProjectDefaultName is the name of the default project that can never be deleted.
 const ProjectDefaultName = "default"
 
@@ -14,6 +18,12 @@ type ProjectsPost struct {
 	// The name of the new project
 	// Example: foo
 	Name string `json:"name" yaml:"name"`
+
+	// Name of the project template to create the project from
+	// Example: tenant
+	//
+	// API extension: project_templates
+	Template string `json:"template" yaml:"template"`
 }
 
 // ProjectPost represents the fields required to rename a project
@@ -99,3 +109,50 @@ type ProjectStateResource struct {
 	// Example: 4
 	Usage int64
 }
+
+// ProjectUsage represents the resources consumed by a project over a period of time
+//
+// swagger:model
+//
+// API extension: projects_usage_accounting.
+type ProjectUsage struct {
+	// Start of the period
+	// Example: 2026-09-01T00:00:00Z
+	From time.Time `json:"from" yaml:"from"`
+
+	// End of the period
+	// Example: 2026-10-01T00:00:00Z
+	To time.Time `json:"to" yaml:"to"`
+
+	// Time spent running instances (in seconds)
+	// Example: 2592000
+	InstanceSeconds float64 `json:"instance_seconds" yaml:"instance_seconds"`
+
+	// CPU time consumed by the instances (in seconds)
+	// Example: 86400.5
+	CPUSeconds float64 `json:"cpu_seconds" yaml:"cpu_seconds"`
+
+	// Memory used by the instances over time (in byte-seconds)
+	// Example: 2782664294400000
+	MemoryByteSeconds float64 `json:"memory_byte_seconds" yaml:"memory_byte_seconds"`
+
+	// Root disk space used by the instances over time (in byte-seconds)
+	// Example: 27826642944000000
+	DiskByteSeconds float64 `json:"disk_byte_seconds" yaml:"disk_byte_seconds"`
+
+	// Data read from the instance disks (in bytes)
+	// Example: 10737418240
+	DiskReadBytes int64 `json:"disk_read_bytes" yaml:"disk_read_bytes"`
+
+	// Data written to the instance disks (in bytes)
+	// Example: 5368709120
+	DiskWrittenBytes int64 `json:"disk_written_bytes" yaml:"disk_written_bytes"`
+
+	// Data received by the instance network interfaces (in bytes)
+	// Example: 21474836480
+	NetworkReceivedBytes int64 `json:"network_received_bytes" yaml:"network_received_bytes"`
+
+	// Data transmitted by the instance network interfaces (in bytes)
+	// Example: 1073741824
+	NetworkTransmittedBytes int64 `json:"network_transmitted_bytes" yaml:"network_transmitted_bytes"`
+}
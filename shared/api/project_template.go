@@ -0,0 +1,97 @@
+package api
+
+// ProjectTemplatesPost represents the fields of a new project template.
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplatesPost struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// Name of the project template
+	// Example: tenant
+	Name string `json:"name" yaml:"name"`
+}
+
+// ProjectTemplatePut represents the modifiable fields of a project template.
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplatePut struct {
+	// Description of the project template
+	// Example: Self-service tenant
+	Description string `json:"description" yaml:"description"`
+
+	// Configuration of the projects created from the template
+	// Example: {"features.networks": "true", "restricted": "true", "limits.instances": "10"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// Profiles created in the project (the default profile is updated)
+	Profiles []ProfilesPost `json:"profiles" yaml:"profiles"`
+
+	// Network ACLs created in the project
+	NetworkACLs []NetworkACLsPost `json:"network_acls" yaml:"network_acls"`
+
+	// Networks created in the project
+	Networks []NetworksPost `json:"networks" yaml:"networks"`
+
+	// Custom storage volumes created in the project
+	StorageVolumes []StackStorageVolume `json:"storage_volumes" yaml:"storage_volumes"`
+
+	// Fingerprints of the restricted client certificates given access to the project
+	// Example: ["2b9fa2e1bc7e7bd1a5bf3e9a6e4b3e2d9c0c7b1b8e0f4a4b3d2b7d4c2a1f0e9d"]
+	Certificates []string `json:"certificates" yaml:"certificates"`
+
+	// Roles granted to authorization groups on the project
+	AuthGroups []ProjectTemplateAuthGroup `json:"auth_groups" yaml:"auth_groups"`
+}
+
+// ProjectTemplate represents a reusable definition of a project.
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplate struct {
+	ProjectTemplatePut `yaml:",inline"`
+
+	// Name of the project template
+	// Example: tenant
+	Name string `json:"name" yaml:"name"`
+
+	// List of projects created from the template
+	// Read only: true
+	// Example: ["/1.0/projects/tenant1", "/1.0/projects/tenant2"]
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+}
+
+// Writable converts a full ProjectTemplate struct into a ProjectTemplatePut struct (filters read-only fields).
+func (t *ProjectTemplate) Writable() ProjectTemplatePut {
+	return t.ProjectTemplatePut
+}
+
+// ProjectTemplateAuthGroup represents a role granted to an authorization group on the projects created from a template.
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplateAuthGroup struct {
+	// Name of the authorization group
+	// Example: tenant-admins
+	Group string `json:"group" yaml:"group"`
+
+	// Name of the role granted on the project
+	// Example: instance-operator
+	Role string `json:"role" yaml:"role"`
+}
+
+// ProjectTemplateApplyPost represents the projects to update to the current definition of a template.
+//
+// swagger:model
+//
+// API extension: project_templates.
+type ProjectTemplateApplyPost struct {
+	// Names of the projects to update (all the projects created from the template if empty)
+	// Example: ["tenant1"]
+	Projects []string `json:"projects" yaml:"projects"`
+}
@@ -0,0 +1,67 @@
+package api
+
+// ReplicationRemotesPost represents the fields of a new replication remote.
+//
+// swagger:model
+//
+// API extension: instance_replication.
+type ReplicationRemotesPost struct {
+	ReplicationRemotePut `yaml:",inline"`
+
+	// Name of the replication remote
+	// Example: dr-site
+	Name string `json:"name" yaml:"name"`
+}
+
+// ReplicationRemotePut represents the modifiable fields of a replication remote.
+//
+// swagger:model
+//
+// API extension: instance_replication.
+type ReplicationRemotePut struct {
+	// Description of the replication remote
+	// Example: Disaster recovery site
+	Description string `json:"description" yaml:"description"`
+
+	// Address of the remote server or cluster
+	// Example: https://dr.example.net:8443
+	Address string `json:"address" yaml:"address"`
+
+	// PEM encoded certificate of the remote server (empty if signed by a trusted CA)
+	// Example: X509 PEM certificate
+	Certificate string `json:"certificate" yaml:"certificate"`
+
+	// Project to replicate instances into on the remote server (empty for the instance's project)
+	// Example: default
+	TargetProject string `json:"target_project" yaml:"target_project"`
+}
+
+// ReplicationRemote represents a remote server that instances can be replicated to.
+//
+// swagger:model
+//
+// API extension: instance_replication.
+type ReplicationRemote struct {
+	ReplicationRemotePut `yaml:",inline"`
+
+	// Name of the replication remote
+	// Example: dr-site
+	Name string `json:"name" yaml:"name"`
+
+	// PEM encoded client certificate used to authenticate to the remote server
+	// Example: X509 PEM certificate
+	//
+	// Read only: true
+	ClientCertificate string `json:"client_certificate" yaml:"client_certificate"`
+
+	// List of instances and profiles referencing the replication remote
+	// Example: ["/1.0/instances/c1"]
+	//
+	// Read only: true
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+}
+
+// Writable converts a full ReplicationRemote struct into a ReplicationRemotePut struct (filters read-only fields).
+func (r *ReplicationRemote) Writable() ReplicationRemotePut {
+	return r.ReplicationRemotePut
+}
@@ -0,0 +1,14 @@
+package scriptlet
+
+// ClusterRebalanceInstance represents an instance the cluster re-balancing scriptlet may move.
+//
+// API extension: cluster_rebalance_policy.
+type ClusterRebalanceInstance struct {
+	Name      string            `json:"name"`
+	Project   string            `json:"project"`
+	Type      string            `json:"type"`
+	Location  string            `json:"location"`
+	Live      bool              `json:"live"`
+	Config    map[string]string `json:"config"`
+	Resources InstanceResources `json:"resources"`
+}
@@ -0,0 +1,25 @@
+package scriptlet
+
+import (
+	"github.com/lxc/incus/v6/shared/api"
+)
+
+// InstanceAdmissionReasonCreate is when a new instance is being created.
+const InstanceAdmissionReasonCreate = "create"
+
+// InstanceAdmissionReasonUpdate is when the configuration, devices or profiles of an existing instance are being changed.
+const InstanceAdmissionReasonUpdate = "update"
+
+// InstanceAdmission represents an instance creation or update request submitted to the admission scriptlet.
+//
+// API extension: instances_admission_scriptlet.
+type InstanceAdmission struct {
+	api.InstancePut `yaml:",inline"`
+
+	Name            string                       `json:"name"`
+	Type            string                       `json:"type"`
+	Project         string                       `json:"project"`
+	Reason          string                       `json:"reason"`
+	ExpandedConfig  map[string]string            `json:"expanded_config"`
+	ExpandedDevices map[string]map[string]string `json:"expanded_devices"`
+}
@@ -0,0 +1,57 @@
+package api
+
+// SecretsPost represents the fields of a new secret.
+//
+// swagger:model
+//
+// API extension: secrets.
+type SecretsPost struct {
+	SecretPut `yaml:",inline"`
+
+	// Name of the secret
+	// Example: db-password
+	Name string `json:"name" yaml:"name"`
+}
+
+// SecretPut represents the modifiable fields of a secret.
+//
+// swagger:model
+//
+// API extension: secrets.
+type SecretPut struct {
+	// Description of the secret
+	// Example: Password of the application database
+	Description string `json:"description" yaml:"description"`
+
+	// Value of the secret (write-only, left unchanged on update when empty)
+	// Example: s3cr3t
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Secret represents a value kept out of the instance configuration.
+//
+// swagger:model
+//
+// API extension: secrets.
+type Secret struct {
+	SecretPut `yaml:",inline"`
+
+	// Name of the secret
+	// Example: db-password
+	Name string `json:"name" yaml:"name"`
+
+	// Project the secret belongs to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// List of instances and profiles referencing the secret
+	// Example: ["/1.0/instances/c1", "/1.0/profiles/default"]
+	//
+	// Read only: true
+	UsedBy []string `json:"used_by" yaml:"used_by"`
+}
+
+// Writable converts a full Secret struct into a SecretPut struct (filters read-only fields).
+func (s *Secret) Writable() SecretPut {
+	return s.SecretPut
+}
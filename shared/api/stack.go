@@ -0,0 +1,126 @@
+package api
+
+// StacksPost represents the fields of a new stack.
+//
+// swagger:model
+//
+// API extension: stacks.
+type StacksPost struct {
+	StackPut `yaml:",inline"`
+
+	// Name of the stack
+	// Example: myapp
+	Name string `json:"name" yaml:"name"`
+}
+
+// StackPut represents the modifiable fields of a stack.
+//
+// swagger:model
+//
+// API extension: stacks.
+type StackPut struct {
+	// Description of the stack
+	// Example: Web application with its database
+	Description string `json:"description" yaml:"description"`
+
+	// Profiles managed by the stack
+	Profiles []ProfilesPost `json:"profiles" yaml:"profiles"`
+
+	// Network ACLs managed by the stack
+	//
+	// API extension: project_templates
+	NetworkACLs []NetworkACLsPost `json:"network_acls" yaml:"network_acls"`
+
+	// Networks managed by the stack
+	Networks []NetworksPost `json:"networks" yaml:"networks"`
+
+	// Network forwards managed by the stack
+	NetworkForwards []StackNetworkForward `json:"network_forwards" yaml:"network_forwards"`
+
+	// Custom storage volumes managed by the stack
+	StorageVolumes []StackStorageVolume `json:"storage_volumes" yaml:"storage_volumes"`
+
+	// Instances managed by the stack
+	Instances []InstancesPost `json:"instances" yaml:"instances"`
+}
+
+// Stack represents a set of resources managed together.
+//
+// swagger:model
+//
+// API extension: stacks.
+type Stack struct {
+	StackPut `yaml:",inline"`
+
+	// Name of the stack
+	// Example: myapp
+	Name string `json:"name" yaml:"name"`
+
+	// Project the stack belongs to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+}
+
+// Writable converts a full Stack struct into a StackPut struct (filters read-only fields).
+func (s *Stack) Writable() StackPut {
+	return s.StackPut
+}
+
+// StackNetworkForward represents a network forward managed by a stack.
+//
+// swagger:model
+//
+// API extension: stacks.
+type StackNetworkForward struct {
+	NetworkForwardsPost `yaml:",inline"`
+
+	// Name of the network the forward belongs to
+	// Example: incusbr0
+	Network string `json:"network" yaml:"network"`
+}
+
+// StackStorageVolume represents a custom storage volume managed by a stack.
+//
+// swagger:model
+//
+// API extension: stacks.
+type StackStorageVolume struct {
+	StorageVolumesPost `yaml:",inline"`
+
+	// Name of the storage pool the volume belongs to
+	// Example: default
+	Pool string `json:"pool" yaml:"pool"`
+}
+
+// StackPlan represents the changes needed to bring a stack to its desired state.
+//
+// swagger:model
+//
+// API extension: stacks.
+type StackPlan struct {
+	// List of changes, in the order they are applied
+	Changes []StackChange `json:"changes" yaml:"changes"`
+}
+
+// StackChange represents a single change to a resource of a stack.
+//
+// swagger:model
+//
+// API extension: stacks.
+type StackChange struct {
+	// Action to take on the resource (create, update or delete)
+	// Example: update
+	Action string `json:"action" yaml:"action"`
+
+	// Type of the resource (profile, network-acl, network, network-forward, storage-volume or instance)
+	// Example: instance
+	Type string `json:"type" yaml:"type"`
+
+	// Name of the resource
+	// Example: web
+	Name string `json:"name" yaml:"name"`
+
+	// Fields being modified by an update
+	// Example: ["config.limits.cpu", "devices.eth0"]
+	Fields []string `json:"fields" yaml:"fields"`
+}
@@ -155,6 +155,25 @@ func ParseBitSizeString(input string) (int64, error) {
 	return handleOverflow(valueInt, multiplicator)
 }
 
+// GetBitSizeString takes a number of bits and precision and returns a
+// human representation of the amount of data.
+func GetBitSizeString(input int64, precision uint) string {
+	if input < 1000 {
+		return fmt.Sprintf("%dbit", input)
+	}
+
+	value := float64(input)
+
+	for _, unit := range []string{"kbit", "Mbit", "Gbit", "Tbit", "Pbit", "Ebit"} {
+		value = value / 1000
+		if value < 1000 {
+			return fmt.Sprintf("%.*f%s", precision, value, unit)
+		}
+	}
+
+	return fmt.Sprintf("%.*fEbit", precision, value)
+}
+
 // GetByteSizeString takes a number of bytes and precision and returns a
 // human representation of the amount of data.
 func GetByteSizeString(input int64, precision uint) string {